@@ -2,6 +2,7 @@ package apierror
 
 import (
 	"fmt"
+	"time"
 )
 
 // APIError represents a custom error type.
@@ -15,14 +16,39 @@ type APIError interface {
 
 // DefaultAPIError represents a JSON marshalable custom error type.
 type DefaultAPIError struct {
-	ErrID      string `json:"id"`
-	ErrData    any    `json:"data,omitempty"`
-	ErrMessage string `json:"message,omitempty"`
-	ErrOrigin  string `json:"origin,omitempty"`
+	ErrID        string        `json:"id"`
+	ErrData      any           `json:"data,omitempty"`
+	ErrMessage   string        `json:"message,omitempty"`
+	ErrOrigin    string        `json:"origin,omitempty"`
+	ErrRetryable bool          `json:"retryable,omitempty"`
+	cause        error         // Underlying error, never sent to clients.
+	status       int           // HTTP status code chosen by the error, 0 if none.
+	retryAfter   time.Duration // Delay before retrying, 0 if unknown.
 }
 
 var _ APIError = (*DefaultAPIError)(nil)
 
+// StatusCoder is implemented by API errors that dictate the HTTP status
+// code of their response. A status of 0 leaves the choice to the error
+// handler.
+type StatusCoder interface {
+	Status() int
+}
+
+// DefaultAPIError implements the StatusCoder interface.
+var _ StatusCoder = (*DefaultAPIError)(nil)
+
+// Retrier is implemented by API errors telling whether and when the
+// request may be retried. Output paths turn RetryAfter into a Retry-After
+// header on 429 and 503 responses.
+type Retrier interface {
+	Retryable() bool
+	RetryAfter() time.Duration
+}
+
+// DefaultAPIError implements the Retrier interface.
+var _ Retrier = (*DefaultAPIError)(nil)
+
 // NewAPIError returns a new error with the given ID.
 //
 // Parameters:
@@ -39,7 +65,11 @@ func NewAPIError(id string) *DefaultAPIError {
 	}
 }
 
-// APIErrorFrom converts an APIError to a DefaultAPIError.
+// APIErrorFrom converts an APIError to a DefaultAPIError. The error chain
+// is preserved: a DefaultAPIError keeps its cause, any other APIError
+// becomes the cause of the result, so errors.Is and errors.As still find
+// it and the errors it wraps. The status of a StatusCoder and the retry
+// information of a Retrier are kept.
 //
 // Parameters:
 //   - err: The APIError to convert.
@@ -47,12 +77,25 @@ func NewAPIError(id string) *DefaultAPIError {
 // Returns:
 //   - *DefaultAPIError: A new DefaultAPIError instance.
 func APIErrorFrom(err APIError) *DefaultAPIError {
-	return &DefaultAPIError{
+	if d, ok := err.(*DefaultAPIError); ok {
+		new := *d
+		return &new
+	}
+	new := &DefaultAPIError{
 		ErrID:      err.ID(),
 		ErrData:    err.Data(),
 		ErrMessage: err.Message(),
 		ErrOrigin:  err.Origin(),
+		cause:      err,
 	}
+	if sc, ok := err.(StatusCoder); ok {
+		new.status = sc.Status()
+	}
+	if r, ok := err.(Retrier); ok {
+		new.ErrRetryable = r.Retryable()
+		new.retryAfter = r.RetryAfter()
+	}
+	return new
 }
 
 // WithID returns a new error with the given ID.
@@ -107,6 +150,66 @@ func (e *DefaultAPIError) WithOrigin(origin string) *DefaultAPIError {
 	return &new
 }
 
+// WithStatus returns a new error dictating the HTTP status code of its
+// response, which error handlers use instead of mapping the ID. The
+// status is not part of the JSON representation.
+//
+// Parameters:
+//   - status: The HTTP status code, or 0 to let the error handler choose.
+//
+// Returns:
+//   - *DefaultAPIError: A new DefaultAPIError.
+func (e *DefaultAPIError) WithStatus(status int) *DefaultAPIError {
+	new := *e
+	new.status = status
+	return &new
+}
+
+// WithRetryable returns a new error telling clients whether repeating the
+// request may succeed.
+//
+// Parameters:
+//   - retryable: Whether the request may be retried.
+//
+// Returns:
+//   - *DefaultAPIError: A new DefaultAPIError.
+func (e *DefaultAPIError) WithRetryable(retryable bool) *DefaultAPIError {
+	new := *e
+	new.ErrRetryable = retryable
+	return &new
+}
+
+// WithRetryAfter returns a new retryable error asking clients to wait for
+// the given duration before retrying. Output paths send it as the
+// Retry-After header of 429 and 503 responses.
+//
+// Parameters:
+//   - d: The delay before retrying.
+//
+// Returns:
+//   - *DefaultAPIError: A new DefaultAPIError.
+func (e *DefaultAPIError) WithRetryAfter(d time.Duration) *DefaultAPIError {
+	new := *e
+	new.ErrRetryable = true
+	new.retryAfter = d
+	return &new
+}
+
+// WithCause returns a new error wrapping the given underlying error. The
+// cause is available to errors.Is, errors.As and errors.Unwrap but is not
+// part of the error message or the JSON representation.
+//
+// Parameters:
+//   - cause: The underlying error.
+//
+// Returns:
+//   - *DefaultAPIError: A new DefaultAPIError.
+func (e *DefaultAPIError) WithCause(cause error) *DefaultAPIError {
+	new := *e
+	new.cause = cause
+	return &new
+}
+
 // Error returns the full error message as a string. If the error has a message,
 // it returns the ID followed by the message. Otherwise, it returns just the ID.
 //
@@ -150,3 +253,35 @@ func (e *DefaultAPIError) Message() string {
 func (e *DefaultAPIError) Origin() string {
 	return e.ErrOrigin
 }
+
+// Status returns the HTTP status code set with WithStatus.
+//
+// Returns:
+//   - int: The status code, or 0 if none was set.
+func (e *DefaultAPIError) Status() int {
+	return e.status
+}
+
+// Retryable reports whether the request may be retried.
+//
+// Returns:
+//   - bool: Whether the request may be retried.
+func (e *DefaultAPIError) Retryable() bool {
+	return e.ErrRetryable
+}
+
+// RetryAfter returns the delay set with WithRetryAfter.
+//
+// Returns:
+//   - time.Duration: The delay before retrying, or 0 if unknown.
+func (e *DefaultAPIError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// Unwrap returns the underlying error, if any.
+//
+// Returns:
+//   - error: The cause of the error, or nil.
+func (e *DefaultAPIError) Unwrap() error {
+	return e.cause
+}
@@ -1,7 +1,11 @@
 package apierror
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -116,3 +120,78 @@ func (s *APIErrorTestSuite) Test_Error() {
 	errWithMsg := base.WithMessage(msg)
 	s.Equal("E004: "+msg, errWithMsg.Error())
 }
+
+// Test_WithCause verifies that the cause is reachable through the error
+// chain but not part of the message or JSON.
+func (s *APIErrorTestSuite) Test_WithCause() {
+	cause := errors.New("connection refused")
+	base := NewAPIError("E005").WithMessage("unavailable")
+	errWithCause := base.WithCause(fmt.Errorf("dial: %w", cause))
+
+	s.NotSame(base, errWithCause)
+	s.Nil(base.Unwrap())
+	s.ErrorIs(errWithCause, cause)
+	s.Equal("E005: unavailable", errWithCause.Error())
+	data, err := json.Marshal(errWithCause)
+	s.Require().NoError(err)
+	s.JSONEq(`{"id":"E005","message":"unavailable"}`, string(data))
+}
+
+// customAPIError is an APIError other than DefaultAPIError.
+type customAPIError struct{ DefaultAPIError }
+
+// Test_APIErrorFrom verifies that conversions keep the error chain.
+func (s *APIErrorTestSuite) Test_APIErrorFrom() {
+	cause := errors.New("cause")
+	d := NewAPIError("E006").WithCause(cause)
+	converted := APIErrorFrom(d)
+	s.NotSame(d, converted)
+	s.ErrorIs(converted, cause)
+
+	custom := &customAPIError{DefaultAPIError: *NewAPIError("E007")}
+	converted = APIErrorFrom(custom)
+	s.Equal("E007", converted.ID())
+	var target *customAPIError
+	s.Require().ErrorAs(converted, &target)
+	s.Same(custom, target)
+}
+
+// Test_WithStatus verifies that the status is kept by copies and
+// conversions but not serialized.
+func (s *APIErrorTestSuite) Test_WithStatus() {
+	base := NewAPIError("E008")
+	s.Equal(0, base.Status())
+
+	errWithStatus := base.WithStatus(410)
+	s.NotSame(base, errWithStatus)
+	s.Equal(410, errWithStatus.Status())
+	s.Equal(410, errWithStatus.WithMessage("gone").Status())
+	s.Equal(410, APIErrorFrom(errWithStatus).Status())
+	s.Equal(410, APIErrorFrom(&customAPIError{DefaultAPIError: *errWithStatus}).Status())
+
+	data, err := json.Marshal(errWithStatus)
+	s.Require().NoError(err)
+	s.JSONEq(`{"id":"E008"}`, string(data))
+}
+
+// Test_WithRetryAfter verifies the retry information of errors.
+func (s *APIErrorTestSuite) Test_WithRetryAfter() {
+	base := NewAPIError("E009")
+	s.False(base.Retryable())
+
+	retryable := base.WithRetryable(true)
+	s.True(retryable.Retryable())
+	s.Zero(retryable.RetryAfter())
+
+	delayed := base.WithRetryAfter(time.Minute)
+	s.True(delayed.Retryable())
+	s.Equal(time.Minute, delayed.RetryAfter())
+	s.Equal(time.Minute, APIErrorFrom(&customAPIError{DefaultAPIError: *delayed}).RetryAfter())
+
+	data, err := json.Marshal(delayed)
+	s.Require().NoError(err)
+	s.JSONEq(`{"id":"E009","retryable":true}`, string(data))
+	parsed, err := ParseAPIError(data)
+	s.Require().NoError(err)
+	s.True(parsed.Retryable())
+}
@@ -0,0 +1,137 @@
+package apierror
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// IDs of the errors built by the constructors in this package.
+const (
+	IDBadRequest      = "bad_request"
+	IDUnauthorized    = "unauthorized"
+	IDForbidden       = "forbidden"
+	IDNotFound        = "not_found"
+	IDConflict        = "conflict"
+	IDTooManyRequests = "too_many_requests"
+	IDInternal        = "internal_error"
+	IDUnavailable     = "service_unavailable"
+)
+
+// BadRequest returns a 400 bad_request error for a malformed request.
+//
+// Parameters:
+//   - message: Describes what is wrong with the request.
+//
+// Returns:
+//   - *DefaultAPIError: A new DefaultAPIError.
+func BadRequest(message string) *DefaultAPIError {
+	return NewAPIError(IDBadRequest).WithMessage(message).
+		WithStatus(http.StatusBadRequest)
+}
+
+// Unauthorized returns a 401 unauthorized error for a request without
+// valid credentials.
+//
+// Parameters:
+//   - message: The message, or "" for "authentication required".
+//
+// Returns:
+//   - *DefaultAPIError: A new DefaultAPIError.
+func Unauthorized(message string) *DefaultAPIError {
+	if message == "" {
+		message = "authentication required"
+	}
+	return NewAPIError(IDUnauthorized).WithMessage(message).
+		WithStatus(http.StatusUnauthorized)
+}
+
+// Forbidden returns a 403 forbidden error for a caller lacking permission.
+//
+// Parameters:
+//   - message: The message, or "" for "access denied".
+//
+// Returns:
+//   - *DefaultAPIError: A new DefaultAPIError.
+func Forbidden(message string) *DefaultAPIError {
+	if message == "" {
+		message = "access denied"
+	}
+	return NewAPIError(IDForbidden).WithMessage(message).
+		WithStatus(http.StatusForbidden)
+}
+
+// NotFound returns a 404 not_found error for a missing resource, e.g.
+// NotFound("user", 42) with the message "user 42 not found" and the data
+// {"resource": "user", "id": 42}.
+//
+// Parameters:
+//   - resource: The kind of resource.
+//   - id: The identifier of the resource, or nil.
+//
+// Returns:
+//   - *DefaultAPIError: A new DefaultAPIError.
+func NotFound(resource string, id any) *DefaultAPIError {
+	data := map[string]any{"resource": resource}
+	message := resource + " not found"
+	if id != nil {
+		data["id"] = id
+		message = fmt.Sprintf("%s %v not found", resource, id)
+	}
+	return NewAPIError(IDNotFound).WithMessage(message).WithData(data).
+		WithStatus(http.StatusNotFound)
+}
+
+// Conflict returns a 409 conflict error for a request clashing with the
+// current state of a resource, e.g. a duplicate name.
+//
+// Parameters:
+//   - message: Describes the conflict.
+//
+// Returns:
+//   - *DefaultAPIError: A new DefaultAPIError.
+func Conflict(message string) *DefaultAPIError {
+	return NewAPIError(IDConflict).WithMessage(message).
+		WithStatus(http.StatusConflict)
+}
+
+// TooManyRequests returns a retryable 429 too_many_requests error. The
+// delay is sent as the Retry-After header.
+//
+// Parameters:
+//   - retryAfter: The delay before retrying, or 0 if unknown.
+//
+// Returns:
+//   - *DefaultAPIError: A new DefaultAPIError.
+func TooManyRequests(retryAfter time.Duration) *DefaultAPIError {
+	return NewAPIError(IDTooManyRequests).WithMessage("too many requests").
+		WithStatus(http.StatusTooManyRequests).WithRetryAfter(retryAfter)
+}
+
+// Internal returns a 500 internal_error error revealing nothing about
+// its cause, which is kept for errors.Is and errors.As.
+//
+// Parameters:
+//   - cause: The underlying error, or nil.
+//
+// Returns:
+//   - *DefaultAPIError: A new DefaultAPIError.
+func Internal(cause error) *DefaultAPIError {
+	return NewAPIError(IDInternal).WithMessage("Internal server error").
+		WithStatus(http.StatusInternalServerError).WithCause(cause)
+}
+
+// Unavailable returns a retryable 503 service_unavailable error, e.g. for
+// maintenance or an overloaded dependency. The delay is sent as the
+// Retry-After header.
+//
+// Parameters:
+//   - retryAfter: The delay before retrying, or 0 if unknown.
+//
+// Returns:
+//   - *DefaultAPIError: A new DefaultAPIError.
+func Unavailable(retryAfter time.Duration) *DefaultAPIError {
+	return NewAPIError(IDUnavailable).
+		WithMessage("service temporarily unavailable").
+		WithStatus(http.StatusServiceUnavailable).WithRetryAfter(retryAfter)
+}
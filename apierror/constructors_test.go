@@ -0,0 +1,53 @@
+package apierror
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstructors(t *testing.T) {
+	cause := errors.New("disk full")
+	testCases := []struct {
+		name       string
+		err        *DefaultAPIError
+		wantID     string
+		wantStatus int
+		wantMsg    string
+	}{
+		{"Bad request", BadRequest("bad cursor"), IDBadRequest,
+			http.StatusBadRequest, "bad cursor"},
+		{"Unauthorized", Unauthorized(""), IDUnauthorized,
+			http.StatusUnauthorized, "authentication required"},
+		{"Forbidden", Forbidden("admins only"), IDForbidden,
+			http.StatusForbidden, "admins only"},
+		{"Not found", NotFound("user", 42), IDNotFound,
+			http.StatusNotFound, "user 42 not found"},
+		{"Not found without ID", NotFound("config", nil), IDNotFound,
+			http.StatusNotFound, "config not found"},
+		{"Conflict", Conflict("name taken"), IDConflict,
+			http.StatusConflict, "name taken"},
+		{"Too many requests", TooManyRequests(time.Second), IDTooManyRequests,
+			http.StatusTooManyRequests, "too many requests"},
+		{"Internal", Internal(cause), IDInternal,
+			http.StatusInternalServerError, "Internal server error"},
+		{"Unavailable", Unavailable(0), IDUnavailable,
+			http.StatusServiceUnavailable, "service temporarily unavailable"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantID, tc.err.ID())
+			assert.Equal(t, tc.wantStatus, tc.err.Status())
+			assert.Equal(t, tc.wantMsg, tc.err.Message())
+		})
+	}
+
+	assert.Equal(t, map[string]any{"resource": "user", "id": 42},
+		NotFound("user", 42).Data())
+	assert.Equal(t, time.Second, TooManyRequests(time.Second).RetryAfter())
+	assert.True(t, Unavailable(0).Retryable())
+	assert.ErrorIs(t, Internal(cause), cause)
+}
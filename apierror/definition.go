@@ -0,0 +1,139 @@
+package apierror
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Definition documents an error ID for API documentation and client SDK
+// generation.
+type Definition struct {
+	ID          string `json:"id"`                // The error ID.
+	Status      int    `json:"status,omitempty"`  // The HTTP status code.
+	Description string `json:"description"`       // When the error occurs.
+	Example     any    `json:"example,omitempty"` // An example response body.
+}
+
+// definitions holds the definitions declared with Define.
+var definitions = struct {
+	sync.RWMutex
+	byID map[string]Definition
+}{byID: map[string]Definition{}}
+
+// Define declares an error ID in the program-wide error catalog. A
+// non-zero status is also registered with RegisterStatus. Defining an ID
+// again replaces its definition. Define IDs during initialization:
+//
+//	var ErrQuota = apierror.Define(apierror.Definition{
+//		ID:          "quota_exceeded",
+//		Status:      http.StatusTooManyRequests,
+//		Description: "The monthly request quota of the account is used up.",
+//	})
+//
+// Parameters:
+//   - def: The definition.
+//
+// Returns:
+//   - *DefaultAPIError: A new error with the ID and status, ready to return.
+func Define(def Definition) *DefaultAPIError {
+	definitions.Lock()
+	definitions.byID[def.ID] = def
+	definitions.Unlock()
+	if def.Status != 0 {
+		RegisterStatus(def.ID, def.Status)
+	}
+	return NewAPIError(def.ID).WithStatus(def.Status)
+}
+
+// Definitions returns the declared error definitions sorted by ID.
+//
+// Returns:
+//   - []Definition: The definitions.
+func Definitions() []Definition {
+	definitions.RLock()
+	defs := make([]Definition, 0, len(definitions.byID))
+	for _, def := range definitions.byID {
+		defs = append(defs, def)
+	}
+	definitions.RUnlock()
+	slices.SortFunc(defs, func(a, b Definition) int { return cmp.Compare(a.ID, b.ID) })
+	return defs
+}
+
+// ExportJSON writes the error catalog as a JSON array of definitions.
+// Definitions without an example get the minimal error body {"id": ID}.
+//
+// Parameters:
+//   - w: The destination writer.
+//
+// Returns:
+//   - error: An error if writing fails.
+func ExportJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(exportedDefinitions())
+}
+
+// ExportMarkdown writes the error catalog as a Markdown document with a
+// summary table and a section per error showing its example body.
+//
+// Parameters:
+//   - w: The destination writer.
+//
+// Returns:
+//   - error: An error if an example cannot be encoded or writing fails.
+func ExportMarkdown(w io.Writer) error {
+	defs := exportedDefinitions()
+	var b strings.Builder
+	b.WriteString("# Errors\n\n| ID | Status | Description |\n| --- | --- | --- |\n")
+	for _, def := range defs {
+		fmt.Fprintf(&b, "| `%s` | %s | %s |\n",
+			def.ID, statusText(def.Status), markdownCell(def.Description))
+	}
+	for _, def := range defs {
+		example, err := json.MarshalIndent(def.Example, "", "  ")
+		if err != nil {
+			return fmt.Errorf("apierror: example of %q: %w", def.ID, err)
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", def.ID)
+		if def.Status != 0 {
+			fmt.Fprintf(&b, "Status: %s\n\n", statusText(def.Status))
+		}
+		if def.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", def.Description)
+		}
+		fmt.Fprintf(&b, "```json\n%s\n```\n", example)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// exportedDefinitions returns the definitions with default examples.
+func exportedDefinitions() []Definition {
+	defs := Definitions()
+	for i := range defs {
+		if defs[i].Example == nil {
+			defs[i].Example = NewAPIError(defs[i].ID)
+		}
+	}
+	return defs
+}
+
+// statusText formats a status code with its reason phrase.
+func statusText(status int) string {
+	if status == 0 {
+		return "-"
+	}
+	return strings.TrimSpace(fmt.Sprintf("%d %s", status, http.StatusText(status)))
+}
+
+// markdownCell escapes text for a Markdown table cell.
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,53 @@
+package apierror
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefine(t *testing.T) {
+	errQuota := Define(Definition{
+		ID:          "def_test_quota",
+		Status:      http.StatusTooManyRequests,
+		Description: "Quota | limit used up.",
+		Example:     NewAPIError("def_test_quota").WithData(map[string]int{"limit": 100}),
+	})
+	Define(Definition{ID: "def_test_busy", Description: "Try later."})
+
+	assert.Equal(t, "def_test_quota", errQuota.ID())
+	assert.Equal(t, http.StatusTooManyRequests, errQuota.Status())
+	status, ok := StatusFor("def_test_quota")
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusTooManyRequests, status)
+
+	var ids []string
+	for _, def := range Definitions() {
+		ids = append(ids, def.ID)
+	}
+	assert.Subset(t, ids, []string{"def_test_busy", "def_test_quota"})
+	assert.Less(t, slices.Index(ids, "def_test_busy"), slices.Index(ids, "def_test_quota"))
+
+	var buf bytes.Buffer
+	require.NoError(t, ExportJSON(&buf))
+	var exported []map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &exported))
+	assert.Contains(t, exported, map[string]any{
+		"id": "def_test_busy", "description": "Try later.",
+		"example": map[string]any{"id": "def_test_busy"},
+	})
+
+	buf.Reset()
+	require.NoError(t, ExportMarkdown(&buf))
+	md := buf.String()
+	assert.Contains(t, md, "| `def_test_quota` | 429 Too Many Requests | Quota \\| limit used up. |\n")
+	assert.Contains(t, md, "| `def_test_busy` | - | Try later. |\n")
+	assert.Contains(t, md, "## def_test_quota\n\nStatus: 429 Too Many Requests\n\n"+
+		"Quota | limit used up.\n\n```json\n{\n  \"id\": \"def_test_quota\",\n"+
+		"  \"data\": {\n    \"limit\": 100\n  }\n}\n```\n")
+}
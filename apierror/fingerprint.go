@@ -0,0 +1,59 @@
+package apierror
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// Patterns of message parts that vary between occurrences of one failure.
+var (
+	uuidPattern   = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+	quotedPattern = regexp.MustCompile(`"[^"]*"|'[^']*'`)
+	hexPattern    = regexp.MustCompile(`\b0x[0-9a-f]+\b|` +
+		`\b(?:[0-9a-f]*[0-9][0-9a-f]*[a-f]|[0-9a-f]*[a-f][0-9a-f]*[0-9])[0-9a-f]*\b`)
+	numberPattern = regexp.MustCompile(`\d+(\.\d+)?`)
+)
+
+// Fingerprint returns a stable hash identifying a kind of failure, so
+// monitoring systems can group identical failures across instances. It
+// covers the ID, the origin and the message normalized to lower case with
+// UUIDs, quoted strings, hex values and numbers replaced by placeholders
+// and white space collapsed: "user 42 not found" and "User 7 not found"
+// share a fingerprint. Data is ignored.
+//
+// Parameters:
+//   - err: The API error.
+//
+// Returns:
+//   - string: 16 hex digits.
+func Fingerprint(err APIError) string {
+	h := sha256.New()
+	for _, part := range []string{
+		err.ID(), err.Origin(), normalizeMessage(err.Message()),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// Fingerprint returns the fingerprint of the error, see the package-level
+// Fingerprint function.
+//
+// Returns:
+//   - string: 16 hex digits.
+func (e *DefaultAPIError) Fingerprint() string {
+	return Fingerprint(e)
+}
+
+// normalizeMessage removes the variable parts of a message.
+func normalizeMessage(msg string) string {
+	msg = strings.ToLower(msg)
+	msg = uuidPattern.ReplaceAllString(msg, "<uuid>")
+	msg = quotedPattern.ReplaceAllString(msg, "<str>")
+	msg = hexPattern.ReplaceAllString(msg, "<hex>")
+	msg = numberPattern.ReplaceAllString(msg, "<n>")
+	return strings.Join(strings.Fields(msg), " ")
+}
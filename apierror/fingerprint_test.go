@@ -0,0 +1,43 @@
+package apierror
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	base := NewAPIError("not_found").WithOrigin("users")
+	fp := base.WithMessage("user 42 not found").Fingerprint()
+	assert.Len(t, fp, 16)
+
+	same := []*DefaultAPIError{
+		base.WithMessage("User 7  not found"),
+		base.WithMessage("user 42 not found").WithData("ignored"),
+	}
+	for _, e := range same {
+		assert.Equal(t, fp, e.Fingerprint(), e.Message())
+	}
+	different := []*DefaultAPIError{
+		base.WithMessage("user 42 is disabled"),
+		base.WithOrigin("orders").WithMessage("user 42 not found"),
+		base.WithID("gone").WithMessage("user 42 not found"),
+	}
+	for _, e := range different {
+		assert.NotEqual(t, fp, e.Fingerprint(), e.Error())
+	}
+}
+
+func TestNormalizeMessage(t *testing.T) {
+	testCases := []struct{ in, want string }{
+		{"Order 123 failed", "order <n> failed"},
+		{"id 6f1c2e0a-9b7d-4c3e-8a2f-1d2e3f4a5b6c missing", "id <uuid> missing"},
+		{`name "bob" taken`, "name <str> taken"},
+		{"at 0x1f and deadbeef1", "at <hex> and <hex>"},
+		{"took 1.5s", "took <n>s"},
+		{"  spaced \t out ", "spaced out"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, normalizeMessage(tc.in), tc.in)
+	}
+}
@@ -0,0 +1,78 @@
+package apierror
+
+import (
+	"strings"
+	"sync"
+)
+
+// Catalog holds translated error messages by locale and error ID.
+type Catalog map[string]map[string]string
+
+// Message returns the message for an error ID in a locale. Without one,
+// the locale is retried with subtags removed, so "fi" serves "fi-FI".
+//
+// Parameters:
+//   - id: The error ID.
+//   - locale: The locale, a BCP 47 language tag.
+//
+// Returns:
+//   - string: The translated message.
+//   - bool: Whether a message was found.
+func (c Catalog) Message(id string, locale string) (string, bool) {
+	for {
+		if msg, ok := c[locale][id]; ok {
+			return msg, true
+		}
+		i := strings.LastIndexByte(locale, '-')
+		if i < 0 {
+			return "", false
+		}
+		locale = locale[:i]
+	}
+}
+
+// messages holds the messages registered with RegisterMessages.
+var messages = struct {
+	sync.RWMutex
+	catalog Catalog
+}{catalog: Catalog{}}
+
+// RegisterMessages adds translated messages of a locale, keyed by error
+// ID, to the program-wide catalog used by Localize. Messages registered
+// again replace earlier ones. Register messages during initialization.
+//
+// Parameters:
+//   - locale: The locale, a BCP 47 language tag.
+//   - msgs: The messages by error ID.
+func RegisterMessages(locale string, msgs map[string]string) {
+	messages.Lock()
+	defer messages.Unlock()
+	m := messages.catalog[locale]
+	if m == nil {
+		m = make(map[string]string, len(msgs))
+		messages.catalog[locale] = m
+	}
+	for id, msg := range msgs {
+		m[id] = msg
+	}
+}
+
+// Localize returns a new error whose message is the translation of its ID
+// in the given locale from the messages registered with
+// RegisterMessages. Without a translation, the message is kept.
+//
+// Parameters:
+//   - locale: The locale, a BCP 47 language tag.
+//
+// Returns:
+//   - *DefaultAPIError: A new DefaultAPIError.
+func (e *DefaultAPIError) Localize(locale string) *DefaultAPIError {
+	messages.RLock()
+	msg, ok := messages.catalog.Message(e.ErrID, locale)
+	messages.RUnlock()
+	if !ok {
+		new := *e
+		return &new
+	}
+	return e.WithMessage(msg)
+}
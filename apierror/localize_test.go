@@ -0,0 +1,45 @@
+package apierror
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog_Message(t *testing.T) {
+	c := Catalog{
+		"fi":    {"not_found": "Ei löytynyt"},
+		"pt-BR": {"not_found": "Não encontrado"},
+	}
+	testCases := []struct {
+		locale string
+		want   string
+		found  bool
+	}{
+		{"fi", "Ei löytynyt", true},
+		{"fi-FI", "Ei löytynyt", true},
+		{"pt-BR", "Não encontrado", true},
+		{"pt", "", false},
+		{"sv", "", false},
+	}
+	for _, tc := range testCases {
+		msg, ok := c.Message("not_found", tc.locale)
+		assert.Equal(t, tc.found, ok, tc.locale)
+		assert.Equal(t, tc.want, msg, tc.locale)
+	}
+	_, ok := Catalog(nil).Message("not_found", "fi")
+	assert.False(t, ok)
+}
+
+func TestDefaultAPIError_Localize(t *testing.T) {
+	RegisterMessages("localize-test", map[string]string{"gone": "Poissa"})
+	err := NewAPIError("gone").WithMessage("Gone")
+
+	localized := err.Localize("localize-test-FI")
+	assert.Equal(t, "Poissa", localized.Message())
+	assert.Equal(t, "Gone", err.Message())
+
+	kept := err.Localize("other")
+	assert.NotSame(t, err, kept)
+	assert.Equal(t, "Gone", kept.Message())
+}
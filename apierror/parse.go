@@ -0,0 +1,85 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// dataTypes holds the data types registered with RegisterDataType.
+var dataTypes sync.Map // error ID -> reflect.Type
+
+// RegisterDataType declares the type of the data of errors with the given
+// ID, so parsed errors carry a T instead of generic JSON values:
+//
+//	apierror.RegisterDataType[QuotaInfo]("quota_exceeded")
+//
+// Parameters:
+//   - id: The error ID.
+func RegisterDataType[T any](id string) {
+	dataTypes.Store(id, reflect.TypeFor[T]())
+}
+
+// UnmarshalJSON decodes an error in its JSON format. The data is decoded
+// into the type registered for the ID with RegisterDataType. Without one,
+// or when the data does not fit it, the data is decoded into generic JSON
+// values: maps, slices, strings, float64 numbers and bools.
+//
+// Parameters:
+//   - data: The JSON data.
+//
+// Returns:
+//   - error: An error if the data is not a valid JSON object.
+func (e *DefaultAPIError) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID        string          `json:"id"`
+		Data      json.RawMessage `json:"data"`
+		Message   string          `json:"message"`
+		Origin    string          `json:"origin"`
+		Retryable bool            `json:"retryable"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*e = DefaultAPIError{
+		ErrID:        raw.ID,
+		ErrMessage:   raw.Message,
+		ErrOrigin:    raw.Origin,
+		ErrRetryable: raw.Retryable,
+	}
+	if len(raw.Data) == 0 || string(raw.Data) == "null" {
+		return nil
+	}
+	if t, ok := dataTypes.Load(raw.ID); ok {
+		v := reflect.New(t.(reflect.Type))
+		if err := json.Unmarshal(raw.Data, v.Interface()); err == nil {
+			e.ErrData = v.Elem().Interface()
+			return nil
+		}
+	}
+	return json.Unmarshal(raw.Data, &e.ErrData)
+}
+
+// ErrNotAPIError is returned by ParseAPIError for JSON without an error ID.
+var ErrNotAPIError = errors.New("apierror: not an API error")
+
+// ParseAPIError parses an error response body, e.g. in a client of a
+// pureapi service. The data is decoded as by UnmarshalJSON.
+//
+// Parameters:
+//   - data: The response body.
+//
+// Returns:
+//   - *DefaultAPIError: The parsed error.
+//   - error: An error if data is not JSON or has no error ID.
+func ParseAPIError(data []byte) (*DefaultAPIError, error) {
+	var e DefaultAPIError
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	if e.ErrID == "" {
+		return nil, ErrNotAPIError
+	}
+	return &e, nil
+}
@@ -0,0 +1,53 @@
+package apierror
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type parseTestQuota struct {
+	Limit int `json:"limit"`
+}
+
+func TestParseAPIError(t *testing.T) {
+	RegisterDataType[parseTestQuota]("parse_test_quota")
+
+	testCases := []struct {
+		name string
+		body string
+		want *DefaultAPIError
+	}{
+		{"Plain", `{"id":"not_found","message":"missing","origin":"users"}`,
+			NewAPIError("not_found").WithMessage("missing").WithOrigin("users")},
+		{"Typed data", `{"id":"parse_test_quota","data":{"limit":5}}`,
+			NewAPIError("parse_test_quota").WithData(parseTestQuota{Limit: 5})},
+		{"Mismatched data", `{"id":"parse_test_quota","data":"x"}`,
+			NewAPIError("parse_test_quota").WithData("x")},
+		{"Generic data", `{"id":"conflict","data":{"n":1}}`,
+			NewAPIError("conflict").WithData(map[string]any{"n": 1.0})},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseAPIError([]byte(tc.body))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	_, err := ParseAPIError([]byte(`{"message":"x"}`))
+	assert.ErrorIs(t, err, ErrNotAPIError)
+	_, err = ParseAPIError([]byte(`<html>`))
+	assert.Error(t, err)
+}
+
+func TestDefaultAPIError_RoundTrip(t *testing.T) {
+	want := NewAPIError("conflict").WithMessage("taken").WithData([]any{"a"})
+	data, err := json.Marshal(want)
+	require.NoError(t, err)
+	var got DefaultAPIError
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, want, &got)
+}
@@ -0,0 +1,45 @@
+package apierror
+
+import "sync"
+
+// statuses holds the status codes registered with RegisterStatus.
+var statuses = struct {
+	sync.RWMutex
+	ids map[string]int
+}{ids: map[string]int{}}
+
+// RegisterStatus maps an error ID to an HTTP status code for the whole
+// program. Error handlers consult the mapping for IDs they do not map
+// themselves, so packages can declare the status of their own errors next
+// to their IDs:
+//
+//	func init() {
+//		apierror.RegisterStatus("quota_exceeded", http.StatusTooManyRequests)
+//	}
+//
+// Registering an ID again replaces its status. Register IDs during
+// initialization.
+//
+// Parameters:
+//   - id: The error ID.
+//   - status: The HTTP status code.
+func RegisterStatus(id string, status int) {
+	statuses.Lock()
+	defer statuses.Unlock()
+	statuses.ids[id] = status
+}
+
+// StatusFor returns the status code registered for an error ID.
+//
+// Parameters:
+//   - id: The error ID.
+//
+// Returns:
+//   - int: The registered status code.
+//   - bool: Whether the ID is registered.
+func StatusFor(id string) (int, bool) {
+	statuses.RLock()
+	defer statuses.RUnlock()
+	status, ok := statuses.ids[id]
+	return status, ok
+}
@@ -0,0 +1,22 @@
+package apierror
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterStatus(t *testing.T) {
+	_, ok := StatusFor("test_status_unregistered")
+	assert.False(t, ok)
+
+	RegisterStatus("test_status_quota", http.StatusTooManyRequests)
+	status, ok := StatusFor("test_status_quota")
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusTooManyRequests, status)
+
+	RegisterStatus("test_status_quota", http.StatusPaymentRequired)
+	status, _ = StatusFor("test_status_quota")
+	assert.Equal(t, http.StatusPaymentRequired, status)
+}
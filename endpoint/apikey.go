@@ -0,0 +1,227 @@
+package endpoint
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/aatuh/pureapi-core/event"
+)
+
+// EventAuthFailed is emitted when a request fails authentication. Its data
+// is an *AuthFailure.
+const EventAuthFailed event.EventType = "event_auth_failed"
+
+// AuthFailure describes a rejected authentication attempt. It never
+// contains the submitted credentials.
+type AuthFailure struct {
+	Scheme     string // Authentication scheme, e.g. "api_key".
+	Reason     string // "missing" or "invalid".
+	Method     string
+	Path       string
+	RemoteAddr string
+	RequestID  string
+}
+
+// maxAPIKeyCacheEntries bounds the API key cache.
+const maxAPIKeyCacheEntries = 10000
+
+// APIKeyLookup resolves an API key to its caller. It returns nil for
+// unknown keys, or an error if the key cannot be checked.
+type APIKeyLookup func(ctx context.Context, key string) (*Principal, error)
+
+// APIKeyOption configures APIKeyAuth.
+type APIKeyOption func(*apiKeyConfig)
+
+// apiKeyConfig holds the API key auth settings.
+type apiKeyConfig struct {
+	header  string
+	query   string
+	ttl     time.Duration
+	emitter event.EventEmitter
+}
+
+// WithAPIKeyHeader sets the header carrying the key. The default is
+// "X-API-Key"; an empty name disables header lookup.
+//
+// Parameters:
+//   - header: The header name.
+//
+// Returns:
+//   - APIKeyOption: The option.
+func WithAPIKeyHeader(header string) APIKeyOption {
+	return func(c *apiKeyConfig) { c.header = header }
+}
+
+// WithAPIKeyQuery also accepts the key in a query parameter, checked after
+// the header. Keys in URLs end up in access logs, so prefer headers.
+//
+// Parameters:
+//   - param: The query parameter name.
+//
+// Returns:
+//   - APIKeyOption: The option.
+func WithAPIKeyQuery(param string) APIKeyOption {
+	return func(c *apiKeyConfig) { c.query = param }
+}
+
+// WithAPIKeyCache caches lookup results, for known and unknown keys, for
+// ttl. Lookup errors are not cached. Keys are cached by their SHA-256
+// hash.
+//
+// Parameters:
+//   - ttl: How long results are cached.
+//
+// Returns:
+//   - APIKeyOption: The option.
+func WithAPIKeyCache(ttl time.Duration) APIKeyOption {
+	return func(c *apiKeyConfig) { c.ttl = ttl }
+}
+
+// WithAPIKeyEvents emits EventAuthFailed to emitter for rejected requests.
+//
+// Parameters:
+//   - emitter: The event emitter.
+//
+// Returns:
+//   - APIKeyOption: The option.
+func WithAPIKeyEvents(emitter event.EventEmitter) APIKeyOption {
+	return func(c *apiKeyConfig) { c.emitter = emitter }
+}
+
+// APIKeyAuth returns a middleware authenticating requests by API key. The
+// principal returned by lookup is stored in the request context. Missing
+// and unknown keys are rejected with a 401 unauthorized API error; lookup
+// errors become a 500 internal_error.
+//
+// Parameters:
+//   - lookup: Resolves keys to principals.
+//   - opts: Optional API key options.
+//
+// Returns:
+//   - Middleware: The API key middleware.
+func APIKeyAuth(lookup APIKeyLookup, opts ...APIKeyOption) Middleware {
+	cfg := apiKeyConfig{header: "X-API-Key"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.emitter == nil {
+		cfg.emitter = event.NewNoopEventEmitter()
+	}
+	if cfg.ttl > 0 {
+		lookup = newAPIKeyCache(lookup, cfg.ttl).lookup
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := cfg.key(r)
+			if key == "" {
+				cfg.fail(w, r, "missing", "missing API key")
+				return
+			}
+			p, err := lookup(r.Context(), key)
+			if err != nil {
+				writeAPIError(w, r, http.StatusInternalServerError,
+					apierror.NewAPIError("internal_error").
+						WithMessage("Internal server error"))
+				return
+			}
+			if p == nil {
+				cfg.fail(w, r, "invalid", "invalid API key")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+		})
+	}
+}
+
+// key returns the API key of the request, or "".
+func (c *apiKeyConfig) key(r *http.Request) string {
+	if c.header != "" {
+		if key := r.Header.Get(c.header); key != "" {
+			return key
+		}
+	}
+	if c.query != "" {
+		return r.URL.Query().Get(c.query)
+	}
+	return ""
+}
+
+// fail emits EventAuthFailed and writes a 401 response.
+func (c *apiKeyConfig) fail(
+	w http.ResponseWriter, r *http.Request, reason, message string,
+) {
+	event.EmitCtx(c.emitter, r.Context(), event.NewEvent(
+		EventAuthFailed,
+		fmt.Sprintf("API key authentication failed: %s %s: %s",
+			r.Method, r.URL.Path, reason),
+	).WithData(&AuthFailure{
+		Scheme:     "api_key",
+		Reason:     reason,
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		RemoteAddr: r.RemoteAddr,
+		RequestID:  RequestIDFromRequest(r),
+	}))
+	writeAPIError(w, r, http.StatusUnauthorized,
+		apierror.NewAPIError(ErrIDUnauthorized).WithMessage(message))
+}
+
+// apiKeyCache caches API key lookups.
+type apiKeyCache struct {
+	next    APIKeyLookup
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]apiKeyEntry
+}
+
+// apiKeyEntry is a cached lookup result.
+type apiKeyEntry struct {
+	principal *Principal
+	expires   time.Time
+}
+
+// newAPIKeyCache creates a cache in front of next.
+func newAPIKeyCache(next APIKeyLookup, ttl time.Duration) *apiKeyCache {
+	return &apiKeyCache{
+		next:    next,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[[sha256.Size]byte]apiKeyEntry{},
+	}
+}
+
+// lookup returns the cached result or calls the wrapped lookup.
+func (c *apiKeyCache) lookup(ctx context.Context, key string) (*Principal, error) {
+	hash := sha256.Sum256([]byte(key))
+	c.mu.Lock()
+	entry, ok := c.entries[hash]
+	c.mu.Unlock()
+	now := c.now()
+	if ok && now.Before(entry.expires) {
+		return entry.principal, nil
+	}
+	p, err := c.next(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxAPIKeyCacheEntries {
+		for h, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, h)
+			}
+		}
+		if len(c.entries) >= maxAPIKeyCacheEntries {
+			clear(c.entries)
+		}
+	}
+	c.entries[hash] = apiKeyEntry{principal: p, expires: now.Add(c.ttl)}
+	return p, nil
+}
@@ -0,0 +1,113 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyAuth(t *testing.T) {
+	lookup := func(_ context.Context, key string) (*Principal, error) {
+		switch key {
+		case "good":
+			return &Principal{ID: "client-1"}, nil
+		case "broken":
+			return nil, errors.New("store down")
+		}
+		return nil, nil
+	}
+
+	testCases := []struct {
+		name       string
+		header     string
+		query      string
+		wantStatus int
+		wantBody   string
+		wantReason string
+	}{
+		{name: "Header", header: "good", wantStatus: http.StatusOK, wantBody: "client-1"},
+		{name: "Query", query: "good", wantStatus: http.StatusOK, wantBody: "client-1"},
+		{name: "Missing", wantStatus: http.StatusUnauthorized, wantReason: "missing",
+			wantBody: `{"id":"unauthorized","message":"missing API key"}`},
+		{name: "Invalid", header: "bad", wantStatus: http.StatusUnauthorized, wantReason: "invalid",
+			wantBody: `{"id":"unauthorized","message":"invalid API key"}`},
+		{name: "Lookup error", header: "broken", wantStatus: http.StatusInternalServerError,
+			wantBody: `{"id":"internal_error","message":"Internal server error"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			emitter := &dummyEventEmitter{}
+			h := APIKeyAuth(lookup, WithAPIKeyQuery("api_key"), WithAPIKeyEvents(emitter))(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					p, _ := PrincipalFromRequest(r)
+					_, _ = w.Write([]byte(p.ID))
+				}),
+			)
+			r := httptest.NewRequest(http.MethodGet, "/items?api_key="+tc.query, nil)
+			if tc.header != "" {
+				r.Header.Set("X-API-Key", tc.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			if tc.wantStatus == http.StatusOK {
+				assert.Equal(t, tc.wantBody, rec.Body.String())
+			} else {
+				assert.JSONEq(t, tc.wantBody, rec.Body.String())
+			}
+			if tc.wantReason == "" {
+				assert.Empty(t, emitter.events)
+				return
+			}
+			require.Len(t, emitter.events, 1)
+			assert.Equal(t, EventAuthFailed, emitter.events[0].Type)
+			failure := emitter.events[0].Data.(*AuthFailure)
+			assert.Equal(t, "api_key", failure.Scheme)
+			assert.Equal(t, tc.wantReason, failure.Reason)
+			assert.Equal(t, "/items", failure.Path)
+		})
+	}
+}
+
+func TestAPIKeyCache(t *testing.T) {
+	calls := 0
+	fail := false
+	cache := newAPIKeyCache(func(_ context.Context, key string) (*Principal, error) {
+		calls++
+		if fail {
+			return nil, errors.New("store down")
+		}
+		if key == "good" {
+			return &Principal{ID: "client-1"}, nil
+		}
+		return nil, nil
+	}, time.Minute)
+	now := time.Unix(1_700_000_000, 0)
+	cache.now = func() time.Time { return now }
+
+	for range 2 {
+		p, err := cache.lookup(context.Background(), "good")
+		require.NoError(t, err)
+		assert.Equal(t, "client-1", p.ID)
+		p, err = cache.lookup(context.Background(), "bad")
+		require.NoError(t, err)
+		assert.Nil(t, p)
+	}
+	assert.Equal(t, 2, calls)
+
+	now = now.Add(2 * time.Minute)
+	fail = true
+	_, err := cache.lookup(context.Background(), "good")
+	assert.Error(t, err)
+	fail = false
+	_, err = cache.lookup(context.Background(), "good")
+	require.NoError(t, err)
+	assert.Equal(t, 4, calls)
+}
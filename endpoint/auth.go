@@ -0,0 +1,176 @@
+package endpoint
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// API error IDs of authentication and authorization failures.
+const (
+	ErrIDUnauthorized = apierror.IDUnauthorized
+	ErrIDForbidden    = apierror.IDForbidden
+)
+
+// Principal is an authenticated caller. Authentication middlewares store it
+// in the request context, where handlers and authorization middlewares read
+// it with PrincipalFromRequest.
+type Principal struct {
+	ID         string         // Caller identifier, e.g. a user or client ID.
+	Roles      []string       // Roles granted to the caller.
+	Scopes     []string       // Scopes granted to the caller.
+	Attributes map[string]any // Additional authenticator specific data.
+}
+
+// principalKey is the context key of the authenticated principal.
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal.
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - p: The authenticated principal.
+//
+// Returns:
+//   - context.Context: The derived context.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored in ctx.
+//
+// Parameters:
+//   - ctx: The context.
+//
+// Returns:
+//   - *Principal: The principal.
+//   - bool: Whether a principal was found.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// PrincipalFromRequest returns the principal of an authenticated request.
+//
+// Parameters:
+//   - r: The HTTP request.
+//
+// Returns:
+//   - *Principal: The principal.
+//   - bool: Whether the request is authenticated.
+func PrincipalFromRequest(r *http.Request) (*Principal, bool) {
+	return PrincipalFromContext(r.Context())
+}
+
+// BasicAuthValidator checks basic auth credentials. It returns the
+// authenticated principal, nil if the credentials are invalid, or an error
+// if they cannot be checked.
+type BasicAuthValidator func(
+	ctx context.Context, username, password string,
+) (*Principal, error)
+
+// BasicAuthUsers returns a validator accepting a fixed set of username and
+// password pairs. Credentials are compared in constant time, including for
+// unknown usernames. The principal ID is the username.
+//
+// Parameters:
+//   - users: The passwords by username.
+//
+// Returns:
+//   - BasicAuthValidator: The validator.
+func BasicAuthUsers(users map[string]string) BasicAuthValidator {
+	hashes := make(map[string][sha256.Size]byte, len(users))
+	for user, password := range users {
+		hashes[user] = sha256.Sum256([]byte(password))
+	}
+	var unknown [sha256.Size]byte
+	return func(
+		_ context.Context, username, password string,
+	) (*Principal, error) {
+		want, found := hashes[username]
+		if !found {
+			want = unknown
+		}
+		got := sha256.Sum256([]byte(password))
+		if subtle.ConstantTimeCompare(got[:], want[:]) != 1 || !found {
+			return nil, nil
+		}
+		return &Principal{ID: username}, nil
+	}
+}
+
+// BasicAuthOption configures BasicAuth.
+type BasicAuthOption func(*basicAuthConfig)
+
+// basicAuthConfig holds the basic auth settings.
+type basicAuthConfig struct {
+	realm string
+}
+
+// WithBasicAuthRealm sets the realm announced in the WWW-Authenticate
+// header. The default is "Restricted".
+//
+// Parameters:
+//   - realm: The realm.
+//
+// Returns:
+//   - BasicAuthOption: The option.
+func WithBasicAuthRealm(realm string) BasicAuthOption {
+	return func(c *basicAuthConfig) { c.realm = realm }
+}
+
+// BasicAuth returns a middleware authenticating requests with HTTP basic
+// auth. The principal returned by the validator is stored in the request
+// context. Missing or invalid credentials are rejected with a 401
+// unauthorized API error and a WWW-Authenticate challenge; validator errors
+// become a 500 internal_error.
+//
+// Parameters:
+//   - validator: The credential validator.
+//   - opts: Optional basic auth options.
+//
+// Returns:
+//   - Middleware: The basic auth middleware.
+func BasicAuth(
+	validator BasicAuthValidator, opts ...BasicAuthOption,
+) Middleware {
+	cfg := basicAuthConfig{realm: "Restricted"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	challenge := fmt.Sprintf(`Basic realm=%q, charset="UTF-8"`, cfg.realm)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			if !ok {
+				unauthorized(w, r, challenge, "missing credentials")
+				return
+			}
+			p, err := validator(r.Context(), username, password)
+			if err != nil {
+				writeAPIError(w, r, http.StatusInternalServerError,
+					apierror.NewAPIError("internal_error").
+						WithMessage("Internal server error"))
+				return
+			}
+			if p == nil {
+				unauthorized(w, r, challenge, "invalid credentials")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+		})
+	}
+}
+
+// unauthorized writes a 401 unauthorized API error with a challenge.
+func unauthorized(
+	w http.ResponseWriter, r *http.Request, challenge, message string,
+) {
+	w.Header().Set("WWW-Authenticate", challenge)
+	writeAPIError(w, r, http.StatusUnauthorized,
+		apierror.NewAPIError(ErrIDUnauthorized).WithMessage(message))
+}
@@ -0,0 +1,80 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicAuth(t *testing.T) {
+	validator := BasicAuthUsers(map[string]string{"ada": "secret"})
+	failing := func(context.Context, string, string) (*Principal, error) {
+		return nil, errors.New("store down")
+	}
+
+	testCases := []struct {
+		name       string
+		validator  BasicAuthValidator
+		user, pass string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "Valid", validator: validator, user: "ada", pass: "secret",
+			wantStatus: http.StatusOK, wantBody: "ada"},
+		{name: "Wrong password", validator: validator, user: "ada", pass: "nope",
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   `{"id":"unauthorized","message":"invalid credentials"}`},
+		{name: "Unknown user", validator: validator, user: "bob", pass: "secret",
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   `{"id":"unauthorized","message":"invalid credentials"}`},
+		{name: "Missing", validator: validator,
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   `{"id":"unauthorized","message":"missing credentials"}`},
+		{name: "Validator error", validator: failing, user: "ada", pass: "secret",
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"id":"internal_error","message":"Internal server error"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := BasicAuth(tc.validator, WithBasicAuthRealm("admin"))(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					p, _ := PrincipalFromRequest(r)
+					_, _ = w.Write([]byte(p.ID))
+				}),
+			)
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.user != "" {
+				r.SetBasicAuth(tc.user, tc.pass)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			if tc.wantStatus == http.StatusOK {
+				assert.Equal(t, tc.wantBody, rec.Body.String())
+				return
+			}
+			assert.JSONEq(t, tc.wantBody, rec.Body.String())
+			if tc.wantStatus == http.StatusUnauthorized {
+				assert.Equal(t, `Basic realm="admin", charset="UTF-8"`,
+					rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestPrincipalFromContext(t *testing.T) {
+	_, ok := PrincipalFromContext(context.Background())
+	assert.False(t, ok)
+	_, ok = PrincipalFromContext(WithPrincipal(context.Background(), nil))
+	assert.False(t, ok)
+	p, ok := PrincipalFromContext(
+		WithPrincipal(context.Background(), &Principal{ID: "x"}),
+	)
+	assert.True(t, ok)
+	assert.Equal(t, "x", p.ID)
+}
@@ -0,0 +1,120 @@
+package endpoint
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// AuthorizeWrapperID is the ID of the wrappers created by RequireAccess.
+const AuthorizeWrapperID = "authorize"
+
+// AccessRule lists what a principal needs to call an endpoint.
+type AccessRule struct {
+	Scopes []string `json:"scopes,omitempty"` // All scopes are required.
+	Roles  []string `json:"roles,omitempty"`  // Any one role is sufficient.
+}
+
+// AccessDenied is the data of the forbidden API errors written by
+// Authorize.
+type AccessDenied struct {
+	MissingScopes []string `json:"missing_scopes,omitempty"`
+	RequiredRoles []string `json:"required_roles,omitempty"`
+}
+
+// Authorize returns a middleware checking the principal stored by an
+// authentication middleware against rule. Requests without a principal
+// are rejected with a 401 unauthorized API error, principals lacking a
+// scope or role with a 403 forbidden one whose data is an AccessDenied.
+//
+// Parameters:
+//   - rule: The access rule.
+//
+// Returns:
+//   - Middleware: The authorization middleware.
+func Authorize(rule AccessRule) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := PrincipalFromRequest(r)
+			if !ok {
+				writeAPIError(w, r, http.StatusUnauthorized,
+					apierror.NewAPIError(ErrIDUnauthorized).
+						WithMessage("authentication required"))
+				return
+			}
+			if denied := rule.check(p); denied != nil {
+				writeAPIError(w, r, http.StatusForbidden,
+					apierror.NewAPIError(ErrIDForbidden).
+						WithMessage("insufficient permissions").
+						WithData(denied))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireAccess returns a wrapper running Authorize with rule. The rule is
+// also the wrapper's data, so tools reading the endpoint's stack, such as
+// AccessRules, can discover it:
+//
+//	stack := endpoint.NewStack(
+//		endpoint.NewWrapper("auth", endpoint.BearerAuth(verifier)),
+//		endpoint.RequireAccess(endpoint.AccessRule{Scopes: []string{"orders:write"}}),
+//	)
+//
+// Parameters:
+//   - rule: The access rule.
+//
+// Returns:
+//   - *DefaultWrapper: The authorization wrapper.
+func RequireAccess(rule AccessRule) *DefaultWrapper {
+	return NewWrapper(AuthorizeWrapperID, Authorize(rule)).WithData(rule)
+}
+
+// AccessRules returns the access rules declared by the wrappers of a
+// middleware stack, in stack order.
+//
+// Parameters:
+//   - m: The middlewares, usually a Stack.
+//
+// Returns:
+//   - []AccessRule: The declared rules.
+func AccessRules(m Middlewares) []AccessRule {
+	s, ok := m.(interface{ Wrappers() []Wrapper })
+	if !ok {
+		return nil
+	}
+	var rules []AccessRule
+	for _, w := range s.Wrappers() {
+		switch rule := w.Data().(type) {
+		case AccessRule:
+			rules = append(rules, rule)
+		case *AccessRule:
+			if rule != nil {
+				rules = append(rules, *rule)
+			}
+		}
+	}
+	return rules
+}
+
+// check returns why the principal is denied, or nil.
+func (a AccessRule) check(p *Principal) *AccessDenied {
+	var denied AccessDenied
+	for _, scope := range a.Scopes {
+		if !slices.Contains(p.Scopes, scope) {
+			denied.MissingScopes = append(denied.MissingScopes, scope)
+		}
+	}
+	if len(a.Roles) > 0 && !slices.ContainsFunc(a.Roles, func(role string) bool {
+		return slices.Contains(p.Roles, role)
+	}) {
+		denied.RequiredRoles = a.Roles
+	}
+	if denied.MissingScopes == nil && denied.RequiredRoles == nil {
+		return nil
+	}
+	return &denied
+}
@@ -0,0 +1,65 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorize(t *testing.T) {
+	rule := AccessRule{Scopes: []string{"read", "write"}, Roles: []string{"admin", "editor"}}
+	testCases := []struct {
+		name       string
+		principal  *Principal
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "Allowed", wantStatus: http.StatusOK,
+			principal: &Principal{Scopes: []string{"write", "read"}, Roles: []string{"editor"}}},
+		{name: "Unauthenticated", wantStatus: http.StatusUnauthorized,
+			wantBody: `{"id":"unauthorized","message":"authentication required"}`},
+		{name: "Missing scope", wantStatus: http.StatusForbidden,
+			principal: &Principal{Scopes: []string{"read"}, Roles: []string{"admin"}},
+			wantBody: `{"id":"forbidden","message":"insufficient permissions",
+				"data":{"missing_scopes":["write"]}}`},
+		{name: "Missing role", wantStatus: http.StatusForbidden,
+			principal: &Principal{Scopes: []string{"read", "write"}, Roles: []string{"viewer"}},
+			wantBody: `{"id":"forbidden","message":"insufficient permissions",
+				"data":{"required_roles":["admin","editor"]}}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stack := NewStack(RequireAccess(rule))
+			h := stack.Middlewares().Chain(http.HandlerFunc(
+				func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				},
+			))
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.principal != nil {
+				r = r.WithContext(WithPrincipal(r.Context(), tc.principal))
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			if tc.wantBody != "" {
+				assert.JSONEq(t, tc.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAccessRules(t *testing.T) {
+	stack := NewStack(
+		NewWrapper("auth", func(next http.Handler) http.Handler { return next }),
+		RequireAccess(AccessRule{Scopes: []string{"a"}}),
+		NewWrapper("custom", nil).WithData(&AccessRule{Roles: []string{"b"}}),
+	)
+	assert.Equal(t, []AccessRule{
+		{Scopes: []string{"a"}}, {Roles: []string{"b"}},
+	}, AccessRules(stack))
+	assert.Nil(t, AccessRules(NewMiddlewares()))
+}
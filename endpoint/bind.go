@@ -0,0 +1,165 @@
+package endpoint
+
+import (
+	"mime"
+	"net/http"
+	"reflect"
+
+	"github.com/aatuh/pureapi-core/internal/bind"
+	"github.com/aatuh/pureapi-core/router"
+)
+
+// BindInputOption configures a binding input handler.
+type BindInputOption func(*bindInput)
+
+// WithBindJSON sets the options used to decode JSON bodies.
+//
+// Parameters:
+//   - opts: JSON input options.
+//
+// Returns:
+//   - BindInputOption: A bind input option function.
+func WithBindJSON(opts ...JSONInputOption) BindInputOption {
+	return func(b *bindInput) {
+		for _, opt := range opts {
+			opt(&b.json)
+		}
+	}
+}
+
+// BindInput returns an input handler populating Input from several parts
+// of the request. The body is decoded first when the request has a
+// Content-Type: JSON bodies as by JSONInput and form-urlencoded bodies by
+// the "form" tag. Route params, query parameters and headers are then bound
+// to fields tagged "path", "query" and "header":
+//
+//	type GetUser struct {
+//		ID     int64  `path:"id"`
+//		Fields string `query:"fields"`
+//		APIKey string `header:"X-Api-Key"`
+//	}
+//
+// Values are converted like FormInput fields. Conversion failures of all
+// sources are reported together as one invalid_input API error with a list
+// of FieldError values as data.
+//
+// Parameters:
+//   - opts: Optional bind input options.
+//
+// Returns:
+//   - InputHandler[Input]: The binding input handler.
+func BindInput[Input any](opts ...BindInputOption) InputHandler[Input] {
+	cfg := bindInput{json: jsonInput{maxDepth: 32}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.json.resolveSchema(reflect.TypeFor[Input]())
+	return &bindInputHandler[Input]{cfg: cfg}
+}
+
+// bindInput holds the binding input settings.
+type bindInput struct {
+	json jsonInput
+}
+
+// bindInputHandler binds request data to inputs.
+type bindInputHandler[Input any] struct {
+	cfg bindInput
+}
+
+// Handle decodes the body and binds params, query and headers.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//
+// Returns:
+//   - *Input: The bound input.
+//   - error: An API error if the request cannot be bound.
+func (h *bindInputHandler[Input]) Handle(
+	_ http.ResponseWriter, r *http.Request,
+) (*Input, error) {
+	var in Input
+	fields, err := h.bindBody(r, &in)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range []string{"path", "query", "header"} {
+		fe, err := bindSource(r, &in, tag)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, fe...)
+	}
+	if len(fields) > 0 {
+		return nil, invalidFields(fields)
+	}
+	return &in, nil
+}
+
+// bindBody decodes the request body according to its content type. Field
+// errors of form bodies are returned for aggregation.
+func (h *bindInputHandler[Input]) bindBody(
+	r *http.Request, in *Input,
+) ([]FieldError, error) {
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return nil, nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(ct)
+	switch {
+	case isJSONMediaType(mediaType):
+		return nil, h.cfg.json.decode(r, in)
+	case isFormMediaType(mediaType):
+		if err := parseForm(r); err != nil {
+			return nil, err
+		}
+		err := bind.Struct(in, "form", func(name string) ([]string, bool) {
+			v, ok := r.PostForm[name]
+			return v, ok
+		})
+		if err == nil {
+			return nil, nil
+		}
+		fe, ok := fieldErrors(err, "body")
+		if !ok {
+			return nil, invalidInput(err.Error())
+		}
+		return fe, nil
+	}
+	return nil, unsupportedMediaType(ct)
+}
+
+// bindSource binds the request values selected by tag, "path", "query" or
+// "header", to dst. Field errors are returned for aggregation.
+func bindSource(r *http.Request, dst any, tag string) ([]FieldError, error) {
+	var lookup bind.Lookup
+	switch tag {
+	case "path":
+		params := router.ParamsFromContext(r.Context())
+		lookup = func(name string) ([]string, bool) {
+			v, ok := params[name]
+			return []string{v}, ok
+		}
+	case "query":
+		query := r.URL.Query()
+		lookup = func(name string) ([]string, bool) {
+			v, ok := query[name]
+			return v, ok
+		}
+	default:
+		lookup = func(name string) ([]string, bool) {
+			v := r.Header.Values(name)
+			return v, len(v) > 0
+		}
+	}
+	err := bind.Struct(dst, tag, lookup)
+	if err == nil {
+		return nil, nil
+	}
+	fields, ok := fieldErrors(err, tag)
+	if !ok {
+		return nil, invalidInput(err.Error())
+	}
+	return fields, nil
+}
@@ -0,0 +1,107 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/aatuh/pureapi-core/router"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bindTestInput struct {
+	ID     int64    `path:"id"`
+	Page   int      `query:"page"`
+	Tags   []string `query:"tag"`
+	APIKey string   `header:"X-Api-Key"`
+	Name   string   `json:"name" form:"name"`
+}
+
+func bindRequest(method, target, contentType, body string, params router.Params) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	return r.WithContext(router.WithParams(r.Context(), params))
+}
+
+func TestBindInput(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{name: "JSON body", contentType: "application/json", body: `{"name":"Go"}`},
+		{name: "Form body", contentType: "application/x-www-form-urlencoded", body: "name=Go"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := bindRequest(http.MethodPost, "/users/7?page=2&tag=a&tag=b",
+				tc.contentType, tc.body, router.Params{"id": "7"})
+			r.Header.Set("x-api-key", "secret")
+
+			in, err := BindInput[bindTestInput]().Handle(httptest.NewRecorder(), r)
+			require.NoError(t, err)
+			assert.Equal(t, &bindTestInput{
+				ID: 7, Page: 2, Tags: []string{"a", "b"}, APIKey: "secret", Name: "Go",
+			}, in)
+		})
+	}
+}
+
+func TestBindInput_NoBody(t *testing.T) {
+	r := bindRequest(http.MethodGet, "/users/7", "", "", router.Params{"id": "7"})
+	in, err := BindInput[bindTestInput]().Handle(httptest.NewRecorder(), r)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), in.ID)
+	assert.Zero(t, in.Page)
+}
+
+func TestBindInput_AggregatedErrors(t *testing.T) {
+	r := bindRequest(http.MethodGet, "/users/x?page=last", "", "", router.Params{"id": "x"})
+	_, err := BindInput[bindTestInput]().Handle(httptest.NewRecorder(), r)
+
+	apiErr, ok := err.(apierror.APIError)
+	require.True(t, ok)
+	assert.Equal(t, ErrIDInvalidInput, apiErr.ID())
+	assert.Equal(t, []FieldError{
+		{Field: "id", In: "path", Message: `invalid integer "x"`},
+		{Field: "page", In: "query", Message: `invalid integer "last"`},
+	}, apiErr.Data())
+}
+
+func TestBindInput_BodyErrors(t *testing.T) {
+	r := bindRequest(http.MethodPost, "/", "text/plain", "x", nil)
+	_, err := BindInput[bindTestInput]().Handle(httptest.NewRecorder(), r)
+	apiErr, ok := err.(apierror.APIError)
+	require.True(t, ok)
+	assert.Equal(t, ErrIDUnsupportedMediaType, apiErr.ID())
+
+	r = bindRequest(http.MethodPost, "/", "application/json", `{"name":"Go","x":1}`, nil)
+	_, err = BindInput[bindTestInput](WithBindJSON(WithJSONStrict())).
+		Handle(httptest.NewRecorder(), r)
+	apiErr, ok = err.(apierror.APIError)
+	require.True(t, ok)
+	assert.Equal(t, ErrIDInvalidInput, apiErr.ID())
+}
+
+func TestBindInput_JSONSchema(t *testing.T) {
+	h := BindInput[bindTestInput](WithBindJSON(WithJSONSchemaFromType()))
+
+	r := bindRequest(http.MethodPost, "/users/7", "application/json", `{"name":"Go"}`, router.Params{"id": "7"})
+	in, err := h.Handle(httptest.NewRecorder(), r)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), in.ID)
+
+	r = bindRequest(http.MethodPost, "/users/7", "application/json", `{"name":1}`, router.Params{"id": "7"})
+	_, err = h.Handle(httptest.NewRecorder(), r)
+	apiErr, ok := err.(apierror.APIError)
+	require.True(t, ok)
+	assert.Equal(t, []FieldError{
+		{Field: "name", In: "body", Message: "must be of type string"},
+	}, apiErr.Data())
+}
@@ -0,0 +1,361 @@
+package endpoint
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/aatuh/pureapi-core/event"
+)
+
+// Circuit breaker events. Their data is the breaker name.
+const (
+	EventCircuitOpened   event.EventType = "event_circuit_opened"
+	EventCircuitHalfOpen event.EventType = "event_circuit_half_open"
+	EventCircuitClosed   event.EventType = "event_circuit_closed"
+)
+
+// circuitEvents are the events emitted when entering a state.
+var circuitEvents = map[CircuitState]event.EventType{
+	CircuitOpen:     EventCircuitOpened,
+	CircuitHalfOpen: EventCircuitHalfOpen,
+	CircuitClosed:   EventCircuitClosed,
+}
+
+// ErrIDCircuitOpen is the API error ID of requests rejected by an open
+// circuit breaker.
+const ErrIDCircuitOpen = "circuit_open"
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+// Circuit breaker states.
+const (
+	CircuitClosed   CircuitState = iota // Requests pass and are measured.
+	CircuitOpen                         // Requests are rejected.
+	CircuitHalfOpen                     // Probe requests test recovery.
+)
+
+// String returns the state name.
+//
+// Returns:
+//   - string: "closed", "open" or "half-open".
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "closed"
+}
+
+// BreakerOption configures a CircuitBreaker.
+type BreakerOption func(*CircuitBreaker)
+
+// WithBreakerName names the breaker in its events.
+//
+// Parameters:
+//   - name: The breaker name.
+//
+// Returns:
+//   - BreakerOption: The option.
+func WithBreakerName(name string) BreakerOption {
+	return func(b *CircuitBreaker) { b.name = name }
+}
+
+// WithBreakerWindow sets the number of recent calls the rates are computed
+// over, and the minimum number of calls before the breaker may open. The
+// defaults are 20 and 10.
+//
+// Parameters:
+//   - size: The window size.
+//   - minCalls: The minimum number of calls.
+//
+// Returns:
+//   - BreakerOption: The option.
+func WithBreakerWindow(size, minCalls int) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.window = make([]callOutcome, max(size, 1))
+		b.minCalls = max(minCalls, 1)
+	}
+}
+
+// WithBreakerFailureRate sets the failure rate, between 0 and 1, opening
+// the breaker. The default is 0.5.
+//
+// Parameters:
+//   - rate: The failure rate threshold.
+//
+// Returns:
+//   - BreakerOption: The option.
+func WithBreakerFailureRate(rate float64) BreakerOption {
+	return func(b *CircuitBreaker) { b.failureRate = rate }
+}
+
+// WithBreakerSlowCalls treats calls taking at least threshold as slow and
+// opens the breaker when the slow call rate reaches rate. Slow calls are
+// not counted by default.
+//
+// Parameters:
+//   - threshold: The duration making a call slow.
+//   - rate: The slow call rate threshold.
+//
+// Returns:
+//   - BreakerOption: The option.
+func WithBreakerSlowCalls(threshold time.Duration, rate float64) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.slowThreshold = threshold
+		b.slowRate = rate
+	}
+}
+
+// WithBreakerOpenTimeout sets how long the breaker stays open before
+// letting probes through. The default is 30 seconds.
+//
+// Parameters:
+//   - timeout: The open duration.
+//
+// Returns:
+//   - BreakerOption: The option.
+func WithBreakerOpenTimeout(timeout time.Duration) BreakerOption {
+	return func(b *CircuitBreaker) { b.openTimeout = timeout }
+}
+
+// WithBreakerProbes sets the number of successful probe calls closing a
+// half-open breaker. The default is 3.
+//
+// Parameters:
+//   - probes: The number of probes.
+//
+// Returns:
+//   - BreakerOption: The option.
+func WithBreakerProbes(probes int) BreakerOption {
+	return func(b *CircuitBreaker) { b.probes = max(probes, 1) }
+}
+
+// WithBreakerFailureStatus sets which response status codes count as
+// failures. By default 5xx responses and panics are failures.
+//
+// Parameters:
+//   - fn: Reports whether a status code is a failure.
+//
+// Returns:
+//   - BreakerOption: The option.
+func WithBreakerFailureStatus(fn func(status int) bool) BreakerOption {
+	return func(b *CircuitBreaker) { b.isFailure = fn }
+}
+
+// WithBreakerEvents emits state change events to emitter.
+//
+// Parameters:
+//   - emitter: The event emitter.
+//
+// Returns:
+//   - BreakerOption: The option.
+func WithBreakerEvents(emitter event.EventEmitter) BreakerOption {
+	return func(b *CircuitBreaker) { b.emitter = emitter }
+}
+
+// callOutcome is a measured call.
+type callOutcome struct {
+	failed bool
+	slow   bool
+}
+
+// CircuitBreaker stops calling an endpoint whose recent calls mostly fail
+// or are slow, giving its dependencies time to recover. Use one breaker
+// per endpoint.
+//
+// While closed, calls are measured over a sliding window. When the failure
+// or slow call rate reaches its threshold the breaker opens and rejects
+// calls with a 503 circuit_open API error and a Retry-After header. After
+// the open timeout it lets a few probe calls through: if they all succeed
+// it closes, otherwise it opens again.
+type CircuitBreaker struct {
+	name          string
+	minCalls      int
+	failureRate   float64
+	slowThreshold time.Duration
+	slowRate      float64
+	openTimeout   time.Duration
+	probes        int
+	isFailure     func(status int) bool
+	emitter       event.EventEmitter
+	now           func() time.Time
+
+	mu        sync.Mutex
+	state     CircuitState
+	window    []callOutcome
+	next      int
+	calls     int
+	openedAt  time.Time
+	inFlight  int
+	successes int
+}
+
+// NewCircuitBreaker creates a circuit breaker.
+//
+// Parameters:
+//   - opts: Optional breaker options.
+//
+// Returns:
+//   - *CircuitBreaker: A new CircuitBreaker instance.
+func NewCircuitBreaker(opts ...BreakerOption) *CircuitBreaker {
+	b := &CircuitBreaker{
+		window:      make([]callOutcome, 20),
+		minCalls:    10,
+		failureRate: 0.5,
+		openTimeout: 30 * time.Second,
+		probes:      3,
+		isFailure:   func(status int) bool { return status >= 500 },
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.emitter == nil {
+		b.emitter = event.NewNoopEventEmitter()
+	}
+	return b
+}
+
+// State returns the current state.
+//
+// Returns:
+//   - CircuitState: The breaker state.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Middleware returns a middleware guarding the next handler with the
+// breaker.
+//
+// Returns:
+//   - Middleware: The circuit breaker middleware.
+func (b *CircuitBreaker) Middleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			wait, ok := b.allow()
+			if !ok {
+				seconds := int(math.Ceil(wait.Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+				writeAPIError(w, r, http.StatusServiceUnavailable,
+					apierror.NewAPIError(ErrIDCircuitOpen).
+						WithMessage("service temporarily unavailable"))
+				return
+			}
+			start := b.now()
+			mw := &metricsWriter{ResponseWriter: w}
+			failed := true
+			defer func() {
+				b.record(callOutcome{
+					failed: failed,
+					slow: b.slowThreshold > 0 &&
+						b.now().Sub(start) >= b.slowThreshold,
+				})
+			}()
+			next.ServeHTTP(mw, r)
+			status := mw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			failed = b.isFailure(status)
+		})
+	}
+}
+
+// CircuitBreakerMiddleware returns a middleware with a new circuit breaker.
+//
+// Parameters:
+//   - opts: Optional breaker options.
+//
+// Returns:
+//   - Middleware: The circuit breaker middleware.
+func CircuitBreakerMiddleware(opts ...BreakerOption) Middleware {
+	return NewCircuitBreaker(opts...).Middleware()
+}
+
+// allow reports whether a call may proceed, or how long the breaker stays
+// open.
+func (b *CircuitBreaker) allow() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case CircuitOpen:
+		remaining := b.openTimeout - b.now().Sub(b.openedAt)
+		if remaining > 0 {
+			return remaining, false
+		}
+		b.transition(CircuitHalfOpen)
+		fallthrough
+	case CircuitHalfOpen:
+		if b.inFlight+b.successes >= b.probes {
+			return b.openTimeout, false
+		}
+		b.inFlight++
+	}
+	return 0, true
+}
+
+// record accounts a finished call.
+func (b *CircuitBreaker) record(o callOutcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case CircuitHalfOpen:
+		b.inFlight--
+		if o.failed || o.slow {
+			b.transition(CircuitOpen)
+			return
+		}
+		b.successes++
+		if b.successes >= b.probes {
+			b.transition(CircuitClosed)
+		}
+	case CircuitClosed:
+		b.window[b.next] = o
+		b.next = (b.next + 1) % len(b.window)
+		b.calls = min(b.calls+1, len(b.window))
+		if b.calls < b.minCalls {
+			return
+		}
+		var failed, slow int
+		for _, c := range b.window[:b.calls] {
+			if c.failed {
+				failed++
+			}
+			if c.slow {
+				slow++
+			}
+		}
+		total := float64(b.calls)
+		if float64(failed)/total >= b.failureRate ||
+			(b.slowThreshold > 0 && float64(slow)/total >= b.slowRate) {
+			b.transition(CircuitOpen)
+		}
+	}
+}
+
+// transition changes the state, resets its counters and emits an event.
+func (b *CircuitBreaker) transition(state CircuitState) {
+	b.state = state
+	b.inFlight, b.successes = 0, 0
+	switch state {
+	case CircuitOpen:
+		b.openedAt = b.now()
+	case CircuitClosed:
+		clear(b.window)
+		b.next, b.calls = 0, 0
+	}
+	b.emitter.Emit(event.NewEvent(
+		circuitEvents[state],
+		fmt.Sprintf("Circuit breaker %q is %s", b.name, state),
+	).WithData(b.name))
+}
@@ -0,0 +1,104 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	emitter := &dummyEventEmitter{}
+	b := NewCircuitBreaker(
+		WithBreakerName("orders"), WithBreakerWindow(4, 4),
+		WithBreakerOpenTimeout(time.Minute), WithBreakerProbes(2),
+		WithBreakerEvents(emitter),
+	)
+	now := time.Unix(1_700_000_000, 0)
+	b.now = func() time.Time { return now }
+
+	status := http.StatusInternalServerError
+	h := b.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	call := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rec
+	}
+
+	// Two failures out of four reach the default 50% failure rate.
+	call()
+	call()
+	status = http.StatusOK
+	call()
+	assert.Equal(t, CircuitClosed, b.State())
+	call()
+	assert.Equal(t, CircuitOpen, b.State())
+
+	rec := call()
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"id":"circuit_open","message":"service temporarily unavailable"}`,
+		rec.Body.String())
+
+	// A failing probe reopens the breaker.
+	now = now.Add(time.Minute)
+	status = http.StatusBadGateway
+	assert.Equal(t, http.StatusBadGateway, call().Code)
+	assert.Equal(t, CircuitOpen, b.State())
+
+	// Successful probes close it.
+	now = now.Add(time.Minute)
+	status = http.StatusOK
+	call()
+	assert.Equal(t, CircuitHalfOpen, b.State())
+	call()
+	assert.Equal(t, CircuitClosed, b.State())
+
+	var types []event.EventType
+	for _, e := range emitter.events {
+		types = append(types, e.Type)
+		assert.Equal(t, "orders", e.Data)
+	}
+	assert.Equal(t, []event.EventType{
+		EventCircuitOpened, EventCircuitHalfOpen, EventCircuitOpened,
+		EventCircuitHalfOpen, EventCircuitClosed,
+	}, types)
+}
+
+func TestCircuitBreaker_SlowCalls(t *testing.T) {
+	b := NewCircuitBreaker(WithBreakerWindow(2, 2),
+		WithBreakerSlowCalls(time.Second, 1))
+	now := time.Unix(1_700_000_000, 0)
+	b.now = func() time.Time { return now }
+	h := b.Middleware()(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		now = now.Add(2 * time.Second)
+	}))
+
+	for range 2 {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	assert.Equal(t, CircuitOpen, b.State())
+}
+
+func TestCircuitBreaker_HalfOpenLimit(t *testing.T) {
+	b := NewCircuitBreaker(WithBreakerProbes(1))
+	now := time.Unix(1_700_000_000, 0)
+	b.now = func() time.Time { return now }
+	b.mu.Lock()
+	b.transition(CircuitOpen)
+	b.mu.Unlock()
+	now = now.Add(time.Hour)
+
+	_, ok := b.allow()
+	require.True(t, ok)
+	_, ok = b.allow()
+	assert.False(t, ok, "only one probe may be in flight")
+	b.record(callOutcome{})
+	assert.Equal(t, CircuitClosed, b.State())
+}
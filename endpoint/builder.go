@@ -0,0 +1,292 @@
+package endpoint
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/aatuh/pureapi-core/event"
+)
+
+// Builder assembles an endpoint, its handler pipeline and its middlewares
+// in one fluent chain:
+//
+//	ep := endpoint.Build[GetUser]().
+//		Get("/users/:id").
+//		Use(auth).
+//		In(endpoint.PathInput[GetUser]()).
+//		Logic(getUser).
+//		Out(endpoint.JSONOutput())
+//
+// Every step returns a new builder, so a partially configured builder can
+// serve as a base for several endpoints. Out finishes the chain.
+type Builder[Input any] struct {
+	method       string
+	url          string
+	wrappers     []Wrapper
+	inputHandler InputHandler[Input]
+	logicFn      HandlerLogicFn[Input]
+	errorHandler ErrorHandler
+	status       int
+	emitter      event.EventEmitter
+	operation    Operation
+	bodyLimit    int64
+}
+
+// Build starts building an endpoint whose input is of type Input.
+//
+// Returns:
+//   - *Builder[Input]: A new Builder instance.
+func Build[Input any]() *Builder[Input] {
+	return &Builder[Input]{method: http.MethodGet, url: "/"}
+}
+
+// Route sets the method and URL of the endpoint.
+//
+// Parameters:
+//   - method: The HTTP method.
+//   - url: The URL of the endpoint.
+//
+// Returns:
+//   - *Builder[Input]: A new builder.
+func (b *Builder[Input]) Route(method string, url string) *Builder[Input] {
+	new := *b
+	new.method = method
+	new.url = defaultURL(url)
+	return &new
+}
+
+// Get sets the endpoint to GET url.
+//
+// Parameters:
+//   - url: The URL of the endpoint.
+//
+// Returns:
+//   - *Builder[Input]: A new builder.
+func (b *Builder[Input]) Get(url string) *Builder[Input] {
+	return b.Route(http.MethodGet, url)
+}
+
+// Post sets the endpoint to POST url.
+//
+// Parameters:
+//   - url: The URL of the endpoint.
+//
+// Returns:
+//   - *Builder[Input]: A new builder.
+func (b *Builder[Input]) Post(url string) *Builder[Input] {
+	return b.Route(http.MethodPost, url)
+}
+
+// Put sets the endpoint to PUT url.
+//
+// Parameters:
+//   - url: The URL of the endpoint.
+//
+// Returns:
+//   - *Builder[Input]: A new builder.
+func (b *Builder[Input]) Put(url string) *Builder[Input] {
+	return b.Route(http.MethodPut, url)
+}
+
+// Patch sets the endpoint to PATCH url.
+//
+// Parameters:
+//   - url: The URL of the endpoint.
+//
+// Returns:
+//   - *Builder[Input]: A new builder.
+func (b *Builder[Input]) Patch(url string) *Builder[Input] {
+	return b.Route(http.MethodPatch, url)
+}
+
+// Delete sets the endpoint to DELETE url.
+//
+// Parameters:
+//   - url: The URL of the endpoint.
+//
+// Returns:
+//   - *Builder[Input]: A new builder.
+func (b *Builder[Input]) Delete(url string) *Builder[Input] {
+	return b.Route(http.MethodDelete, url)
+}
+
+// Use appends middlewares to the endpoint. The first middleware becomes
+// the outermost one.
+//
+// Parameters:
+//   - middlewares: The middlewares to append.
+//
+// Returns:
+//   - *Builder[Input]: A new builder.
+func (b *Builder[Input]) Use(middlewares ...Middleware) *Builder[Input] {
+	wrappers := make([]Wrapper, len(middlewares))
+	for i, mw := range middlewares {
+		wrappers[i] = NewWrapper("", mw)
+	}
+	return b.Wrap(wrappers...)
+}
+
+// Wrap appends middleware wrappers to the endpoint, keeping their IDs and
+// data for tools reading the stack, such as AccessRules.
+//
+// Parameters:
+//   - wrappers: The wrappers to append.
+//
+// Returns:
+//   - *Builder[Input]: A new builder.
+func (b *Builder[Input]) Wrap(wrappers ...Wrapper) *Builder[Input] {
+	new := *b
+	new.wrappers = append(slices.Clip(b.wrappers), wrappers...)
+	return &new
+}
+
+// In sets the input handler. Without one, the logic receives a zero Input.
+//
+// Parameters:
+//   - inputHandler: The input handler.
+//
+// Returns:
+//   - *Builder[Input]: A new builder.
+func (b *Builder[Input]) In(inputHandler InputHandler[Input]) *Builder[Input] {
+	new := *b
+	new.inputHandler = inputHandler
+	return &new
+}
+
+// Logic sets the handler logic function. It is required.
+//
+// Parameters:
+//   - logicFn: The handler logic function.
+//
+// Returns:
+//   - *Builder[Input]: A new builder.
+func (b *Builder[Input]) Logic(logicFn HandlerLogicFn[Input]) *Builder[Input] {
+	new := *b
+	new.logicFn = logicFn
+	return &new
+}
+
+// Errors sets the error handler. The default is DefaultErrorHandler.
+//
+// Parameters:
+//   - errorHandler: The error handler.
+//
+// Returns:
+//   - *Builder[Input]: A new builder.
+func (b *Builder[Input]) Errors(errorHandler ErrorHandler) *Builder[Input] {
+	new := *b
+	new.errorHandler = errorHandler
+	return &new
+}
+
+// Status sets the status code of successful responses, like
+// DefaultHandler.WithSuccessStatus.
+//
+// Parameters:
+//   - status: The success status code.
+//
+// Returns:
+//   - *Builder[Input]: A new builder.
+func (b *Builder[Input]) Status(status int) *Builder[Input] {
+	new := *b
+	new.status = status
+	return &new
+}
+
+// Events sets the emitter logger of the handler.
+//
+// Parameters:
+//   - emitter: The event emitter.
+//
+// Returns:
+//   - *Builder[Input]: A new builder.
+func (b *Builder[Input]) Events(emitter event.EventEmitter) *Builder[Input] {
+	new := *b
+	new.emitter = emitter
+	return &new
+}
+
+// Doc sets the API documentation of the endpoint. Input and Output default
+// to the types of the handler.
+//
+// Parameters:
+//   - op: The operation.
+//
+// Returns:
+//   - *Builder[Input]: A new builder.
+func (b *Builder[Input]) Doc(op Operation) *Builder[Input] {
+	new := *b
+	new.operation = op
+	return &new
+}
+
+// BodyLimit sets the request body limit of the endpoint, like
+// DefaultEndpoint.WithBodyLimit.
+//
+// Parameters:
+//   - limit: The maximum request body size in bytes.
+//
+// Returns:
+//   - *Builder[Input]: A new builder.
+func (b *Builder[Input]) BodyLimit(limit int64) *Builder[Input] {
+	new := *b
+	new.bodyLimit = limit
+	return &new
+}
+
+// Out sets the output handler and builds the endpoint. It panics if Logic
+// was not set, since that is a programming error.
+//
+// Parameters:
+//   - outputHandler: The output handler.
+//
+// Returns:
+//   - *DefaultEndpoint: The built endpoint.
+func (b *Builder[Input]) Out(outputHandler OutputHandler) *DefaultEndpoint {
+	if b.logicFn == nil {
+		panic("endpoint: Build: Logic is required")
+	}
+	inputHandler := b.inputHandler
+	if inputHandler == nil {
+		inputHandler = zeroInputHandler[Input]{}
+	}
+	errorHandler := b.errorHandler
+	if errorHandler == nil {
+		errorHandler = DefaultErrorHandler{}
+	}
+	handler := NewHandler(inputHandler, b.logicFn, errorHandler, outputHandler).
+		WithSuccessStatus(b.status)
+	if b.emitter != nil {
+		handler = handler.WithEmitterLogger(b.emitter)
+	}
+	op := b.operation
+	if op.Input == nil {
+		op.Input = handler.InputType()
+	}
+	return &DefaultEndpoint{
+		URLVal:         b.url,
+		MethodVal:      b.method,
+		MiddlewaresVal: NewStack(slices.Clone(b.wrappers)...),
+		HandlerVal:     handler.Handle,
+		BodyLimitVal:   b.bodyLimit,
+		OperationVal:   op,
+	}
+}
+
+// zeroInputHandler returns a zero input for every request.
+type zeroInputHandler[Input any] struct{}
+
+// Handle returns a zero input.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//
+// Returns:
+//   - *Input: A zero input.
+//   - error: Always nil.
+func (zeroInputHandler[Input]) Handle(
+	http.ResponseWriter, *http.Request,
+) (*Input, error) {
+	return new(Input), nil
+}
@@ -0,0 +1,73 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// builderInput is the input of the builder tests.
+type builderInput struct {
+	Name string `json:"name"`
+}
+
+func TestBuilder(t *testing.T) {
+	var events []string
+	base := Build[builderInput]().Use(makeMiddleware("base", &events))
+	ep := base.
+		Post("/users").
+		Use(makeMiddleware("auth", &events)).
+		Wrap(RequireAccess(AccessRule{Roles: []string{"admin"}})).
+		In(JSONInput[builderInput]()).
+		Logic(func(_ http.ResponseWriter, _ *http.Request, in *builderInput) (any, error) {
+			events = append(events, "logic")
+			return NewResponse(map[string]string{"name": in.Name}).
+				WithStatus(http.StatusCreated), nil
+		}).
+		Doc(Operation{Summary: "Create user"}).
+		BodyLimit(1024).
+		Out(JSONOutput())
+
+	assert.Equal(t, http.MethodPost, ep.Method())
+	assert.Equal(t, "/users", ep.URL())
+	assert.Equal(t, int64(1024), ep.BodyLimit())
+	assert.Equal(t, "Create user", ep.Operation().Summary)
+	assert.Equal(t, reflect.TypeFor[builderInput](), ep.Operation().Input)
+	assert.Equal(t, []AccessRule{{Roles: []string{"admin"}}}, AccessRules(ep.Middlewares()))
+
+	// The base builder is unaffected by the endpoint's steps.
+	assert.Len(t, base.wrappers, 1)
+
+	r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"ada"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r = r.WithContext(WithPrincipal(r.Context(), &Principal{Roles: []string{"admin"}}))
+	rec := httptest.NewRecorder()
+	ep.Middlewares().Chain(ep.Handler()).ServeHTTP(rec, r)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"name":"ada"}`, rec.Body.String())
+	assert.Equal(t, []string{"base-pre", "auth-pre", "logic", "auth-post", "base-post"}, events)
+}
+
+func TestBuilder_Defaults(t *testing.T) {
+	ep := Build[builderInput]().
+		Logic(func(_ http.ResponseWriter, _ *http.Request, in *builderInput) (any, error) {
+			require.NotNil(t, in)
+			return "ok", nil
+		}).
+		Out(JSONOutput())
+	assert.Equal(t, http.MethodGet, ep.Method())
+	assert.Equal(t, "/", ep.URL())
+
+	rec := httptest.NewRecorder()
+	ep.Middlewares().Chain(ep.Handler()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `"ok"`, rec.Body.String())
+
+	assert.Panics(t, func() { Build[builderInput]().Out(JSONOutput()) })
+}
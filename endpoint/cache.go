@@ -0,0 +1,240 @@
+package endpoint
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheDirective configures the policy of CacheControl.
+type CacheDirective func(*cachePolicy)
+
+// cachePolicy holds the Cache-Control directives and Vary headers.
+type cachePolicy struct {
+	directives []string
+	vary       []string
+	noStore    bool
+}
+
+// set adds a directive, replacing an earlier one of the same name.
+func (p *cachePolicy) set(name string, value string) {
+	directive := name
+	if value != "" {
+		directive += "=" + value
+	}
+	for i, d := range p.directives {
+		if d == name || strings.HasPrefix(d, name+"=") {
+			p.directives[i] = directive
+			return
+		}
+	}
+	p.directives = append(p.directives, directive)
+}
+
+// CachePublic lets browsers and shared caches store responses for maxAge.
+//
+// Parameters:
+//   - maxAge: How long responses stay fresh.
+//
+// Returns:
+//   - CacheDirective: The directive.
+func CachePublic(maxAge time.Duration) CacheDirective {
+	return func(p *cachePolicy) {
+		p.set("public", "")
+		p.set("max-age", cacheSeconds(maxAge))
+	}
+}
+
+// CachePrivate lets only the client's own cache store responses for
+// maxAge, for responses specific to the caller.
+//
+// Parameters:
+//   - maxAge: How long responses stay fresh.
+//
+// Returns:
+//   - CacheDirective: The directive.
+func CachePrivate(maxAge time.Duration) CacheDirective {
+	return func(p *cachePolicy) {
+		p.set("private", "")
+		p.set("max-age", cacheSeconds(maxAge))
+	}
+}
+
+// CacheNoStore forbids storing responses at all. Unlike other policies, it
+// also applies to error responses.
+//
+// Returns:
+//   - CacheDirective: The directive.
+func CacheNoStore() CacheDirective {
+	return func(p *cachePolicy) {
+		p.set("no-store", "")
+		p.noStore = true
+	}
+}
+
+// CacheNoCache lets caches store responses but requires revalidating them
+// before every use, e.g. with ETags.
+//
+// Returns:
+//   - CacheDirective: The directive.
+func CacheNoCache() CacheDirective {
+	return func(p *cachePolicy) { p.set("no-cache", "") }
+}
+
+// StaleWhileRevalidate lets caches serve a stale response for d while they
+// fetch a fresh one in the background.
+//
+// Parameters:
+//   - d: How long a stale response may be served.
+//
+// Returns:
+//   - CacheDirective: The directive.
+func StaleWhileRevalidate(d time.Duration) CacheDirective {
+	return func(p *cachePolicy) {
+		p.set("stale-while-revalidate", cacheSeconds(d))
+	}
+}
+
+// StaleIfError lets caches serve a stale response for d when fetching a
+// fresh one fails.
+//
+// Parameters:
+//   - d: How long a stale response may be served.
+//
+// Returns:
+//   - CacheDirective: The directive.
+func StaleIfError(d time.Duration) CacheDirective {
+	return func(p *cachePolicy) { p.set("stale-if-error", cacheSeconds(d)) }
+}
+
+// Immutable tells caches that fresh responses never change, so they are
+// not revalidated on reload. Use it for versioned URLs.
+//
+// Returns:
+//   - CacheDirective: The directive.
+func Immutable() CacheDirective {
+	return func(p *cachePolicy) { p.set("immutable", "") }
+}
+
+// CacheVary adds request headers the response depends on to Vary, so
+// caches store a variant per header value.
+//
+// Parameters:
+//   - headers: The request header names.
+//
+// Returns:
+//   - CacheDirective: The directive.
+func CacheVary(headers ...string) CacheDirective {
+	return func(p *cachePolicy) { p.vary = append(p.vary, headers...) }
+}
+
+// CacheControl returns a middleware setting the Cache-Control header of a
+// route's responses:
+//
+//	endpoint.CacheControl(
+//		endpoint.CachePublic(5*time.Minute),
+//		endpoint.StaleWhileRevalidate(time.Minute),
+//	)
+//
+// The header is set when the response starts, on success and redirect
+// responses only, so errors are not cached; CacheNoStore applies to all
+// responses. A Cache-Control header set by the handler wins. Vary values
+// added by the handler and by negotiating middlewares and output handlers,
+// such as Accept from NegotiatingOutput and Accept-Language from
+// NegotiateLocale, are merged with those of CacheVary into one
+// de-duplicated Vary header.
+//
+// Parameters:
+//   - directives: The cache directives.
+//
+// Returns:
+//   - Middleware: The cache control middleware.
+func CacheControl(directives ...CacheDirective) Middleware {
+	var p cachePolicy
+	for _, d := range directives {
+		d(&p)
+	}
+	value := strings.Join(p.directives, ", ")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(&cacheWriter{
+				ResponseWriter: w, policy: &p, value: value,
+			}, r)
+		})
+	}
+}
+
+// cacheWriter applies a cache policy when the response starts.
+type cacheWriter struct {
+	http.ResponseWriter
+	policy *cachePolicy
+	value  string
+	wrote  bool
+}
+
+// WriteHeader sets the cache headers and writes the status.
+func (cw *cacheWriter) WriteHeader(code int) {
+	if !cw.wrote && code >= 200 {
+		cw.wrote = true
+		cw.apply(code)
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+// Write starts the response if needed and writes the data.
+func (cw *cacheWriter) Write(p []byte) (int, error) {
+	if !cw.wrote {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush starts the response if needed and flushes the wrapped writer.
+func (cw *cacheWriter) Flush() {
+	if !cw.wrote {
+		cw.WriteHeader(http.StatusOK)
+	}
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (cw *cacheWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// apply sets Cache-Control for the status and merges the Vary headers.
+func (cw *cacheWriter) apply(code int) {
+	h := cw.Header()
+	if cw.value != "" && h.Get("Cache-Control") == "" &&
+		(code < http.StatusBadRequest || cw.policy.noStore) {
+		h.Set("Cache-Control", cw.value)
+	}
+	vary := mergeVary(append(h.Values("Vary"), cw.policy.vary...))
+	if vary != "" {
+		h.Set("Vary", vary)
+	}
+}
+
+// mergeVary joins Vary values, dropping duplicates regardless of case.
+func mergeVary(values []string) string {
+	var names []string
+	seen := map[string]bool{}
+	for _, v := range values {
+		for _, name := range strings.Split(v, ",") {
+			name = strings.TrimSpace(name)
+			key := http.CanonicalHeaderKey(name)
+			if name == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// cacheSeconds formats a duration as whole seconds, at least 0.
+func cacheSeconds(d time.Duration) string {
+	return strconv.FormatInt(max(int64(d/time.Second), 0), 10)
+}
@@ -0,0 +1,67 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControl(t *testing.T) {
+	testCases := []struct {
+		name       string
+		directives []CacheDirective
+		status     int
+		handlerCC  string
+		want       string
+	}{
+		{"Public", []CacheDirective{
+			CachePublic(5 * time.Minute), StaleWhileRevalidate(time.Minute),
+		}, http.StatusOK, "", "public, max-age=300, stale-while-revalidate=60"},
+		{"Private", []CacheDirective{CachePrivate(time.Hour), Immutable()},
+			http.StatusOK, "", "private, max-age=3600, immutable"},
+		{"Replaced", []CacheDirective{
+			CachePublic(time.Minute), StaleIfError(-time.Second), CachePublic(time.Hour),
+		}, http.StatusOK, "", "public, max-age=3600, stale-if-error=0"},
+		{"No cache", []CacheDirective{CacheNoCache()},
+			http.StatusNotModified, "", "no-cache"},
+		{"Error", []CacheDirective{CachePublic(time.Minute)},
+			http.StatusNotFound, "", ""},
+		{"No store error", []CacheDirective{CacheNoStore()},
+			http.StatusInternalServerError, "", "no-store"},
+		{"Handler wins", []CacheDirective{CachePublic(time.Minute)},
+			http.StatusOK, "private", "private"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := CacheControl(tc.directives...)(http.HandlerFunc(
+				func(w http.ResponseWriter, _ *http.Request) {
+					if tc.handlerCC != "" {
+						w.Header().Set("Cache-Control", tc.handlerCC)
+					}
+					w.WriteHeader(tc.status)
+				},
+			))
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Equal(t, tc.want, rec.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func TestCacheControl_Vary(t *testing.T) {
+	h := NegotiateLocale("en")(CacheControl(
+		CachePublic(time.Minute), CacheVary("accept-language", "Authorization"),
+	)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Add("Vary", "Accept, Accept-Language")
+		_, _ = w.Write([]byte("ok"))
+	})))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, []string{"Accept-Language, Accept, Authorization"},
+		rec.Header().Values("Vary"))
+	assert.Equal(t, "public, max-age=60", rec.Header().Get("Cache-Control"))
+}
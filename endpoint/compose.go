@@ -0,0 +1,165 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// PathInput returns an input handler binding route params to the fields of
+// Input tagged "path". Values are converted like FormInput fields.
+//
+// Returns:
+//   - InputHandler[Input]: The path input handler.
+func PathInput[Input any]() InputHandler[Input] {
+	return &sourceInputHandler[Input]{tag: "path"}
+}
+
+// QueryInput returns an input handler binding query parameters to the
+// fields of Input tagged "query". Values are converted like FormInput
+// fields.
+//
+// Returns:
+//   - InputHandler[Input]: The query input handler.
+func QueryInput[Input any]() InputHandler[Input] {
+	return &sourceInputHandler[Input]{tag: "query"}
+}
+
+// HeaderInput returns an input handler binding request headers to the
+// fields of Input tagged "header". Values are converted like FormInput
+// fields.
+//
+// Returns:
+//   - InputHandler[Input]: The header input handler.
+func HeaderInput[Input any]() InputHandler[Input] {
+	return &sourceInputHandler[Input]{tag: "header"}
+}
+
+// sourceInputHandler binds a single request source by struct tag.
+type sourceInputHandler[Input any] struct {
+	tag string
+}
+
+// Handle binds the source values.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//
+// Returns:
+//   - *Input: The bound input.
+//   - error: An API error if a value cannot be converted.
+func (h *sourceInputHandler[Input]) Handle(
+	_ http.ResponseWriter, r *http.Request,
+) (*Input, error) {
+	var in Input
+	fields, err := bindSource(r, &in, h.tag)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) > 0 {
+		return nil, invalidFields(fields)
+	}
+	return &in, nil
+}
+
+// ComposeInput returns an input handler running several input handlers and
+// merging their results into one Input. Handlers run in order and later
+// handlers take precedence: each non-zero field of a result overwrites the
+// field set by earlier handlers, while zero fields leave it untouched.
+// Embedded structs are merged field by field, other fields as a whole. For
+// non-struct inputs the last result wins. At most one handler may read the
+// request body.
+//
+//	handler := ComposeInput(
+//		JSONInput[UpdateUser](),
+//		PathInput[UpdateUser](),   // The route param wins over the body.
+//		HeaderInput[UpdateUser](),
+//	)
+//
+// Field errors of all handlers (invalid_input API errors with FieldError
+// data) are reported together; any other error stops the chain and is
+// returned as is.
+//
+// Parameters:
+//   - handlers: The input handlers, lowest precedence first.
+//
+// Returns:
+//   - InputHandler[Input]: The composite input handler.
+func ComposeInput[Input any](handlers ...InputHandler[Input]) InputHandler[Input] {
+	return &composeInputHandler[Input]{handlers: handlers}
+}
+
+// composeInputHandler merges the results of several input handlers.
+type composeInputHandler[Input any] struct {
+	handlers []InputHandler[Input]
+}
+
+// Handle runs the handlers and merges their results.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//
+// Returns:
+//   - *Input: The merged input.
+//   - error: An error returned by a handler.
+func (h *composeInputHandler[Input]) Handle(
+	w http.ResponseWriter, r *http.Request,
+) (*Input, error) {
+	var in Input
+	dst := reflect.ValueOf(&in).Elem()
+	var fields []FieldError
+	for _, ih := range h.handlers {
+		res, err := ih.Handle(w, r)
+		if err != nil {
+			fe, ok := invalidFieldsData(err)
+			if !ok {
+				return nil, err
+			}
+			fields = append(fields, fe...)
+			continue
+		}
+		if res != nil {
+			mergeValue(dst, reflect.ValueOf(res).Elem())
+		}
+	}
+	if len(fields) > 0 {
+		return nil, invalidFields(fields)
+	}
+	return &in, nil
+}
+
+// invalidFieldsData returns the field errors of an invalid_input API error.
+func invalidFieldsData(err error) ([]FieldError, bool) {
+	var apiErr apierror.APIError
+	if !errors.As(err, &apiErr) || apiErr.ID() != ErrIDInvalidInput {
+		return nil, false
+	}
+	fields, ok := apiErr.Data().([]FieldError)
+	return fields, ok
+}
+
+// mergeValue copies the non-zero fields of src into dst.
+func mergeValue(dst, src reflect.Value) {
+	if src.Kind() != reflect.Struct {
+		dst.Set(src)
+		return
+	}
+	for i := 0; i < src.NumField(); i++ {
+		f := src.Type().Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		sv, dv := src.Field(i), dst.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			mergeValue(dv, sv)
+			continue
+		}
+		if dv.CanSet() && !sv.IsZero() {
+			dv.Set(sv)
+		}
+	}
+}
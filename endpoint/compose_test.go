@@ -0,0 +1,97 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/aatuh/pureapi-core/router"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type composeMeta struct {
+	Trace string `header:"X-Trace" json:"trace"`
+}
+
+type composeTestInput struct {
+	composeMeta
+	ID    int64  `path:"id" json:"id"`
+	Name  string `json:"name"`
+	Limit int    `query:"limit" json:"limit"`
+}
+
+func TestComposeInput(t *testing.T) {
+	r := bindRequest(http.MethodPut, "/users/7?limit=5", "application/json",
+		`{"id":1,"name":"Go","limit":2,"trace":"body"}`, router.Params{"id": "7"})
+	r.Header.Set("X-Trace", "header")
+
+	in, err := ComposeInput(
+		JSONInput[composeTestInput](),
+		PathInput[composeTestInput](),
+		QueryInput[composeTestInput](),
+		HeaderInput[composeTestInput](),
+	).Handle(httptest.NewRecorder(), r)
+	require.NoError(t, err)
+	assert.Equal(t, &composeTestInput{
+		composeMeta: composeMeta{Trace: "header"}, ID: 7, Name: "Go", Limit: 5,
+	}, in)
+}
+
+func TestComposeInput_ZeroValuesKeepEarlier(t *testing.T) {
+	r := bindRequest(http.MethodPut, "/users/7", "application/json",
+		`{"name":"Go","limit":2}`, router.Params{"id": "7"})
+
+	in, err := ComposeInput(
+		JSONInput[composeTestInput](),
+		PathInput[composeTestInput](),
+		QueryInput[composeTestInput](),
+	).Handle(httptest.NewRecorder(), r)
+	require.NoError(t, err)
+	assert.Equal(t, 2, in.Limit)
+	assert.Equal(t, int64(7), in.ID)
+}
+
+func TestComposeInput_Errors(t *testing.T) {
+	r := bindRequest(http.MethodGet, "/users/x?limit=all", "", "", router.Params{"id": "x"})
+	_, err := ComposeInput(
+		PathInput[composeTestInput](),
+		QueryInput[composeTestInput](),
+	).Handle(httptest.NewRecorder(), r)
+	apiErr, ok := err.(apierror.APIError)
+	require.True(t, ok)
+	assert.Equal(t, ErrIDInvalidInput, apiErr.ID())
+	assert.Equal(t, []FieldError{
+		{Field: "id", In: "path", Message: `invalid integer "x"`},
+		{Field: "limit", In: "query", Message: `invalid integer "all"`},
+	}, apiErr.Data())
+
+	errStop := errors.New("stop")
+	_, err = ComposeInput(
+		PathInput[composeTestInput](),
+		&dummyComposeInput[composeTestInput]{err: errStop},
+	).Handle(httptest.NewRecorder(), r)
+	assert.ErrorIs(t, err, errStop)
+}
+
+func TestComposeInput_NonStruct(t *testing.T) {
+	first, second := "first", "second"
+	in, err := ComposeInput[string](
+		&dummyComposeInput[string]{res: &first},
+		&dummyComposeInput[string]{res: &second},
+	).Handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "second", *in)
+}
+
+// dummyComposeInput returns fixed results.
+type dummyComposeInput[T any] struct {
+	res *T
+	err error
+}
+
+func (d *dummyComposeInput[T]) Handle(http.ResponseWriter, *http.Request) (*T, error) {
+	return d.res, d.err
+}
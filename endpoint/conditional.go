@@ -0,0 +1,81 @@
+package endpoint
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// RequestPredicate reports whether a request matches a condition.
+type RequestPredicate func(r *http.Request) bool
+
+// When returns a middleware running mw only for requests matching
+// predicate; other requests go straight to the next handler. This keeps a
+// single shared stack while exempting some requests from a middleware:
+//
+//	stack := endpoint.NewStack(
+//		endpoint.NewWrapper("auth", endpoint.Unless(
+//			endpoint.PathPrefix("/public/"), endpoint.BearerAuth(verifier),
+//		)),
+//	)
+//
+// Parameters:
+//   - predicate: Reports whether mw runs for a request.
+//   - mw: The conditional middleware.
+//
+// Returns:
+//   - Middleware: The conditional middleware.
+func When(predicate RequestPredicate, mw Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if predicate(r) {
+				wrapped.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Unless returns a middleware running mw only for requests not matching
+// predicate.
+//
+// Parameters:
+//   - predicate: Reports whether mw is skipped for a request.
+//   - mw: The conditional middleware.
+//
+// Returns:
+//   - Middleware: The conditional middleware.
+func Unless(predicate RequestPredicate, mw Middleware) Middleware {
+	return When(func(r *http.Request) bool { return !predicate(r) }, mw)
+}
+
+// PathPrefix returns a predicate matching requests whose path starts with
+// any of the prefixes.
+//
+// Parameters:
+//   - prefixes: The path prefixes.
+//
+// Returns:
+//   - RequestPredicate: The predicate.
+func PathPrefix(prefixes ...string) RequestPredicate {
+	return func(r *http.Request) bool {
+		return slices.ContainsFunc(prefixes, func(p string) bool {
+			return strings.HasPrefix(r.URL.Path, p)
+		})
+	}
+}
+
+// MethodIs returns a predicate matching requests using any of the methods.
+//
+// Parameters:
+//   - methods: The HTTP methods.
+//
+// Returns:
+//   - RequestPredicate: The predicate.
+func MethodIs(methods ...string) RequestPredicate {
+	return func(r *http.Request) bool {
+		return slices.Contains(methods, r.Method)
+	}
+}
@@ -0,0 +1,58 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhen(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mw       func(mw Middleware) Middleware
+		method   string
+		path     string
+		expected []string
+	}{
+		{
+			name:     "When matches",
+			mw:       func(mw Middleware) Middleware { return When(PathPrefix("/admin/"), mw) },
+			method:   http.MethodGet,
+			path:     "/admin/users",
+			expected: []string{"m-pre", "final", "m-post"},
+		},
+		{
+			name:     "When does not match",
+			mw:       func(mw Middleware) Middleware { return When(PathPrefix("/admin/"), mw) },
+			method:   http.MethodGet,
+			path:     "/users",
+			expected: []string{"final"},
+		},
+		{
+			name:     "Unless matches",
+			mw:       func(mw Middleware) Middleware { return Unless(PathPrefix("/public/", "/health"), mw) },
+			method:   http.MethodGet,
+			path:     "/health",
+			expected: []string{"final"},
+		},
+		{
+			name:     "Unless does not match",
+			mw:       func(mw Middleware) Middleware { return Unless(MethodIs(http.MethodGet), mw) },
+			method:   http.MethodPost,
+			path:     "/",
+			expected: []string{"m-pre", "final", "m-post"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var events []string
+			h := tc.mw(makeMiddleware("m", &events))(http.HandlerFunc(
+				func(http.ResponseWriter, *http.Request) { events = append(events, "final") },
+			))
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tc.method, tc.path, nil))
+			assert.Equal(t, tc.expected, events)
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package endpoint
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Secure cookie errors. Decode and Read return ErrCookieInvalid for values
+// that were tampered with or signed by an unknown key, and ErrCookieExpired
+// for values older than the configured max age.
+var (
+	ErrCookieInvalid  = errors.New("secure cookie: invalid value")
+	ErrCookieExpired  = errors.New("secure cookie: expired value")
+	ErrCookieTooLarge = errors.New("secure cookie: value too large")
+)
+
+// maxCookieSize is the largest encoded cookie value browsers reliably keep.
+const maxCookieSize = 4096
+
+// CookieKey is a secure cookie key pair. Hash signs values with
+// HMAC-SHA256 and should be at least 32 random bytes. Block, if set,
+// encrypts values with AES-GCM and must be 16, 24 or 32 bytes long.
+type CookieKey struct {
+	Hash  []byte
+	Block []byte
+}
+
+// SecureCookieOption configures a SecureCookie.
+type SecureCookieOption func(*SecureCookie)
+
+// WithCookiePath sets the cookie path. The default is "/".
+//
+// Parameters:
+//   - path: The cookie path.
+//
+// Returns:
+//   - SecureCookieOption: The option.
+func WithCookiePath(path string) SecureCookieOption {
+	return func(s *SecureCookie) { s.path = path }
+}
+
+// WithCookieDomain sets the cookie domain. By default cookies are host-only.
+//
+// Parameters:
+//   - domain: The cookie domain.
+//
+// Returns:
+//   - SecureCookieOption: The option.
+func WithCookieDomain(domain string) SecureCookieOption {
+	return func(s *SecureCookie) { s.domain = domain }
+}
+
+// WithCookieMaxAge sets the lifetime of written cookies and rejects decoded
+// values older than it. Zero, the default, writes session cookies and
+// accepts values of any age.
+//
+// Parameters:
+//   - maxAge: The cookie lifetime.
+//
+// Returns:
+//   - SecureCookieOption: The option.
+func WithCookieMaxAge(maxAge time.Duration) SecureCookieOption {
+	return func(s *SecureCookie) { s.maxAge = maxAge }
+}
+
+// WithCookieSameSite sets the SameSite attribute. The default is
+// http.SameSiteLaxMode.
+//
+// Parameters:
+//   - mode: The SameSite mode.
+//
+// Returns:
+//   - SecureCookieOption: The option.
+func WithCookieSameSite(mode http.SameSite) SecureCookieOption {
+	return func(s *SecureCookie) { s.sameSite = mode }
+}
+
+// WithCookieInsecure clears the Secure attribute so cookies are sent over
+// plain HTTP, e.g. in local development.
+//
+// Returns:
+//   - SecureCookieOption: The option.
+func WithCookieInsecure() SecureCookieOption {
+	return func(s *SecureCookie) { s.insecure = true }
+}
+
+// WithCookieScriptAccess clears the HttpOnly attribute so scripts can read
+// the cookie.
+//
+// Returns:
+//   - SecureCookieOption: The option.
+func WithCookieScriptAccess() SecureCookieOption {
+	return func(s *SecureCookie) { s.scriptAccess = true }
+}
+
+// SecureCookie signs, and optionally encrypts, cookie values. Values are
+// bound to the cookie name and carry their creation time, so a value cannot
+// be moved to another cookie and expires with the configured max age.
+//
+// Keys support rotation: the first key encodes new values and every key is
+// tried when decoding, so a new key can be prepended while the old one is
+// still accepted. Cookies are written Secure, HttpOnly and SameSite=Lax by
+// default.
+type SecureCookie struct {
+	keys         []cookieKey
+	path         string
+	domain       string
+	maxAge       time.Duration
+	sameSite     http.SameSite
+	insecure     bool
+	scriptAccess bool
+	now          func() time.Time
+}
+
+// cookieKey is a prepared CookieKey.
+type cookieKey struct {
+	hash []byte
+	aead cipher.AEAD
+}
+
+// NewSecureCookie creates a secure cookie codec.
+//
+// Parameters:
+//   - keys: The keys, newest first.
+//   - opts: Optional cookie options.
+//
+// Returns:
+//   - *SecureCookie: A new SecureCookie instance.
+//   - error: An error if no keys are given or a key is invalid.
+func NewSecureCookie(
+	keys []CookieKey, opts ...SecureCookieOption,
+) (*SecureCookie, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("secure cookie: no keys")
+	}
+	s := &SecureCookie{
+		path:     "/",
+		sameSite: http.SameSiteLaxMode,
+		now:      time.Now,
+	}
+	for i, key := range keys {
+		if len(key.Hash) == 0 {
+			return nil, fmt.Errorf("secure cookie: key %d: empty hash key", i)
+		}
+		k := cookieKey{hash: key.Hash}
+		if len(key.Block) > 0 {
+			block, err := aes.NewCipher(key.Block)
+			if err != nil {
+				return nil, fmt.Errorf("secure cookie: key %d: %w", i, err)
+			}
+			if k.aead, err = cipher.NewGCM(block); err != nil {
+				return nil, fmt.Errorf("secure cookie: key %d: %w", i, err)
+			}
+		}
+		s.keys = append(s.keys, k)
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// Encode signs, and encrypts if the key has a block key, a cookie value.
+//
+// Parameters:
+//   - name: The cookie name.
+//   - value: The value to encode.
+//
+// Returns:
+//   - string: The encoded cookie value.
+//   - error: ErrCookieTooLarge if the encoded value exceeds 4096 bytes.
+func (s *SecureCookie) Encode(name string, value []byte) (string, error) {
+	key := s.keys[0]
+	data := make([]byte, 8, 8+len(value))
+	binary.BigEndian.PutUint64(data, uint64(s.now().Unix()))
+	data = append(data, value...)
+	if key.aead != nil {
+		nonce := make([]byte, key.aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		data = key.aead.Seal(nonce, nonce, data, []byte(name))
+	}
+	data = append(data, cookieMAC(key.hash, name, data)...)
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	if len(encoded) > maxCookieSize {
+		return "", ErrCookieTooLarge
+	}
+	return encoded, nil
+}
+
+// Decode verifies and decodes a cookie value produced by Encode.
+//
+// Parameters:
+//   - name: The cookie name.
+//   - encoded: The encoded cookie value.
+//
+// Returns:
+//   - []byte: The decoded value.
+//   - error: ErrCookieInvalid or ErrCookieExpired if the value is rejected.
+func (s *SecureCookie) Decode(name, encoded string) ([]byte, error) {
+	if len(encoded) > maxCookieSize {
+		return nil, ErrCookieInvalid
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(raw) < sha256.Size {
+		return nil, ErrCookieInvalid
+	}
+	data, mac := raw[:len(raw)-sha256.Size], raw[len(raw)-sha256.Size:]
+	for _, key := range s.keys {
+		if !hmac.Equal(mac, cookieMAC(key.hash, name, data)) {
+			continue
+		}
+		payload := data
+		if key.aead != nil {
+			n := key.aead.NonceSize()
+			if len(payload) < n {
+				return nil, ErrCookieInvalid
+			}
+			payload, err = key.aead.Open(nil, payload[:n], payload[n:], []byte(name))
+			if err != nil {
+				return nil, ErrCookieInvalid
+			}
+		}
+		if len(payload) < 8 {
+			return nil, ErrCookieInvalid
+		}
+		created := time.Unix(int64(binary.BigEndian.Uint64(payload)), 0)
+		if s.maxAge > 0 && s.now().Sub(created) > s.maxAge {
+			return nil, ErrCookieExpired
+		}
+		return payload[8:], nil
+	}
+	return nil, ErrCookieInvalid
+}
+
+// Write encodes a value and sets it as a cookie on the response.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - name: The cookie name.
+//   - value: The value to store.
+//
+// Returns:
+//   - error: An error if the value cannot be encoded.
+func (s *SecureCookie) Write(
+	w http.ResponseWriter, name string, value []byte,
+) error {
+	encoded, err := s.Encode(name, value)
+	if err != nil {
+		return err
+	}
+	c := s.cookie(name, encoded)
+	if s.maxAge > 0 {
+		c.MaxAge = int(s.maxAge / time.Second)
+		c.Expires = s.now().Add(s.maxAge)
+	}
+	http.SetCookie(w, c)
+	return nil
+}
+
+// Read reads and decodes a cookie from the request.
+//
+// Parameters:
+//   - r: The HTTP request.
+//   - name: The cookie name.
+//
+// Returns:
+//   - []byte: The decoded value.
+//   - error: http.ErrNoCookie if the cookie is missing, or a Decode error.
+func (s *SecureCookie) Read(r *http.Request, name string) ([]byte, error) {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.Decode(name, c.Value)
+}
+
+// Delete expires a cookie written by Write.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - name: The cookie name.
+func (s *SecureCookie) Delete(w http.ResponseWriter, name string) {
+	c := s.cookie(name, "")
+	c.MaxAge = -1
+	c.Expires = time.Unix(0, 0)
+	http.SetCookie(w, c)
+}
+
+// cookie returns a cookie with the configured attributes.
+func (s *SecureCookie) cookie(name, value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     s.path,
+		Domain:   s.domain,
+		Secure:   !s.insecure,
+		HttpOnly: !s.scriptAccess,
+		SameSite: s.sameSite,
+	}
+}
+
+// cookieMAC returns the HMAC-SHA256 of the cookie name and data.
+func cookieMAC(key []byte, name string, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name))
+	mac.Write([]byte{0})
+	mac.Write(data)
+	return mac.Sum(nil)
+}
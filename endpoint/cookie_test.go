@@ -0,0 +1,127 @@
+package endpoint
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testHashKey  = bytes.Repeat([]byte("h"), 32)
+	testBlockKey = bytes.Repeat([]byte("b"), 32)
+)
+
+func TestSecureCookie_RoundTrip(t *testing.T) {
+	for _, key := range []CookieKey{
+		{Hash: testHashKey},
+		{Hash: testHashKey, Block: testBlockKey},
+	} {
+		sc, err := NewSecureCookie([]CookieKey{key})
+		require.NoError(t, err)
+
+		encoded, err := sc.Encode("session", []byte("user-1"))
+		require.NoError(t, err)
+		if key.Block != nil {
+			assert.NotContains(t, encoded, "user-1")
+		}
+		value, err := sc.Decode("session", encoded)
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", string(value))
+
+		_, err = sc.Decode("other", encoded)
+		assert.ErrorIs(t, err, ErrCookieInvalid)
+		tampered := []byte(encoded)
+		tampered[len(tampered)/2] ^= 1
+		_, err = sc.Decode("session", string(tampered))
+		assert.ErrorIs(t, err, ErrCookieInvalid)
+	}
+}
+
+func TestSecureCookie_KeyRotation(t *testing.T) {
+	oldKey := CookieKey{Hash: testHashKey, Block: testBlockKey}
+	newKey := CookieKey{Hash: bytes.Repeat([]byte("n"), 32)}
+	old, err := NewSecureCookie([]CookieKey{oldKey})
+	require.NoError(t, err)
+	rotated, err := NewSecureCookie([]CookieKey{newKey, oldKey})
+	require.NoError(t, err)
+
+	encoded, err := old.Encode("c", []byte("v"))
+	require.NoError(t, err)
+	value, err := rotated.Decode("c", encoded)
+	require.NoError(t, err)
+	assert.Equal(t, "v", string(value))
+
+	encoded, err = rotated.Encode("c", []byte("v"))
+	require.NoError(t, err)
+	_, err = old.Decode("c", encoded)
+	assert.ErrorIs(t, err, ErrCookieInvalid)
+}
+
+func TestSecureCookie_MaxAge(t *testing.T) {
+	sc, err := NewSecureCookie(
+		[]CookieKey{{Hash: testHashKey}}, WithCookieMaxAge(time.Hour),
+	)
+	require.NoError(t, err)
+	now := time.Unix(1_700_000_000, 0)
+	sc.now = func() time.Time { return now }
+
+	encoded, err := sc.Encode("c", []byte("v"))
+	require.NoError(t, err)
+	now = now.Add(2 * time.Hour)
+	_, err = sc.Decode("c", encoded)
+	assert.ErrorIs(t, err, ErrCookieExpired)
+}
+
+func TestSecureCookie_WriteRead(t *testing.T) {
+	sc, err := NewSecureCookie(
+		[]CookieKey{{Hash: testHashKey}},
+		WithCookieMaxAge(time.Minute), WithCookieDomain("example.com"),
+	)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, sc.Write(rec, "session", []byte("v")))
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	c := cookies[0]
+	assert.True(t, c.Secure)
+	assert.True(t, c.HttpOnly)
+	assert.Equal(t, http.SameSiteLaxMode, c.SameSite)
+	assert.Equal(t, "/", c.Path)
+	assert.Equal(t, "example.com", c.Domain)
+	assert.Equal(t, 60, c.MaxAge)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	_, err = sc.Read(r, "session")
+	assert.ErrorIs(t, err, http.ErrNoCookie)
+	r.AddCookie(c)
+	value, err := sc.Read(r, "session")
+	require.NoError(t, err)
+	assert.Equal(t, "v", string(value))
+
+	rec = httptest.NewRecorder()
+	sc.Delete(rec, "session")
+	assert.Equal(t, -1, rec.Result().Cookies()[0].MaxAge)
+}
+
+func TestSecureCookie_Errors(t *testing.T) {
+	_, err := NewSecureCookie(nil)
+	assert.EqualError(t, err, "secure cookie: no keys")
+	_, err = NewSecureCookie([]CookieKey{{}})
+	assert.EqualError(t, err, "secure cookie: key 0: empty hash key")
+	_, err = NewSecureCookie([]CookieKey{{Hash: testHashKey, Block: []byte("short")}})
+	assert.ErrorContains(t, err, "secure cookie: key 0: crypto/aes: invalid key size")
+
+	sc, err := NewSecureCookie([]CookieKey{{Hash: testHashKey}})
+	require.NoError(t, err)
+	_, err = sc.Encode("c", []byte(strings.Repeat("x", maxCookieSize)))
+	assert.ErrorIs(t, err, ErrCookieTooLarge)
+	_, err = sc.Decode("c", "not base64!")
+	assert.ErrorIs(t, err, ErrCookieInvalid)
+}
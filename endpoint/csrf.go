@@ -0,0 +1,282 @@
+package endpoint
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"mime"
+	"net/http"
+	"slices"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// ErrIDCSRFInvalid is the API error ID of requests rejected by
+// CSRFMiddleware.
+const ErrIDCSRFInvalid = "csrf_invalid"
+
+// csrfTokenSize is the number of random bytes in a CSRF token.
+const csrfTokenSize = 32
+
+// csrfTokenKey is the context key of the request's CSRF token.
+type csrfTokenKey struct{}
+
+// CSRFOption configures CSRFMiddleware.
+type CSRFOption func(*csrfConfig)
+
+// csrfConfig holds the CSRF middleware settings.
+type csrfConfig struct {
+	cookieName string
+	header     string
+	formField  string
+	cookie     *SecureCookie
+	insecure   bool
+	exempt     []func(*http.Request) bool
+}
+
+// WithCSRFCookieName sets the name of the token cookie. The default is
+// "csrf_token".
+//
+// Parameters:
+//   - name: The cookie name.
+//
+// Returns:
+//   - CSRFOption: The option.
+func WithCSRFCookieName(name string) CSRFOption {
+	return func(c *csrfConfig) { c.cookieName = name }
+}
+
+// WithCSRFHeader sets the request header carrying the token. The default
+// is "X-CSRF-Token".
+//
+// Parameters:
+//   - header: The header name.
+//
+// Returns:
+//   - CSRFOption: The option.
+func WithCSRFHeader(header string) CSRFOption {
+	return func(c *csrfConfig) { c.header = header }
+}
+
+// WithCSRFFormField sets the url-encoded form field carrying the token
+// when the header is absent. The default is "csrf_token"; an empty name
+// disables form lookup.
+//
+// Parameters:
+//   - field: The form field name.
+//
+// Returns:
+//   - CSRFOption: The option.
+func WithCSRFFormField(field string) CSRFOption {
+	return func(c *csrfConfig) { c.formField = field }
+}
+
+// WithCSRFSecureCookie stores the token in a cookie signed by sc, so it
+// cannot be planted by a sibling subdomain. The cookie then uses the
+// attributes of sc and, being HttpOnly by default, clients read the token
+// from a page rendered with CSRFToken rather than from the cookie.
+//
+// Parameters:
+//   - sc: The secure cookie codec.
+//
+// Returns:
+//   - CSRFOption: The option.
+func WithCSRFSecureCookie(sc *SecureCookie) CSRFOption {
+	return func(c *csrfConfig) { c.cookie = sc }
+}
+
+// WithCSRFInsecureCookie clears the Secure attribute of the plain token
+// cookie, e.g. for local development over HTTP.
+//
+// Returns:
+//   - CSRFOption: The option.
+func WithCSRFInsecureCookie() CSRFOption {
+	return func(c *csrfConfig) { c.insecure = true }
+}
+
+// WithCSRFExempt skips token verification for requests matching fn, e.g.
+// webhooks authenticated by signatures. Multiple exemptions are combined.
+//
+// Parameters:
+//   - fn: Reports whether a request is exempt.
+//
+// Returns:
+//   - CSRFOption: The option.
+func WithCSRFExempt(fn func(r *http.Request) bool) CSRFOption {
+	return func(c *csrfConfig) { c.exempt = append(c.exempt, fn) }
+}
+
+// WithCSRFExemptPaths skips token verification for requests to the given
+// URL paths.
+//
+// Parameters:
+//   - paths: The exempt paths.
+//
+// Returns:
+//   - CSRFOption: The option.
+func WithCSRFExemptPaths(paths ...string) CSRFOption {
+	return WithCSRFExempt(func(r *http.Request) bool {
+		return slices.Contains(paths, r.URL.Path)
+	})
+}
+
+// CSRFMiddleware returns a middleware protecting browser-facing endpoints
+// with the double-submit cookie pattern. Every request gets a token cookie,
+// issued when missing or invalid, and the token is available to handlers
+// through CSRFToken. Requests with unsafe methods (anything but GET, HEAD,
+// OPTIONS and TRACE) must echo the token in the X-CSRF-Token header or, for
+// url-encoded form bodies, the csrf_token form field, otherwise they are
+// rejected with a 403 csrf_invalid API error. Multipart bodies must use the
+// header: they are left unread so that MultipartInput and
+// StreamMultipartInput can still stream them.
+//
+// By default the cookie holds the plain token and is readable by scripts,
+// so single-page apps can copy it into the header.
+//
+// Parameters:
+//   - opts: Optional CSRF options.
+//
+// Returns:
+//   - Middleware: The CSRF middleware.
+func CSRFMiddleware(opts ...CSRFOption) Middleware {
+	cfg := csrfConfig{
+		cookieName: "csrf_token",
+		header:     "X-CSRF-Token",
+		formField:  "csrf_token",
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := cfg.readToken(r)
+			if !ok {
+				var err error
+				if token, err = cfg.issueToken(w); err != nil {
+					writeAPIError(w, r, http.StatusInternalServerError,
+						apierror.NewAPIError("internal_error").
+							WithMessage("Internal server error"))
+					return
+				}
+			}
+			r = r.WithContext(context.WithValue(r.Context(), csrfTokenKey{}, token))
+
+			if !safeMethod(r.Method) && !cfg.isExempt(r) {
+				submitted := cfg.submittedToken(r)
+				if !ok || subtle.ConstantTimeCompare(
+					[]byte(submitted), []byte(token),
+				) != 1 {
+					writeAPIError(w, r, http.StatusForbidden,
+						apierror.NewAPIError(ErrIDCSRFInvalid).
+							WithMessage("missing or invalid CSRF token"))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CSRFToken returns the CSRF token of a request handled by CSRFMiddleware,
+// e.g. to render it into a form's hidden csrf_token field.
+//
+// Parameters:
+//   - r: The HTTP request.
+//
+// Returns:
+//   - string: The token, or "" outside CSRFMiddleware.
+func CSRFToken(r *http.Request) string {
+	token, _ := r.Context().Value(csrfTokenKey{}).(string)
+	return token
+}
+
+// NewCSRFToken generates a random CSRF token.
+//
+// Returns:
+//   - string: The base64url encoded token.
+//   - error: An error if the random source fails.
+func NewCSRFToken() (string, error) {
+	b := make([]byte, csrfTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// readToken returns the token stored in the request's cookie, if valid.
+func (c *csrfConfig) readToken(r *http.Request) (string, bool) {
+	var token string
+	if c.cookie != nil {
+		value, err := c.cookie.Read(r, c.cookieName)
+		if err != nil {
+			return "", false
+		}
+		token = string(value)
+	} else {
+		cookie, err := r.Cookie(c.cookieName)
+		if err != nil {
+			return "", false
+		}
+		token = cookie.Value
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(b) != csrfTokenSize {
+		return "", false
+	}
+	return token, true
+}
+
+// issueToken generates a token and sets the token cookie.
+func (c *csrfConfig) issueToken(w http.ResponseWriter) (string, error) {
+	token, err := NewCSRFToken()
+	if err != nil {
+		return "", err
+	}
+	if c.cookie != nil {
+		return token, c.cookie.Write(w, c.cookieName, []byte(token))
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     c.cookieName,
+		Value:    token,
+		Path:     "/",
+		Secure:   !c.insecure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return token, nil
+}
+
+// submittedToken returns the token echoed by the client. Only url-encoded
+// forms are parsed, as parsing a multipart body would consume it.
+func (c *csrfConfig) submittedToken(r *http.Request) string {
+	if token := r.Header.Get(c.header); token != "" {
+		return token
+	}
+	if c.formField == "" {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if isFormMediaType(mediaType) {
+		return r.PostFormValue(c.formField)
+	}
+	return ""
+}
+
+// isExempt reports whether any exemption matches the request.
+func (c *csrfConfig) isExempt(r *http.Request) bool {
+	for _, fn := range c.exempt {
+		if fn(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// safeMethod reports whether a method is safe per RFC 9110.
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func csrfHandler(opts ...CSRFOption) http.Handler {
+	return CSRFMiddleware(opts...)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(CSRFToken(r)))
+		},
+	))
+}
+
+func TestCSRFMiddleware(t *testing.T) {
+	h := csrfHandler(WithCSRFExemptPaths("/webhook"))
+
+	// A safe request issues a token cookie.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	cookie := cookies[0]
+	assert.Equal(t, "csrf_token", cookie.Name)
+	assert.True(t, cookie.Secure)
+	assert.False(t, cookie.HttpOnly)
+	assert.Equal(t, cookie.Value, rec.Body.String())
+	token := cookie.Value
+
+	form := url.Values{"csrf_token": {token}}.Encode()
+	testCases := []struct {
+		name       string
+		path       string
+		header     string
+		form       string
+		cookie     bool
+		wantStatus int
+	}{
+		{name: "Header", header: token, cookie: true, wantStatus: http.StatusOK},
+		{name: "Form field", form: form, cookie: true, wantStatus: http.StatusOK},
+		{name: "Missing token", cookie: true, wantStatus: http.StatusForbidden},
+		{name: "Wrong token", header: token[1:] + "A", cookie: true, wantStatus: http.StatusForbidden},
+		{name: "Missing cookie", header: token, wantStatus: http.StatusForbidden},
+		{name: "Exempt", path: "/webhook", wantStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := tc.path
+			if path == "" {
+				path = "/"
+			}
+			r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(tc.form))
+			if tc.form != "" {
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			if tc.header != "" {
+				r.Header.Set("X-CSRF-Token", tc.header)
+			}
+			if tc.cookie {
+				r.AddCookie(cookie)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			if tc.wantStatus == http.StatusForbidden {
+				assert.JSONEq(t,
+					`{"id":"csrf_invalid","message":"missing or invalid CSRF token"}`,
+					rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCSRFMiddleware_SecureCookie(t *testing.T) {
+	sc, err := NewSecureCookie([]CookieKey{{Hash: testHashKey}})
+	require.NoError(t, err)
+	h := csrfHandler(WithCSRFSecureCookie(sc), WithCSRFCookieName("_csrf"))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	cookie := rec.Result().Cookies()[0]
+	token := rec.Body.String()
+	assert.True(t, cookie.HttpOnly)
+	assert.NotEqual(t, token, cookie.Value)
+
+	r := httptest.NewRequest(http.MethodDelete, "/", nil)
+	r.AddCookie(cookie)
+	r.Header.Set("X-CSRF-Token", token)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// A planted unsigned cookie is replaced.
+	r = httptest.NewRequest(http.MethodPost, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "_csrf", Value: token})
+	r.Header.Set("X-CSRF-Token", token)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Len(t, rec.Result().Cookies(), 1)
+}
+
+func TestCSRFMiddleware_Multipart(t *testing.T) {
+	h := CSRFMiddleware()(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			in, err := MultipartInput[multipartTestInput]().Handle(w, r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(in.Title))
+		},
+	))
+	token, err := NewCSRFToken()
+	require.NoError(t, err)
+	newRequest := func() *http.Request {
+		r := newMultipartRequest(t,
+			multipartTestPart{name: "csrf_token", content: token},
+			multipartTestPart{name: "title", content: "Hello"},
+		)
+		r.AddCookie(&http.Cookie{Name: "csrf_token", Value: token})
+		return r
+	}
+
+	// The body is left for the input handler to stream.
+	r := newRequest()
+	r.Header.Set("X-CSRF-Token", token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Hello", rec.Body.String())
+
+	// Multipart bodies must carry the token in the header.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newRequest())
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
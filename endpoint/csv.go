@@ -0,0 +1,252 @@
+package endpoint
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// CSVOption configures the CSV encoder and output handler.
+type CSVOption func(*csvConfig)
+
+// WithCSVDelimiter sets the field delimiter. Defaults to a comma.
+//
+// Parameters:
+//   - delimiter: The field delimiter, e.g. ';' or '\t'.
+//
+// Returns:
+//   - CSVOption: A CSV option function.
+func WithCSVDelimiter(delimiter rune) CSVOption {
+	return func(c *csvConfig) { c.delimiter = delimiter }
+}
+
+// WithCSVHeader replaces the header row. For slices of structs the names
+// label the columns in field order; for [][]string they are written before
+// the rows. The number of names must match the number of columns.
+//
+// Parameters:
+//   - names: The header names.
+//
+// Returns:
+//   - CSVOption: A CSV option function.
+func WithCSVHeader(names ...string) CSVOption {
+	return func(c *csvConfig) { c.header = names }
+}
+
+// WithCSVNoHeader omits the header row of slices of structs.
+//
+// Returns:
+//   - CSVOption: A CSV option function.
+func WithCSVNoHeader() CSVOption {
+	return func(c *csvConfig) { c.noHeader = true }
+}
+
+// WithCSVFilename makes CSVOutput send successful responses as a download
+// with a Content-Disposition attachment header.
+//
+// Parameters:
+//   - filename: The suggested file name, e.g. "users.csv".
+//
+// Returns:
+//   - CSVOption: A CSV option function.
+func WithCSVFilename(filename string) CSVOption {
+	return func(c *csvConfig) { c.filename = filename }
+}
+
+// csvConfig holds the CSV settings.
+type csvConfig struct {
+	delimiter rune
+	header    []string
+	noHeader  bool
+	filename  string
+}
+
+// CSVEncoder returns an encoder producing text/csv. It encodes [][]string
+// as is and slices of structs as a header row followed by one row per
+// element. Column names come from the "csv" struct tag, falling back to the
+// field name; fields tagged "-" are skipped. API errors are rendered as an
+// id,message table.
+//
+// Parameters:
+//   - opts: Optional CSV options.
+//
+// Returns:
+//   - Encoder: The CSV encoder.
+func CSVEncoder(opts ...CSVOption) Encoder {
+	cfg := csvConfig{delimiter: ','}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return csvEncoder{cfg: cfg}
+}
+
+// CSVOutput returns an output handler that always writes CSV, encoded like
+// CSVEncoder. With WithCSVFilename, successful responses carry a
+// Content-Disposition header so browsers download them, e.g. for export
+// endpoints.
+//
+// Parameters:
+//   - opts: Optional CSV options.
+//
+// Returns:
+//   - OutputHandler: The CSV output handler.
+func CSVOutput(opts ...CSVOption) OutputHandler {
+	enc := CSVEncoder(opts...).(csvEncoder)
+	return &csvOutput{
+		filename: enc.cfg.filename,
+		out:      negotiatingOutput{encoders: []Encoder{enc}},
+	}
+}
+
+// csvOutput writes CSV responses.
+type csvOutput struct {
+	filename string
+	out      negotiatingOutput
+}
+
+// Handle writes the output or error as CSV.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//   - out: The output value.
+//   - outputError: The error to render instead of out, if any.
+//   - statusCode: The response status code.
+//
+// Returns:
+//   - error: An error if encoding or writing fails.
+func (o *csvOutput) Handle(
+	w http.ResponseWriter,
+	r *http.Request,
+	out any,
+	outputError error,
+	statusCode int,
+) error {
+	if o.filename != "" && outputError == nil {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType(
+			"attachment", map[string]string{"filename": o.filename},
+		))
+	}
+	return o.out.Handle(w, r, out, outputError, statusCode)
+}
+
+// csvEncoder encodes tabular values as CSV.
+type csvEncoder struct {
+	cfg csvConfig
+}
+
+// ContentType returns the CSV content type.
+func (csvEncoder) ContentType() string { return "text/csv; charset=utf-8" }
+
+// Encode writes v as CSV.
+func (e csvEncoder) Encode(w io.Writer, v any) error {
+	rows, err := e.rows(v)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = e.cfg.delimiter
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("csvEncoder: %w", err)
+	}
+	return nil
+}
+
+// rows converts a value to CSV records with the configured header.
+func (e csvEncoder) rows(v any) ([][]string, error) {
+	if _, ok := v.(apierror.APIError); ok {
+		return csvRows(v)
+	}
+	if data, ok := v.([][]string); ok {
+		if e.cfg.header != nil {
+			return append([][]string{e.cfg.header}, data...), nil
+		}
+		return data, nil
+	}
+	rows, err := csvRows(v)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case e.cfg.noHeader:
+		return rows[1:], nil
+	case e.cfg.header != nil:
+		if len(e.cfg.header) != len(rows[0]) {
+			return nil, fmt.Errorf(
+				"csvEncoder: header has %d names for %d columns",
+				len(e.cfg.header), len(rows[0]),
+			)
+		}
+		rows[0] = e.cfg.header
+	}
+	return rows, nil
+}
+
+// csvRows converts a value to CSV records.
+func csvRows(v any) ([][]string, error) {
+	switch t := v.(type) {
+	case [][]string:
+		return t, nil
+	case apierror.APIError:
+		return [][]string{{"id", "message"}, {t.ID(), t.Message()}}, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("csvEncoder: unsupported type %T", v)
+	}
+	elem := rv.Type().Elem()
+	for elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("csvEncoder: unsupported element type %s", elem)
+	}
+	cols := csvColumns(elem)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.name
+	}
+	rows := [][]string{header}
+	for i := 0; i < rv.Len(); i++ {
+		item := reflect.Indirect(rv.Index(i))
+		row := make([]string, len(cols))
+		if item.IsValid() {
+			for j, c := range cols {
+				row[j] = fmt.Sprint(item.Field(c.index).Interface())
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// csvColumn maps a struct field to a CSV column.
+type csvColumn struct {
+	name  string
+	index int
+}
+
+// csvColumns returns the exported columns of a struct type.
+func csvColumns(t reflect.Type) []csvColumn {
+	var cols []csvColumn
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Tag.Get("csv")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		cols = append(cols, csvColumn{name: name, index: i})
+	}
+	return cols
+}
@@ -0,0 +1,69 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type csvTestRow struct {
+	ID     int    `csv:"id"`
+	Name   string `csv:"name"`
+	Secret string `csv:"-"`
+}
+
+func TestCSVOutput(t *testing.T) {
+	rows := []csvTestRow{{1, "Ada", "x"}, {2, "Lin; Go", "y"}}
+	testCases := []struct {
+		name string
+		opts []CSVOption
+		out  any
+		want string
+	}{
+		{name: "Default", out: rows, want: "id,name\n1,Ada\n2,Lin; Go\n"},
+		{name: "Delimiter", opts: []CSVOption{WithCSVDelimiter(';')}, out: rows,
+			want: "id;name\n1;Ada\n2;\"Lin; Go\"\n"},
+		{name: "Header", opts: []CSVOption{WithCSVHeader("ID", "Full name")}, out: rows,
+			want: "ID,Full name\n1,Ada\n2,Lin; Go\n"},
+		{name: "No header", opts: []CSVOption{WithCSVNoHeader()}, out: rows,
+			want: "1,Ada\n2,Lin; Go\n"},
+		{name: "Records with header", opts: []CSVOption{WithCSVHeader("a", "b")},
+			out: [][]string{{"1", "2"}}, want: "a,b\n1,2\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			err := CSVOutput(tc.opts...).Handle(rec, httptest.NewRequest(http.MethodGet, "/", nil), tc.out, nil, http.StatusOK)
+			require.NoError(t, err)
+			assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+			assert.Empty(t, rec.Header().Get("Content-Disposition"))
+			assert.Equal(t, tc.want, rec.Body.String())
+		})
+	}
+}
+
+func TestCSVOutput_Filename(t *testing.T) {
+	oh := CSVOutput(WithCSVFilename("users export.csv"))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, oh.Handle(rec, req, []csvTestRow{{1, "Ada", ""}}, nil, http.StatusOK))
+	assert.Equal(t, `attachment; filename="users export.csv"`, rec.Header().Get("Content-Disposition"))
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, oh.Handle(rec, req, nil, errors.New("boom"), http.StatusInternalServerError))
+	assert.Empty(t, rec.Header().Get("Content-Disposition"))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "id,message\ninternal_error,Internal server error\n", rec.Body.String())
+}
+
+func TestCSVOutput_HeaderMismatch(t *testing.T) {
+	err := CSVOutput(WithCSVHeader("only")).Handle(httptest.NewRecorder(),
+		httptest.NewRequest(http.MethodGet, "/", nil), []csvTestRow{{1, "Ada", ""}}, nil, http.StatusOK)
+	assert.EqualError(t, err, "csvEncoder: header has 1 names for 2 columns")
+}
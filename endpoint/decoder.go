@@ -0,0 +1,127 @@
+package endpoint
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// Decoder deserializes request bodies of some media types. It is the input
+// counterpart of Encoder.
+type Decoder interface {
+	// MediaTypes returns the media types the decoder accepts, e.g.
+	// "application/json".
+	MediaTypes() []string
+	// Decode reads a body from r into v. Malformed bodies should be
+	// reported as API errors, other errors become invalid_input.
+	Decode(r io.Reader, v any) error
+}
+
+// JSONDecoder returns a decoder for application/json bodies, decoding like
+// JSONInput with the given options.
+//
+// Parameters:
+//   - opts: Optional JSON input options.
+//
+// Returns:
+//   - Decoder: The JSON decoder.
+func JSONDecoder(opts ...JSONInputOption) Decoder {
+	cfg := jsonInput{maxDepth: 32}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return jsonDecoder{cfg: cfg}
+}
+
+// jsonDecoder decodes JSON bodies.
+type jsonDecoder struct {
+	cfg jsonInput
+}
+
+// jsonDecoder implements the Decoder interface.
+var _ Decoder = jsonDecoder{}
+
+// MediaTypes returns the JSON media type.
+func (jsonDecoder) MediaTypes() []string { return []string{"application/json"} }
+
+// Decode reads and decodes a JSON body.
+func (d jsonDecoder) Decode(r io.Reader, v any) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return d.cfg.decodeBody(body, v)
+}
+
+// NegotiatingInput returns an input handler choosing the decoder by the
+// request's Content-Type. Requests without or with an unknown Content-Type
+// are rejected with unsupported_media_type; body limit violations become
+// request_too_large as with JSONInput.
+//
+// Parameters:
+//   - decoders: The available decoders.
+//
+// Returns:
+//   - InputHandler[Input]: The negotiating input handler.
+func NegotiatingInput[Input any](decoders ...Decoder) InputHandler[Input] {
+	if len(decoders) == 0 {
+		decoders = []Decoder{JSONDecoder()}
+	}
+	return &negotiatingInput[Input]{decoders: decoders}
+}
+
+// negotiatingInput decodes bodies with the decoder matching the request.
+type negotiatingInput[Input any] struct {
+	decoders []Decoder
+}
+
+// Handle decodes the request body.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//
+// Returns:
+//   - *Input: The decoded input.
+//   - error: An API error if the body cannot be decoded.
+func (h *negotiatingInput[Input]) Handle(
+	_ http.ResponseWriter, r *http.Request,
+) (*Input, error) {
+	var dec Decoder
+	if err := checkContentType(r, false, func(mediaType string) bool {
+		dec = h.decoder(mediaType)
+		return dec != nil
+	}); err != nil {
+		return nil, err
+	}
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	var in Input
+	if err := dec.Decode(bytes.NewReader(body), &in); err != nil {
+		var apiErr apierror.APIError
+		if errors.As(err, &apiErr) {
+			return nil, err
+		}
+		return nil, invalidInput("malformed request body")
+	}
+	return &in, nil
+}
+
+// decoder returns the decoder accepting the media type, or nil. Decoders
+// for application/json also accept +json media types.
+func (h *negotiatingInput[Input]) decoder(mediaType string) Decoder {
+	for _, d := range h.decoders {
+		for _, mt := range d.MediaTypes() {
+			if mt == mediaType ||
+				(mt == "application/json" && isJSONMediaType(mediaType)) {
+				return d
+			}
+		}
+	}
+	return nil
+}
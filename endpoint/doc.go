@@ -20,7 +20,7 @@
 //	}
 //
 //	// Input handler: parse JSON request body
-//	inputHandler := inputJSON[UserRequest]()
+//	inputHandler := JSONInput[UserRequest](WithJSONStrict())
 //
 //	// Business logic: create user
 //	businessLogic := func(ctx context.Context, req UserRequest) (UserResponse, error) {
@@ -34,7 +34,7 @@
 //	}
 //
 //	// Output handler: write JSON response
-//	outputHandler := outputJSON[UserResponse]()
+//	outputHandler := JSONOutput()
 //
 //	// Create the endpoint handler
 //	handler := NewHandler(inputHandler, businessLogic, errorHandler, outputHandler)
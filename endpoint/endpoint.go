@@ -16,17 +16,32 @@ type Endpoint interface {
 	WithHandler(http.HandlerFunc) Endpoint
 }
 
+// BodyLimiter is implemented by endpoints declaring their own maximum request
+// body size. A positive limit overrides the server-wide limit for the route, a
+// negative limit disables it and zero inherits it.
+type BodyLimiter interface {
+	BodyLimit() int64
+}
+
 // DefaultEndpoint represents an API endpoint with middlewares.
 type DefaultEndpoint struct {
 	URLVal         string
 	MethodVal      string
 	MiddlewaresVal Middlewares
 	HandlerVal     http.HandlerFunc // Optional handler for the endpoint.
+	BodyLimitVal   int64            // Optional request body limit in bytes.
+	OperationVal   Operation        // Optional API documentation.
 }
 
 // defaultEndpoint implements the Endpoint interface.
 var _ Endpoint = (*DefaultEndpoint)(nil)
 
+// DefaultEndpoint implements the BodyLimiter interface.
+var _ BodyLimiter = (*DefaultEndpoint)(nil)
+
+// DefaultEndpoint implements the Documented interface.
+var _ Documented = (*DefaultEndpoint)(nil)
+
 // NewEndpoint creates a new DefaultEndpoint with the given details.
 //
 // Parameters:
@@ -80,6 +95,22 @@ func (e *DefaultEndpoint) Handler() http.HandlerFunc {
 	return e.HandlerVal
 }
 
+// BodyLimit returns the request body limit of the endpoint.
+//
+// Returns:
+//   - int64: The limit in bytes, 0 if the server-wide limit applies.
+func (e *DefaultEndpoint) BodyLimit() int64 {
+	return e.BodyLimitVal
+}
+
+// Operation returns the API documentation of the endpoint.
+//
+// Returns:
+//   - Operation: The operation.
+func (e *DefaultEndpoint) Operation() Operation {
+	return e.OperationVal
+}
+
 // WithURL sets the URL of the endpoint. It returns a new endpoint.
 //
 // Parameters:
@@ -132,3 +163,32 @@ func (e *DefaultEndpoint) WithHandler(handler http.HandlerFunc) Endpoint {
 	new.HandlerVal = handler
 	return &new
 }
+
+// WithBodyLimit sets the request body limit of the endpoint, overriding the
+// server-wide limit. Use a negative value to disable the limit for the route.
+// It returns a new endpoint.
+//
+// Parameters:
+//   - limit: The maximum request body size in bytes.
+//
+// Returns:
+//   - Endpoint: A new Endpoint.
+func (e *DefaultEndpoint) WithBodyLimit(limit int64) Endpoint {
+	new := *e
+	new.BodyLimitVal = limit
+	return &new
+}
+
+// WithOperation sets the API documentation of the endpoint. It returns a new
+// endpoint.
+//
+// Parameters:
+//   - op: The operation, e.g. built with OperationOf.
+//
+// Returns:
+//   - Endpoint: A new Endpoint.
+func (e *DefaultEndpoint) WithOperation(op Operation) Endpoint {
+	new := *e
+	new.OperationVal = op
+	return &new
+}
@@ -112,3 +112,27 @@ func TestEndpointWithHandler(t *testing.T) {
 		"Response status code should be 200 OK",
 	)
 }
+
+// TestWithBodyLimit tests that WithBodyLimit returns a new endpoint carrying
+// the limit.
+func TestWithBodyLimit(t *testing.T) {
+	original := NewEndpoint("/upload", http.MethodPost)
+	updated := original.WithBodyLimit(50 << 20)
+
+	bl, ok := updated.(BodyLimiter)
+	if !ok {
+		t.Fatalf("expected endpoint to implement BodyLimiter")
+	}
+	if bl.BodyLimit() != 50<<20 {
+		t.Errorf("expected limit %d, got %d", 50<<20, bl.BodyLimit())
+	}
+	if original.BodyLimit() != 0 {
+		t.Errorf("expected original limit to be unchanged, got %d", original.BodyLimit())
+	}
+
+	// The limit survives further chaining.
+	chained := updated.WithHandler(func(http.ResponseWriter, *http.Request) {})
+	if chained.(BodyLimiter).BodyLimit() != 50<<20 {
+		t.Errorf("expected limit to survive chaining")
+	}
+}
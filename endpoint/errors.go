@@ -0,0 +1,259 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// ErrorRegistry maps errors to status codes and public API errors. API
+// errors dictating their status with apierror.StatusCoder, such as
+// DefaultAPIError.WithStatus, keep it. Other errors are matched in this
+// order: sentinels (errors.Is), error types (errors.As), API error IDs
+// (errors.As on apierror.APIError), and context cancellation as the
+// request_canceled and deadline_exceeded IDs. IDs are looked up in the
+// registry's own mappings, then among those registered with
+// apierror.RegisterStatus, then among the defaults. Unmatched errors
+// become a 500 internal_error that reveals nothing about the cause.
+//
+// Registries are immutable; the With methods return modified copies, so
+// build them during startup.
+type ErrorRegistry struct {
+	ids       map[string]int
+	sentinels []sentinelMapping
+	types     []func(err error) (int, apierror.APIError, bool)
+}
+
+// sentinelMapping maps a sentinel error to a response.
+type sentinelMapping struct {
+	target error
+	status int
+	public apierror.APIError
+}
+
+// defaultErrorIDs are the API error IDs mapped by NewErrorRegistry.
+var defaultErrorIDs = map[string]int{
+	ErrIDValidation:           http.StatusBadRequest,
+	ErrIDInvalidInput:         http.StatusBadRequest,
+	"not_found":               http.StatusNotFound,
+	"resource_not_found":      http.StatusNotFound,
+	ErrIDUnauthorized:         http.StatusUnauthorized,
+	ErrIDForbidden:            http.StatusForbidden,
+	ErrIDCSRFInvalid:          http.StatusForbidden,
+	ErrIDRequestTimeout:       http.StatusRequestTimeout,
+	"conflict":                http.StatusConflict,
+	ErrIDRequestTooLarge:      http.StatusRequestEntityTooLarge,
+	ErrIDUnsupportedMediaType: http.StatusUnsupportedMediaType,
+	ErrIDTooManyRequests:      http.StatusTooManyRequests,
+	ErrIDNotAcceptable:        http.StatusNotAcceptable,
+	ErrIDRequestCanceled:      StatusClientClosedRequest,
+	ErrIDCircuitOpen:          http.StatusServiceUnavailable,
+	apierror.IDUnavailable:    http.StatusServiceUnavailable,
+	ErrIDDeadlineExceeded:     http.StatusGatewayTimeout,
+}
+
+// defaultErrorRegistry backs the zero DefaultErrorHandler.
+var defaultErrorRegistry = NewErrorRegistry()
+
+// NewErrorRegistry returns a registry with the default ID mappings, which
+// apierror.RegisterStatus and WithID may override:
+// validation_error and invalid_input to 400, unauthorized to 401,
+// forbidden and csrf_invalid to 403, not_found and resource_not_found to
+// 404, not_acceptable to 406, request_timeout to 408, conflict to 409,
+// request_too_large to 413, unsupported_media_type to 415,
+// too_many_requests to 429, request_canceled to 499, circuit_open and
+// service_unavailable to 503 and deadline_exceeded to 504.
+//
+// Returns:
+//   - *ErrorRegistry: A new ErrorRegistry instance.
+func NewErrorRegistry() *ErrorRegistry {
+	return &ErrorRegistry{}
+}
+
+// WithID maps API errors with the given ID to a status code. The API error
+// itself is returned to the client.
+//
+// Parameters:
+//   - id: The API error ID.
+//   - status: The HTTP status code.
+//
+// Returns:
+//   - *ErrorRegistry: A new registry instance.
+func (e *ErrorRegistry) WithID(id string, status int) *ErrorRegistry {
+	new := *e
+	new.ids = make(map[string]int, len(e.ids)+1)
+	for k, v := range e.ids {
+		new.ids[k] = v
+	}
+	new.ids[id] = status
+	return &new
+}
+
+// WithSentinel maps errors matching target with errors.Is to a status code
+// and a public API error, e.g. sql.ErrNoRows to 404 not_found.
+//
+// Parameters:
+//   - target: The sentinel error.
+//   - status: The HTTP status code.
+//   - public: The API error returned to the client.
+//
+// Returns:
+//   - *ErrorRegistry: A new registry instance.
+func (e *ErrorRegistry) WithSentinel(
+	target error, status int, public apierror.APIError,
+) *ErrorRegistry {
+	new := *e
+	new.sentinels = append(
+		append([]sentinelMapping{}, e.sentinels...),
+		sentinelMapping{target: target, status: status, public: public},
+	)
+	return &new
+}
+
+// WithErrorType maps errors of type T, found with errors.As, to a status
+// code and the API error returned by convert.
+//
+// Parameters:
+//   - e: The registry to extend.
+//   - status: The HTTP status code.
+//   - convert: Builds the public API error from the matched error.
+//
+// Returns:
+//   - *ErrorRegistry: A new registry instance.
+func WithErrorType[T error](
+	e *ErrorRegistry, status int, convert func(T) apierror.APIError,
+) *ErrorRegistry {
+	new := *e
+	new.types = append(
+		append([]func(error) (int, apierror.APIError, bool){}, e.types...),
+		func(err error) (int, apierror.APIError, bool) {
+			var target T
+			if !errors.As(err, &target) {
+				return 0, nil, false
+			}
+			return status, convert(target), true
+		},
+	)
+	return &new
+}
+
+// Handle maps an error to a status code and API error. Public API errors
+// of sentinel, type and context mappings, and the internal_error fallback,
+// wrap err as their cause, so errors.Is and errors.As still match it.
+//
+// Parameters:
+//   - err: The error to map.
+//
+// Returns:
+//   - int: The HTTP status code.
+//   - apierror.APIError: The API error returned to the client.
+func (e *ErrorRegistry) Handle(err error) (int, apierror.APIError) {
+	var apiErr apierror.APIError
+	if errors.As(err, &apiErr) {
+		if sc, ok := apiErr.(apierror.StatusCoder); ok && sc.Status() != 0 {
+			return sc.Status(), apiErr
+		}
+	}
+	for _, s := range e.sentinels {
+		if errors.Is(err, s.target) {
+			return s.status, withCause(s.public, err)
+		}
+	}
+	for _, match := range e.types {
+		if status, public, ok := match(err); ok {
+			return status, withCause(public, err)
+		}
+	}
+	if apiErr != nil {
+		if status, ok := e.status(apiErr.ID()); ok {
+			return status, apiErr
+		}
+	}
+	if public := contextAPIError(err); public != nil {
+		if status, ok := e.status(public.ID()); ok {
+			return status, withCause(public, err)
+		}
+	}
+	return http.StatusInternalServerError, apierror.NewAPIError("internal_error").
+		WithMessage("Internal server error").WithCause(err)
+}
+
+// status returns the status code of an API error ID.
+func (e *ErrorRegistry) status(id string) (int, bool) {
+	if status, ok := e.ids[id]; ok {
+		return status, true
+	}
+	if status, ok := apierror.StatusFor(id); ok {
+		return status, true
+	}
+	status, ok := defaultErrorIDs[id]
+	return status, ok
+}
+
+// withCause attaches err as the cause of a public API error, so the error
+// chain survives the mapping. API errors other than DefaultAPIError are
+// returned unchanged.
+func withCause(public apierror.APIError, err error) apierror.APIError {
+	if d, ok := public.(*apierror.DefaultAPIError); ok && d != err {
+		return d.WithCause(err)
+	}
+	return public
+}
+
+// contextAPIError returns the API error of a context cancellation, or nil.
+func contextAPIError(err error) apierror.APIError {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return apierror.NewAPIError(ErrIDDeadlineExceeded).
+			WithMessage("Request deadline exceeded")
+	case errors.Is(err, context.Canceled):
+		return apierror.NewAPIError(ErrIDRequestCanceled).
+			WithMessage("Request canceled")
+	}
+	return nil
+}
+
+// writeAPIError writes an API error as JSON, rendered by RenderError.
+// Middlewares use it to reject requests before they reach a handler.
+func writeAPIError(
+	w http.ResponseWriter, r *http.Request, status int, err apierror.APIError,
+) {
+	enc := jsonEncoder{}
+	setRetryAfter(w.Header(), status, err)
+	w.Header().Set("Content-Type", enc.ContentType())
+	w.WriteHeader(status)
+	_ = enc.Encode(w, RenderError(r, status, err))
+}
+
+// setRetryAfter sets the Retry-After header of 429 and 503 responses from
+// the delay of an apierror.Retrier in the error chain, unless the header
+// is already set.
+func setRetryAfter(h http.Header, status int, err error) {
+	if status != http.StatusTooManyRequests &&
+		status != http.StatusServiceUnavailable || h.Get("Retry-After") != "" {
+		return
+	}
+	var r apierror.Retrier
+	if !errors.As(err, &r) || r.RetryAfter() <= 0 {
+		return
+	}
+	h.Set("Retry-After", strconv.Itoa(int(math.Ceil(r.RetryAfter().Seconds()))))
+}
+
+// errorFingerprint returns the apierror.Fingerprint of a handler error,
+// taken from the API error in its chain or else from the public error with
+// the message of err, so unmapped internal errors are told apart.
+func errorFingerprint(err error, public apierror.APIError) string {
+	var apiErr apierror.APIError
+	if errors.As(err, &apiErr) {
+		return apierror.Fingerprint(apiErr)
+	}
+	if public == nil {
+		public = apierror.NewAPIError("internal_error")
+	}
+	return apierror.Fingerprint(apierror.APIErrorFrom(public).WithMessage(err.Error()))
+}
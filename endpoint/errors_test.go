@@ -0,0 +1,208 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/stretchr/testify/assert"
+)
+
+var errNoRows = errors.New("no rows")
+
+type quotaError struct{ limit int }
+
+func (e *quotaError) Error() string { return fmt.Sprintf("quota %d exceeded", e.limit) }
+
+func TestErrorRegistry(t *testing.T) {
+	reg := NewErrorRegistry().
+		WithSentinel(errNoRows, http.StatusNotFound,
+			apierror.NewAPIError("not_found").WithMessage("resource not found")).
+		WithID("payment_required", http.StatusPaymentRequired)
+	reg = WithErrorType(reg, http.StatusTooManyRequests,
+		func(e *quotaError) apierror.APIError {
+			return apierror.NewAPIError("quota_exceeded").
+				WithMessage(fmt.Sprintf("limit is %d", e.limit))
+		})
+
+	testCases := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantID     string
+		wantMsg    string
+	}{
+		{"Wrapped sentinel", fmt.Errorf("load user: %w", errNoRows),
+			http.StatusNotFound, "not_found", "resource not found"},
+		{"Error type", fmt.Errorf("call: %w", &quotaError{limit: 5}),
+			http.StatusTooManyRequests, "quota_exceeded", "limit is 5"},
+		{"Custom ID", apierror.NewAPIError("payment_required"),
+			http.StatusPaymentRequired, "payment_required", ""},
+		{"Default ID", apierror.NewAPIError("conflict").WithMessage("taken"),
+			http.StatusConflict, "conflict", "taken"},
+		{"Wrapped API error", fmt.Errorf("x: %w", apierror.NewAPIError("forbidden")),
+			http.StatusForbidden, "forbidden", ""},
+		{"Unknown ID", apierror.NewAPIError("db_secret"),
+			http.StatusInternalServerError, "internal_error", "Internal server error"},
+		{"Canceled", fmt.Errorf("query: %w", context.Canceled),
+			StatusClientClosedRequest, ErrIDRequestCanceled, "Request canceled"},
+		{"Deadline", context.DeadlineExceeded,
+			http.StatusGatewayTimeout, ErrIDDeadlineExceeded, "Request deadline exceeded"},
+		{"Plain error", errors.New("secret detail"),
+			http.StatusInternalServerError, "internal_error", "Internal server error"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, apiErr := DefaultErrorHandler{Registry: reg}.Handle(tc.err)
+			assert.Equal(t, tc.wantStatus, status)
+			assert.Equal(t, tc.wantID, apiErr.ID())
+			assert.Equal(t, tc.wantMsg, apiErr.Message())
+		})
+	}
+}
+
+func TestErrorRegistry_Immutable(t *testing.T) {
+	base := NewErrorRegistry()
+	_ = base.WithID("teapot", http.StatusTeapot).
+		WithSentinel(errNoRows, http.StatusNotFound, apierror.NewAPIError("not_found"))
+
+	status, _ := base.Handle(apierror.NewAPIError("teapot"))
+	assert.Equal(t, http.StatusInternalServerError, status)
+	status, _ = base.Handle(errNoRows)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	status, _ = DefaultErrorHandler{}.Handle(apierror.NewAPIError(ErrIDNotAcceptable))
+	assert.Equal(t, http.StatusNotAcceptable, status)
+}
+
+func TestErrorRegistry_Cause(t *testing.T) {
+	reg := NewErrorRegistry().
+		WithSentinel(errNoRows, http.StatusNotFound, apierror.NewAPIError("not_found"))
+	reg = WithErrorType(reg, http.StatusTooManyRequests,
+		func(e *quotaError) apierror.APIError { return apierror.NewAPIError("quota_exceeded") })
+
+	for _, err := range []error{
+		fmt.Errorf("load user: %w", errNoRows),
+		&quotaError{limit: 1},
+		fmt.Errorf("query: %w", context.Canceled),
+		errors.New("plain"),
+	} {
+		_, apiErr := DefaultErrorHandler{Registry: reg}.Handle(err)
+		assert.ErrorIs(t, apiErr, err)
+	}
+
+	_, apiErr := reg.Handle(fmt.Errorf("load user: %w", errNoRows))
+	assert.ErrorIs(t, apiErr, errNoRows)
+	var qe *quotaError
+	_, apiErr = reg.Handle(fmt.Errorf("call: %w", &quotaError{limit: 2}))
+	assert.ErrorAs(t, apiErr, &qe)
+	assert.Equal(t, 2, qe.limit)
+}
+
+func TestErrorRegistry_RegisteredStatus(t *testing.T) {
+	apierror.RegisterStatus("registry_test_quota", http.StatusTooManyRequests)
+
+	status, apiErr := DefaultErrorHandler{}.Handle(apierror.NewAPIError("registry_test_quota"))
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.Equal(t, "registry_test_quota", apiErr.ID())
+
+	// Mappings of the registry itself take precedence.
+	reg := NewErrorRegistry().WithID("registry_test_quota", http.StatusServiceUnavailable)
+	status, _ = reg.Handle(apierror.NewAPIError("registry_test_quota"))
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+}
+
+func TestErrorRegistry_ErrorStatus(t *testing.T) {
+	reg := NewErrorRegistry().
+		WithSentinel(errNoRows, http.StatusNotFound, apierror.NewAPIError("not_found"))
+	testCases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"Unknown ID", apierror.NewAPIError("gone").WithStatus(http.StatusGone),
+			http.StatusGone},
+		{"Overrides ID", apierror.NewAPIError("conflict").WithStatus(http.StatusLocked),
+			http.StatusLocked},
+		{"Overrides sentinel", fmt.Errorf("x: %w", apierror.NewAPIError("gone").
+			WithStatus(http.StatusGone).WithCause(errNoRows)), http.StatusGone},
+		{"Zero status", apierror.NewAPIError("conflict").WithStatus(0),
+			http.StatusConflict},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, _ := reg.Handle(tc.err)
+			assert.Equal(t, tc.wantStatus, status)
+		})
+	}
+}
+
+func TestErrorRetryAfter(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"Too many requests", apierror.NewAPIError(ErrIDTooManyRequests).
+			WithRetryAfter(1500 * time.Millisecond), "2"},
+		{"Unavailable", apierror.NewAPIError("maintenance").
+			WithStatus(http.StatusServiceUnavailable).WithRetryAfter(time.Minute), "60"},
+		{"Other status", apierror.NewAPIError("conflict").
+			WithRetryAfter(time.Minute), ""},
+		{"No delay", apierror.NewAPIError(ErrIDTooManyRequests).
+			WithRetryable(true), ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHandler(
+				&dummyInputHandler{},
+				func(http.ResponseWriter, *http.Request, *string) (any, error) {
+					return nil, tc.err
+				},
+				DefaultErrorHandler{}, JSONOutput(),
+			)
+			rec := httptest.NewRecorder()
+			h.Handle(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Equal(t, tc.want, rec.Header().Get("Retry-After"))
+			assert.Contains(t, rec.Body.String(), `"retryable":true`)
+
+			rec = httptest.NewRecorder()
+			status, apiErr := DefaultErrorHandler{}.Handle(tc.err)
+			writeAPIError(rec, httptest.NewRequest(http.MethodGet, "/", nil), status, apiErr)
+			assert.Equal(t, tc.want, rec.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestErrorFingerprint(t *testing.T) {
+	fingerprint := func(err error) string {
+		emitter := &dummyEventEmitter{}
+		h := NewHandler(
+			&dummyInputHandler{},
+			func(http.ResponseWriter, *http.Request, *string) (any, error) {
+				return nil, err
+			},
+			DefaultErrorHandler{}, JSONOutput(),
+		).WithEmitterLogger(emitter)
+		h.Handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		for _, ev := range emitter.events {
+			if ev.Type == EventError {
+				return ev.Data.(map[string]any)["fingerprint"].(string)
+			}
+		}
+		t.Fatal("no error event")
+		return ""
+	}
+
+	notFound := apierror.NotFound("user", 1)
+	assert.Equal(t, notFound.Fingerprint(), fingerprint(fmt.Errorf("x: %w", notFound)))
+	assert.Equal(t, fingerprint(errors.New("db timeout after 30s")),
+		fingerprint(errors.New("db timeout after 5s")))
+	assert.NotEqual(t, fingerprint(errors.New("db timeout")),
+		fingerprint(errors.New("disk full")))
+}
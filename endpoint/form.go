@@ -0,0 +1,119 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/aatuh/pureapi-core/internal/bind"
+)
+
+// FieldError describes an input field that failed to bind. Input handlers
+// attach a list of them as the data of invalid_input API errors.
+type FieldError struct {
+	Field   string `json:"field"`
+	In      string `json:"in,omitempty"` // path, query, header or body.
+	Message string `json:"message"`
+}
+
+// init lets clients parse the field errors of input errors with
+// apierror.ParseAPIError.
+func init() {
+	apierror.RegisterDataType[[]FieldError](ErrIDInvalidInput)
+	apierror.RegisterDataType[[]FieldError](ErrIDValidation)
+}
+
+// FormInput returns an input handler decoding
+// application/x-www-form-urlencoded bodies into Input. Fields are bound by
+// their "form" struct tag; strings, booleans, numbers, time.Duration,
+// time.Time (RFC 3339), encoding.TextUnmarshaler implementations and
+// pointers to and slices of them are supported. Failures are returned as
+// the same API errors as JSONInput; conversion failures carry a list of
+// FieldError values as data.
+//
+// Returns:
+//   - InputHandler[Input]: The form input handler.
+func FormInput[Input any]() InputHandler[Input] {
+	return &formInputHandler[Input]{}
+}
+
+// formInputHandler decodes form-urlencoded request bodies.
+type formInputHandler[Input any] struct{}
+
+// Handle decodes the request body.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//
+// Returns:
+//   - *Input: The decoded input.
+//   - error: An API error if the body cannot be decoded.
+func (h *formInputHandler[Input]) Handle(
+	_ http.ResponseWriter, r *http.Request,
+) (*Input, error) {
+	if err := checkContentType(r, false, isFormMediaType); err != nil {
+		return nil, err
+	}
+	if err := parseForm(r); err != nil {
+		return nil, err
+	}
+	var in Input
+	err := bind.Struct(&in, "form", func(name string) ([]string, bool) {
+		values, ok := r.PostForm[name]
+		return values, ok
+	})
+	if err != nil {
+		return nil, bindError(err, "")
+	}
+	return &in, nil
+}
+
+// parseForm parses the request body as a form, mapping body limit
+// violations.
+func parseForm(r *http.Request) error {
+	if err := r.ParseForm(); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return requestTooLarge(fmt.Sprintf(
+				"request body exceeds %d bytes", maxErr.Limit,
+			))
+		}
+		return invalidInput("malformed form body")
+	}
+	return nil
+}
+
+// isFormMediaType reports whether the media type is form-urlencoded.
+func isFormMediaType(mediaType string) bool {
+	return mediaType == "application/x-www-form-urlencoded"
+}
+
+// bindError converts a bind error to an invalid_input API error listing the
+// failed fields.
+func bindError(err error, in string) error {
+	fields, ok := fieldErrors(err, in)
+	if !ok {
+		return invalidInput(err.Error())
+	}
+	return invalidFields(fields)
+}
+
+// fieldErrors converts the field errors of a bind error.
+func fieldErrors(err error, in string) ([]FieldError, bool) {
+	var errs bind.Errors
+	if !errors.As(err, &errs) {
+		return nil, false
+	}
+	fields := make([]FieldError, len(errs))
+	for i, fe := range errs {
+		fields[i] = FieldError{Field: fe.Field, In: in, Message: fe.Err.Error()}
+	}
+	return fields, true
+}
+
+// invalidFields returns an invalid_input API error listing fields.
+func invalidFields(fields []FieldError) error {
+	return invalidInput("invalid input fields").WithData(fields)
+}
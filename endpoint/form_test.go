@@ -0,0 +1,92 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type formTestInput struct {
+	Name   string   `form:"name"`
+	Age    int      `form:"age"`
+	Agree  bool     `form:"agree"`
+	Colors []string `form:"color"`
+}
+
+func TestFormInput(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/?name=query",
+		strings.NewReader("name=Go&age=15&agree=on&color=red&color=blue"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	in, err := FormInput[formTestInput]().Handle(httptest.NewRecorder(), req)
+	require.NoError(t, err)
+	assert.Equal(t, &formTestInput{
+		Name: "Go", Age: 15, Agree: true, Colors: []string{"red", "blue"},
+	}, in, "query parameters are ignored")
+}
+
+func TestFormInput_Errors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		body        string
+		wantErrID   string
+		wantStatus  int
+	}{
+		{name: "Missing content type", body: "name=Go",
+			wantErrID: ErrIDUnsupportedMediaType, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "JSON content type", contentType: "application/json", body: `{}`,
+			wantErrID: ErrIDUnsupportedMediaType, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "Malformed", contentType: "application/x-www-form-urlencoded", body: "name=%zz",
+			wantErrID: ErrIDInvalidInput, wantStatus: http.StatusBadRequest},
+		{name: "Wrong type", contentType: "application/x-www-form-urlencoded", body: "age=old",
+			wantErrID: ErrIDInvalidInput, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			_, err := FormInput[formTestInput]().Handle(httptest.NewRecorder(), req)
+			require.Error(t, err)
+			apiErr, ok := err.(apierror.APIError)
+			require.True(t, ok)
+			assert.Equal(t, tc.wantErrID, apiErr.ID())
+			status, _ := DefaultErrorHandler{}.Handle(err)
+			assert.Equal(t, tc.wantStatus, status)
+		})
+	}
+}
+
+func TestFormInput_FieldErrors(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/",
+		strings.NewReader("age=old&agree=maybe"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	_, err := FormInput[formTestInput]().Handle(httptest.NewRecorder(), req)
+	apiErr, ok := err.(apierror.APIError)
+	require.True(t, ok)
+	assert.Equal(t, []FieldError{
+		{Field: "age", Message: `invalid integer "old"`},
+		{Field: "agree", Message: `invalid boolean "maybe"`},
+	}, apiErr.Data())
+}
+
+func TestFormInput_TooLarge(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/",
+		strings.NewReader("name="+strings.Repeat("a", 100)))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Body = http.MaxBytesReader(httptest.NewRecorder(), req.Body, 10)
+
+	_, err := FormInput[formTestInput]().Handle(httptest.NewRecorder(), req)
+	apiErr, ok := err.(apierror.APIError)
+	require.True(t, ok)
+	assert.Equal(t, ErrIDRequestTooLarge, apiErr.ID())
+}
@@ -2,7 +2,10 @@ package endpoint
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/aatuh/pureapi-core/apierror"
 	"github.com/aatuh/pureapi-core/event"
@@ -11,12 +14,75 @@ import (
 // Constants for event event.
 const (
 	// EventError is emitted when an error occurs during request processing.
+	// Its data holds the status, the error, the public error and the
+	// error's fingerprint, see apierror.Fingerprint.
 	EventError event.EventType = "event_error"
 
 	// EventOutputError event is emitted when an output error occurs.
 	EventOutputError event.EventType = "event_output_error"
+
+	// EventHandled is emitted when a request has been handled. Its data
+	// holds the method, path, status, duration, input and output sizes in
+	// bytes and the request ID.
+	EventHandled event.EventType = "event_handled"
+
+	// EventRequestCanceled is emitted when the request context is canceled
+	// or its deadline expires before the response is written.
+	EventRequestCanceled event.EventType = "event_request_canceled"
 )
 
+// init declares the schemas of the handler events.
+func init() {
+	event.DeclareEvent(event.Schema{
+		Type:        EventError,
+		Description: "An error occurred during request processing.",
+		Fields: []event.FieldSchema{
+			{Name: "status", Type: "int", Required: true, Description: "The response status code."},
+			{Name: "err", Type: "error", Required: true, Description: "The error."},
+			{Name: "out", Type: "error", Description: "The public error."},
+			{Name: "fingerprint", Type: "string", Description: "The error fingerprint."},
+		},
+	})
+	event.DeclareEvent(event.Schema{
+		Type:        EventOutputError,
+		Description: "The output handler failed to write the response.",
+		Fields: []event.FieldSchema{
+			{Name: "err", Type: "error", Required: true, Description: "The error."},
+		},
+	})
+	event.DeclareEvent(event.Schema{
+		Type:        EventHandled,
+		Description: "A request has been handled.",
+		Fields: []event.FieldSchema{
+			{Name: "method", Type: "string", Required: true, Description: "The request method."},
+			{Name: "path", Type: "string", Required: true, Description: "The request path."},
+			{Name: "status", Type: "int", Required: true, Description: "The response status code."},
+			{Name: "duration", Type: "time.Duration", Required: true, Description: "The handling time."},
+			{Name: "input_bytes", Type: "int64", Required: true, Description: "The request body size."},
+			{Name: "output_bytes", Type: "int64", Required: true, Description: "The response body size."},
+			{Name: "request_id", Type: "string", Description: "The request ID."},
+		},
+	})
+	event.DeclareEvent(event.Schema{
+		Type:        EventRequestCanceled,
+		Description: "The request context ended before the response was written.",
+		Fields: []event.FieldSchema{
+			{Name: "status", Type: "int", Required: true, Description: "The response status code."},
+			{Name: "err", Type: "error", Required: true, Description: "The context error."},
+		},
+	})
+}
+
+// Error IDs of canceled requests.
+const (
+	ErrIDRequestCanceled  = "request_canceled"
+	ErrIDDeadlineExceeded = "deadline_exceeded"
+)
+
+// StatusClientClosedRequest is the non-standard status code of requests
+// the client canceled before the response was written.
+const StatusClientClosedRequest = 499
+
 // InputHandler defines how to process the request input.
 type InputHandler[Input any] interface {
 	Handle(w http.ResponseWriter, r *http.Request) (*Input, error)
@@ -32,32 +98,22 @@ type ErrorHandler interface {
 	Handle(err error) (int, apierror.APIError)
 }
 
-// DefaultErrorHandler provides a sensible default error mapping.
-type DefaultErrorHandler struct{}
+// DefaultErrorHandler provides a sensible default error mapping. The zero
+// value uses the mappings of NewErrorRegistry; set Registry to add
+// sentinels, error types or IDs.
+type DefaultErrorHandler struct {
+	Registry *ErrorRegistry
+}
 
 // Handle maps errors to appropriate HTTP responses.
-// Returns 400 for validation errors, 404 for not found, 500 for others.
+// Returns 400 for validation errors, 404 for not found, 413 and 415 for
+// rejected request bodies, 406 for unacceptable responses, 500 for others,
+// unless the registry maps them differently.
 func (d DefaultErrorHandler) Handle(err error) (int, apierror.APIError) {
-	// Check for specific error types
-	if apiErr, ok := err.(apierror.APIError); ok {
-		switch apiErr.ID() {
-		case "validation_error", "invalid_input":
-			return http.StatusBadRequest, apiErr
-		case "not_found", "resource_not_found":
-			return http.StatusNotFound, apiErr
-		case "unauthorized":
-			return http.StatusUnauthorized, apiErr
-		case "forbidden":
-			return http.StatusForbidden, apiErr
-		case "conflict":
-			return http.StatusConflict, apiErr
-		default:
-			return http.StatusInternalServerError, apierror.NewAPIError("internal_error").WithMessage("Internal server error")
-		}
+	if d.Registry != nil {
+		return d.Registry.Handle(err)
 	}
-
-	// Default to 500 for unknown errors
-	return http.StatusInternalServerError, apierror.NewAPIError("internal_error").WithMessage("Internal server error")
+	return defaultErrorRegistry.Handle(err)
 }
 
 // OutputHandler processes and writes the endpoint response.
@@ -76,6 +132,22 @@ type HandlerLogicFn[Input any] func(
 	w http.ResponseWriter, r *http.Request, i *Input,
 ) (any, error)
 
+// BeforeLogicHook runs after the input is bound and validated, before the
+// handler logic. It may modify the input; returning an error skips the logic
+// and writes the error response.
+type BeforeLogicHook[Input any] func(r *http.Request, i *Input) error
+
+// AfterLogicHook runs after the handler logic with its output and error,
+// and returns the output and error to use instead, e.g. to enrich the
+// output or translate the error.
+type AfterLogicHook[Input any] func(
+	r *http.Request, i *Input, out any, err error,
+) (any, error)
+
+// ErrorHook observes the errors of the pipeline before the ErrorHandler
+// maps them, e.g. for auditing or metrics.
+type ErrorHook func(r *http.Request, err error)
+
 // DefaultHandler represents an endpoint with input, business logic, and
 // output.
 type DefaultHandler[Input any] struct {
@@ -84,6 +156,13 @@ type DefaultHandler[Input any] struct {
 	errorHandler   ErrorHandler
 	outputHandler  OutputHandler
 	emitterLogger  event.EventEmitter
+	replayLimit    int64
+	errorRenderer  ErrorRenderer
+	successStatus  int
+	trailers       []TrailerFunc
+	beforeLogic    []BeforeLogicHook[Input]
+	afterLogic     []AfterLogicHook[Input]
+	onError        []ErrorHook
 }
 
 // NewHandler creates a new handler. During request handling it
@@ -182,8 +261,108 @@ func (h *DefaultHandler[Input]) WithEmitterLogger(
 	return &new
 }
 
-// Handle executes common endpoints logic. It calls the input handler, handler
-// logic, and output handler.
+// WithSuccessStatus sets the status code of successful responses, e.g.
+// http.StatusCreated for create endpoints, and returns a new handler
+// instance. The default is http.StatusOK. With http.StatusNoContent or
+// http.StatusNotModified a nil output writes no body. A Response returned
+// by the logic still sets its own status.
+//
+// Parameters:
+//   - status: The success status code.
+//
+// Returns:
+//   - *DefaultHandler[Input]: A new handler instance.
+func (h *DefaultHandler[Input]) WithSuccessStatus(
+	status int,
+) *DefaultHandler[Input] {
+	new := *h
+	new.successStatus = status
+	return &new
+}
+
+// WithBodyReplay buffers request bodies of up to maxBytes before the input
+// handler runs and returns a new handler instance. Every consumer of the
+// pipeline can then read the whole body: the input handler and the logic
+// each get a fresh r.Body, and RawBody returns the bytes, e.g. for
+// signature checks or audit logging. Larger bodies are rejected with a
+// request_too_large API error before any consumer runs. To share the body
+// with middlewares as well, use RawBodyMiddleware instead.
+//
+// Parameters:
+//   - maxBytes: The maximum body size in bytes.
+//
+// Returns:
+//   - *DefaultHandler[Input]: A new handler instance.
+func (h *DefaultHandler[Input]) WithBodyReplay(
+	maxBytes int64,
+) *DefaultHandler[Input] {
+	new := *h
+	new.replayLimit = maxBytes
+	return &new
+}
+
+// WithBeforeLogic adds hooks running before the handler logic, in order,
+// and returns a new handler instance.
+//
+// Parameters:
+//   - hooks: The hooks to add.
+//
+// Returns:
+//   - *DefaultHandler[Input]: A new handler instance.
+func (h *DefaultHandler[Input]) WithBeforeLogic(
+	hooks ...BeforeLogicHook[Input],
+) *DefaultHandler[Input] {
+	new := *h
+	new.beforeLogic = append(slices.Clip(h.beforeLogic), hooks...)
+	return &new
+}
+
+// WithAfterLogic adds hooks running after the handler logic, in order, and
+// returns a new handler instance. Each hook receives the output and error
+// returned by the previous one.
+//
+// Parameters:
+//   - hooks: The hooks to add.
+//
+// Returns:
+//   - *DefaultHandler[Input]: A new handler instance.
+func (h *DefaultHandler[Input]) WithAfterLogic(
+	hooks ...AfterLogicHook[Input],
+) *DefaultHandler[Input] {
+	new := *h
+	new.afterLogic = append(slices.Clip(h.afterLogic), hooks...)
+	return &new
+}
+
+// WithOnError adds hooks observing the errors of the input handler,
+// validation, hooks and logic, and returns a new handler instance.
+//
+// Parameters:
+//   - hooks: The hooks to add.
+//
+// Returns:
+//   - *DefaultHandler[Input]: A new handler instance.
+func (h *DefaultHandler[Input]) WithOnError(
+	hooks ...ErrorHook,
+) *DefaultHandler[Input] {
+	new := *h
+	new.onError = append(slices.Clip(h.onError), hooks...)
+	return &new
+}
+
+// Handle executes common endpoints logic. It calls the input handler,
+// validates the input if it implements Validator or ContextValidator, and
+// calls the handler logic and output handler. Logic may return a Response
+// to set the status code, headers and cookies of the success response.
+//
+// The request context is checked before each step and after the logic
+// returns. Once it is canceled or past its deadline, the remaining steps
+// and the normal response are skipped; the context error is mapped by the
+// ErrorHandler instead, 499 or 504 with DefaultErrorHandler, and
+// EventRequestCanceled is emitted.
+//
+// Every request ends with EventHandled. The writer passed to the logic and
+// output handler implements http.Flusher when the underlying writer does.
 //
 // Parameters:
 //   - w: The HTTP response writer.
@@ -191,29 +370,152 @@ func (h *DefaultHandler[Input]) WithEmitterLogger(
 func (h *DefaultHandler[Input]) Handle(
 	w http.ResponseWriter, r *http.Request,
 ) {
+	start := time.Now()
+	mw := &metricsWriter{ResponseWriter: w}
+	body := &countingReader{ReadCloser: r.Body}
+	if r.Body != nil {
+		r = r.WithContext(r.Context())
+		r.Body = body
+	}
+	defer h.emitHandled(r, mw, body, start)
+	if len(h.trailers) == 0 {
+		h.handle(mw, r)
+		return
+	}
+	tw := newTrailerWriter(mw, h.trailers)
+	h.handle(tw, r)
+	tw.finish()
+}
+
+// handle runs the pipeline steps.
+func (h *DefaultHandler[Input]) handle(
+	w http.ResponseWriter, r *http.Request,
+) {
+	if h.canceled(w, r) {
+		return
+	}
+	// Buffer the body for replay.
+	if h.replayLimit > 0 {
+		var err error
+		if r, err = bufferBody(r, h.replayLimit); err != nil {
+			h.handleError(w, r, err)
+			return
+		}
+	}
 	// Handle input.
 	input, err := h.inputHandler.Handle(w, r)
+	if h.canceled(w, r) {
+		return
+	}
 	if err != nil {
 		h.handleError(w, r, err)
 		return
 	}
-	// Call handler logic.
+	// Validate input.
+	if err := validateInput(r.Context(), input); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	if h.canceled(w, r) {
+		return
+	}
+	// Run the hooks and handler logic.
+	for _, hook := range h.beforeLogic {
+		if err := hook(r, input); err != nil {
+			h.handleError(w, r, err)
+			return
+		}
+	}
+	if h.replayLimit > 0 {
+		replayBody(r)
+	}
 	out, err := h.handlerLogicFn(w, r, input)
+	for _, hook := range h.afterLogic {
+		out, err = hook(r, input, out, err)
+	}
+	if h.canceled(w, r) {
+		return
+	}
 	if err != nil {
 		h.handleError(w, r, err)
 		return
 	}
 	// Write output.
-	h.handleOutput(w, r, out, nil, http.StatusOK)
+	status := h.successStatus
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if resp, ok := asResponse(out); ok {
+		status = resp.write(w, status)
+		out = resp.Body
+	}
+	if out == nil && bodyless(status) {
+		w.WriteHeader(status)
+		return
+	}
+	h.handleOutput(w, r, out, nil, status)
+}
+
+// emitHandled emits EventHandled.
+func (h *DefaultHandler[Input]) emitHandled(
+	r *http.Request, mw *metricsWriter, body *countingReader, start time.Time,
+) {
+	duration := time.Since(start)
+	status := mw.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	event.EmitCtx(h.emitterLogger, r.Context(),
+		event.NewEvent(
+			EventHandled,
+			fmt.Sprintf(
+				"Handled %s %s, status: %d, duration: %s",
+				r.Method, r.URL.Path, status, duration,
+			),
+		).WithData(map[string]any{
+			"method":       r.Method,
+			"path":         r.URL.Path,
+			"status":       status,
+			"duration":     duration,
+			"input_bytes":  body.n,
+			"output_bytes": mw.n,
+			"request_id":   RequestIDFromRequest(r),
+		}),
+	)
+}
+
+// canceled reports whether the request context is done. If so, it maps the
+// context error and writes the error response.
+func (h *DefaultHandler[Input]) canceled(
+	w http.ResponseWriter, r *http.Request,
+) bool {
+	err := r.Context().Err()
+	if err == nil {
+		return false
+	}
+	statusCode, outError := h.errorHandler.Handle(err)
+	event.EmitCtx(h.emitterLogger, r.Context(),
+		event.NewEvent(
+			EventRequestCanceled,
+			fmt.Sprintf(
+				"Request canceled, status: %d, err: %s", statusCode, err,
+			),
+		).WithData(map[string]any{"status": statusCode, "err": err}),
+	)
+	h.handleOutput(w, r, nil, outError, statusCode)
+	return true
 }
 
 // handleError maps apierror and writes the error response.
 func (h *DefaultHandler[Input]) handleError(
 	w http.ResponseWriter, r *http.Request, err error,
 ) {
+	for _, hook := range h.onError {
+		hook(r, err)
+	}
 	// Handle error.
 	statusCode, outError := h.errorHandler.Handle(err)
-	h.emitterLogger.Emit(
+	event.EmitCtx(h.emitterLogger, r.Context(),
 		event.NewEvent(
 			EventError,
 			fmt.Sprintf(
@@ -222,11 +524,15 @@ func (h *DefaultHandler[Input]) handleError(
 				err,
 				outError,
 			),
-		).WithData(
-			map[string]any{"status": statusCode, "err": err, "out": outError},
-		),
+		).WithData(map[string]any{
+			"status":      statusCode,
+			"err":         err,
+			"out":         outError,
+			"fingerprint": errorFingerprint(err, outError),
+		}),
 	)
 	// Handle and write output.
+	setRetryAfter(w.Header(), statusCode, outError)
 	h.handleOutput(w, r, nil, outError, statusCode)
 }
 
@@ -250,13 +556,77 @@ func (tw *trackingWriter) Write(p []byte) (int, error) {
 	return tw.ResponseWriter.Write(p)
 }
 
+// Flush commits the response and flushes the wrapped writer, if it
+// supports flushing.
+func (tw *trackingWriter) Flush() {
+	tw.wrote = true
+	_ = http.NewResponseController(tw.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (tw *trackingWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
+
+// metricsWriter records the status code and the number of bytes written.
+type metricsWriter struct {
+	http.ResponseWriter
+	status int
+	n      int64
+}
+
+func (mw *metricsWriter) WriteHeader(code int) {
+	if mw.status == 0 {
+		mw.status = code
+	}
+	mw.ResponseWriter.WriteHeader(code)
+}
+
+func (mw *metricsWriter) Write(p []byte) (int, error) {
+	if mw.status == 0 {
+		mw.status = http.StatusOK
+	}
+	n, err := mw.ResponseWriter.Write(p)
+	mw.n += int64(n)
+	return n, err
+}
+
+// Flush commits the response and flushes the wrapped writer, if it
+// supports flushing.
+func (mw *metricsWriter) Flush() {
+	if mw.status == 0 {
+		mw.status = http.StatusOK
+	}
+	_ = http.NewResponseController(mw.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (mw *metricsWriter) Unwrap() http.ResponseWriter {
+	return mw.ResponseWriter
+}
+
+// countingReader counts the bytes read from a request body.
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.ReadCloser.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
 // handleOutput processes and writes the endpoint response.
 func (h *DefaultHandler[Input]) handleOutput(
 	w http.ResponseWriter, r *http.Request, out any, outError error, status int,
 ) {
+	if outError != nil && h.errorRenderer != nil {
+		r = withErrorRenderer(r, h.errorRenderer)
+	}
 	tw := &trackingWriter{ResponseWriter: w}
 	if err := h.outputHandler.Handle(tw, r, out, outError, status); err != nil {
-		h.emitterLogger.Emit(
+		event.EmitCtx(h.emitterLogger, r.Context(),
 			event.NewEvent(
 				EventOutputError, fmt.Sprintf("Error handling output: %+v", err),
 			).WithData(map[string]any{"err": err}),
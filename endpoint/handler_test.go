@@ -1,11 +1,15 @@
 package endpoint
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aatuh/pureapi-core/apierror"
 	"github.com/aatuh/pureapi-core/event"
@@ -63,6 +67,17 @@ func (d *dummyOutputHandler) Handle(
 	return d.retErr
 }
 
+// bodyInputHandler returns the raw request body as input.
+type bodyInputHandler struct{}
+
+func (bodyInputHandler) Handle(
+	w http.ResponseWriter, r *http.Request,
+) (*string, error) {
+	b, err := io.ReadAll(r.Body)
+	in := string(b)
+	return &in, err
+}
+
 // dummyOutputHandlerNoWrite is used to simulate an output failure without
 // writing a header.
 type dummyOutputHandlerNoWrite struct {
@@ -257,3 +272,162 @@ func (s *HandlerTestSuite) Test_Handle_NilEmitterLogger() {
 	s.True(outHandler.called, "Output handler should be called")
 	s.Equal("logic", rr.Body.String(), "Expected output 'logic'")
 }
+
+// Test_Handle_Canceled verifies that canceled requests skip the normal
+// response and are mapped to 499 or 504.
+func (s *HandlerTestSuite) Test_Handle_Canceled() {
+	testCases := []struct {
+		name           string
+		cancelBefore   bool
+		timeout        time.Duration
+		expectedStatus int
+		expectedID     string
+		logicCalled    bool
+	}{
+		{name: "CanceledBefore", cancelBefore: true,
+			expectedStatus: StatusClientClosedRequest, expectedID: ErrIDRequestCanceled},
+		{name: "CanceledDuringLogic", logicCalled: true,
+			expectedStatus: StatusClientClosedRequest, expectedID: ErrIDRequestCanceled},
+		{name: "DeadlineDuringLogic", timeout: 10 * time.Millisecond, logicCalled: true,
+			expectedStatus: http.StatusGatewayTimeout, expectedID: ErrIDDeadlineExceeded},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.timeout > 0 {
+				ctx, cancel = context.WithTimeout(context.Background(), tc.timeout)
+				defer cancel()
+			}
+			if tc.cancelBefore {
+				cancel()
+			}
+
+			called := false
+			input := "valid"
+			logicFn := func(
+				w http.ResponseWriter, r *http.Request, i *string,
+			) (any, error) {
+				called = true
+				if tc.timeout > 0 {
+					<-r.Context().Done()
+				} else {
+					cancel()
+				}
+				return "logicOutput", nil
+			}
+			outHandler := &dummyOutputHandler{}
+			emitter := &dummyEventEmitter{}
+			handler := NewHandler(
+				&dummyInputHandler{result: &input}, logicFn,
+				DefaultErrorHandler{}, outHandler,
+			).WithEmitterLogger(emitter)
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+			handler.Handle(rr, req)
+
+			s.Equal(tc.logicCalled, called)
+			s.Equal(tc.expectedStatus, rr.Code)
+			s.Nil(outHandler.out)
+			apiErr, ok := outHandler.outErr.(apierror.APIError)
+			s.Require().True(ok)
+			s.Equal(tc.expectedID, apiErr.ID())
+			s.Require().Len(emitter.events, 2)
+			s.Equal(EventRequestCanceled, emitter.events[0].Type)
+			s.Equal(EventHandled, emitter.events[1].Type)
+		})
+	}
+}
+
+// Test_Handle_HandledEvent verifies the completion event data.
+func (s *HandlerTestSuite) Test_Handle_HandledEvent() {
+	inHandler := &bodyInputHandler{}
+	logicFn := func(
+		w http.ResponseWriter, r *http.Request, i *string,
+	) (any, error) {
+		return "out:" + *i, nil
+	}
+	emitter := &dummyEventEmitter{}
+	handler := NewHandler(
+		inHandler, logicFn, &dummyErrorHandler{}, &dummyOutputHandler{},
+	).WithEmitterLogger(emitter)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/items", strings.NewReader("hello"))
+	req = req.WithContext(context.WithValue(req.Context(), RequestIDKey{}, "req-1"))
+	handler.Handle(rr, req)
+
+	s.Require().Len(emitter.events, 1)
+	ev := emitter.events[0]
+	s.Equal(EventHandled, ev.Type)
+	data := ev.Data.(map[string]any)
+	s.Equal("POST", data["method"])
+	s.Equal("/items", data["path"])
+	s.Equal(http.StatusOK, data["status"])
+	s.Equal(int64(5), data["input_bytes"])
+	s.Equal(int64(len("out:hello")), data["output_bytes"])
+	s.Equal("req-1", data["request_id"])
+	s.Greater(data["duration"].(time.Duration), time.Duration(0))
+	s.Equal("req-1", RequestIDFromContext(ev.Context()))
+	s.Equal("github.com/aatuh/pureapi-core/endpoint", ev.Source)
+	s.NoError(event.ValidateEvent(ev))
+}
+
+// Test_Handle_Hooks verifies the order and effects of the logic hooks.
+func (s *HandlerTestSuite) Test_Handle_Hooks() {
+	var calls []string
+	var observed []error
+	logicErr := errors.New("logic error")
+	newHandler := func(logicErr error) *DefaultHandler[string] {
+		return NewHandler(
+			&bodyInputHandler{},
+			func(_ http.ResponseWriter, _ *http.Request, i *string) (any, error) {
+				calls = append(calls, "logic:"+*i)
+				return *i, logicErr
+			},
+			&dummyErrorHandler{retStatus: http.StatusTeapot},
+			&dummyOutputHandler{},
+		).WithBeforeLogic(
+			func(_ *http.Request, i *string) error {
+				calls = append(calls, "before")
+				*i = strings.ToUpper(*i)
+				return nil
+			},
+		).WithAfterLogic(
+			func(_ *http.Request, _ *string, out any, err error) (any, error) {
+				calls = append(calls, "after")
+				if err != nil {
+					return nil, err
+				}
+				return out.(string) + "!", nil
+			},
+		).WithOnError(func(_ *http.Request, err error) {
+			observed = append(observed, err)
+		})
+	}
+
+	rr := httptest.NewRecorder()
+	newHandler(nil).Handle(rr, httptest.NewRequest("POST", "/", strings.NewReader("hi")))
+	s.Equal([]string{"before", "logic:HI", "after"}, calls)
+	s.Equal("HI!", rr.Body.String())
+	s.Empty(observed)
+
+	calls = nil
+	rr = httptest.NewRecorder()
+	newHandler(logicErr).Handle(rr, httptest.NewRequest("POST", "/", strings.NewReader("hi")))
+	s.Equal(http.StatusTeapot, rr.Code)
+	s.Equal([]error{logicErr}, observed)
+
+	// A failing before hook skips the logic.
+	calls, observed = nil, nil
+	hookErr := errors.New("hook error")
+	rr = httptest.NewRecorder()
+	newHandler(nil).WithBeforeLogic(func(*http.Request, *string) error {
+		return hookErr
+	}).Handle(rr, httptest.NewRequest("POST", "/", strings.NewReader("hi")))
+	s.Equal([]string{"before"}, calls)
+	s.Equal([]error{hookErr}, observed)
+	s.Equal(http.StatusTeapot, rr.Code)
+}
@@ -0,0 +1,317 @@
+package endpoint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/aatuh/pureapi-core/jsonschema"
+)
+
+// Error IDs returned by the built-in input handlers.
+const (
+	ErrIDInvalidInput         = "invalid_input"
+	ErrIDUnsupportedMediaType = "unsupported_media_type"
+	ErrIDRequestTooLarge      = "request_too_large"
+	ErrIDRequestTimeout       = "request_timeout"
+)
+
+// JSONInputOption configures a JSON input handler.
+type JSONInputOption func(*jsonInput)
+
+// WithJSONStrict rejects bodies containing fields that are not present in
+// the input type.
+//
+// Returns:
+//   - JSONInputOption: A JSON input option function.
+func WithJSONStrict() JSONInputOption {
+	return func(j *jsonInput) { j.strict = true }
+}
+
+// WithJSONMaxDepth sets the maximum nesting depth of objects and arrays.
+// Defaults to 32. Zero disables the check.
+//
+// Parameters:
+//   - depth: The maximum nesting depth.
+//
+// Returns:
+//   - JSONInputOption: A JSON input option function.
+func WithJSONMaxDepth(depth int) JSONInputOption {
+	return func(j *jsonInput) { j.maxDepth = depth }
+}
+
+// WithJSONOptionalContentType accepts requests without a Content-Type
+// header. Requests declaring a non-JSON content type are still rejected.
+//
+// Returns:
+//   - JSONInputOption: A JSON input option function.
+func WithJSONOptionalContentType() JSONInputOption {
+	return func(j *jsonInput) { j.optionalContentType = true }
+}
+
+// WithJSONSchema validates request bodies against a JSON Schema before they
+// are decoded. Violations are returned as an invalid_input API error with a
+// list of FieldError values as data.
+//
+// Parameters:
+//   - schema: The JSON Schema of the body.
+//
+// Returns:
+//   - JSONInputOption: A JSON input option function.
+func WithJSONSchema(schema *jsonschema.Schema) JSONInputOption {
+	return func(j *jsonInput) { j.schema = schema }
+}
+
+// WithJSONSchemaFromType is like WithJSONSchema with a schema derived from
+// the input type, using the same rules as the OpenAPI generator: fields
+// without omitempty are required and jsonschema tags add constraints.
+// Fields bound to path, query or header parameters are left out.
+//
+// Returns:
+//   - JSONInputOption: A JSON input option function.
+func WithJSONSchemaFromType() JSONInputOption {
+	return func(j *jsonInput) { j.deriveSchema = true }
+}
+
+// JSONInput returns an input handler decoding the request body as JSON into
+// Input. The request must declare application/json or a +json media type and
+// contain exactly one JSON value. Failures are returned as API errors:
+// invalid_input for malformed bodies, unsupported_media_type for other
+// content types and request_too_large when the body limit is exceeded, which
+// DefaultErrorHandler maps to 400, 415 and 413.
+//
+// Parameters:
+//   - opts: Optional JSON input options.
+//
+// Returns:
+//   - InputHandler[Input]: The JSON input handler.
+func JSONInput[Input any](opts ...JSONInputOption) InputHandler[Input] {
+	cfg := jsonInput{maxDepth: 32}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.resolveSchema(reflect.TypeFor[Input]())
+	return &jsonInputHandler[Input]{cfg: cfg}
+}
+
+// jsonInput holds the JSON input settings.
+type jsonInput struct {
+	strict              bool
+	maxDepth            int
+	optionalContentType bool
+	schema              *jsonschema.Schema
+	deriveSchema        bool
+}
+
+// resolveSchema derives the body schema from the input type if requested
+// and no explicit schema is set.
+func (c *jsonInput) resolveSchema(t reflect.Type) {
+	if c.deriveSchema && c.schema == nil {
+		c.schema = (&jsonschema.Reflector{}).Object(
+			t, "path", "query", "header",
+		)
+	}
+}
+
+// jsonInputHandler decodes JSON request bodies.
+type jsonInputHandler[Input any] struct {
+	cfg jsonInput
+}
+
+// Handle decodes the request body.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//
+// Returns:
+//   - *Input: The decoded input.
+//   - error: An API error if the body cannot be decoded.
+func (h *jsonInputHandler[Input]) Handle(
+	_ http.ResponseWriter, r *http.Request,
+) (*Input, error) {
+	if err := checkContentType(
+		r, h.cfg.optionalContentType, isJSONMediaType,
+	); err != nil {
+		return nil, err
+	}
+	var in Input
+	if err := h.cfg.decode(r, &in); err != nil {
+		return nil, err
+	}
+	return &in, nil
+}
+
+// decode reads the request body and decodes it into dst.
+func (c jsonInput) decode(r *http.Request, dst any) error {
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	return c.decodeBody(body, dst)
+}
+
+// decodeBody validates and decodes a JSON body into dst.
+func (c jsonInput) decodeBody(body []byte, dst any) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return invalidInput("request body is empty")
+	}
+	if c.maxDepth > 0 && jsonDepthExceeds(body, c.maxDepth) {
+		return invalidInput(
+			fmt.Sprintf("JSON nesting exceeds depth %d", c.maxDepth),
+		)
+	}
+	if c.schema != nil {
+		if err := validateSchema(c.schema, body); err != nil {
+			return err
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if c.strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(dst); err != nil {
+		return invalidInput(jsonErrorMessage(err))
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return invalidInput("request body must contain a single JSON value")
+	}
+	return nil
+}
+
+// validateSchema validates a JSON body against a schema. Malformed bodies
+// pass, leaving the error to the decoder.
+func validateSchema(schema *jsonschema.Schema, body []byte) error {
+	violations, err := schema.ValidateJSON(body)
+	if err != nil || len(violations) == 0 {
+		return nil
+	}
+	fields := make([]FieldError, len(violations))
+	for i, v := range violations {
+		fields[i] = FieldError{Field: v.Path, In: "body", Message: v.Message}
+	}
+	return invalidFields(fields)
+}
+
+// checkContentType validates the request media type.
+func checkContentType(
+	r *http.Request, optional bool, accept func(string) bool,
+) error {
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		if optional {
+			return nil
+		}
+		return apierror.NewAPIError(ErrIDUnsupportedMediaType).
+			WithMessage("missing Content-Type")
+	}
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil || !accept(mediaType) {
+		return unsupportedMediaType(ct)
+	}
+	return nil
+}
+
+// unsupportedMediaType returns an unsupported_media_type API error.
+func unsupportedMediaType(ct string) *apierror.DefaultAPIError {
+	return apierror.NewAPIError(ErrIDUnsupportedMediaType).
+		WithMessage(fmt.Sprintf("unsupported Content-Type %q", ct))
+}
+
+// isJSONMediaType reports whether the media type is JSON.
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" ||
+		(strings.HasPrefix(mediaType, "application/") &&
+			strings.HasSuffix(mediaType, "+json"))
+}
+
+// readBody reads the request body, mapping body limit violations.
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, bodyReadError(err)
+	}
+	return body, nil
+}
+
+// bodyReadError maps a request body read error to an API error.
+func bodyReadError(err error) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return requestTooLarge(fmt.Sprintf(
+			"request body exceeds %d bytes", maxErr.Limit,
+		))
+	}
+	return invalidInput("failed to read request body")
+}
+
+// invalidInput returns an invalid_input API error.
+func invalidInput(message string) *apierror.DefaultAPIError {
+	return apierror.NewAPIError(ErrIDInvalidInput).WithMessage(message)
+}
+
+// requestTooLarge returns a request_too_large API error.
+func requestTooLarge(message string) *apierror.DefaultAPIError {
+	return apierror.NewAPIError(ErrIDRequestTooLarge).WithMessage(message)
+}
+
+// jsonErrorMessage describes a decoding error without echoing input.
+func jsonErrorMessage(err error) string {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		if typeErr.Field != "" {
+			return fmt.Sprintf(
+				"field %q must be of type %s", typeErr.Field, typeErr.Type,
+			)
+		}
+		return fmt.Sprintf("body must be of type %s", typeErr.Type)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return "malformed JSON: unexpected end of input"
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return "unknown field " + strings.TrimPrefix(err.Error(), "json: unknown field ")
+	}
+	return "malformed JSON"
+}
+
+// jsonDepthExceeds reports whether objects and arrays nest deeper than max.
+func jsonDepthExceeds(data []byte, max int) bool {
+	depth := 0
+	inString, escaped := false, false
+	for _, c := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			switch c {
+			case '\\':
+				escaped = true
+			case '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+			if depth > max {
+				return true
+			}
+		case c == '}' || c == ']':
+			depth--
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/aatuh/pureapi-core/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonTestInput struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestJSONInput(t *testing.T) {
+	testCases := []struct {
+		name        string
+		opts        []JSONInputOption
+		contentType string
+		body        string
+		wantErrID   string
+		wantStatus  int
+	}{
+		{name: "Valid", contentType: "application/json", body: `{"name":"Go","age":15}`},
+		{name: "Charset and suffix", contentType: "application/vnd.api+json; charset=utf-8", body: `{"name":"Go"}`},
+		{name: "Unknown field lenient", contentType: "application/json", body: `{"name":"Go","x":1}`},
+		{name: "Unknown field strict", opts: []JSONInputOption{WithJSONStrict()}, contentType: "application/json",
+			body: `{"name":"Go","x":1}`, wantErrID: ErrIDInvalidInput, wantStatus: http.StatusBadRequest},
+		{name: "Missing content type", body: `{}`, wantErrID: ErrIDUnsupportedMediaType,
+			wantStatus: http.StatusUnsupportedMediaType},
+		{name: "Optional content type", opts: []JSONInputOption{WithJSONOptionalContentType()}, body: `{}`},
+		{name: "Wrong content type", contentType: "text/plain", body: `{}`,
+			wantErrID: ErrIDUnsupportedMediaType, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "Empty body", contentType: "application/json", body: " ",
+			wantErrID: ErrIDInvalidInput, wantStatus: http.StatusBadRequest},
+		{name: "Malformed", contentType: "application/json", body: `{"name":`,
+			wantErrID: ErrIDInvalidInput, wantStatus: http.StatusBadRequest},
+		{name: "Wrong type", contentType: "application/json", body: `{"age":"old"}`,
+			wantErrID: ErrIDInvalidInput, wantStatus: http.StatusBadRequest},
+		{name: "Trailing data", contentType: "application/json", body: `{} {}`,
+			wantErrID: ErrIDInvalidInput, wantStatus: http.StatusBadRequest},
+		{name: "Too deep", opts: []JSONInputOption{WithJSONMaxDepth(3)}, contentType: "application/json",
+			body: `{"name":"[[[[","x":[[[1]]]}`, wantErrID: ErrIDInvalidInput, wantStatus: http.StatusBadRequest},
+		{name: "Brackets in strings", opts: []JSONInputOption{WithJSONMaxDepth(1)}, contentType: "application/json",
+			body: `{"name":"{[\"{"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			in, err := JSONInput[jsonTestInput](tc.opts...).Handle(httptest.NewRecorder(), req)
+			if tc.wantErrID == "" {
+				require.NoError(t, err)
+				require.NotNil(t, in)
+				return
+			}
+			require.Error(t, err)
+			apiErr, ok := err.(apierror.APIError)
+			require.True(t, ok, "error should be an APIError")
+			assert.Equal(t, tc.wantErrID, apiErr.ID())
+			status, _ := DefaultErrorHandler{}.Handle(err)
+			assert.Equal(t, tc.wantStatus, status)
+		})
+	}
+}
+
+func TestJSONInput_Decodes(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Go","age":15}`))
+	req.Header.Set("Content-Type", "application/json")
+	in, err := JSONInput[jsonTestInput]().Handle(httptest.NewRecorder(), req)
+	require.NoError(t, err)
+	assert.Equal(t, jsonTestInput{Name: "Go", Age: 15}, *in)
+}
+
+func TestJSONInput_BodyLimit(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"`+strings.Repeat("a", 100)+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Body = http.MaxBytesReader(rec, req.Body, 16)
+
+	_, err := JSONInput[jsonTestInput]().Handle(rec, req)
+	require.Error(t, err)
+	status, apiErr := DefaultErrorHandler{}.Handle(err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	assert.Equal(t, ErrIDRequestTooLarge, apiErr.ID())
+}
+
+func TestJSONInput_Schema(t *testing.T) {
+	type createUser struct {
+		ID   int64  `json:"id" path:"id"`
+		Name string `json:"name" jsonschema:"minLength=2"`
+		Role string `json:"role,omitempty" jsonschema:"enum=admin|member"`
+	}
+	explicit := jsonschema.MustParse([]byte(
+		`{"type":"object","required":["name"],"properties":{"name":{"type":"string"}}}`,
+	))
+	testCases := []struct {
+		name       string
+		opts       []JSONInputOption
+		body       string
+		wantFields []FieldError
+	}{
+		{name: "Derived valid", opts: []JSONInputOption{WithJSONSchemaFromType()},
+			body: `{"name":"Go","role":"admin"}`},
+		{name: "Derived invalid", opts: []JSONInputOption{WithJSONSchemaFromType()},
+			body: `{"name":"G","role":"root"}`, wantFields: []FieldError{
+				{Field: "name", In: "body", Message: "must be at least 2 characters"},
+				{Field: "role", In: "body", Message: `must be one of "admin", "member"`},
+			}},
+		{name: "Explicit missing", opts: []JSONInputOption{WithJSONSchema(explicit)},
+			body: `{}`, wantFields: []FieldError{
+				{Field: "name", In: "body", Message: "is required"},
+			}},
+		{name: "Explicit wins", opts: []JSONInputOption{WithJSONSchema(explicit), WithJSONSchemaFromType()},
+			body: `{"name":"G"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			in, err := JSONInput[createUser](tc.opts...).Handle(httptest.NewRecorder(), req)
+			if tc.wantFields == nil {
+				require.NoError(t, err)
+				require.NotNil(t, in)
+				return
+			}
+			require.Error(t, err)
+			status, apiErr := DefaultErrorHandler{}.Handle(err)
+			assert.Equal(t, http.StatusBadRequest, status)
+			assert.Equal(t, ErrIDInvalidInput, apiErr.ID())
+			assert.Equal(t, tc.wantFields, apiErr.Data())
+		})
+	}
+}
@@ -0,0 +1,590 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/aatuh/pureapi-core/internal/jwt"
+)
+
+// ErrTokenInvalid is returned by JWT verifiers for malformed, badly signed,
+// expired or otherwise unacceptable tokens.
+var ErrTokenInvalid = errors.New("invalid token")
+
+// Claims are the claims of a verified token.
+type Claims map[string]any
+
+// String returns a string claim, or "".
+//
+// Parameters:
+//   - name: The claim name.
+//
+// Returns:
+//   - string: The claim value.
+func (c Claims) String(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// Strings returns a claim holding a string or an array of strings.
+//
+// Parameters:
+//   - name: The claim name.
+//
+// Returns:
+//   - []string: The claim values.
+func (c Claims) Strings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// Time returns a NumericDate claim, such as exp.
+//
+// Parameters:
+//   - name: The claim name.
+//
+// Returns:
+//   - time.Time: The claim time.
+//   - bool: Whether the claim is present and numeric.
+func (c Claims) Time(name string) (time.Time, bool) {
+	switch v := c[name].(type) {
+	case float64:
+		return time.Unix(int64(v), 0), true
+	case int64:
+		return time.Unix(v, 0), true
+	case int:
+		return time.Unix(int64(v), 0), true
+	}
+	return time.Time{}, false
+}
+
+// Subject returns the sub claim.
+func (c Claims) Subject() string { return c.String("sub") }
+
+// Issuer returns the iss claim.
+func (c Claims) Issuer() string { return c.String("iss") }
+
+// Audience returns the aud claim.
+func (c Claims) Audience() []string { return c.Strings("aud") }
+
+// Scopes returns the space separated scope claim, or the scp array used by
+// some providers.
+func (c Claims) Scopes() []string {
+	if scope := c.String("scope"); scope != "" {
+		return strings.Fields(scope)
+	}
+	return c.Strings("scp")
+}
+
+// TokenVerifier verifies bearer tokens.
+type TokenVerifier interface {
+	// Verify returns the claims of a valid token. Invalid tokens should be
+	// reported with ErrTokenInvalid; API errors are rendered as is.
+	Verify(ctx context.Context, token string) (Claims, error)
+}
+
+// TokenVerifierFunc adapts a function to the TokenVerifier interface.
+type TokenVerifierFunc func(ctx context.Context, token string) (Claims, error)
+
+// Verify calls f(ctx, token).
+func (f TokenVerifierFunc) Verify(
+	ctx context.Context, token string,
+) (Claims, error) {
+	return f(ctx, token)
+}
+
+// KeyFunc returns the key verifying a token signed with the algorithm and
+// key ID: a []byte secret for HMAC, or an *rsa.PublicKey,
+// *ecdsa.PublicKey or ed25519.PublicKey.
+type KeyFunc func(ctx context.Context, alg, kid string) (any, error)
+
+// JWTSecret returns a key function for HMAC signed tokens.
+//
+// Parameters:
+//   - secret: The shared secret.
+//
+// Returns:
+//   - KeyFunc: The key function.
+func JWTSecret(secret []byte) KeyFunc {
+	return func(context.Context, string, string) (any, error) {
+		return secret, nil
+	}
+}
+
+// JWTOption configures a JWT verifier.
+type JWTOption func(*jwtVerifier)
+
+// WithJWTIssuer requires the iss claim to equal issuer.
+//
+// Parameters:
+//   - issuer: The expected issuer.
+//
+// Returns:
+//   - JWTOption: The option.
+func WithJWTIssuer(issuer string) JWTOption {
+	return func(v *jwtVerifier) { v.issuer = issuer }
+}
+
+// WithJWTAudience requires the aud claim to contain audience.
+//
+// Parameters:
+//   - audience: The expected audience.
+//
+// Returns:
+//   - JWTOption: The option.
+func WithJWTAudience(audience string) JWTOption {
+	return func(v *jwtVerifier) { v.audience = audience }
+}
+
+// WithJWTAlgorithms restricts the accepted signing algorithms. By default
+// every supported algorithm is accepted, limited by the key type the key
+// function returns.
+//
+// Parameters:
+//   - algs: The accepted algorithms, e.g. "RS256".
+//
+// Returns:
+//   - JWTOption: The option.
+func WithJWTAlgorithms(algs ...string) JWTOption {
+	return func(v *jwtVerifier) { v.algs = algs }
+}
+
+// WithJWTLeeway sets the clock skew tolerated when checking exp and nbf.
+// The default is one minute.
+//
+// Parameters:
+//   - leeway: The tolerated clock skew.
+//
+// Returns:
+//   - JWTOption: The option.
+func WithJWTLeeway(leeway time.Duration) JWTOption {
+	return func(v *jwtVerifier) { v.leeway = leeway }
+}
+
+// JWTVerifier returns a verifier for signed JSON Web Tokens. It checks the
+// signature with the key returned by keyFunc, the exp and nbf claims when
+// present, and the issuer and audience when configured.
+//
+// Parameters:
+//   - keyFunc: Returns the verification key of a token.
+//   - opts: Optional JWT options.
+//
+// Returns:
+//   - TokenVerifier: The JWT verifier.
+func JWTVerifier(keyFunc KeyFunc, opts ...JWTOption) TokenVerifier {
+	v := &jwtVerifier{keyFunc: keyFunc, leeway: time.Minute, now: time.Now}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// jwtVerifier verifies JSON Web Tokens.
+type jwtVerifier struct {
+	keyFunc  KeyFunc
+	issuer   string
+	audience string
+	algs     []string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// jwtVerifier implements the TokenVerifier interface.
+var _ TokenVerifier = (*jwtVerifier)(nil)
+
+// Verify parses and validates a token.
+//
+// Parameters:
+//   - ctx: The request context.
+//   - token: The compact serialized token.
+//
+// Returns:
+//   - Claims: The token claims.
+//   - error: An error wrapping ErrTokenInvalid if the token is rejected.
+func (v *jwtVerifier) Verify(ctx context.Context, token string) (Claims, error) {
+	t, err := jwt.Parse(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenInvalid, err)
+	}
+	if v.algs != nil && !slices.Contains(v.algs, t.Header.Alg) {
+		return nil, fmt.Errorf("%w: algorithm %q not allowed",
+			ErrTokenInvalid, t.Header.Alg)
+	}
+	key, err := v.keyFunc(ctx, t.Header.Alg, t.Header.Kid)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenInvalid, err)
+	}
+	if err := t.Verify(key); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenInvalid, err)
+	}
+	claims := Claims(t.Claims)
+	now := v.now()
+	if exp, ok := claims.Time("exp"); ok && !now.Before(exp.Add(v.leeway)) {
+		return nil, fmt.Errorf("%w: token expired", ErrTokenInvalid)
+	}
+	if nbf, ok := claims.Time("nbf"); ok && now.Add(v.leeway).Before(nbf) {
+		return nil, fmt.Errorf("%w: token not yet valid", ErrTokenInvalid)
+	}
+	if v.issuer != "" && claims.Issuer() != v.issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer", ErrTokenInvalid)
+	}
+	if v.audience != "" && !slices.Contains(claims.Audience(), v.audience) {
+		return nil, fmt.Errorf("%w: unexpected audience", ErrTokenInvalid)
+	}
+	return claims, nil
+}
+
+// JWKSOption configures JWKSKeyFunc.
+type JWKSOption func(*jwks)
+
+// WithJWKSClient sets the HTTP client fetching the key set. The default is
+// a client with a 10 second timeout.
+//
+// Parameters:
+//   - client: The HTTP client.
+//
+// Returns:
+//   - JWKSOption: The option.
+func WithJWKSClient(client *http.Client) JWKSOption {
+	return func(j *jwks) { j.client = client }
+}
+
+// WithJWKSRefresh sets how long fetched keys are cached. The default is one
+// hour.
+//
+// Parameters:
+//   - refresh: The cache duration.
+//
+// Returns:
+//   - JWKSOption: The option.
+func WithJWKSRefresh(refresh time.Duration) JWKSOption {
+	return func(j *jwks) { j.refresh = refresh }
+}
+
+// JWKSKeyFunc returns a key function resolving keys by ID from a JSON Web
+// Key Set URL. Keys are cached and refetched when the cache expires or, at
+// most once per minute, when a token names an unknown key ID. A failed first
+// fetch is also retried at most once per minute, failing lookups with its
+// error meanwhile.
+//
+// Parameters:
+//   - url: The key set URL.
+//   - opts: Optional JWKS options.
+//
+// Returns:
+//   - KeyFunc: The key function.
+func JWKSKeyFunc(url string, opts ...JWKSOption) KeyFunc {
+	j := &jwks{
+		url:     url,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		refresh: time.Hour,
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+	return j.key
+}
+
+// jwks caches a remote key set.
+type jwks struct {
+	url     string
+	client  *http.Client
+	refresh time.Duration
+	now     func() time.Time
+
+	mu       sync.Mutex
+	keys     map[string]any
+	fetched  time.Time // Time of the last fetch, failed or not.
+	fetchErr error     // Error of the last fetch.
+	fetching *jwksFetch
+}
+
+// jwksFetch is an in-flight key set fetch shared by concurrent lookups.
+// done is closed when it finishes.
+type jwksFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// maxJWKSSize limits the size of fetched key sets.
+const maxJWKSSize = 1 << 20
+
+// jwksMinInterval is the minimum time between fetches triggered by unknown
+// key IDs or retrying a failed first fetch.
+const jwksMinInterval = time.Minute
+
+// jwksFetchTimeout bounds a key set fetch, which is detached from the
+// contexts of the requests waiting for it.
+const jwksFetchTimeout = 10 * time.Second
+
+// key returns the key with the ID, fetching the key set if needed. While
+// no key set could be fetched, the last fetch error is returned and fetches
+// are retried at most once per jwksMinInterval. The fetch runs without
+// holding the lock, so lookups of cached keys are not delayed, and
+// concurrent lookups share one fetch. A lookup whose context ends stops
+// waiting without canceling the fetch for the others.
+func (j *jwks) key(ctx context.Context, _, kid string) (any, error) {
+	j.mu.Lock()
+	key, ok := j.keys[kid]
+	since := j.now().Sub(j.fetched)
+	var stale bool
+	switch {
+	case j.fetched.IsZero():
+		stale = true
+	case j.keys == nil:
+		// Back off while the key set is unavailable.
+		stale = since >= jwksMinInterval
+	default:
+		stale = since >= j.refresh || (!ok && since >= jwksMinInterval)
+	}
+	if !stale {
+		fetchErr := j.fetchErr
+		empty := j.keys == nil
+		j.mu.Unlock()
+		if empty {
+			return nil, fetchErr
+		}
+		return jwksLookup(key, ok, kid)
+	}
+	f := j.fetching
+	if f == nil {
+		f = &jwksFetch{done: make(chan struct{})}
+		j.fetching = f
+		go j.refetch(f)
+	}
+	j.mu.Unlock()
+
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	j.mu.Lock()
+	key, ok = j.keys[kid]
+	empty := j.keys == nil
+	j.mu.Unlock()
+	if f.err != nil && empty {
+		return nil, f.err
+	}
+	return jwksLookup(key, ok, kid)
+}
+
+// refetch runs a fetch and stores its result. A failed fetch keeps the
+// previous keys.
+func (j *jwks) refetch(f *jwksFetch) {
+	ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+	defer cancel()
+	keys, err := j.fetch(ctx)
+
+	j.mu.Lock()
+	if err == nil {
+		j.keys = keys
+	}
+	j.fetched = j.now()
+	j.fetchErr = err
+	j.fetching = nil
+	f.err = err
+	j.mu.Unlock()
+	close(f.done)
+}
+
+// jwksLookup returns the result of a key lookup.
+func jwksLookup(key any, ok bool, kid string) (any, error) {
+	if !ok {
+		return nil, fmt.Errorf("unknown key ID %q", kid)
+	}
+	return key, nil
+}
+
+// fetch downloads and parses the key set.
+func (j *jwks) fetch(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch key set: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch key set: status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+	if err != nil {
+		return nil, fmt.Errorf("fetch key set: %w", err)
+	}
+	return jwt.ParseJWKS(data)
+}
+
+// claimsKey is the context key of the verified token claims.
+type claimsKey struct{}
+
+// ClaimsFromContext returns the claims stored by BearerAuth.
+//
+// Parameters:
+//   - ctx: The context.
+//
+// Returns:
+//   - Claims: The token claims.
+//   - bool: Whether claims were found.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	c, ok := ctx.Value(claimsKey{}).(Claims)
+	return c, ok
+}
+
+// ClaimsFromRequest returns the claims of a request authenticated by
+// BearerAuth.
+//
+// Parameters:
+//   - r: The HTTP request.
+//
+// Returns:
+//   - Claims: The token claims.
+//   - bool: Whether claims were found.
+func ClaimsFromRequest(r *http.Request) (Claims, bool) {
+	return ClaimsFromContext(r.Context())
+}
+
+// BearerOption configures BearerAuth.
+type BearerOption func(*bearerConfig)
+
+// bearerConfig holds the bearer auth settings.
+type bearerConfig struct {
+	realm  string
+	scopes []string
+}
+
+// WithBearerRealm sets the realm announced in the WWW-Authenticate header.
+//
+// Parameters:
+//   - realm: The realm.
+//
+// Returns:
+//   - BearerOption: The option.
+func WithBearerRealm(realm string) BearerOption {
+	return func(c *bearerConfig) { c.realm = realm }
+}
+
+// WithBearerScopes requires the token to grant all scopes. Tokens lacking
+// one are rejected with 403.
+//
+// Parameters:
+//   - scopes: The required scopes.
+//
+// Returns:
+//   - BearerOption: The option.
+func WithBearerScopes(scopes ...string) BearerOption {
+	return func(c *bearerConfig) { c.scopes = scopes }
+}
+
+// BearerAuth returns a middleware authenticating requests with
+// "Authorization: Bearer" tokens. The verified claims are stored in the
+// request context, together with a Principal built from the sub, roles and
+// scope claims.
+//
+// Missing and invalid tokens are rejected with a 401 unauthorized API
+// error, tokens lacking a required scope with a 403 forbidden one, each
+// with a RFC 6750 WWW-Authenticate challenge. API errors returned by the
+// verifier are rendered with their registry status.
+//
+// Parameters:
+//   - verifier: The token verifier, e.g. JWTVerifier.
+//   - opts: Optional bearer options.
+//
+// Returns:
+//   - Middleware: The bearer auth middleware.
+func BearerAuth(verifier TokenVerifier, opts ...BearerOption) Middleware {
+	var cfg bearerConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				unauthorized(w, r, cfg.challenge(""), "missing bearer token")
+				return
+			}
+			claims, err := verifier.Verify(r.Context(), token)
+			if err != nil {
+				var apiErr apierror.APIError
+				if errors.As(err, &apiErr) && !errors.Is(err, ErrTokenInvalid) {
+					status, public := defaultErrorRegistry.Handle(err)
+					writeAPIError(w, r, status, public)
+					return
+				}
+				unauthorized(w, r, cfg.challenge(`error="invalid_token"`),
+					"invalid bearer token")
+				return
+			}
+			for _, scope := range cfg.scopes {
+				if !slices.Contains(claims.Scopes(), scope) {
+					w.Header().Set("WWW-Authenticate", cfg.challenge(fmt.Sprintf(
+						`error="insufficient_scope", scope=%q`,
+						strings.Join(cfg.scopes, " "),
+					)))
+					writeAPIError(w, r, http.StatusForbidden,
+						apierror.NewAPIError(ErrIDForbidden).
+							WithMessage("insufficient scope"))
+					return
+				}
+			}
+			ctx := context.WithValue(r.Context(), claimsKey{}, claims)
+			ctx = WithPrincipal(ctx, &Principal{
+				ID:         claims.Subject(),
+				Roles:      claims.Strings("roles"),
+				Scopes:     claims.Scopes(),
+				Attributes: claims,
+			})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// challenge returns a Bearer WWW-Authenticate challenge with params.
+func (c *bearerConfig) challenge(params string) string {
+	var parts []string
+	if c.realm != "" {
+		parts = append(parts, fmt.Sprintf("realm=%q", c.realm))
+	}
+	if params != "" {
+		parts = append(parts, params)
+	}
+	if len(parts) == 0 {
+		return "Bearer"
+	}
+	return "Bearer " + strings.Join(parts, ", ")
+}
+
+// bearerToken extracts the token of an Authorization: Bearer header.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
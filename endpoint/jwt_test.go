@@ -0,0 +1,241 @@
+package endpoint
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/aatuh/pureapi-core/internal/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jwtTestSecret = []byte("jwt-secret")
+
+func signTestJWT(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	token, err := jwt.Sign(jwt.Header{Alg: "HS256"}, claims, jwtTestSecret)
+	require.NoError(t, err)
+	return token
+}
+
+func TestJWTVerifier(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	verifier := JWTVerifier(JWTSecret(jwtTestSecret),
+		WithJWTIssuer("https://issuer"), WithJWTAudience("api"),
+		WithJWTLeeway(0)).(*jwtVerifier)
+	verifier.now = func() time.Time { return now }
+
+	valid := map[string]any{
+		"sub": "ada", "iss": "https://issuer", "aud": []string{"api", "web"},
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	with := func(key string, value any) map[string]any {
+		claims := map[string]any{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		claims[key] = value
+		return claims
+	}
+
+	testCases := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{name: "Valid", token: signTestJWT(t, valid)},
+		{name: "Expired", token: signTestJWT(t, with("exp", now.Unix())),
+			wantErr: "invalid token: token expired"},
+		{name: "Not yet valid", token: signTestJWT(t, with("nbf", now.Add(time.Hour).Unix())),
+			wantErr: "invalid token: token not yet valid"},
+		{name: "Issuer", token: signTestJWT(t, with("iss", "other")),
+			wantErr: "invalid token: unexpected issuer"},
+		{name: "Audience", token: signTestJWT(t, with("aud", "web")),
+			wantErr: "invalid token: unexpected audience"},
+		{name: "Malformed", token: "x.y",
+			wantErr: "invalid token: jwt: malformed token"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := verifier.Verify(context.Background(), tc.token)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				assert.ErrorIs(t, err, ErrTokenInvalid)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "ada", claims.Subject())
+			assert.Equal(t, []string{"api", "web"}, claims.Audience())
+		})
+	}
+
+	restricted := JWTVerifier(JWTSecret(jwtTestSecret), WithJWTAlgorithms("RS256"))
+	_, err := restricted.Verify(context.Background(), signTestJWT(t, valid))
+	assert.EqualError(t, err, `invalid token: algorithm "HS256" not allowed`)
+}
+
+func TestJWKSKeyFunc(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	b64 := base64.RawURLEncoding.EncodeToString
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		fmt.Fprintf(w, `{"keys":[{"kty":"EC","kid":"k1","crv":"P-256","x":"%s","y":"%s"}]}`,
+			b64(key.X.FillBytes(make([]byte, 32))), b64(key.Y.FillBytes(make([]byte, 32))))
+	}))
+	defer srv.Close()
+
+	verifier := JWTVerifier(JWKSKeyFunc(srv.URL))
+	token, err := jwt.Sign(jwt.Header{Alg: "ES256", Kid: "k1"},
+		map[string]any{"sub": "svc"}, key)
+	require.NoError(t, err)
+	for range 2 {
+		claims, err := verifier.Verify(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, "svc", claims.Subject())
+	}
+	assert.Equal(t, int32(1), fetches.Load())
+
+	token, err = jwt.Sign(jwt.Header{Alg: "ES256", Kid: "k2"},
+		map[string]any{"sub": "svc"}, key)
+	require.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), token)
+	assert.EqualError(t, err, `invalid token: unknown key ID "k2"`)
+}
+
+func TestJWKSKeyFunc_SharedFetch(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	b64 := base64.RawURLEncoding.EncodeToString
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		<-release
+		fmt.Fprintf(w, `{"keys":[{"kty":"EC","kid":"k1","crv":"P-256","x":"%s","y":"%s"}]}`,
+			b64(key.X.FillBytes(make([]byte, 32))), b64(key.Y.FillBytes(make([]byte, 32))))
+	}))
+	defer srv.Close()
+	keyFunc := JWKSKeyFunc(srv.URL)
+
+	// A lookup giving up does not cancel the fetch for the others.
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error, 1)
+	go func() {
+		_, err := keyFunc(ctx, "ES256", "k1")
+		canceled <- err
+	}()
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := keyFunc(context.Background(), "ES256", "k1")
+			assert.NoError(t, err)
+			assert.NotNil(t, got)
+		}()
+	}
+	cancel()
+	assert.ErrorIs(t, <-canceled, context.Canceled)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), fetches.Load())
+}
+
+func TestJWKSKeyFunc_FailureBackoff(t *testing.T) {
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	now := time.Unix(1_700_000_000, 0)
+	j := &jwks{url: srv.URL, client: srv.Client(), refresh: time.Hour,
+		now: func() time.Time { return now }}
+
+	for range 5 {
+		_, err := j.key(context.Background(), "ES256", "k1")
+		assert.EqualError(t, err, "fetch key set: status 503")
+	}
+	assert.Equal(t, int32(1), fetches.Load())
+
+	now = now.Add(jwksMinInterval)
+	_, err := j.key(context.Background(), "ES256", "k1")
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), fetches.Load())
+}
+
+func TestBearerAuth(t *testing.T) {
+	verifier := JWTVerifier(JWTSecret(jwtTestSecret))
+	token := signTestJWT(t, map[string]any{
+		"sub": "ada", "scope": "read write", "roles": []string{"admin"},
+	})
+	forbidden := TokenVerifierFunc(func(context.Context, string) (Claims, error) {
+		return nil, apierror.NewAPIError(ErrIDForbidden).WithMessage("banned")
+	})
+
+	testCases := []struct {
+		name          string
+		verifier      TokenVerifier
+		opts          []BearerOption
+		header        string
+		wantStatus    int
+		wantChallenge string
+		wantBody      string
+	}{
+		{name: "Valid", verifier: verifier, header: "Bearer " + token,
+			opts: []BearerOption{WithBearerScopes("read")}, wantStatus: http.StatusOK,
+			wantBody: "ada [admin] [read write]"},
+		{name: "Missing", verifier: verifier, opts: []BearerOption{WithBearerRealm("api")},
+			wantStatus: http.StatusUnauthorized, wantChallenge: `Bearer realm="api"`,
+			wantBody: `{"id":"unauthorized","message":"missing bearer token"}`},
+		{name: "Invalid", verifier: verifier, header: "Bearer " + token + "x",
+			wantStatus: http.StatusUnauthorized, wantChallenge: `Bearer error="invalid_token"`,
+			wantBody: `{"id":"unauthorized","message":"invalid bearer token"}`},
+		{name: "Insufficient scope", verifier: verifier, header: "bearer " + token,
+			opts: []BearerOption{WithBearerScopes("read", "admin")}, wantStatus: http.StatusForbidden,
+			wantChallenge: `Bearer error="insufficient_scope", scope="read admin"`,
+			wantBody:      `{"id":"forbidden","message":"insufficient scope"}`},
+		{name: "Verifier API error", verifier: forbidden, header: "Bearer x",
+			wantStatus: http.StatusForbidden,
+			wantBody:   `{"id":"forbidden","message":"banned"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := BearerAuth(tc.verifier, tc.opts...)(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					p, _ := PrincipalFromRequest(r)
+					claims, _ := ClaimsFromRequest(r)
+					fmt.Fprintf(w, "%s %v %v", claims.Subject(), p.Roles, p.Scopes)
+				},
+			))
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				r.Header.Set("Authorization", tc.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			assert.Equal(t, tc.wantChallenge, rec.Header().Get("WWW-Authenticate"))
+			if tc.wantStatus == http.StatusOK {
+				assert.Equal(t, tc.wantBody, rec.Body.String())
+				return
+			}
+			assert.JSONEq(t, tc.wantBody, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package endpoint
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// localeKey is the context key of the negotiated locale.
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx carrying the locale.
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - locale: The locale, a BCP 47 language tag such as "en-US".
+//
+// Returns:
+//   - context.Context: The derived context.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale stored in ctx.
+//
+// Parameters:
+//   - ctx: The context.
+//
+// Returns:
+//   - string: The locale.
+//   - bool: Whether a locale was found.
+func LocaleFromContext(ctx context.Context) (string, bool) {
+	l, ok := ctx.Value(localeKey{}).(string)
+	return l, ok && l != ""
+}
+
+// LocaleFromRequest returns the locale negotiated for a request.
+//
+// Parameters:
+//   - r: The HTTP request.
+//
+// Returns:
+//   - string: The locale.
+//   - bool: Whether a locale was negotiated.
+func LocaleFromRequest(r *http.Request) (string, bool) {
+	return LocaleFromContext(r.Context())
+}
+
+// NegotiateLocale returns a middleware choosing the locale of each request
+// from its Accept-Language header and the supported locales, with
+// MatchLocale. The locale is stored in the request context, read with
+// LocaleFromRequest, and announced in the Content-Language header; Vary
+// gets Accept-Language so caches keep the translations apart. It panics
+// without supported locales, since that is a programming error.
+//
+// Parameters:
+//   - supported: The supported locales, the default first.
+//
+// Returns:
+//   - Middleware: The locale negotiation middleware.
+func NegotiateLocale(supported ...string) Middleware {
+	if len(supported) == 0 {
+		panic("endpoint: NegotiateLocale: no supported locales")
+	}
+	supported = slices.Clone(supported)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			locale := MatchLocale(
+				strings.Join(r.Header.Values("Accept-Language"), ","),
+				supported,
+			)
+			w.Header().Add("Vary", "Accept-Language")
+			w.Header().Set("Content-Language", locale)
+			next.ServeHTTP(w, r.WithContext(WithLocale(r.Context(), locale)))
+		})
+	}
+}
+
+// MatchLocale returns the supported locale best matching an
+// Accept-Language header. Language ranges are tried by descending quality;
+// a range matches a supported locale equal to it, then one it is a prefix
+// of ("en" matches "en-US"), then one equal to the range with subtags
+// removed ("en-GB" matches "en"). Matching ignores case. Without a match,
+// or for "*", the first supported locale is returned.
+//
+// Parameters:
+//   - acceptLanguage: The Accept-Language header value.
+//   - supported: The supported locales, the default first.
+//
+// Returns:
+//   - string: The matched locale, or "" if supported is empty.
+func MatchLocale(acceptLanguage string, supported []string) string {
+	if len(supported) == 0 {
+		return ""
+	}
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if tag == "*" {
+			break
+		}
+		if l, ok := lookupLocale(tag, supported); ok {
+			return l
+		}
+	}
+	return supported[0]
+}
+
+// lookupLocale matches one language range against the supported locales.
+func lookupLocale(tag string, supported []string) (string, bool) {
+	for _, l := range supported {
+		if strings.EqualFold(l, tag) {
+			return l, true
+		}
+	}
+	for _, l := range supported {
+		if len(l) > len(tag) && l[len(tag)] == '-' &&
+			strings.EqualFold(l[:len(tag)], tag) {
+			return l, true
+		}
+	}
+	for i := strings.LastIndexByte(tag, '-'); i > 0; i = strings.LastIndexByte(tag, '-') {
+		tag = tag[:i]
+		for _, l := range supported {
+			if strings.EqualFold(l, tag) {
+				return l, true
+			}
+		}
+	}
+	return "", false
+}
+
+// parseAcceptLanguage returns the language ranges of an Accept-Language
+// header by descending quality, dropping excluded ranges.
+func parseAcceptLanguage(value string) []string {
+	type langRange struct {
+		tag string
+		q   float64
+	}
+	var ranges []langRange
+	for _, part := range strings.Split(value, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if k, v, ok := strings.Cut(strings.TrimSpace(params), "="); ok &&
+			strings.TrimSpace(k) == "q" {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, langRange{tag: tag, q: q})
+		}
+	}
+	slices.SortStableFunc(ranges, func(a, b langRange) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		}
+		return 0
+	})
+	tags := make([]string, len(ranges))
+	for i, r := range ranges {
+		tags[i] = r.tag
+	}
+	return tags
+}
+
+// MessageCatalog holds translated error messages by locale and API error
+// ID.
+type MessageCatalog = apierror.Catalog
+
+// LocalizedErrorRenderer returns an error renderer replacing the messages
+// of API errors with their translation for the request locale, as
+// negotiated by NegotiateLocale. Translations are looked up in catalog,
+// then among the messages registered with apierror.RegisterMessages; a
+// locale without one falls back to its base language, "fi-FI" to "fi".
+// Errors without a translation keep their message. The result is rendered
+// by next, DefaultErrorRenderer if nil:
+//
+//	apierror.RegisterMessages("fi", map[string]string{
+//		"not_found": "Resurssia ei löytynyt",
+//	})
+//	endpoint.SetErrorRenderer(endpoint.LocalizedErrorRenderer(nil, nil))
+//
+// Parameters:
+//   - catalog: Translated messages overriding the registered ones, or nil.
+//   - next: The renderer of the translated errors.
+//
+// Returns:
+//   - ErrorRenderer: The localizing error renderer.
+func LocalizedErrorRenderer(
+	catalog MessageCatalog, next ErrorRenderer,
+) ErrorRenderer {
+	if next == nil {
+		next = DefaultErrorRenderer
+	}
+	return func(r *http.Request, status int, err apierror.APIError) any {
+		if locale, ok := LocaleFromRequest(r); ok {
+			if msg, ok := catalog.Message(err.ID(), locale); ok {
+				err = apierror.APIErrorFrom(err).WithMessage(msg)
+			} else {
+				err = apierror.APIErrorFrom(err).Localize(locale)
+			}
+		}
+		return next(r, status, err)
+	}
+}
@@ -0,0 +1,77 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchLocale(t *testing.T) {
+	supported := []string{"en-US", "fi", "de-DE", "de"}
+	testCases := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{"Empty", "", "en-US"},
+		{"Exact", "fi", "fi"},
+		{"Case", "DE-de", "de-DE"},
+		{"Prefix", "en", "en-US"},
+		{"Truncated", "fi-FI", "fi"},
+		{"Quality", "fi;q=0.5, de-CH;q=0.8", "de"},
+		{"Order", "de-AT, fi", "de"},
+		{"Excluded", "fi;q=0, sv", "en-US"},
+		{"Wildcard", "sv, *;q=0.5", "en-US"},
+		{"Unsupported", "sv, ja", "en-US"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, MatchLocale(tc.accept, supported))
+		})
+	}
+	assert.Equal(t, "", MatchLocale("en", nil))
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	var locale string
+	h := NegotiateLocale("en", "fi")(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			locale, _ = LocaleFromRequest(r)
+		},
+	))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Language", "fi-FI,fi;q=0.9,en;q=0.8")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+
+	assert.Equal(t, "fi", locale)
+	assert.Equal(t, "fi", rec.Header().Get("Content-Language"))
+	assert.Equal(t, "Accept-Language", rec.Header().Get("Vary"))
+
+	assert.Panics(t, func() { NegotiateLocale() })
+}
+
+func TestLocalizedErrorRenderer(t *testing.T) {
+	render := LocalizedErrorRenderer(MessageCatalog{
+		"fi": {"not_found": "Ei löytynyt"},
+	}, nil)
+	err := apierror.NewAPIError("not_found").WithMessage("Not found")
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Equal(t, "Not found",
+		render(r, 404, err).(apierror.APIError).Message())
+
+	r = r.WithContext(WithLocale(r.Context(), "fi"))
+	assert.Equal(t, "Ei löytynyt",
+		render(r, 404, err).(apierror.APIError).Message())
+	assert.Equal(t, "Not found", err.Message())
+
+	// Registered messages serve locales missing from the catalog.
+	apierror.RegisterMessages("sv", map[string]string{"not_found": "Hittades inte"})
+	r = r.WithContext(WithLocale(r.Context(), "sv-SE"))
+	assert.Equal(t, "Hittades inte",
+		render(r, 404, err).(apierror.APIError).Message())
+}
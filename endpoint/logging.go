@@ -0,0 +1,321 @@
+package endpoint
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aatuh/pureapi-core/event"
+)
+
+// EventRequestLog is emitted by LoggingMiddleware for every request. Its
+// data is a *RequestLog.
+const EventRequestLog event.EventType = "event_request_log"
+
+// redactedValue replaces the values of redacted headers.
+const redactedValue = "[REDACTED]"
+
+// RequestLog describes a handled request. It implements slog.LogValuer, so
+// listeners can pass it to a log/slog logger as is.
+type RequestLog struct {
+	Method                string
+	Path                  string
+	Query                 string
+	RequestID             string
+	Status                int
+	Duration              time.Duration
+	RequestHeader         http.Header
+	RequestBody           string // Captured body, empty if not logged.
+	RequestBodyTruncated  bool
+	RequestBytes          int64 // Bytes read by the handler.
+	ResponseHeader        http.Header
+	ResponseBody          string // Captured body, empty if not logged.
+	ResponseBodyTruncated bool
+	ResponseBytes         int64
+}
+
+// LogValue returns the log as a slog group.
+//
+// Returns:
+//   - slog.Value: The group value.
+func (l *RequestLog) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("method", l.Method),
+		slog.String("path", l.Path),
+		slog.Int("status", l.Status),
+		slog.Duration("duration", l.Duration),
+		slog.Int64("request_bytes", l.RequestBytes),
+		slog.Int64("response_bytes", l.ResponseBytes),
+	}
+	if l.Query != "" {
+		attrs = append(attrs, slog.String("query", l.Query))
+	}
+	if l.RequestID != "" {
+		attrs = append(attrs, slog.String("request_id", l.RequestID))
+	}
+	attrs = append(attrs,
+		slog.Any("request_header", l.RequestHeader),
+		slog.Any("response_header", l.ResponseHeader),
+	)
+	if l.RequestBody != "" {
+		attrs = append(attrs,
+			slog.String("request_body", l.RequestBody),
+			slog.Bool("request_body_truncated", l.RequestBodyTruncated),
+		)
+	}
+	if l.ResponseBody != "" {
+		attrs = append(attrs,
+			slog.String("response_body", l.ResponseBody),
+			slog.Bool("response_body_truncated", l.ResponseBodyTruncated),
+		)
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LoggingOption configures LoggingMiddleware.
+type LoggingOption func(*loggingConfig)
+
+// WithLogBodyLimit sets the maximum number of body bytes captured per
+// request and response. Defaults to 4096. Zero disables body capture.
+//
+// Parameters:
+//   - n: The maximum number of bytes.
+//
+// Returns:
+//   - LoggingOption: A logging option function.
+func WithLogBodyLimit(n int) LoggingOption {
+	return func(c *loggingConfig) { c.bodyLimit = n }
+}
+
+// WithLogBodyTypes replaces the media types whose bodies are captured.
+// Entries ending in "/" match a whole top-level type (e.g. "text/"),
+// entries starting with "+" match a structured syntax suffix (e.g. "+json").
+// Defaults to JSON, XML, form-urlencoded and text bodies.
+//
+// Parameters:
+//   - types: The media types to capture.
+//
+// Returns:
+//   - LoggingOption: A logging option function.
+func WithLogBodyTypes(types ...string) LoggingOption {
+	return func(c *loggingConfig) { c.bodyTypes = types }
+}
+
+// WithLogRedactHeaders adds headers whose values are replaced with
+// "[REDACTED]". Authorization, Proxy-Authorization, Cookie, Set-Cookie and
+// X-Api-Key are always redacted.
+//
+// Parameters:
+//   - names: The header names.
+//
+// Returns:
+//   - LoggingOption: A logging option function.
+func WithLogRedactHeaders(names ...string) LoggingOption {
+	return func(c *loggingConfig) {
+		for _, name := range names {
+			c.redact[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// loggingConfig holds the logging settings.
+type loggingConfig struct {
+	bodyLimit int
+	bodyTypes []string
+	redact    map[string]bool
+}
+
+// LoggingMiddleware returns a middleware emitting EventRequestLog with the
+// request and response details of every request. Bodies are captured up to
+// the body limit when their media type is allowed; only the request bytes
+// the handler actually reads are seen. Sensitive headers are redacted.
+//
+// Parameters:
+//   - emitter: The event emitter receiving the logs.
+//   - opts: Optional logging options.
+//
+// Returns:
+//   - Middleware: The logging middleware.
+func LoggingMiddleware(
+	emitter event.EventEmitter, opts ...LoggingOption,
+) Middleware {
+	cfg := loggingConfig{
+		bodyLimit: 4096,
+		bodyTypes: []string{
+			"application/json", "+json", "application/xml", "+xml",
+			"application/x-www-form-urlencoded", "text/",
+		},
+		redact: map[string]bool{
+			"Authorization":       true,
+			"Proxy-Authorization": true,
+			"Cookie":              true,
+			"Set-Cookie":          true,
+			"X-Api-Key":           true,
+		},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if emitter == nil {
+		emitter = event.NewNoopEventEmitter()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			body := &captureReader{r: r.Body}
+			if r.Body != nil {
+				if cfg.capture(r.Header.Get("Content-Type")) {
+					body.limit = cfg.bodyLimit
+				}
+				r = r.WithContext(r.Context())
+				r.Body = body
+			}
+			lw := &loggingWriter{ResponseWriter: w, cfg: &cfg}
+			defer func() {
+				log := &RequestLog{
+					Method:                r.Method,
+					Path:                  r.URL.Path,
+					Query:                 r.URL.RawQuery,
+					RequestID:             RequestIDFromRequest(r),
+					Status:                lw.status,
+					Duration:              time.Since(start),
+					RequestHeader:         cfg.redactHeader(r.Header),
+					RequestBody:           body.buf.String(),
+					RequestBodyTruncated:  body.truncated,
+					RequestBytes:          body.n,
+					ResponseHeader:        cfg.redactHeader(w.Header()),
+					ResponseBody:          lw.buf.String(),
+					ResponseBodyTruncated: lw.truncated,
+					ResponseBytes:         lw.n,
+				}
+				if log.Status == 0 {
+					log.Status = http.StatusOK
+				}
+				event.EmitCtx(emitter, r.Context(), event.NewEvent(
+					EventRequestLog,
+					fmt.Sprintf(
+						"%s %s, status: %d, duration: %s",
+						log.Method, log.Path, log.Status, log.Duration,
+					),
+				).WithData(log))
+			}()
+			next.ServeHTTP(lw, r)
+		})
+	}
+}
+
+// capture reports whether bodies of the content type are captured.
+func (c *loggingConfig) capture(contentType string) bool {
+	if c.bodyLimit <= 0 || contentType == "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range c.bodyTypes {
+		switch {
+		case strings.HasSuffix(t, "/"):
+			if strings.HasPrefix(mediaType, t) {
+				return true
+			}
+		case strings.HasPrefix(t, "+"):
+			if strings.HasSuffix(mediaType, t) {
+				return true
+			}
+		case mediaType == t:
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeader returns a copy of h with sensitive values replaced.
+func (c *loggingConfig) redactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for name, values := range out {
+		if c.redact[name] {
+			for i := range values {
+				values[i] = redactedValue
+			}
+		}
+	}
+	return out
+}
+
+// captureReader counts the bytes read and keeps up to limit of them.
+type captureReader struct {
+	r         io.ReadCloser
+	limit     int
+	buf       bytes.Buffer
+	n         int64
+	truncated bool
+}
+
+func (c *captureReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	c.truncated = keep(&c.buf, p[:n], c.limit) || c.truncated
+	return n, err
+}
+
+func (c *captureReader) Close() error { return c.r.Close() }
+
+// loggingWriter records the status and captures the response body.
+type loggingWriter struct {
+	http.ResponseWriter
+	cfg       *loggingConfig
+	status    int
+	capture   bool
+	buf       bytes.Buffer
+	n         int64
+	truncated bool
+}
+
+func (lw *loggingWriter) WriteHeader(code int) {
+	if lw.status == 0 {
+		lw.status = code
+		lw.capture = lw.cfg.capture(lw.Header().Get("Content-Type"))
+	}
+	lw.ResponseWriter.WriteHeader(code)
+}
+
+func (lw *loggingWriter) Write(p []byte) (int, error) {
+	if lw.status == 0 {
+		if lw.Header().Get("Content-Type") == "" {
+			lw.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		lw.WriteHeader(http.StatusOK)
+	}
+	n, err := lw.ResponseWriter.Write(p)
+	lw.n += int64(n)
+	if lw.capture {
+		lw.truncated = keep(&lw.buf, p[:n], lw.cfg.bodyLimit) || lw.truncated
+	}
+	return n, err
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (lw *loggingWriter) Unwrap() http.ResponseWriter {
+	return lw.ResponseWriter
+}
+
+// keep appends p to buf up to limit bytes and reports whether bytes were
+// dropped.
+func keep(buf *bytes.Buffer, p []byte, limit int) bool {
+	room := limit - buf.Len()
+	if room <= 0 {
+		return len(p) > 0 && limit > 0
+	}
+	if len(p) > room {
+		buf.Write(p[:room])
+		return true
+	}
+	buf.Write(p)
+	return false
+}
@@ -0,0 +1,108 @@
+package endpoint
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingMiddleware(t *testing.T) {
+	emitter := &dummyEventEmitter{}
+	h := LoggingMiddleware(emitter, WithLogBodyLimit(8), WithLogRedactHeaders("x-secret"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Set-Cookie", "session=abc")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":1,"name":"Go"}`))
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/users?x=1", strings.NewReader(`{"name":"Go"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("X-Secret", "s3")
+	req.Header.Set("Accept", "application/json")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Len(t, emitter.events, 1)
+	assert.Equal(t, EventRequestLog, emitter.events[0].Type)
+	log := emitter.events[0].Data.(*RequestLog)
+	assert.Equal(t, http.MethodPost, log.Method)
+	assert.Equal(t, "/users", log.Path)
+	assert.Equal(t, "x=1", log.Query)
+	assert.Equal(t, http.StatusCreated, log.Status)
+	assert.Equal(t, `{"name":`, log.RequestBody)
+	assert.True(t, log.RequestBodyTruncated)
+	assert.Equal(t, int64(13), log.RequestBytes)
+	assert.Equal(t, `{"id":1,`, log.ResponseBody)
+	assert.True(t, log.ResponseBodyTruncated)
+	assert.Equal(t, int64(20), log.ResponseBytes)
+	assert.Equal(t, redactedValue, log.RequestHeader.Get("Authorization"))
+	assert.Equal(t, redactedValue, log.RequestHeader.Get("X-Secret"))
+	assert.Equal(t, "application/json", log.RequestHeader.Get("Accept"))
+	assert.Equal(t, redactedValue, log.ResponseHeader.Get("Set-Cookie"))
+	assert.Equal(t, "Bearer token", req.Header.Get("Authorization"), "request must not be modified")
+}
+
+func TestLoggingMiddleware_BodyTypes(t *testing.T) {
+	testCases := []struct {
+		name        string
+		opts        []LoggingOption
+		contentType string
+		wantBody    string
+	}{
+		{name: "Text", contentType: "text/plain; charset=utf-8", wantBody: "hello"},
+		{name: "Binary", contentType: "application/octet-stream"},
+		{name: "Custom types", opts: []LoggingOption{WithLogBodyTypes("application/octet-stream")},
+			contentType: "application/octet-stream", wantBody: "hello"},
+		{name: "Disabled", opts: []LoggingOption{WithLogBodyLimit(0)}, contentType: "text/plain"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			emitter := &dummyEventEmitter{}
+			h := LoggingMiddleware(emitter, tc.opts...)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					body, _ := io.ReadAll(r.Body)
+					w.Header().Set("Content-Type", tc.contentType)
+					_, _ = w.Write(body)
+				}),
+			)
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello"))
+			req.Header.Set("Content-Type", tc.contentType)
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			log := emitter.events[0].Data.(*RequestLog)
+			assert.Equal(t, tc.wantBody, log.RequestBody)
+			assert.Equal(t, tc.wantBody, log.ResponseBody)
+			assert.Equal(t, int64(5), log.RequestBytes)
+			assert.Equal(t, int64(5), log.ResponseBytes)
+			assert.Equal(t, http.StatusOK, log.Status)
+		})
+	}
+}
+
+func TestRequestLog_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	emitter := &dummyEventEmitter{}
+	h := LoggingMiddleware(emitter)(http.NotFoundHandler())
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	ev := emitter.events[0]
+	logger.Info(ev.Message, "request", ev.Data)
+	out := buf.String()
+	assert.Contains(t, out, `"path":"/missing"`)
+	assert.Contains(t, out, `"status":404`)
+	assert.Contains(t, out, `"response_body":"404 page not found\n"`)
+	assert.Equal(t, event.EventType("event_request_log"), ev.Type)
+}
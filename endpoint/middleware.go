@@ -38,6 +38,11 @@ type Stack interface {
 	InsertBefore(id string, w Wrapper) (Stack, bool)
 	InsertAfter(id string, w Wrapper) (Stack, bool)
 	Remove(id string) (Stack, bool)
+	AddGroup(name string, wrappers ...Wrapper) Stack
+	Group(name string) []Wrapper
+	Groups() []string
+	RemoveGroup(name string) (Stack, bool)
+	Merge(other Stack, policy MergePolicy) (Stack, error)
 }
 
 // DefaultMiddlewares is an immutable slice of Middleware functions.
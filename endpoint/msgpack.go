@@ -0,0 +1,99 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/aatuh/pureapi-core/internal/msgpack"
+)
+
+// MsgpackContentType is the Content-Type of MessagePack responses.
+const MsgpackContentType = "application/msgpack"
+
+// MsgpackEncoder returns an encoder producing application/msgpack, for use
+// with NegotiatingOutput. Structs are encoded as maps keyed by the
+// "msgpack" struct tag, falling back to the "json" tag, so API errors keep
+// their JSON field names.
+//
+// Returns:
+//   - Encoder: The MessagePack encoder.
+func MsgpackEncoder() Encoder { return msgpackCodec{} }
+
+// MsgpackDecoder returns a decoder for application/msgpack,
+// application/vnd.msgpack and application/x-msgpack bodies, for use with
+// NegotiatingInput. Fields are matched like MsgpackEncoder names them.
+//
+// Returns:
+//   - Decoder: The MessagePack decoder.
+func MsgpackDecoder() Decoder { return msgpackCodec{} }
+
+// MsgpackInput returns an input handler decoding MessagePack request
+// bodies into Input. Use NegotiatingInput with JSONDecoder and
+// MsgpackDecoder to accept both formats.
+//
+// Returns:
+//   - InputHandler[Input]: The MessagePack input handler.
+func MsgpackInput[Input any]() InputHandler[Input] {
+	return NegotiatingInput[Input](MsgpackDecoder())
+}
+
+// msgpackCodec encodes and decodes MessagePack.
+type msgpackCodec struct{}
+
+// ContentType returns the MessagePack content type.
+func (msgpackCodec) ContentType() string { return MsgpackContentType }
+
+// Encode writes v as MessagePack.
+func (msgpackCodec) Encode(w io.Writer, v any) error {
+	if e, ok := v.(apierror.APIError); ok {
+		v = apierror.APIErrorFrom(e)
+	}
+	data, err := msgpack.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// MediaTypes returns the accepted MessagePack media types.
+func (msgpackCodec) MediaTypes() []string {
+	return []string{
+		MsgpackContentType, "application/vnd.msgpack", "application/x-msgpack",
+	}
+}
+
+// Decode reads and decodes a MessagePack body.
+func (msgpackCodec) Decode(r io.Reader, v any) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return invalidInput("request body is empty")
+	}
+	err = msgpack.Unmarshal(body, v)
+	var typeErr *msgpack.TypeError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &typeErr):
+		if typeErr.Field != "" {
+			return invalidInput(fmt.Sprintf(
+				"field %q must be of type %s", typeErr.Field, typeErr.Type,
+			))
+		}
+		return invalidInput(fmt.Sprintf("body must be of type %s", typeErr.Type))
+	case errors.Is(err, msgpack.ErrSyntax):
+		return invalidInput("malformed MessagePack")
+	}
+	return err
+}
+
+// msgpackCodec implements the Encoder and Decoder interfaces.
+var (
+	_ Encoder = msgpackCodec{}
+	_ Decoder = msgpackCodec{}
+)
@@ -0,0 +1,111 @@
+package endpoint
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/internal/msgpack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func msgpackBody(t *testing.T, v any) *bytes.Reader {
+	t.Helper()
+	data, err := msgpack.Marshal(v)
+	require.NoError(t, err)
+	return bytes.NewReader(data)
+}
+
+func TestMsgpackInput(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", msgpackBody(t, map[string]any{"name": "Go", "age": 15}))
+	r.Header.Set("Content-Type", "application/x-msgpack")
+	in, err := MsgpackInput[jsonTestInput]().Handle(httptest.NewRecorder(), r)
+	require.NoError(t, err)
+	assert.Equal(t, jsonTestInput{Name: "Go", Age: 15}, *in)
+}
+
+func TestMsgpackInput_Errors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		body        func(t *testing.T) *bytes.Reader
+		wantStatus  int
+		wantMsg     string
+	}{
+		{name: "Wrong type", contentType: MsgpackContentType,
+			body: func(t *testing.T) *bytes.Reader {
+				return msgpackBody(t, map[string]any{"age": "old"})
+			},
+			wantStatus: http.StatusBadRequest, wantMsg: `field "age" must be of type int`},
+		{name: "Malformed", contentType: MsgpackContentType,
+			body:       func(*testing.T) *bytes.Reader { return bytes.NewReader([]byte{0x82, 0xa1}) },
+			wantStatus: http.StatusBadRequest, wantMsg: "malformed MessagePack"},
+		{name: "Empty", contentType: MsgpackContentType,
+			body:       func(*testing.T) *bytes.Reader { return bytes.NewReader(nil) },
+			wantStatus: http.StatusBadRequest, wantMsg: "request body is empty"},
+		{name: "JSON content type", contentType: "application/json",
+			body:       func(*testing.T) *bytes.Reader { return bytes.NewReader([]byte(`{}`)) },
+			wantStatus: http.StatusUnsupportedMediaType, wantMsg: `unsupported Content-Type "application/json"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", tc.body(t))
+			r.Header.Set("Content-Type", tc.contentType)
+			_, err := MsgpackInput[jsonTestInput]().Handle(httptest.NewRecorder(), r)
+			status, apiErr := DefaultErrorHandler{}.Handle(err)
+			assert.Equal(t, tc.wantStatus, status)
+			assert.Equal(t, tc.wantMsg, apiErr.Message())
+		})
+	}
+}
+
+func TestNegotiatingInput(t *testing.T) {
+	h := NegotiatingInput[jsonTestInput](JSONDecoder(WithJSONStrict()), MsgpackDecoder())
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Go"}`))
+	r.Header.Set("Content-Type", "application/vnd.api+json")
+	in, err := h.Handle(httptest.NewRecorder(), r)
+	require.NoError(t, err)
+	assert.Equal(t, "Go", in.Name)
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Go","x":1}`))
+	r.Header.Set("Content-Type", "application/json")
+	_, err = h.Handle(httptest.NewRecorder(), r)
+	_, apiErr := DefaultErrorHandler{}.Handle(err)
+	assert.Equal(t, ErrIDInvalidInput, apiErr.ID())
+
+	r = httptest.NewRequest(http.MethodPost, "/", msgpackBody(t, map[string]any{"name": "Pack"}))
+	r.Header.Set("Content-Type", MsgpackContentType)
+	in, err = h.Handle(httptest.NewRecorder(), r)
+	require.NoError(t, err)
+	assert.Equal(t, "Pack", in.Name)
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("x"))
+	_, err = h.Handle(httptest.NewRecorder(), r)
+	_, apiErr = DefaultErrorHandler{}.Handle(err)
+	assert.Equal(t, ErrIDUnsupportedMediaType, apiErr.ID())
+}
+
+func TestMsgpackEncoder_Negotiation(t *testing.T) {
+	oh := NegotiatingOutput(JSONEncoder(), MsgpackEncoder())
+
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/msgpack")
+	require.NoError(t, oh.Handle(rec, r, jsonTestInput{Name: "Go", Age: 15}, nil, http.StatusOK))
+	assert.Equal(t, MsgpackContentType, rec.Header().Get("Content-Type"))
+	var out map[string]any
+	require.NoError(t, msgpack.Unmarshal(rec.Body.Bytes(), &out))
+	assert.Equal(t, map[string]any{"name": "Go", "age": int64(15)}, out)
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, oh.Handle(rec, r, nil, errors.New("secret"), http.StatusInternalServerError))
+	out = nil
+	require.NoError(t, msgpack.Unmarshal(rec.Body.Bytes(), &out))
+	assert.Equal(t, map[string]any{"id": "internal_error", "message": "Internal server error"}, out)
+}
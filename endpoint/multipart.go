@@ -0,0 +1,421 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aatuh/pureapi-core/internal/bind"
+)
+
+// maxMultipartParts caps the number of parts read from one request.
+const maxMultipartParts = 1000
+
+// UploadedFile describes a file part of a multipart request. Input fields
+// of type *UploadedFile or []*UploadedFile tagged with the part name
+// receive the uploaded files.
+type UploadedFile struct {
+	FieldName   string               // Form field name of the part.
+	Filename    string               // Client file name, without directories.
+	ContentType string               // Content-Type of the part.
+	Header      textproto.MIMEHeader // Headers of the part.
+	Size        int64                // Number of bytes stored.
+	Path        string               // Location on disk, if stored there.
+}
+
+// Open opens the stored file for reading. It is only available for files
+// stored on disk.
+//
+// Returns:
+//   - *os.File: The opened file.
+//   - error: An error if the file is not on disk or cannot be opened.
+func (f *UploadedFile) Open() (*os.File, error) {
+	if f.Path == "" {
+		return nil, fmt.Errorf("UploadedFile: %q is not stored on disk", f.Filename)
+	}
+	return os.Open(f.Path)
+}
+
+// Remove deletes the stored file from disk. It does nothing for files not
+// stored on disk.
+//
+// Returns:
+//   - error: An error if the file cannot be removed.
+func (f *UploadedFile) Remove() error {
+	if f.Path == "" {
+		return nil
+	}
+	err := os.Remove(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// FileStore receives the content of uploaded files while the request is
+// streamed.
+type FileStore interface {
+	// Store consumes content and records its location in file, e.g. by
+	// setting file.Path. It returns the number of bytes stored.
+	Store(file *UploadedFile, content io.Reader) (int64, error)
+}
+
+// FileStoreFunc adapts a function to the FileStore interface.
+type FileStoreFunc func(file *UploadedFile, content io.Reader) (int64, error)
+
+// Store calls f.
+func (f FileStoreFunc) Store(file *UploadedFile, content io.Reader) (int64, error) {
+	return f(file, content)
+}
+
+// DiskStore returns a file store writing each upload to a new file in dir
+// and setting UploadedFile.Path. An empty dir uses os.TempDir. The files are
+// not removed automatically; call UploadedFile.Remove when done.
+//
+// Parameters:
+//   - dir: The destination directory.
+//
+// Returns:
+//   - FileStore: The disk file store.
+func DiskStore(dir string) FileStore {
+	return FileStoreFunc(func(file *UploadedFile, content io.Reader) (int64, error) {
+		f, err := os.CreateTemp(dir, "upload-*"+safeExt(file.Filename))
+		if err != nil {
+			return 0, err
+		}
+		file.Path = f.Name()
+		n, err := io.Copy(f, content)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return n, err
+	})
+}
+
+// WriterStore returns a file store copying each upload to the writer
+// returned by open, e.g. an object storage upload. Writers implementing
+// io.Closer are closed after the copy.
+//
+// Parameters:
+//   - open: Returns the destination writer for a file.
+//
+// Returns:
+//   - FileStore: The writer file store.
+func WriterStore(open func(file *UploadedFile) (io.Writer, error)) FileStore {
+	return FileStoreFunc(func(file *UploadedFile, content io.Reader) (int64, error) {
+		w, err := open(file)
+		if err != nil {
+			return 0, err
+		}
+		n, err := io.Copy(w, content)
+		if c, ok := w.(io.Closer); ok {
+			if cerr := c.Close(); err == nil {
+				err = cerr
+			}
+		}
+		return n, err
+	})
+}
+
+// safeExt returns the extension of a client file name if it is harmless.
+func safeExt(name string) string {
+	ext := filepath.Ext(name)
+	if len(ext) > 16 || strings.ContainsAny(ext, `/\*`) {
+		return ""
+	}
+	return ext
+}
+
+// MultipartInputOption configures a multipart input handler.
+type MultipartInputOption func(*multipartInput)
+
+// WithMultipartMaxPartSize sets the maximum size of a single part. Defaults
+// to 10 MiB.
+//
+// Parameters:
+//   - size: The maximum part size in bytes.
+//
+// Returns:
+//   - MultipartInputOption: A multipart input option function.
+func WithMultipartMaxPartSize(size int64) MultipartInputOption {
+	return func(m *multipartInput) { m.maxPartSize = size }
+}
+
+// WithMultipartMaxTotalSize sets the maximum combined size of all parts.
+// Defaults to 32 MiB.
+//
+// Parameters:
+//   - size: The maximum total size in bytes.
+//
+// Returns:
+//   - MultipartInputOption: A multipart input option function.
+func WithMultipartMaxTotalSize(size int64) MultipartInputOption {
+	return func(m *multipartInput) { m.maxTotalSize = size }
+}
+
+// WithMultipartStore sets where file parts are streamed to. Defaults to
+// DiskStore("").
+//
+// Parameters:
+//   - store: The file store.
+//
+// Returns:
+//   - MultipartInputOption: A multipart input option function.
+func WithMultipartStore(store FileStore) MultipartInputOption {
+	return func(m *multipartInput) { m.store = store }
+}
+
+// WithMultipartTimeout sets the time allowed for reading the whole body.
+// Only StreamMultipartInput applies it; by default there is no limit
+// besides the server's read timeout.
+//
+// Parameters:
+//   - d: The maximum time to read the body.
+//
+// Returns:
+//   - MultipartInputOption: A multipart input option function.
+func WithMultipartTimeout(d time.Duration) MultipartInputOption {
+	return func(m *multipartInput) { m.timeout = d }
+}
+
+// MultipartInput returns an input handler streaming multipart/form-data
+// bodies into Input. File parts are passed to the file store as they are
+// read and bound to *UploadedFile or []*UploadedFile fields; other parts are
+// bound like FormInput fields. Both use the "form" struct tag. Exceeding a
+// size limit yields request_too_large and stored files are removed when the
+// request fails.
+//
+// Parameters:
+//   - opts: Optional multipart input options.
+//
+// Returns:
+//   - InputHandler[Input]: The multipart input handler.
+func MultipartInput[Input any](opts ...MultipartInputOption) InputHandler[Input] {
+	cfg := multipartInput{
+		maxPartSize:  10 << 20,
+		maxTotalSize: 32 << 20,
+		store:        DiskStore(""),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &multipartInputHandler[Input]{cfg: cfg}
+}
+
+// multipartInput holds the multipart input settings.
+type multipartInput struct {
+	maxPartSize  int64
+	maxTotalSize int64
+	store        FileStore
+	timeout      time.Duration
+}
+
+// multipartInputHandler decodes multipart request bodies.
+type multipartInputHandler[Input any] struct {
+	cfg multipartInput
+}
+
+var (
+	uploadedFileType      = reflect.TypeFor[*UploadedFile]()
+	uploadedFileSliceType = reflect.TypeFor[[]*UploadedFile]()
+)
+
+// Handle reads the parts and binds them to the input.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//
+// Returns:
+//   - *Input: The decoded input.
+//   - error: An API error if the body cannot be decoded.
+func (h *multipartInputHandler[Input]) Handle(
+	_ http.ResponseWriter, r *http.Request,
+) (*Input, error) {
+	if err := checkContentType(r, false, isMultipartMediaType); err != nil {
+		return nil, err
+	}
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, invalidInput("malformed multipart body")
+	}
+	values := map[string][]string{}
+	files := map[string][]*UploadedFile{}
+	var stored []*UploadedFile
+	fail := func(err error) (*Input, error) {
+		for _, f := range stored {
+			_ = f.Remove()
+		}
+		return nil, err
+	}
+	var total int64
+	for count := 0; ; count++ {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fail(multipartReadError(err))
+		}
+		if count == maxMultipartParts {
+			return fail(requestTooLarge(fmt.Sprintf(
+				"multipart body exceeds %d parts", maxMultipartParts,
+			)))
+		}
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+		limit, tooLarge := h.partLimit(name, total)
+		lr := &limitedReader{r: part, n: limit}
+		if part.FileName() == "" {
+			data, err := io.ReadAll(lr)
+			switch {
+			case lr.exceeded:
+				return fail(tooLarge)
+			case err != nil:
+				return fail(multipartReadError(err))
+			}
+			total += int64(len(data))
+			values[name] = append(values[name], string(data))
+			continue
+		}
+		file := &UploadedFile{
+			FieldName:   name,
+			Filename:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Header:      part.Header,
+		}
+		n, err := h.cfg.store.Store(file, lr)
+		file.Size = n
+		stored = append(stored, file)
+		switch {
+		case lr.exceeded:
+			return fail(tooLarge)
+		case lr.err != nil:
+			return fail(multipartReadError(lr.err))
+		case err != nil:
+			return fail(fmt.Errorf("MultipartInput: store %q: %w", file.Filename, err))
+		}
+		total += n
+		files[name] = append(files[name], file)
+	}
+	var in Input
+	if err := bindMultipart(&in, values, files); err != nil {
+		return fail(err)
+	}
+	return &in, nil
+}
+
+// partLimit returns the number of bytes the next part may have and the
+// error reported when it has more.
+func (h *multipartInputHandler[Input]) partLimit(
+	name string, total int64,
+) (int64, error) {
+	remaining := h.cfg.maxTotalSize - total
+	if h.cfg.maxPartSize <= remaining {
+		return h.cfg.maxPartSize, requestTooLarge(fmt.Sprintf(
+			"part %q exceeds %d bytes", name, h.cfg.maxPartSize,
+		))
+	}
+	return remaining, requestTooLarge(fmt.Sprintf(
+		"multipart body exceeds %d bytes", h.cfg.maxTotalSize,
+	))
+}
+
+// bindMultipart binds values and files to the "form" tagged fields of in.
+func bindMultipart(
+	in any, values map[string][]string, files map[string][]*UploadedFile,
+) error {
+	rv := reflect.ValueOf(in).Elem()
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	var errs bind.Errors
+	for _, f := range bind.Fields(rv.Type(), "form") {
+		field := rv.FieldByIndex(f.Index)
+		switch f.Type {
+		case uploadedFileType:
+			if fs := files[f.Name]; len(fs) > 0 {
+				field.Set(reflect.ValueOf(fs[0]))
+			}
+		case uploadedFileSliceType:
+			if fs := files[f.Name]; len(fs) > 0 {
+				field.Set(reflect.ValueOf(fs))
+			}
+		default:
+			if err := bind.Set(field, values[f.Name]); err != nil {
+				errs = append(errs, &bind.FieldError{
+					Field: f.Name,
+					Value: strings.Join(values[f.Name], ","),
+					Err:   err,
+				})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return bindError(errs, "")
+	}
+	return nil
+}
+
+// isMultipartMediaType reports whether the media type is multipart form
+// data.
+func isMultipartMediaType(mediaType string) bool {
+	return mediaType == "multipart/form-data"
+}
+
+// multipartReadError maps errors reading the multipart body.
+func multipartReadError(err error) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return requestTooLarge(fmt.Sprintf(
+			"request body exceeds %d bytes", maxErr.Limit,
+		))
+	}
+	return invalidInput("malformed multipart body")
+}
+
+// limitedReader reads at most n bytes and records whether the source had
+// more, as well as the first read error of the source.
+type limitedReader struct {
+	r        io.Reader
+	n        int64
+	exceeded bool
+	err      error
+}
+
+// Read reads from the source until the limit is reached.
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		var one [1]byte
+		n, err := l.r.Read(one[:])
+		if n > 0 {
+			l.exceeded = true
+			return 0, errPartTooLarge
+		}
+		if err != nil && err != io.EOF {
+			l.err = err
+		}
+		return 0, err
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if err != nil && err != io.EOF {
+		l.err = err
+	}
+	return n, err
+}
+
+// errPartTooLarge aborts copying a part that exceeds its limit.
+var errPartTooLarge = errors.New("multipart part too large")
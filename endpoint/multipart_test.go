@@ -0,0 +1,176 @@
+package endpoint
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type multipartTestInput struct {
+	Title       string          `form:"title"`
+	Count       int             `form:"count"`
+	Avatar      *UploadedFile   `form:"avatar"`
+	Attachments []*UploadedFile `form:"attachment"`
+}
+
+type multipartTestPart struct {
+	name, filename, content string
+}
+
+func newMultipartRequest(t *testing.T, parts ...multipartTestPart) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, p := range parts {
+		var w io.Writer
+		var err error
+		if p.filename != "" {
+			w, err = mw.CreateFormFile(p.name, p.filename)
+		} else {
+			w, err = mw.CreateFormField(p.name)
+		}
+		require.NoError(t, err)
+		_, err = io.WriteString(w, p.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, mw.Close())
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestMultipartInput_DiskStore(t *testing.T) {
+	dir := t.TempDir()
+	req := newMultipartRequest(t,
+		multipartTestPart{name: "title", content: "Hello"},
+		multipartTestPart{name: "count", content: "2"},
+		multipartTestPart{name: "avatar", filename: "../me.png", content: "png"},
+		multipartTestPart{name: "attachment", filename: "a.txt", content: "aa"},
+		multipartTestPart{name: "attachment", filename: "b.txt", content: "bbb"},
+	)
+
+	in, err := MultipartInput[multipartTestInput](
+		WithMultipartStore(DiskStore(dir)),
+	).Handle(httptest.NewRecorder(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Hello", in.Title)
+	assert.Equal(t, 2, in.Count)
+	require.NotNil(t, in.Avatar)
+	assert.Equal(t, "me.png", in.Avatar.Filename)
+	assert.Equal(t, "application/octet-stream", in.Avatar.ContentType)
+	assert.Equal(t, int64(3), in.Avatar.Size)
+	assert.True(t, strings.HasPrefix(in.Avatar.Path, dir))
+	assert.True(t, strings.HasSuffix(in.Avatar.Path, ".png"))
+	data, err := os.ReadFile(in.Avatar.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(data))
+	require.Len(t, in.Attachments, 2)
+	assert.Equal(t, "b.txt", in.Attachments[1].Filename)
+
+	require.NoError(t, in.Avatar.Remove())
+	_, err = in.Avatar.Open()
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestMultipartInput_WriterStore(t *testing.T) {
+	var buf bytes.Buffer
+	store := WriterStore(func(f *UploadedFile) (io.Writer, error) {
+		buf.WriteString(f.FieldName + ":")
+		return &buf, nil
+	})
+	req := newMultipartRequest(t,
+		multipartTestPart{name: "avatar", filename: "me.png", content: "png"},
+	)
+
+	in, err := MultipartInput[multipartTestInput](WithMultipartStore(store)).
+		Handle(httptest.NewRecorder(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "avatar:png", buf.String())
+	assert.Empty(t, in.Avatar.Path)
+	_, err = in.Avatar.Open()
+	assert.Error(t, err)
+}
+
+func TestMultipartInput_Limits(t *testing.T) {
+	testCases := []struct {
+		name    string
+		opts    []MultipartInputOption
+		parts   []multipartTestPart
+		wantMsg string
+	}{
+		{
+			name:    "File part too large",
+			opts:    []MultipartInputOption{WithMultipartMaxPartSize(4)},
+			parts:   []multipartTestPart{{name: "avatar", filename: "a", content: "12345"}},
+			wantMsg: `part "avatar" exceeds 4 bytes`,
+		},
+		{
+			name:    "Field part too large",
+			opts:    []MultipartInputOption{WithMultipartMaxPartSize(4)},
+			parts:   []multipartTestPart{{name: "title", content: "12345"}},
+			wantMsg: `part "title" exceeds 4 bytes`,
+		},
+		{
+			name: "Total too large",
+			opts: []MultipartInputOption{WithMultipartMaxTotalSize(6)},
+			parts: []multipartTestPart{
+				{name: "attachment", filename: "a", content: "1234"},
+				{name: "attachment", filename: "b", content: "1234"},
+			},
+			wantMsg: "multipart body exceeds 6 bytes",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			opts := append(tc.opts, WithMultipartStore(DiskStore(dir)))
+			req := newMultipartRequest(t, tc.parts...)
+
+			_, err := MultipartInput[multipartTestInput](opts...).
+				Handle(httptest.NewRecorder(), req)
+			apiErr, ok := err.(apierror.APIError)
+			require.True(t, ok)
+			assert.Equal(t, ErrIDRequestTooLarge, apiErr.ID())
+			assert.Equal(t, tc.wantMsg, apiErr.Message())
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Empty(t, entries, "stored files are removed on failure")
+		})
+	}
+}
+
+func TestMultipartInput_Errors(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("x"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_, err := MultipartInput[multipartTestInput]().Handle(httptest.NewRecorder(), req)
+	apiErr, ok := err.(apierror.APIError)
+	require.True(t, ok)
+	assert.Equal(t, ErrIDUnsupportedMediaType, apiErr.ID())
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("garbage"))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=xyz")
+	_, err = MultipartInput[multipartTestInput]().Handle(httptest.NewRecorder(), req)
+	apiErr, ok = err.(apierror.APIError)
+	require.True(t, ok)
+	assert.Equal(t, ErrIDInvalidInput, apiErr.ID())
+
+	req = newMultipartRequest(t, multipartTestPart{name: "count", content: "many"})
+	_, err = MultipartInput[multipartTestInput]().Handle(httptest.NewRecorder(), req)
+	apiErr, ok = err.(apierror.APIError)
+	require.True(t, ok)
+	assert.Equal(t, ErrIDInvalidInput, apiErr.ID())
+	assert.Equal(t, []FieldError{{Field: "count", Message: `invalid integer "many"`}},
+		apiErr.Data())
+}
@@ -0,0 +1,186 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"time"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// StreamedPart is a part of a multipart body passed to a PartFunc while
+// the request is read.
+type StreamedPart struct {
+	FieldName   string               // Form field name of the part.
+	Filename    string               // Client file name, "" for value parts.
+	ContentType string               // Content-Type of the part.
+	Header      textproto.MIMEHeader // Headers of the part.
+	Body        io.Reader            // Content, valid until the PartFunc returns.
+}
+
+// Text reads the rest of the part as a string, e.g. the value of a form
+// field.
+//
+// Returns:
+//   - string: The part content.
+//   - error: An error if the part cannot be read.
+func (p *StreamedPart) Text() (string, error) {
+	data, err := io.ReadAll(p.Body)
+	return string(data), err
+}
+
+// PartFunc consumes one part of a multipart body and records the outcome
+// in the input, e.g. the object key an upload was stored under. The
+// context carries the body deadline set by WithMultipartTimeout. Content
+// left unread is skipped.
+type PartFunc[Input any] func(
+	ctx context.Context, in *Input, part *StreamedPart,
+) error
+
+// StreamMultipartInput returns an input handler passing each part of a
+// multipart/form-data body to fn as it arrives, without buffering, so very
+// large uploads can be piped to object storage. Unlike MultipartInput, no
+// fields are bound; fn fills the input:
+//
+//	handler := StreamMultipartInput(
+//		func(ctx context.Context, in *Upload, part *StreamedPart) error {
+//			if part.Filename == "" {
+//				return nil
+//			}
+//			key, err := bucket.Put(ctx, part.Filename, part.Body)
+//			in.Keys = append(in.Keys, key)
+//			return err
+//		},
+//		WithMultipartMaxTotalSize(10<<30),
+//		WithMultipartTimeout(10*time.Minute),
+//	)
+//
+// The part and total size limits of the options apply to what fn reads:
+// reading beyond them fails and the request ends with request_too_large.
+// With WithMultipartTimeout, a body not received in time ends the request
+// with request_timeout. Errors returned by fn end the request as they are.
+// Objects already stored by fn are not removed on failure; use an error
+// hook to clean them up.
+//
+// Parameters:
+//   - fn: The part callback.
+//   - opts: Optional multipart input options. WithMultipartStore is
+//     ignored.
+//
+// Returns:
+//   - InputHandler[Input]: The streaming multipart input handler.
+func StreamMultipartInput[Input any](
+	fn PartFunc[Input], opts ...MultipartInputOption,
+) InputHandler[Input] {
+	cfg := multipartInput{maxPartSize: 10 << 20, maxTotalSize: 32 << 20}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &streamMultipartInputHandler[Input]{
+		multipartInputHandler: multipartInputHandler[Input]{cfg: cfg},
+		fn:                    fn,
+	}
+}
+
+// streamMultipartInputHandler passes multipart parts to a callback.
+type streamMultipartInputHandler[Input any] struct {
+	multipartInputHandler[Input]
+	fn PartFunc[Input]
+}
+
+// Handle reads the parts and passes them to the callback.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//
+// Returns:
+//   - *Input: The input filled by the callback.
+//   - error: An API error if the body cannot be read, or the callback error.
+func (h *streamMultipartInputHandler[Input]) Handle(
+	w http.ResponseWriter, r *http.Request,
+) (*Input, error) {
+	if err := checkContentType(r, false, isMultipartMediaType); err != nil {
+		return nil, err
+	}
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, invalidInput("malformed multipart body")
+	}
+	ctx := r.Context()
+	if h.cfg.timeout > 0 {
+		deadline := time.Now().Add(h.cfg.timeout)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+		_ = http.NewResponseController(w).SetReadDeadline(deadline)
+	}
+	readError := func(err error) error {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && h.cfg.timeout > 0 {
+			return apierror.NewAPIError(ErrIDRequestTimeout).WithMessage(
+				fmt.Sprintf("multipart body not received within %s", h.cfg.timeout),
+			)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return multipartReadError(err)
+	}
+	var in Input
+	var total int64
+	for count := 0; ; count++ {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, readError(err)
+		}
+		if count == maxMultipartParts {
+			return nil, requestTooLarge(fmt.Sprintf(
+				"multipart body exceeds %d parts", maxMultipartParts,
+			))
+		}
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+		limit, tooLarge := h.partLimit(name, total)
+		lr := &limitedReader{r: &contextReader{ctx: ctx, r: part}, n: limit}
+		err = h.fn(ctx, &in, &StreamedPart{
+			FieldName:   name,
+			Filename:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Header:      part.Header,
+			Body:        lr,
+		})
+		switch {
+		case lr.exceeded:
+			return nil, tooLarge
+		case lr.err != nil:
+			return nil, readError(lr.err)
+		case err != nil:
+			return nil, err
+		}
+		total += limit - lr.n
+	}
+	return &in, nil
+}
+
+// contextReader fails reads once its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read reads from the source unless the context is done.
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,88 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type streamTestInput struct {
+	Title string
+	Files map[string]string
+}
+
+func TestStreamMultipartInput(t *testing.T) {
+	req := newMultipartRequest(t,
+		multipartTestPart{name: "title", content: "Hello"},
+		multipartTestPart{name: "file", filename: "a.txt", content: "aaa"},
+		multipartTestPart{name: "file", filename: "b.txt", content: "bbbb"},
+	)
+	h := StreamMultipartInput(
+		func(_ context.Context, in *streamTestInput, part *StreamedPart) error {
+			text, err := part.Text()
+			if part.Filename == "" {
+				in.Title = text
+				return err
+			}
+			if in.Files == nil {
+				in.Files = map[string]string{}
+			}
+			in.Files[part.Filename] = text
+			return err
+		},
+	)
+	in, err := h.Handle(nil, req)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", in.Title)
+	assert.Equal(t, map[string]string{"a.txt": "aaa", "b.txt": "bbbb"}, in.Files)
+}
+
+func TestStreamMultipartInput_Errors(t *testing.T) {
+	readAll := func(_ context.Context, _ *streamTestInput, part *StreamedPart) error {
+		_, err := io.Copy(io.Discard, part.Body)
+		return err
+	}
+	errStore := errors.New("store failed")
+	testCases := []struct {
+		name   string
+		fn     PartFunc[streamTestInput]
+		opts   []MultipartInputOption
+		wantID string
+		want   error
+	}{
+		{"Part size", readAll,
+			[]MultipartInputOption{WithMultipartMaxPartSize(3)}, ErrIDRequestTooLarge, nil},
+		{"Total size", readAll,
+			[]MultipartInputOption{WithMultipartMaxTotalSize(8)}, ErrIDRequestTooLarge, nil},
+		{"Timeout", func(ctx context.Context, in *streamTestInput, part *StreamedPart) error {
+			<-ctx.Done()
+			return readAll(ctx, in, part)
+		}, []MultipartInputOption{WithMultipartTimeout(10 * time.Millisecond)},
+			ErrIDRequestTimeout, nil},
+		{"Callback", func(context.Context, *streamTestInput, *StreamedPart) error {
+			return errStore
+		}, nil, "", errStore},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := newMultipartRequest(t,
+				multipartTestPart{name: "a", filename: "a.txt", content: "aaa"},
+				multipartTestPart{name: "b", filename: "b.txt", content: "bbbbbb"},
+			)
+			_, err := StreamMultipartInput(tc.fn, tc.opts...).Handle(nil, req)
+			if tc.want != nil {
+				assert.ErrorIs(t, err, tc.want)
+				return
+			}
+			apiErr, ok := err.(apierror.APIError)
+			require.True(t, ok, "%v", err)
+			assert.Equal(t, tc.wantID, apiErr.ID())
+		})
+	}
+}
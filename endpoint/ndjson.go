@@ -0,0 +1,330 @@
+package endpoint
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"reflect"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// NDJSONContentType is the Content-Type of NDJSON responses.
+const NDJSONContentType = "application/x-ndjson"
+
+// NDJSONInputOption configures an NDJSON input handler.
+type NDJSONInputOption func(*ndjsonInput)
+
+// WithNDJSONMaxLineSize sets the maximum size of a record line in bytes.
+// Defaults to 1 MiB.
+//
+// Parameters:
+//   - n: The maximum line size in bytes.
+//
+// Returns:
+//   - NDJSONInputOption: An NDJSON input option function.
+func WithNDJSONMaxLineSize(n int) NDJSONInputOption {
+	return func(c *ndjsonInput) { c.maxLineSize = n }
+}
+
+// WithNDJSONStrict rejects records containing fields that are not present
+// in the record type.
+//
+// Returns:
+//   - NDJSONInputOption: An NDJSON input option function.
+func WithNDJSONStrict() NDJSONInputOption {
+	return func(c *ndjsonInput) { c.strict = true }
+}
+
+// ndjsonInput holds the NDJSON input settings.
+type ndjsonInput struct {
+	maxLineSize int
+	strict      bool
+}
+
+// NDJSONStream reads the records of a newline-delimited JSON request body
+// one at a time, so bulk uploads are not buffered in memory. Iterate it
+// with All and check Err afterwards, like a bufio.Scanner:
+//
+//	for rec := range in.All() {
+//		// store rec
+//	}
+//	if err := in.Err(); err != nil {
+//		return nil, err
+//	}
+type NDJSONStream[T any] struct {
+	scanner *bufio.Scanner
+	cfg     ndjsonInput
+	line    int
+	count   int
+	err     error
+}
+
+// All returns an iterator over the records. Blank lines are skipped.
+// Iteration stops at the first malformed record or read error, which Err
+// then reports. The stream can be iterated only once.
+//
+// Returns:
+//   - iter.Seq[T]: The record iterator.
+func (s *NDJSONStream[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for s.err == nil && s.scanner.Scan() {
+			s.line++
+			line := bytes.TrimSpace(s.scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var rec T
+			if err := s.decode(line, &rec); err != nil {
+				s.err = invalidInput(fmt.Sprintf(
+					"line %d: %s", s.line, jsonErrorMessage(err),
+				))
+				return
+			}
+			s.count++
+			if !yield(rec) {
+				return
+			}
+		}
+		if s.err == nil {
+			s.err = s.scanError()
+		}
+	}
+}
+
+// Err returns the error that stopped the iteration, or nil at the end of
+// the body. Errors are API errors: invalid_input for malformed records and
+// request_too_large for oversized lines or bodies.
+//
+// Returns:
+//   - error: The iteration error.
+func (s *NDJSONStream[T]) Err() error {
+	return s.err
+}
+
+// Count returns the number of records read so far.
+//
+// Returns:
+//   - int: The number of records.
+func (s *NDJSONStream[T]) Count() int {
+	return s.count
+}
+
+// decode decodes a single record.
+func (s *NDJSONStream[T]) decode(line []byte, dst *T) error {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	if s.cfg.strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(dst); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("json: more than one value on the line")
+	}
+	return nil
+}
+
+// scanError maps scanner errors to API errors.
+func (s *NDJSONStream[T]) scanError() error {
+	err := s.scanner.Err()
+	var maxErr *http.MaxBytesError
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, bufio.ErrTooLong):
+		return requestTooLarge(fmt.Sprintf(
+			"line %d exceeds %d bytes", s.line+1, s.cfg.maxLineSize,
+		))
+	case errors.As(err, &maxErr):
+		return requestTooLarge(fmt.Sprintf(
+			"request body exceeds %d bytes", maxErr.Limit,
+		))
+	}
+	return invalidInput("failed to read request body")
+}
+
+// NDJSONInput returns an input handler for newline-delimited JSON bodies
+// (application/x-ndjson, application/ndjson or application/jsonl). The
+// handler logic receives an NDJSONStream decoding records of type T while
+// it iterates, so it must consume the stream before returning.
+//
+// Parameters:
+//   - opts: Optional NDJSON input options.
+//
+// Returns:
+//   - InputHandler[NDJSONStream[T]]: The NDJSON input handler.
+func NDJSONInput[T any](
+	opts ...NDJSONInputOption,
+) InputHandler[NDJSONStream[T]] {
+	cfg := ndjsonInput{maxLineSize: 1 << 20}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &ndjsonInputHandler[T]{cfg: cfg}
+}
+
+// ndjsonInputHandler creates NDJSON record streams.
+type ndjsonInputHandler[T any] struct {
+	cfg ndjsonInput
+}
+
+// Handle checks the content type and returns the record stream.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//
+// Returns:
+//   - *NDJSONStream[T]: The record stream.
+//   - error: An API error if the content type is not NDJSON.
+func (h *ndjsonInputHandler[T]) Handle(
+	_ http.ResponseWriter, r *http.Request,
+) (*NDJSONStream[T], error) {
+	if err := checkContentType(r, false, isNDJSONMediaType); err != nil {
+		return nil, err
+	}
+	body := r.Body
+	if body == nil {
+		body = http.NoBody
+	}
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, min(64*1024, h.cfg.maxLineSize)), h.cfg.maxLineSize)
+	return &NDJSONStream[T]{scanner: scanner, cfg: h.cfg}, nil
+}
+
+// isNDJSONMediaType reports whether the media type is NDJSON.
+func isNDJSONMediaType(mediaType string) bool {
+	switch mediaType {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return true
+	}
+	return false
+}
+
+// Records adapts a typed iterator for NDJSONOutput.
+//
+// Parameters:
+//   - seq: The record iterator.
+//
+// Returns:
+//   - iter.Seq[any]: The untyped record iterator.
+func Records[T any](seq iter.Seq[T]) iter.Seq[any] {
+	return func(yield func(any) bool) {
+		for v := range seq {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// NDJSONOutput returns an output handler streaming records as
+// newline-delimited JSON, flushing after each record. The output may be an
+// iter.Seq[any] (see Records), a receive channel or a slice; other values
+// are written as a single record. Streaming stops when the request context
+// is done. API errors are written as a single apierror JSON record.
+//
+// Returns:
+//   - OutputHandler: The NDJSON output handler.
+func NDJSONOutput() OutputHandler {
+	return ndjsonOutput{}
+}
+
+// ndjsonOutput streams NDJSON responses.
+type ndjsonOutput struct{}
+
+// Handle streams the output records or writes the error.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//   - out: The output value.
+//   - outputError: The error to render instead of out, if any.
+//   - statusCode: The response status code.
+//
+// Returns:
+//   - error: An error if encoding or writing fails.
+func (ndjsonOutput) Handle(
+	w http.ResponseWriter,
+	r *http.Request,
+	out any,
+	outputError error,
+	statusCode int,
+) error {
+	w.Header().Set("Content-Type", NDJSONContentType)
+	if outputError != nil {
+		var apiErr apierror.APIError
+		if !errors.As(outputError, &apiErr) {
+			apiErr = apierror.NewAPIError("internal_error").
+				WithMessage("Internal server error")
+		}
+		out = RenderError(r, statusCode, apiErr)
+	}
+	w.WriteHeader(statusCode)
+	rc := http.NewResponseController(w)
+	enc := json.NewEncoder(w)
+	done := r.Context().Done()
+	for rec := range ndjsonRecords(out, done) {
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+		if err := rc.Flush(); err != nil &&
+			!errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ndjsonRecords returns the records of an output value.
+func ndjsonRecords(out any, done <-chan struct{}) iter.Seq[any] {
+	return func(yield func(any) bool) {
+		if seq, ok := out.(iter.Seq[any]); ok {
+			for v := range seq {
+				if isDone(done) || !yield(v) {
+					return
+				}
+			}
+			return
+		}
+		rv := reflect.ValueOf(out)
+		switch {
+		case rv.Kind() == reflect.Chan && rv.Type().ChanDir()&reflect.RecvDir != 0:
+			cases := []reflect.SelectCase{
+				{Dir: reflect.SelectRecv, Chan: rv},
+				{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(done)},
+			}
+			for {
+				chosen, v, ok := reflect.Select(cases)
+				if chosen == 1 || !ok || !yield(v.Interface()) {
+					return
+				}
+			}
+		case (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) &&
+			rv.Type().Elem().Kind() != reflect.Uint8:
+			for i := 0; i < rv.Len(); i++ {
+				if isDone(done) || !yield(rv.Index(i).Interface()) {
+					return
+				}
+			}
+		case out != nil:
+			yield(out)
+		}
+	}
+}
+
+// isDone reports whether the channel is closed.
+func isDone(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,158 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ndjsonRecord struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func ndjsonRequest(body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/bulk", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-ndjson")
+	return r
+}
+
+func TestNDJSONInput(t *testing.T) {
+	in, err := NDJSONInput[ndjsonRecord]().Handle(httptest.NewRecorder(),
+		ndjsonRequest("{\"id\":1,\"name\":\"a\"}\n\n{\"id\":2,\"name\":\"b\"}\r\n{\"id\":3}"))
+	require.NoError(t, err)
+
+	recs := slices.Collect(in.All())
+	require.NoError(t, in.Err())
+	assert.Equal(t, []ndjsonRecord{{1, "a"}, {2, "b"}, {3, ""}}, recs)
+	assert.Equal(t, 3, in.Count())
+}
+
+func TestNDJSONInput_Errors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		opts       []NDJSONInputOption
+		body       string
+		wantRecs   int
+		wantStatus int
+		wantMsg    string
+	}{
+		{name: "Malformed", body: "{\"id\":1}\n{\"id\":\n", wantRecs: 1,
+			wantStatus: http.StatusBadRequest, wantMsg: "line 2: malformed JSON: unexpected end of input"},
+		{name: "Two values", body: "{\"id\":1} {\"id\":2}\n",
+			wantStatus: http.StatusBadRequest, wantMsg: "line 1: malformed JSON"},
+		{name: "Strict", opts: []NDJSONInputOption{WithNDJSONStrict()}, body: "{\"id\":1,\"x\":1}\n",
+			wantStatus: http.StatusBadRequest, wantMsg: `line 1: unknown field "x"`},
+		{name: "Line too long", opts: []NDJSONInputOption{WithNDJSONMaxLineSize(16)},
+			body: "{\"id\":1}\n{\"name\":\"long name\"}\n", wantRecs: 1,
+			wantStatus: http.StatusRequestEntityTooLarge, wantMsg: "line 2 exceeds 16 bytes"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			in, err := NDJSONInput[ndjsonRecord](tc.opts...).Handle(httptest.NewRecorder(), ndjsonRequest(tc.body))
+			require.NoError(t, err)
+			assert.Len(t, slices.Collect(in.All()), tc.wantRecs)
+			require.Error(t, in.Err())
+			status, apiErr := DefaultErrorHandler{}.Handle(in.Err())
+			assert.Equal(t, tc.wantStatus, status)
+			assert.Equal(t, tc.wantMsg, apiErr.Message())
+		})
+	}
+}
+
+func TestNDJSONInput_ContentType(t *testing.T) {
+	r := ndjsonRequest("{}")
+	r.Header.Set("Content-Type", "application/json")
+	_, err := NDJSONInput[ndjsonRecord]().Handle(httptest.NewRecorder(), r)
+	status, _ := DefaultErrorHandler{}.Handle(err)
+	assert.Equal(t, http.StatusUnsupportedMediaType, status)
+}
+
+func TestNDJSONOutput(t *testing.T) {
+	ch := make(chan ndjsonRecord, 2)
+	ch <- ndjsonRecord{1, "a"}
+	ch <- ndjsonRecord{2, "b"}
+	close(ch)
+
+	testCases := []struct {
+		name string
+		out  any
+		want string
+	}{
+		{"Iterator", Records(slices.Values([]ndjsonRecord{{1, "a"}, {2, "b"}})),
+			"{\"id\":1,\"name\":\"a\"}\n{\"id\":2,\"name\":\"b\"}\n"},
+		{"Channel", ch, "{\"id\":1,\"name\":\"a\"}\n{\"id\":2,\"name\":\"b\"}\n"},
+		{"Slice", []ndjsonRecord{{1, "a"}}, "{\"id\":1,\"name\":\"a\"}\n"},
+		{"Single", ndjsonRecord{1, "a"}, "{\"id\":1,\"name\":\"a\"}\n"},
+		{"Nil", nil, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			err := NDJSONOutput().Handle(rec, httptest.NewRequest(http.MethodGet, "/", nil), tc.out, nil, http.StatusOK)
+			require.NoError(t, err)
+			assert.Equal(t, NDJSONContentType, rec.Header().Get("Content-Type"))
+			assert.Equal(t, tc.want, rec.Body.String())
+			assert.True(t, rec.Flushed || tc.want == "")
+		})
+	}
+}
+
+func TestNDJSONOutput_Error(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := NDJSONOutput().Handle(rec, httptest.NewRequest(http.MethodGet, "/", nil),
+		nil, errors.New("secret"), http.StatusInternalServerError)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"id":"internal_error","message":"Internal server error"}`, rec.Body.String())
+}
+
+func TestNDJSONOutput_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int)
+	go func() {
+		ch <- 1
+		cancel()
+	}()
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	require.NoError(t, NDJSONOutput().Handle(rec, r, ch, nil, http.StatusOK))
+	assert.Equal(t, "1\n", rec.Body.String())
+}
+
+func TestNDJSON_Pipeline(t *testing.T) {
+	h := NewHandler(
+		NDJSONInput[ndjsonRecord](),
+		func(_ http.ResponseWriter, _ *http.Request, in *NDJSONStream[ndjsonRecord]) (any, error) {
+			var names []string
+			for rec := range in.All() {
+				names = append(names, rec.Name)
+			}
+			if err := in.Err(); err != nil {
+				return nil, err
+			}
+			return Records(slices.Values(names)), nil
+		},
+		DefaultErrorHandler{}, NDJSONOutput(),
+	)
+
+	rec := httptest.NewRecorder()
+	h.Handle(rec, ndjsonRequest("{\"name\":\"a\"}\n{\"name\":\"b\"}\n"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "\"a\"\n\"b\"\n", rec.Body.String())
+	assert.True(t, rec.Flushed)
+
+	rec = httptest.NewRecorder()
+	h.Handle(rec, ndjsonRequest("{\"name\":\"a\"}\nnot json\n"))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"message":"line 2: malformed JSON`)
+}
@@ -0,0 +1,74 @@
+package endpoint
+
+import "reflect"
+
+// Operation documents an endpoint for generated API descriptions such as
+// OpenAPI documents.
+type Operation struct {
+	ID          string       // Unique operation ID.
+	Summary     string       // Short summary.
+	Description string       // Longer description.
+	Tags        []string     // Grouping tags.
+	Deprecated  bool         // Marks the operation as deprecated.
+	Input       reflect.Type // Input type, nil if the endpoint has none.
+	Output      reflect.Type // Output type, nil if unknown.
+}
+
+// Documented is implemented by endpoints carrying an Operation.
+type Documented interface {
+	Operation() Operation
+}
+
+// TypeDescriber is implemented by handlers that know their input and output
+// types.
+type TypeDescriber interface {
+	InputType() reflect.Type
+	OutputType() reflect.Type
+}
+
+// OperationOf returns an Operation with the input and output types of a
+// handler implementing TypeDescriber, such as DefaultHandler and
+// TypedHandler. Other handlers yield an empty Operation.
+//
+// Parameters:
+//   - handler: The endpoint handler.
+//
+// Returns:
+//   - Operation: The operation with the handler types.
+func OperationOf(handler any) Operation {
+	td, ok := handler.(TypeDescriber)
+	if !ok {
+		return Operation{}
+	}
+	return Operation{Input: td.InputType(), Output: td.OutputType()}
+}
+
+// InputType returns the input type of the handler.
+//
+// Returns:
+//   - reflect.Type: The input type.
+func (h *DefaultHandler[Input]) InputType() reflect.Type {
+	return reflect.TypeFor[Input]()
+}
+
+// OutputType returns nil since the handler logic returns untyped outputs.
+//
+// Returns:
+//   - reflect.Type: Always nil.
+func (h *DefaultHandler[Input]) OutputType() reflect.Type { return nil }
+
+// InputType returns the input type of the handler.
+//
+// Returns:
+//   - reflect.Type: The input type.
+func (h *TypedHandler[Input, Output]) InputType() reflect.Type {
+	return reflect.TypeFor[Input]()
+}
+
+// OutputType returns the output type of the handler.
+//
+// Returns:
+//   - reflect.Type: The output type.
+func (h *TypedHandler[Input, Output]) OutputType() reflect.Type {
+	return reflect.TypeFor[Output]()
+}
@@ -0,0 +1,46 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationOf(t *testing.T) {
+	typed := NewTypedHandler(JSONInput[jsonTestInput](),
+		func(http.ResponseWriter, *http.Request, *jsonTestInput) (typedTestOutput, error) {
+			return typedTestOutput{}, nil
+		}, DefaultErrorHandler{}, TypedOutput[typedTestOutput](JSONOutput()))
+	op := OperationOf(typed)
+	assert.Equal(t, reflect.TypeFor[jsonTestInput](), op.Input)
+	assert.Equal(t, reflect.TypeFor[typedTestOutput](), op.Output)
+
+	untyped := NewHandler(JSONInput[jsonTestInput](),
+		func(http.ResponseWriter, *http.Request, *jsonTestInput) (any, error) { return nil, nil },
+		DefaultErrorHandler{}, JSONOutput())
+	op = OperationOf(untyped)
+	assert.Equal(t, reflect.TypeFor[jsonTestInput](), op.Input)
+	assert.Nil(t, op.Output)
+
+	assert.Equal(t, Operation{}, OperationOf(http.NotFoundHandler()))
+
+	ep := NewEndpoint("/x", http.MethodGet).WithOperation(Operation{Summary: "x"})
+	assert.Equal(t, "x", ep.(Documented).Operation().Summary)
+}
+
+func TestDefaultStack_Chain(t *testing.T) {
+	stack := NewStack(NewWrapper("header", func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Wrapped", "1")
+			next.ServeHTTP(w, r)
+		})
+	}))
+	ep := NewEndpoint("/x", http.MethodGet).WithMiddlewares(stack)
+	rec := httptest.NewRecorder()
+	ep.Middlewares().Chain(http.NotFoundHandler()).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/x", nil))
+	assert.Equal(t, "1", rec.Header().Get("X-Wrapped"))
+}
@@ -0,0 +1,225 @@
+package endpoint
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// ErrIDNotAcceptable is returned when no encoder matches the Accept header.
+const ErrIDNotAcceptable = "not_acceptable"
+
+// Encoder serializes response values for one media type.
+type Encoder interface {
+	// ContentType returns the Content-Type header value, e.g.
+	// "application/json; charset=utf-8".
+	ContentType() string
+	// Encode writes v to w.
+	Encode(w io.Writer, v any) error
+}
+
+// JSONEncoder returns an encoder producing application/json.
+//
+// Returns:
+//   - Encoder: The JSON encoder.
+func JSONEncoder() Encoder { return jsonEncoder{} }
+
+// XMLEncoder returns an encoder producing application/xml. API errors are
+// rendered as an <error> element with id, message and origin children.
+//
+// Returns:
+//   - Encoder: The XML encoder.
+func XMLEncoder() Encoder { return xmlEncoder{} }
+
+// jsonEncoder encodes values as JSON.
+type jsonEncoder struct{}
+
+// ContentType returns the JSON content type.
+func (jsonEncoder) ContentType() string { return "application/json; charset=utf-8" }
+
+// Encode writes v as JSON.
+func (jsonEncoder) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// xmlEncoder encodes values as XML.
+type xmlEncoder struct{}
+
+// xmlError is the XML representation of an API error.
+type xmlError struct {
+	XMLName xml.Name `xml:"error"`
+	ID      string   `xml:"id"`
+	Message string   `xml:"message,omitempty"`
+	Origin  string   `xml:"origin,omitempty"`
+}
+
+// ContentType returns the XML content type.
+func (xmlEncoder) ContentType() string { return "application/xml; charset=utf-8" }
+
+// Encode writes v as XML.
+func (xmlEncoder) Encode(w io.Writer, v any) error {
+	if e, ok := v.(apierror.APIError); ok {
+		v = xmlError{ID: e.ID(), Message: e.Message(), Origin: e.Origin()}
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(v)
+}
+
+// NegotiatingOutput returns an output handler choosing the encoder from the
+// request's Accept header. The first encoder is used when the header is
+// missing or accepts anything. When no encoder is acceptable the response is
+// 406 with a not_acceptable API error rendered by the first encoder.
+//
+// Parameters:
+//   - encoders: The available encoders, in order of preference.
+//
+// Returns:
+//   - OutputHandler: The negotiating output handler.
+func NegotiatingOutput(encoders ...Encoder) OutputHandler {
+	if len(encoders) == 0 {
+		encoders = []Encoder{JSONEncoder()}
+	}
+	return &negotiatingOutput{encoders: encoders, negotiate: true}
+}
+
+// JSONOutput returns an output handler that always writes JSON. API errors
+// are rendered in the apierror JSON format.
+//
+// Returns:
+//   - OutputHandler: The JSON output handler.
+func JSONOutput() OutputHandler {
+	return &negotiatingOutput{encoders: []Encoder{JSONEncoder()}}
+}
+
+// negotiatingOutput writes responses with the best matching encoder.
+type negotiatingOutput struct {
+	encoders  []Encoder
+	negotiate bool
+}
+
+// Handle encodes the output or error and writes the response.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//   - out: The output value.
+//   - outputError: The error to render instead of out, if any.
+//   - statusCode: The response status code.
+//
+// Returns:
+//   - error: An error if encoding or writing fails.
+func (o *negotiatingOutput) Handle(
+	w http.ResponseWriter,
+	r *http.Request,
+	out any,
+	outputError error,
+	statusCode int,
+) error {
+	enc := o.encoders[0]
+	if o.negotiate {
+		w.Header().Add("Vary", "Accept")
+		if enc = negotiateEncoder(r.Header.Values("Accept"), o.encoders); enc == nil {
+			enc = o.encoders[0]
+			statusCode = http.StatusNotAcceptable
+			outputError = apierror.NewAPIError(ErrIDNotAcceptable).
+				WithMessage("none of the accepted media types can be produced")
+		}
+	}
+	body := out
+	if outputError != nil {
+		var apiErr apierror.APIError
+		if !errors.As(outputError, &apiErr) {
+			apiErr = apierror.NewAPIError("internal_error").
+				WithMessage("Internal server error")
+		}
+		body = RenderError(r, statusCode, apiErr)
+	}
+	var buf bytes.Buffer
+	if err := enc.Encode(&buf, body); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", enc.ContentType())
+	w.WriteHeader(statusCode)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// negotiateEncoder returns the encoder with the highest quality in the
+// Accept header, or nil if none is acceptable.
+func negotiateEncoder(accept []string, encoders []Encoder) Encoder {
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		return encoders[0]
+	}
+	var best Encoder
+	bestQ := 0.0
+	for _, enc := range encoders {
+		mediaType, _, err := mime.ParseMediaType(enc.ContentType())
+		if err != nil {
+			continue
+		}
+		if q := acceptQuality(ranges, mediaType); q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+// acceptRange is a media range of an Accept header.
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept parses Accept header values.
+func parseAccept(values []string) []acceptRange {
+	var out []acceptRange
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if qs, ok := params["q"]; ok {
+				if f, err := strconv.ParseFloat(qs, 64); err == nil {
+					q = f
+				}
+			}
+			out = append(out, acceptRange{mediaType: mediaType, q: q})
+		}
+	}
+	return out
+}
+
+// acceptQuality returns the quality of the most specific range matching
+// the media type.
+func acceptQuality(ranges []acceptRange, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+	for _, ar := range ranges {
+		s := -1
+		switch {
+		case ar.mediaType == mediaType:
+			s = 2
+		case ar.mediaType == typ+"/*":
+			s = 1
+		case ar.mediaType == "*/*":
+			s = 0
+		}
+		if s > specificity {
+			q, specificity = ar.q, s
+		}
+	}
+	return q
+}
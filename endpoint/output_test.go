@@ -0,0 +1,110 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type outputItem struct {
+	ID   int    `json:"id" xml:"id" csv:"id"`
+	Name string `json:"name" xml:"name" csv:"name"`
+}
+
+func TestNegotiatingOutput(t *testing.T) {
+	items := []outputItem{{1, "a"}, {2, "b"}}
+	oh := NegotiatingOutput(JSONEncoder(), XMLEncoder(), CSVEncoder())
+
+	testCases := []struct {
+		name        string
+		accept      string
+		status      int
+		contentType string
+	}{
+		{"No Accept", "", http.StatusOK, "application/json; charset=utf-8"},
+		{"Wildcard", "*/*", http.StatusOK, "application/json; charset=utf-8"},
+		{"Exact XML", "application/xml", http.StatusOK, "application/xml; charset=utf-8"},
+		{"Quality", "application/json;q=0.5, text/csv", http.StatusOK, "text/csv; charset=utf-8"},
+		{"Type wildcard", "text/*", http.StatusOK, "text/csv; charset=utf-8"},
+		{"Excluded", "application/json;q=0, */*;q=0.1", http.StatusOK, "application/xml; charset=utf-8"},
+		{"Not acceptable", "image/png", http.StatusNotAcceptable, "application/json; charset=utf-8"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rec := httptest.NewRecorder()
+			require.NoError(t, oh.Handle(rec, req, items, nil, http.StatusOK))
+			assert.Equal(t, tc.status, rec.Code)
+			assert.Equal(t, tc.contentType, rec.Header().Get("Content-Type"))
+			assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+		})
+	}
+}
+
+func TestNegotiatingOutput_Bodies(t *testing.T) {
+	items := []outputItem{{1, "a"}}
+	oh := NegotiatingOutput(JSONEncoder(), XMLEncoder(), CSVEncoder())
+	render := func(accept string, out any, outErr error) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		require.NoError(t, oh.Handle(rec, req, out, outErr, http.StatusOK))
+		return rec.Body.String()
+	}
+
+	assert.JSONEq(t, `[{"id":1,"name":"a"}]`, render("application/json", items, nil))
+	assert.Equal(t, "id,name\n1,a\n", render("text/csv", items, nil))
+	assert.Contains(t, render("application/xml", outputItem{1, "a"}, nil),
+		"<outputItem><id>1</id><name>a</name></outputItem>")
+
+	apiErr := apierror.NewAPIError("not_found").WithMessage("missing")
+	assert.Contains(t, render("application/xml", nil, apiErr),
+		"<error><id>not_found</id><message>missing</message></error>")
+	assert.Equal(t, "id,message\nnot_found,missing\n", render("text/csv", nil, apiErr))
+}
+
+func TestNegotiatingOutput_NotAcceptableError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "image/png")
+	rec := httptest.NewRecorder()
+	require.NoError(t, NegotiatingOutput().Handle(rec, req, "x", nil, http.StatusOK))
+
+	var body apierror.DefaultAPIError
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, ErrIDNotAcceptable, body.ID())
+	status, _ := DefaultErrorHandler{}.Handle(&body)
+	assert.Equal(t, http.StatusNotAcceptable, status)
+}
+
+func TestJSONOutput(t *testing.T) {
+	oh := JSONOutput()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "text/csv")
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, oh.Handle(rec, req, map[string]int{"n": 1}, nil, http.StatusCreated))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"n":1}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, oh.Handle(rec, req, nil, errors.New("boom"), http.StatusInternalServerError))
+	assert.JSONEq(t, `{"id":"internal_error","message":"Internal server error"}`, rec.Body.String())
+}
+
+func TestNegotiatingOutput_EncodeError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "text/csv")
+	rec := httptest.NewRecorder()
+	err := NegotiatingOutput(CSVEncoder()).Handle(rec, req, 42, nil, http.StatusOK)
+	assert.Error(t, err)
+	assert.Empty(t, rec.Body.String())
+}
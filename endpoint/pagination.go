@@ -0,0 +1,239 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Query parameter names read by ParsePageRequest.
+const (
+	QueryPage    = "page"
+	QueryPerPage = "per_page"
+	QueryCursor  = "cursor"
+)
+
+// PaginationOption configures page request parsing.
+type PaginationOption func(*pagination)
+
+// pagination holds the page request settings.
+type pagination struct {
+	defaultPerPage int
+	maxPerPage     int
+}
+
+// WithDefaultPerPage sets the page size used when per_page is missing.
+// Defaults to 20.
+//
+// Parameters:
+//   - n: The default page size.
+//
+// Returns:
+//   - PaginationOption: A pagination option function.
+func WithDefaultPerPage(n int) PaginationOption {
+	return func(p *pagination) { p.defaultPerPage = n }
+}
+
+// WithMaxPerPage sets the largest accepted page size; larger requests are
+// capped. Defaults to 100.
+//
+// Parameters:
+//   - n: The maximum page size.
+//
+// Returns:
+//   - PaginationOption: A pagination option function.
+func WithMaxPerPage(n int) PaginationOption {
+	return func(p *pagination) { p.maxPerPage = n }
+}
+
+// PageRequest is the pagination requested by a client, either by page
+// number or by an opaque cursor.
+type PageRequest struct {
+	Page    int    // 1-based page number.
+	PerPage int    // Page size, within the configured cap.
+	Cursor  string // Opaque cursor token, empty for the first page.
+}
+
+// Offset returns the number of items before the requested page.
+//
+// Returns:
+//   - int: The item offset.
+func (p PageRequest) Offset() int {
+	return (p.Page - 1) * p.PerPage
+}
+
+// ParsePageRequest reads page, per_page and cursor from a decoded query
+// map, such as the one returned by server.QueryMap. Missing values take
+// defaults; per_page is capped at the maximum. Malformed values yield an
+// invalid_input API error listing the offending parameters.
+//
+// Parameters:
+//   - query: The decoded query map.
+//   - opts: Optional pagination options.
+//
+// Returns:
+//   - PageRequest: The page request.
+//   - error: An API error if a parameter is malformed.
+func ParsePageRequest(
+	query map[string]any, opts ...PaginationOption,
+) (PageRequest, error) {
+	cfg := pagination{defaultPerPage: 20, maxPerPage: 100}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	req := PageRequest{Page: 1, PerPage: cfg.defaultPerPage}
+	var fields []FieldError
+	if s, ok := queryString(query, QueryPage); ok {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			fields = append(fields, FieldError{
+				Field: QueryPage, In: "query", Message: "must be a positive integer",
+			})
+		} else {
+			req.Page = n
+		}
+	}
+	if s, ok := queryString(query, QueryPerPage); ok {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			fields = append(fields, FieldError{
+				Field: QueryPerPage, In: "query", Message: "must be a positive integer",
+			})
+		} else {
+			req.PerPage = n
+		}
+	}
+	if cfg.maxPerPage > 0 && req.PerPage > cfg.maxPerPage {
+		req.PerPage = cfg.maxPerPage
+	}
+	req.Cursor, _ = queryString(query, QueryCursor)
+	if len(fields) > 0 {
+		return PageRequest{}, invalidFields(fields)
+	}
+	return req, nil
+}
+
+// queryString returns the first value of a query map entry as a string.
+func queryString(query map[string]any, key string) (string, bool) {
+	switch v := query[key].(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, v != ""
+	case []string:
+		if len(v) == 0 || v[0] == "" {
+			return "", false
+		}
+		return v[0], true
+	default:
+		return fmt.Sprint(v), true
+	}
+}
+
+// Page is the standard envelope of paginated responses. Offset pages carry
+// the page number and total, cursor pages the cursor of the next page.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page"`
+	Total      *int   `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPage returns an offset page.
+//
+// Parameters:
+//   - items: The items of the page.
+//   - req: The page request.
+//   - total: The total number of items.
+//
+// Returns:
+//   - Page[T]: The page.
+func NewPage[T any](items []T, req PageRequest, total int) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Page[T]{Items: items, Page: req.Page, PerPage: req.PerPage, Total: &total}
+}
+
+// NewCursorPage returns a cursor page. An empty next cursor marks the last
+// page.
+//
+// Parameters:
+//   - items: The items of the page.
+//   - req: The page request.
+//   - nextCursor: The cursor of the following page.
+//
+// Returns:
+//   - Page[T]: The page.
+func NewCursorPage[T any](items []T, req PageRequest, nextCursor string) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Page[T]{Items: items, PerPage: req.PerPage, NextCursor: nextCursor}
+}
+
+// LinkHeader returns an RFC 8288 (formerly RFC 5988) Link header value
+// relative to the request URL. Offset pages link first, prev, next and last;
+// cursor pages link next. Other query parameters are preserved.
+//
+// Parameters:
+//   - u: The request URL.
+//
+// Returns:
+//   - string: The header value, or empty if there are no links.
+func (p Page[T]) LinkHeader(u *url.URL) string {
+	var links []string
+	add := func(rel string, set map[string]string) {
+		q := u.Query()
+		for k, v := range set {
+			if v == "" {
+				q.Del(k)
+			} else {
+				q.Set(k, v)
+			}
+		}
+		target := url.URL{Path: u.Path, RawQuery: q.Encode()}
+		links = append(links, fmt.Sprintf("<%s>; rel=%q", target.String(), rel))
+	}
+	perPage := strconv.Itoa(p.PerPage)
+	if p.Total == nil {
+		if p.NextCursor != "" {
+			add("next", map[string]string{
+				QueryCursor: p.NextCursor, QueryPerPage: perPage,
+			})
+		}
+		return strings.Join(links, ", ")
+	}
+	last := 1
+	if p.PerPage > 0 && *p.Total > 0 {
+		last = (*p.Total + p.PerPage - 1) / p.PerPage
+	}
+	page := func(n int) map[string]string {
+		return map[string]string{
+			QueryPage: strconv.Itoa(n), QueryPerPage: perPage, QueryCursor: "",
+		}
+	}
+	add("first", page(1))
+	if p.Page > 1 {
+		add("prev", page(min(p.Page-1, last)))
+	}
+	if p.Page < last {
+		add("next", page(p.Page+1))
+	}
+	add("last", page(last))
+	return strings.Join(links, ", ")
+}
+
+// SetLinkHeader sets the Link header of the response for the page.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+func (p Page[T]) SetLinkHeader(w http.ResponseWriter, r *http.Request) {
+	if links := p.LinkHeader(r.URL); links != "" {
+		w.Header().Set("Link", links)
+	}
+}
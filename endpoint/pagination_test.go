@@ -0,0 +1,87 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePageRequest(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query map[string]any
+		opts  []PaginationOption
+		want  PageRequest
+	}{
+		{name: "Defaults", query: nil, want: PageRequest{Page: 1, PerPage: 20}},
+		{name: "Explicit", query: map[string]any{"page": "3", "per_page": "10"},
+			want: PageRequest{Page: 3, PerPage: 10}},
+		{name: "Capped", query: map[string]any{"per_page": "1000"},
+			opts: []PaginationOption{WithMaxPerPage(50)}, want: PageRequest{Page: 1, PerPage: 50}},
+		{name: "Custom default", query: map[string]any{},
+			opts: []PaginationOption{WithDefaultPerPage(5)}, want: PageRequest{Page: 1, PerPage: 5}},
+		{name: "Repeated and cursor", query: map[string]any{"page": []string{"2", "9"}, "cursor": "abc"},
+			want: PageRequest{Page: 2, PerPage: 20, Cursor: "abc"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParsePageRequest(tc.query, tc.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParsePageRequest_Invalid(t *testing.T) {
+	_, err := ParsePageRequest(map[string]any{"page": "0", "per_page": "x"})
+	apiErr, ok := err.(apierror.APIError)
+	require.True(t, ok)
+	assert.Equal(t, ErrIDInvalidInput, apiErr.ID())
+	assert.Len(t, apiErr.Data(), 2)
+}
+
+func TestPage_OffsetLinks(t *testing.T) {
+	u, _ := url.Parse("/users?page=2&per_page=10&sort=name")
+	req := PageRequest{Page: 2, PerPage: 10}
+	page := NewPage([]string{"a"}, req, 35)
+
+	assert.Equal(t,
+		`</users?page=1&per_page=10&sort=name>; rel="first", `+
+			`</users?page=1&per_page=10&sort=name>; rel="prev", `+
+			`</users?page=3&per_page=10&sort=name>; rel="next", `+
+			`</users?page=4&per_page=10&sort=name>; rel="last"`,
+		page.LinkHeader(u))
+
+	body, err := json.Marshal(page)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"items":["a"],"page":2,"per_page":10,"total":35}`, string(body))
+
+	lastPage := NewPage[string](nil, PageRequest{Page: 1, PerPage: 10}, 0)
+	assert.Equal(t,
+		`</users?page=1&per_page=10&sort=name>; rel="first", `+
+			`</users?page=1&per_page=10&sort=name>; rel="last"`,
+		lastPage.LinkHeader(u))
+	body, _ = json.Marshal(lastPage)
+	assert.JSONEq(t, `{"items":[],"page":1,"per_page":10,"total":0}`, string(body))
+}
+
+func TestPage_CursorLinks(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/events?cursor=old&type=x", nil)
+	rec := httptest.NewRecorder()
+	page := NewCursorPage([]int{1, 2}, PageRequest{PerPage: 2, Cursor: "old"}, "next token")
+	page.SetLinkHeader(rec, r)
+
+	assert.Equal(t, `</events?cursor=next+token&per_page=2&type=x>; rel="next"`,
+		rec.Header().Get("Link"))
+
+	rec = httptest.NewRecorder()
+	NewCursorPage([]int{}, PageRequest{PerPage: 2}, "").SetLinkHeader(rec, r)
+	assert.Empty(t, rec.Header().Get("Link"))
+}
@@ -0,0 +1,191 @@
+package endpoint
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// ErrIDTooManyRequests is the API error ID of requests exceeding a quota.
+const ErrIDTooManyRequests = apierror.IDTooManyRequests
+
+// QuotaUsage is the state of a quota after consuming a request.
+type QuotaUsage struct {
+	Used  int       // Requests counted in the current window.
+	Reset time.Time // When the current window ends.
+}
+
+// QuotaStore counts requests per key in fixed windows. Implementations
+// backed by shared storage let several instances enforce one quota.
+type QuotaStore interface {
+	// Take counts a request for key and returns the usage of the window,
+	// starting a new window of the given length if none is active.
+	Take(ctx context.Context, key string, window time.Duration) (QuotaUsage, error)
+}
+
+// MemoryQuotaStore counts requests in memory.
+type MemoryQuotaStore struct {
+	mu        sync.Mutex
+	windows   map[string]QuotaUsage
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// MemoryQuotaStore implements the QuotaStore interface.
+var _ QuotaStore = (*MemoryQuotaStore)(nil)
+
+// NewMemoryQuotaStore creates an in-memory quota store.
+//
+// Returns:
+//   - *MemoryQuotaStore: A new MemoryQuotaStore instance.
+func NewMemoryQuotaStore() *MemoryQuotaStore {
+	return &MemoryQuotaStore{windows: map[string]QuotaUsage{}, now: time.Now}
+}
+
+// Take counts a request for key. Ended windows are swept at most once per
+// window length.
+//
+// Parameters:
+//   - ctx: The request context.
+//   - key: The quota key.
+//   - window: The window length.
+//
+// Returns:
+//   - QuotaUsage: The usage of the current window.
+//   - error: Always nil.
+func (m *MemoryQuotaStore) Take(
+	_ context.Context, key string, window time.Duration,
+) (QuotaUsage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	if now.Sub(m.lastSweep) >= window {
+		for k, u := range m.windows {
+			if !now.Before(u.Reset) {
+				delete(m.windows, k)
+			}
+		}
+		m.lastSweep = now
+	}
+	u, ok := m.windows[key]
+	if !ok || !now.Before(u.Reset) {
+		u = QuotaUsage{Reset: now.Add(window)}
+	}
+	u.Used++
+	m.windows[key] = u
+	return u, nil
+}
+
+// QuotaOption configures Quota.
+type QuotaOption func(*quotaConfig)
+
+// quotaConfig holds the quota settings.
+type quotaConfig struct {
+	key   func(r *http.Request) string
+	store QuotaStore
+	now   func() time.Time
+}
+
+// WithQuotaKey sets the function identifying the caller a request counts
+// against. Requests for which it returns "" are not limited. The default
+// is the ID of the authenticated principal, falling back to the remote
+// address; behind proxies use a key based on server.ClientIP instead.
+//
+// Parameters:
+//   - fn: Returns the quota key of a request.
+//
+// Returns:
+//   - QuotaOption: The option.
+func WithQuotaKey(fn func(r *http.Request) string) QuotaOption {
+	return func(c *quotaConfig) { c.key = fn }
+}
+
+// WithQuotaStore sets the store counting requests. The default is a new
+// MemoryQuotaStore per middleware.
+//
+// Parameters:
+//   - store: The quota store.
+//
+// Returns:
+//   - QuotaOption: The option.
+func WithQuotaStore(store QuotaStore) QuotaOption {
+	return func(c *quotaConfig) { c.store = store }
+}
+
+// Quota returns a middleware allowing each caller limit requests per
+// window. Every limited response carries the RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers; requests over the limit
+// are rejected with a 429 too_many_requests API error and a Retry-After
+// header. Store failures become a 500 internal_error.
+//
+// Share a store between endpoints by prefixing keys per endpoint, since
+// the store only sees the key.
+//
+// Parameters:
+//   - limit: The number of requests allowed per window.
+//   - window: The window length.
+//   - opts: Optional quota options.
+//
+// Returns:
+//   - Middleware: The quota middleware.
+func Quota(limit int, window time.Duration, opts ...QuotaOption) Middleware {
+	cfg := quotaConfig{key: defaultQuotaKey, now: time.Now}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.store == nil {
+		cfg.store = NewMemoryQuotaStore()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := cfg.key(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			usage, err := cfg.store.Take(r.Context(), key, window)
+			if err != nil {
+				writeAPIError(w, r, http.StatusInternalServerError,
+					apierror.NewAPIError("internal_error").
+						WithMessage("Internal server error"))
+				return
+			}
+			reset := strconv.Itoa(max(int(math.Ceil(
+				usage.Reset.Sub(cfg.now()).Seconds(),
+			)), 0))
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(max(limit-usage.Used, 0)))
+			h.Set("RateLimit-Reset", reset)
+			if usage.Used > limit {
+				h.Set("Retry-After", reset)
+				writeAPIError(w, r, http.StatusTooManyRequests,
+					apierror.NewAPIError(ErrIDTooManyRequests).
+						WithMessage("quota exceeded"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// defaultQuotaKey keys requests by principal ID or remote address.
+func defaultQuotaKey(r *http.Request) string {
+	if p, ok := PrincipalFromRequest(r); ok && p.ID != "" {
+		return "principal:" + p.ID
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if host == "" {
+		return ""
+	}
+	return "ip:" + host
+}
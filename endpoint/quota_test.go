@@ -0,0 +1,78 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingQuotaStore fails every take.
+type failingQuotaStore struct{}
+
+func (failingQuotaStore) Take(context.Context, string, time.Duration) (QuotaUsage, error) {
+	return QuotaUsage{}, errors.New("store down")
+}
+
+func TestQuota(t *testing.T) {
+	store := NewMemoryQuotaStore()
+	now := time.Unix(1_700_000_000, 0)
+	clock := func() time.Time { return now }
+	store.now = clock
+	h := Quota(2, time.Minute, WithQuotaStore(store),
+		func(c *quotaConfig) { c.now = clock },
+	)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	call := func(remoteAddr string, p *Principal) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = remoteAddr
+		if p != nil {
+			r = r.WithContext(WithPrincipal(r.Context(), p))
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		return rec
+	}
+
+	rec := call("10.0.0.1:1234", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", rec.Header().Get("RateLimit-Reset"))
+
+	assert.Equal(t, http.StatusOK, call("10.0.0.1:4321", nil).Code)
+	rec = call("10.0.0.1:1234", nil)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"id":"too_many_requests","message":"quota exceeded"}`,
+		rec.Body.String())
+
+	// Principals have their own quota, independent of their address.
+	assert.Equal(t, http.StatusOK, call("10.0.0.1:1234", &Principal{ID: "ada"}).Code)
+
+	// A new window resets the quota.
+	now = now.Add(time.Minute)
+	assert.Equal(t, http.StatusOK, call("10.0.0.1:1234", nil).Code)
+}
+
+func TestQuota_KeyAndStoreErrors(t *testing.T) {
+	var called bool
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true })
+
+	h := Quota(1, time.Minute, WithQuotaKey(func(*http.Request) string { return "" }),
+		WithQuotaStore(failingQuotaStore{}))(next)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.True(t, called, "empty keys are not limited")
+	assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+
+	h = Quota(1, time.Minute, WithQuotaStore(failingQuotaStore{}))(next)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,101 @@
+package endpoint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// rawBodyKey is the context key of the captured request body.
+type rawBodyKey struct{}
+
+// RawBodyMiddleware returns a middleware buffering the request body, up to
+// maxBytes, and exposing it through RawBody, e.g. to verify HMAC webhook
+// signatures. The request body is replaced with a reader over the same
+// bytes, so input handlers still decode it:
+//
+//	raw, _ := endpoint.RawBody(r)
+//	mac := hmac.New(sha256.New, secret)
+//	mac.Write(raw)
+//	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+//	ok := hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Hub-Signature-256")))
+//
+// Bodies exceeding maxBytes, or failing to read, are not captured; reading
+// the request body then returns the error, which JSONInput and the other
+// built-in input handlers map to request_too_large or invalid_input.
+//
+// Parameters:
+//   - maxBytes: The maximum body size in bytes.
+//
+// Returns:
+//   - Middleware: The raw body middleware.
+func RawBodyMiddleware(maxBytes int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+			if err != nil {
+				r = r.WithContext(r.Context())
+				r.Body = io.NopCloser(&errReader{err: err})
+				next.ServeHTTP(w, r)
+				return
+			}
+			r = r.WithContext(context.WithValue(r.Context(), rawBodyKey{}, body))
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bufferBody reads a body of up to limit bytes and returns a request whose
+// body and RawBody replay it.
+func bufferBody(r *http.Request, limit int64) (*http.Request, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return r, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		return r, bodyReadError(err)
+	}
+	if int64(len(body)) > limit {
+		return r, requestTooLarge(fmt.Sprintf(
+			"request body exceeds %d bytes", limit,
+		))
+	}
+	r = r.WithContext(context.WithValue(r.Context(), rawBodyKey{}, body))
+	replayBody(r)
+	return r, nil
+}
+
+// replayBody rewinds the body of a request buffered by bufferBody.
+func replayBody(r *http.Request) {
+	if body, ok := RawBody(r); ok {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+}
+
+// RawBody returns the request body captured by RawBodyMiddleware or
+// DefaultHandler.WithBodyReplay. The returned slice must not be modified.
+//
+// Parameters:
+//   - r: The HTTP request.
+//
+// Returns:
+//   - []byte: The raw body.
+//   - bool: Whether a body was captured.
+func RawBody(r *http.Request) ([]byte, bool) {
+	body, ok := r.Context().Value(rawBodyKey{}).([]byte)
+	return body, ok
+}
+
+// errReader fails every read with err.
+type errReader struct {
+	err error
+}
+
+func (e *errReader) Read([]byte) (int, error) { return 0, e.err }
@@ -0,0 +1,109 @@
+package endpoint
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawBodyMiddleware(t *testing.T) {
+	secret := []byte("webhook-secret")
+	body := `{"name":"Go","age":15}`
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(body))
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	var verified bool
+	var decoded *jsonTestInput
+	h := RawBodyMiddleware(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, ok := RawBody(r)
+		require.True(t, ok)
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(raw)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		verified = hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Hub-Signature-256")))
+
+		in, err := JSONInput[jsonTestInput]().Handle(w, r)
+		require.NoError(t, err)
+		decoded = in
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Hub-Signature-256", signature)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.True(t, verified)
+	assert.Equal(t, &jsonTestInput{Name: "Go", Age: 15}, decoded)
+}
+
+func TestRawBodyMiddleware_TooLarge(t *testing.T) {
+	var captured bool
+	var inputErr error
+	h := RawBodyMiddleware(8)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, captured = RawBody(r)
+		_, inputErr = JSONInput[jsonTestInput]().Handle(w, r)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(`{"name":"too long"}`))
+	req.Header.Set("Content-Type", "application/json")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.False(t, captured)
+	status, apiErr := DefaultErrorHandler{}.Handle(inputErr)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	assert.Equal(t, ErrIDRequestTooLarge, apiErr.ID())
+}
+
+func TestRawBodyMiddleware_NoBody(t *testing.T) {
+	var captured bool
+	h := RawBodyMiddleware(8)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, captured = RawBody(r)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.False(t, captured)
+}
+
+func TestDefaultHandler_WithBodyReplay(t *testing.T) {
+	body := `{"name":"Go","age":15}`
+	newHandler := func(limit int64) *DefaultHandler[jsonTestInput] {
+		return NewHandler(
+			JSONInput[jsonTestInput](),
+			func(_ http.ResponseWriter, r *http.Request, in *jsonTestInput) (any, error) {
+				raw, ok := RawBody(r)
+				require.True(t, ok)
+				again, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.Equal(t, body, string(raw))
+				assert.Equal(t, body, string(again))
+				return in.Name, nil
+			},
+			DefaultErrorHandler{}, JSONOutput(),
+		).WithBodyReplay(limit)
+	}
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		return r
+	}
+
+	rec := httptest.NewRecorder()
+	newHandler(1024).Handle(rec, newRequest())
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `"Go"`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	newHandler(8).Handle(rec, newRequest())
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.JSONEq(t,
+		`{"id":"request_too_large","message":"request body exceeds 8 bytes"}`,
+		rec.Body.String())
+}
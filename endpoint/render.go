@@ -0,0 +1,108 @@
+package endpoint
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// ErrorRenderer builds the body of an error response from the API error
+// and its status code. The built-in output handlers and middlewares encode
+// the returned value with their encoder, so it must be encodable by every
+// encoder in use; XMLEncoder, for example, cannot encode maps.
+//
+// A renderer can give the whole API one error format:
+//
+//	endpoint.SetErrorRenderer(func(r *http.Request, status int, err apierror.APIError) any {
+//		return map[string]any{"error": map[string]any{
+//			"code":       err.ID(),
+//			"message":    err.Message(),
+//			"request_id": endpoint.RequestIDFromRequest(r),
+//			"docs":       "https://docs.example.com/errors/" + err.ID(),
+//		}}
+//	})
+type ErrorRenderer func(r *http.Request, status int, err apierror.APIError) any
+
+// DefaultErrorRenderer renders API errors in the apierror JSON format.
+//
+// Parameters:
+//   - r: The HTTP request.
+//   - status: The response status code.
+//   - err: The API error.
+//
+// Returns:
+//   - any: The error body.
+func DefaultErrorRenderer(_ *http.Request, _ int, err apierror.APIError) any {
+	return apierror.APIErrorFrom(err)
+}
+
+// errorRenderer is the package-wide error renderer.
+var errorRenderer atomic.Pointer[ErrorRenderer]
+
+// SetErrorRenderer sets the renderer of all error responses written by the
+// built-in output handlers and middlewares. A nil renderer restores
+// DefaultErrorRenderer. Handlers may override it with
+// DefaultHandler.WithErrorRenderer. Set it before serving requests.
+//
+// Parameters:
+//   - renderer: The error renderer.
+func SetErrorRenderer(renderer ErrorRenderer) {
+	if renderer == nil {
+		errorRenderer.Store(nil)
+		return
+	}
+	errorRenderer.Store(&renderer)
+}
+
+// errorRendererKey is the context key of a handler's error renderer.
+type errorRendererKey struct{}
+
+// WithErrorRenderer sets the renderer of the error responses of the
+// handler, overriding the package-wide one, and returns a new handler
+// instance. It applies to output handlers rendering errors with
+// RenderError, such as the built-in ones.
+//
+// Parameters:
+//   - renderer: The error renderer.
+//
+// Returns:
+//   - *DefaultHandler[Input]: A new handler instance.
+func (h *DefaultHandler[Input]) WithErrorRenderer(
+	renderer ErrorRenderer,
+) *DefaultHandler[Input] {
+	new := *h
+	new.errorRenderer = renderer
+	return &new
+}
+
+// RenderError returns the body of an error response, rendered by the
+// handler's renderer, the package-wide one or DefaultErrorRenderer. Custom
+// output handlers use it to render errors like the built-in ones.
+//
+// Parameters:
+//   - r: The HTTP request.
+//   - status: The response status code.
+//   - err: The API error.
+//
+// Returns:
+//   - any: The error body.
+func RenderError(r *http.Request, status int, err apierror.APIError) any {
+	if r != nil {
+		if renderer, ok := r.Context().Value(errorRendererKey{}).(ErrorRenderer); ok {
+			return renderer(r, status, err)
+		}
+	}
+	if renderer := errorRenderer.Load(); renderer != nil {
+		return (*renderer)(r, status, err)
+	}
+	return DefaultErrorRenderer(r, status, err)
+}
+
+// withErrorRenderer returns a request carrying a handler's error renderer.
+func withErrorRenderer(r *http.Request, renderer ErrorRenderer) *http.Request {
+	return r.WithContext(
+		context.WithValue(r.Context(), errorRendererKey{}, renderer),
+	)
+}
@@ -0,0 +1,58 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetErrorRenderer(t *testing.T) {
+	SetErrorRenderer(func(r *http.Request, status int, err apierror.APIError) any {
+		return map[string]any{"error": map[string]any{
+			"code": err.ID(), "status": status, "path": r.URL.Path,
+		}}
+	})
+	t.Cleanup(func() { SetErrorRenderer(nil) })
+
+	// Handler errors.
+	h := NewHandler(
+		&dummyInputHandler{},
+		func(http.ResponseWriter, *http.Request, *string) (any, error) {
+			return nil, errors.New("boom")
+		},
+		DefaultErrorHandler{}, JSONOutput(),
+	)
+	rec := httptest.NewRecorder()
+	h.Handle(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t,
+		`{"error":{"code":"internal_error","status":500,"path":"/items"}}`,
+		rec.Body.String())
+
+	// Middleware errors.
+	rec = httptest.NewRecorder()
+	Authorize(AccessRule{})(http.NotFoundHandler()).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin", nil))
+	assert.JSONEq(t,
+		`{"error":{"code":"unauthorized","status":401,"path":"/admin"}}`,
+		rec.Body.String())
+
+	// Handler renderers take precedence.
+	rec = httptest.NewRecorder()
+	h.WithErrorRenderer(func(_ *http.Request, _ int, err apierror.APIError) any {
+		return err.ID()
+	}).Handle(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+	assert.JSONEq(t, `"internal_error"`, rec.Body.String())
+
+	// A nil renderer restores the default.
+	SetErrorRenderer(nil)
+	rec = httptest.NewRecorder()
+	h.Handle(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+	assert.JSONEq(t,
+		`{"id":"internal_error","message":"Internal server error"}`,
+		rec.Body.String())
+}
@@ -65,8 +65,5 @@ func EventWithRequestID(eventType event.EventType, requestID string, data map[st
 		data = make(map[string]any)
 	}
 	data["request_id"] = requestID
-	return event.Event{
-		Type: eventType,
-		Data: data,
-	}
+	return *event.NewEvent(eventType, "").WithData(data)
 }
@@ -0,0 +1,110 @@
+package endpoint
+
+import (
+	"net/http"
+	"slices"
+)
+
+// Response lets handler logic control the success response. When logic
+// returns a *Response or Response, DefaultHandler applies its headers and
+// cookies and passes Body and Status to the output handler:
+//
+//	return endpoint.NewResponse(user).
+//		WithStatus(http.StatusCreated).
+//		WithHeader("Location", "/users/"+user.ID), nil
+//
+// Responses with a nil Body and status 204 or 304 are written without
+// calling the output handler.
+type Response struct {
+	Status  int            // Status code, the handler's success status if zero.
+	Headers http.Header    // Headers set on the response.
+	Cookies []*http.Cookie // Cookies added to the response.
+	Body    any            // Value passed to the output handler.
+}
+
+// NewResponse creates a new response with the body and the success status
+// of the handler, 200 by default.
+//
+// Parameters:
+//   - body: The value passed to the output handler.
+//
+// Returns:
+//   - *Response: A new Response instance.
+func NewResponse(body any) *Response {
+	return &Response{Body: body}
+}
+
+// WithStatus sets the status code and returns a new response instance.
+//
+// Parameters:
+//   - status: The HTTP status code.
+//
+// Returns:
+//   - *Response: A new response instance.
+func (r *Response) WithStatus(status int) *Response {
+	new := *r
+	new.Status = status
+	return &new
+}
+
+// WithHeader sets a header and returns a new response instance.
+//
+// Parameters:
+//   - key: The header name.
+//   - value: The header value.
+//
+// Returns:
+//   - *Response: A new response instance.
+func (r *Response) WithHeader(key, value string) *Response {
+	new := *r
+	new.Headers = r.Headers.Clone()
+	if new.Headers == nil {
+		new.Headers = http.Header{}
+	}
+	new.Headers.Set(key, value)
+	return &new
+}
+
+// WithCookie adds a cookie and returns a new response instance.
+//
+// Parameters:
+//   - cookie: The cookie to add.
+//
+// Returns:
+//   - *Response: A new response instance.
+func (r *Response) WithCookie(cookie *http.Cookie) *Response {
+	new := *r
+	new.Cookies = append(slices.Clip(r.Cookies), cookie)
+	return &new
+}
+
+// write applies the headers and cookies and returns the status code,
+// defaultStatus if unset.
+func (r *Response) write(w http.ResponseWriter, defaultStatus int) int {
+	for key, values := range r.Headers {
+		w.Header()[http.CanonicalHeaderKey(key)] = values
+	}
+	for _, c := range r.Cookies {
+		http.SetCookie(w, c)
+	}
+	if r.Status == 0 {
+		return defaultStatus
+	}
+	return r.Status
+}
+
+// asResponse returns the response returned by logic, if any.
+func asResponse(out any) (*Response, bool) {
+	switch resp := out.(type) {
+	case *Response:
+		return resp, resp != nil
+	case Response:
+		return &resp, true
+	}
+	return nil, false
+}
+
+// bodyless reports whether a status code forbids a response body.
+func bodyless(status int) bool {
+	return status == http.StatusNoContent || status == http.StatusNotModified
+}
@@ -0,0 +1,106 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponse_Handle(t *testing.T) {
+	created := NewResponse(map[string]string{"id": "1"}).
+		WithStatus(http.StatusCreated).
+		WithHeader("Location", "/items/1").
+		WithCookie(&http.Cookie{Name: "session", Value: "abc"})
+
+	testCases := []struct {
+		name       string
+		out        any
+		wantStatus int
+		wantBody   string
+		wantHeader http.Header
+	}{
+		{
+			name: "Created", out: created, wantStatus: http.StatusCreated,
+			wantBody: "{\"id\":\"1\"}\n",
+			wantHeader: http.Header{
+				"Location":   {"/items/1"},
+				"Set-Cookie": {"session=abc"},
+			},
+		},
+		{
+			name: "Value", out: Response{Headers: http.Header{"x-id": {"7"}}, Body: 1},
+			wantStatus: http.StatusOK, wantBody: "1\n",
+			wantHeader: http.Header{"X-Id": {"7"}},
+		},
+		{
+			name: "No content", out: NewResponse(nil).WithStatus(http.StatusNoContent),
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "Nil pointer", out: (*Response)(nil),
+			wantStatus: http.StatusOK, wantBody: "null\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHandler(
+				&dummyInputHandler{},
+				func(_ http.ResponseWriter, _ *http.Request, _ *string) (any, error) {
+					return tc.out, nil
+				},
+				DefaultErrorHandler{}, JSONOutput(),
+			)
+			rec := httptest.NewRecorder()
+			h.Handle(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			assert.Equal(t, tc.wantBody, rec.Body.String())
+			for key, values := range tc.wantHeader {
+				assert.Equal(t, values, rec.Header()[key], key)
+			}
+		})
+	}
+}
+
+func TestResponse_WithCopies(t *testing.T) {
+	base := NewResponse("x").WithHeader("A", "1")
+	derived := base.WithHeader("B", "2").
+		WithCookie(&http.Cookie{Name: "c"})
+
+	assert.Equal(t, http.Header{"A": {"1"}}, base.Headers)
+	assert.Empty(t, base.Cookies)
+	assert.Equal(t, "2", derived.Headers.Get("B"))
+	assert.Len(t, derived.Cookies, 1)
+}
+
+func TestDefaultHandler_WithSuccessStatus(t *testing.T) {
+	testCases := []struct {
+		name       string
+		status     int
+		out        any
+		wantStatus int
+		wantBody   string
+	}{
+		{"Created", http.StatusCreated, map[string]int{"id": 1}, http.StatusCreated, "{\"id\":1}\n"},
+		{"No content", http.StatusNoContent, nil, http.StatusNoContent, ""},
+		{"Response overrides", http.StatusCreated, NewResponse(1).WithStatus(http.StatusAccepted), http.StatusAccepted, "1\n"},
+		{"Response inherits", http.StatusCreated, NewResponse(1).WithHeader("X-A", "1"), http.StatusCreated, "1\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHandler(
+				&dummyInputHandler{},
+				func(_ http.ResponseWriter, _ *http.Request, _ *string) (any, error) {
+					return tc.out, nil
+				},
+				DefaultErrorHandler{}, JSONOutput(),
+			).WithSuccessStatus(tc.status)
+			rec := httptest.NewRecorder()
+			h.Handle(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			assert.Equal(t, tc.wantBody, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// SanitizeOption configures SanitizeInput.
+type SanitizeOption func(*sanitizeConfig)
+
+// sanitizeConfig holds the sanitization settings.
+type sanitizeConfig struct {
+	normalize func(string) string
+}
+
+// WithNormalizer sets the function applied by the "normalize" directive,
+// e.g. norm.NFC.String of golang.org/x/text for canonical composition.
+//
+// Parameters:
+//   - fn: The unicode normalization function.
+//
+// Returns:
+//   - SanitizeOption: The option.
+func WithNormalizer(fn func(string) string) SanitizeOption {
+	return func(c *sanitizeConfig) { c.normalize = fn }
+}
+
+// SanitizeInput returns an input handler cleaning the string fields of the
+// inputs decoded by inputHandler. Since the handler validates the input
+// after the input handler returns, validation sees the sanitized values.
+//
+// Fields opt in with a "sanitize" tag listing directives, applied in
+// order:
+//
+//	type CreatePost struct {
+//		Title string   `json:"title" sanitize:"trim,normalize,nocontrol"`
+//		Body  string   `json:"body" sanitize:"nohtml,trim"`
+//		Tags  []string `json:"tags" sanitize:"trim"`
+//	}
+//
+// The directives are:
+//   - trim: Removes leading and trailing white space.
+//   - normalize: Maps unicode spaces to ASCII spaces and line separators to
+//     newlines, and removes invisible format characters such as zero-width
+//     spaces and bidi overrides. WithNormalizer replaces it.
+//   - nocontrol: Removes control characters other than tab, newline and
+//     carriage return.
+//   - nohtml: Removes HTML tags and comments, keeping their text content.
+//
+// Tags apply to string fields and to pointers, slices, arrays and map
+// values of strings. Nested structs are sanitized by their own tags. It
+// panics on unknown directives, since that is a programming error.
+//
+// Parameters:
+//   - inputHandler: The input handler decoding the input.
+//   - opts: Optional sanitize options.
+//
+// Returns:
+//   - InputHandler[Input]: The sanitizing input handler.
+func SanitizeInput[Input any](
+	inputHandler InputHandler[Input], opts ...SanitizeOption,
+) InputHandler[Input] {
+	cfg := sanitizeConfig{normalize: normalizeUnicode}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	err := checkSanitizeTags(reflect.TypeFor[Input](), map[reflect.Type]bool{})
+	if err != nil {
+		panic("endpoint: SanitizeInput: " + err.Error())
+	}
+	return &sanitizeInputHandler[Input]{inputHandler: inputHandler, cfg: cfg}
+}
+
+// sanitizeInputHandler sanitizes the inputs of another input handler.
+type sanitizeInputHandler[Input any] struct {
+	inputHandler InputHandler[Input]
+	cfg          sanitizeConfig
+}
+
+// Handle decodes the input and sanitizes its tagged fields.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//
+// Returns:
+//   - *Input: The sanitized input.
+//   - error: An error returned by the wrapped input handler.
+func (h *sanitizeInputHandler[Input]) Handle(
+	w http.ResponseWriter, r *http.Request,
+) (*Input, error) {
+	in, err := h.inputHandler.Handle(w, r)
+	if err != nil || in == nil {
+		return in, err
+	}
+	h.cfg.sanitize(reflect.ValueOf(in).Elem(), nil)
+	return in, nil
+}
+
+// sanitize applies directives to the strings in v and walks nested structs.
+func (c sanitizeConfig) sanitize(v reflect.Value, directives []string) {
+	switch v.Kind() {
+	case reflect.String:
+		if len(directives) > 0 && v.CanSet() {
+			v.SetString(c.apply(v.String(), directives))
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			c.sanitize(v.Elem(), directives)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			c.sanitize(v.Index(i), directives)
+		}
+	case reflect.Map:
+		if len(directives) == 0 || v.Type().Elem().Kind() != reflect.String {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			s := reflect.ValueOf(c.apply(iter.Value().String(), directives))
+			v.SetMapIndex(iter.Key(), s.Convert(v.Type().Elem()))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			c.sanitize(v.Field(i), sanitizeDirectives(f.Tag.Get("sanitize")))
+		}
+	}
+}
+
+// apply runs the directives on s.
+func (c sanitizeConfig) apply(s string, directives []string) string {
+	for _, d := range directives {
+		switch d {
+		case "trim":
+			s = strings.TrimSpace(s)
+		case "normalize":
+			s = c.normalize(s)
+		case "nocontrol":
+			s = strings.Map(func(r rune) rune {
+				if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+					return -1
+				}
+				return r
+			}, s)
+		case "nohtml":
+			s = stripHTML(s)
+		}
+	}
+	return s
+}
+
+// sanitizeDirectives splits a sanitize tag.
+func sanitizeDirectives(tag string) []string {
+	if tag == "" || tag == "-" {
+		return nil
+	}
+	directives := strings.Split(tag, ",")
+	for i, d := range directives {
+		directives[i] = strings.TrimSpace(d)
+	}
+	return directives
+}
+
+// checkSanitizeTags reports unknown directives in the tags of t.
+func checkSanitizeTags(t reflect.Type, seen map[reflect.Type]bool) error {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return checkSanitizeTags(t.Elem(), seen)
+	case reflect.Struct:
+	default:
+		return nil
+	}
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		for _, d := range sanitizeDirectives(f.Tag.Get("sanitize")) {
+			switch d {
+			case "trim", "normalize", "nocontrol", "nohtml":
+			default:
+				return fmt.Errorf("unknown directive %q on field %s", d, f.Name)
+			}
+		}
+		if err := checkSanitizeTags(f.Type, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeUnicode maps unicode spaces and line separators to their ASCII
+// forms and removes format characters.
+func normalizeUnicode(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\u2028' || r == '\u2029':
+			return '\n'
+		case unicode.Is(unicode.Zs, r):
+			return ' '
+		case unicode.Is(unicode.Cf, r):
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// stripHTML removes tags and comments from s. A "<" not starting a tag,
+// as in "a < b", is kept; an unterminated tag is removed to the end.
+func stripHTML(s string) string {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(s, '<')
+		if i < 0 || i+1 == len(s) {
+			b.WriteString(s)
+			return b.String()
+		}
+		b.WriteString(s[:i])
+		s = s[i:]
+		if c := s[1]; !isASCIILetter(c) && c != '/' && c != '!' && c != '?' {
+			b.WriteByte('<')
+			s = s[1:]
+			continue
+		}
+		end := ">"
+		if strings.HasPrefix(s, "<!--") {
+			end = "-->"
+		}
+		j := strings.Index(s, end)
+		if j < 0 {
+			return b.String()
+		}
+		s = s[j+len(end):]
+	}
+}
+
+// isASCIILetter reports whether c is an ASCII letter.
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
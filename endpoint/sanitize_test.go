@@ -0,0 +1,111 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sanitizedInput struct {
+	Title   string            `json:"title" sanitize:"trim,normalize,nocontrol"`
+	Body    string            `json:"body" sanitize:"nohtml,trim"`
+	Tags    []string          `json:"tags" sanitize:"trim"`
+	Note    *string           `json:"note" sanitize:"trim"`
+	Labels  map[string]string `json:"labels" sanitize:"trim"`
+	Raw     string            `json:"raw"`
+	Author  sanitizedAuthor   `json:"author"`
+	Authors []sanitizedAuthor `json:"authors"`
+}
+
+type sanitizedAuthor struct {
+	Name string `json:"name" sanitize:"trim"`
+}
+
+func (i *sanitizedInput) Validate() error {
+	if i.Title == "" {
+		return FieldError{Field: "title", Message: "is required"}
+	}
+	return nil
+}
+
+func TestSanitizeInput(t *testing.T) {
+	body := `{
+		"title": "  Hello\u00a0world\u200b\u0007 ",
+		"body": " <p>Hi <b>there</b></p><!-- x --> a < b ",
+		"tags": [" go ", "api "],
+		"note": " n ",
+		"labels": {"k": " v "},
+		"raw": " raw ",
+		"author": {"name": " Ann "},
+		"authors": [{"name": " Bob "}]
+	}`
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+
+	in, err := SanitizeInput(JSONInput[sanitizedInput]()).
+		Handle(httptest.NewRecorder(), r)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello world", in.Title)
+	assert.Equal(t, "Hi there a < b", in.Body)
+	assert.Equal(t, []string{"go", "api"}, in.Tags)
+	assert.Equal(t, "n", *in.Note)
+	assert.Equal(t, map[string]string{"k": "v"}, in.Labels)
+	assert.Equal(t, " raw ", in.Raw)
+	assert.Equal(t, "Ann", in.Author.Name)
+	assert.Equal(t, "Bob", in.Authors[0].Name)
+}
+
+func TestSanitizeInput_BeforeValidation(t *testing.T) {
+	h := NewHandler(
+		SanitizeInput(JSONInput[sanitizedInput]()),
+		func(http.ResponseWriter, *http.Request, *sanitizedInput) (any, error) {
+			return nil, nil
+		},
+		DefaultErrorHandler{}, JSONOutput(),
+	)
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"title":" \u200b "}`))
+	r.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.Handle(rec, r)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), ErrIDValidation)
+}
+
+func TestSanitizeInput_Normalizer(t *testing.T) {
+	type input struct {
+		Name string `sanitize:"normalize"`
+	}
+	h := SanitizeInput(
+		&dummyComposeInput[input]{res: &input{Name: "abc"}},
+		WithNormalizer(strings.ToUpper),
+	)
+	in, err := h.Handle(nil, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "ABC", in.Name)
+}
+
+func TestSanitizeInput_UnknownDirective(t *testing.T) {
+	type input struct {
+		Name string `sanitize:"trim,shout"`
+	}
+	assert.PanicsWithValue(t,
+		`endpoint: SanitizeInput: unknown directive "shout" on field Name`,
+		func() { SanitizeInput(JSONInput[input]()) })
+}
+
+func TestStripHTML(t *testing.T) {
+	testCases := []struct{ in, want string }{
+		{"plain", "plain"},
+		{"<a href='x'>link</a>", "link"},
+		{"1 < 2 and 3 <", "1 < 2 and 3 <"},
+		{"ok<script", "ok"},
+		{"a<!-- <b> -->c", "ac"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, stripHTML(tc.in), tc.in)
+	}
+}
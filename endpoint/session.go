@@ -0,0 +1,509 @@
+package endpoint
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// SessionStore persists session values by session ID.
+type SessionStore interface {
+	// Load returns the values of a session, or nil if the session does not
+	// exist or has expired.
+	Load(ctx context.Context, id string) (map[string]any, error)
+	// Save stores the values of a session for ttl.
+	Save(ctx context.Context, id string, values map[string]any, ttl time.Duration) error
+	// Delete removes a session.
+	Delete(ctx context.Context, id string) error
+}
+
+// MemorySessionStore keeps sessions in memory. It suits tests and single
+// instance deployments; sessions are lost on restart.
+type MemorySessionStore struct {
+	mu        sync.Mutex
+	sessions  map[string]memorySession
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// memorySession is a stored session.
+type memorySession struct {
+	values  map[string]any
+	expires time.Time
+}
+
+// MemorySessionStore implements the SessionStore interface.
+var _ SessionStore = (*MemorySessionStore)(nil)
+
+// NewMemorySessionStore creates an in-memory session store.
+//
+// Returns:
+//   - *MemorySessionStore: A new MemorySessionStore instance.
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: map[string]memorySession{},
+		now:      time.Now,
+	}
+}
+
+// Load returns a copy of the session values.
+//
+// Parameters:
+//   - ctx: The request context.
+//   - id: The session ID.
+//
+// Returns:
+//   - map[string]any: The values, or nil if not found.
+//   - error: Always nil.
+func (m *MemorySessionStore) Load(
+	_ context.Context, id string,
+) (map[string]any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	if !m.now().Before(s.expires) {
+		delete(m.sessions, id)
+		return nil, nil
+	}
+	return maps.Clone(s.values), nil
+}
+
+// Save stores a copy of the session values. Expired sessions are swept at
+// most once a minute.
+//
+// Parameters:
+//   - ctx: The request context.
+//   - id: The session ID.
+//   - values: The session values.
+//   - ttl: The session lifetime.
+//
+// Returns:
+//   - error: Always nil.
+func (m *MemorySessionStore) Save(
+	_ context.Context, id string, values map[string]any, ttl time.Duration,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	if now.Sub(m.lastSweep) >= time.Minute {
+		for k, s := range m.sessions {
+			if !now.Before(s.expires) {
+				delete(m.sessions, k)
+			}
+		}
+		m.lastSweep = now
+	}
+	m.sessions[id] = memorySession{
+		values:  maps.Clone(values),
+		expires: now.Add(ttl),
+	}
+	return nil
+}
+
+// Delete removes a session.
+//
+// Parameters:
+//   - ctx: The request context.
+//   - id: The session ID.
+//
+// Returns:
+//   - error: Always nil.
+func (m *MemorySessionStore) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
+
+// Session holds the values of a client session. It is safe for concurrent
+// use. Changes are saved by SessionMiddleware before the response header
+// is written.
+type Session struct {
+	mu         sync.Mutex
+	id         string
+	values     map[string]any
+	stored     bool
+	dirty      bool
+	destroyed  bool
+	regenerate bool
+}
+
+// ID returns the session ID, or "" for a new session not yet saved.
+//
+// Returns:
+//   - string: The session ID.
+func (s *Session) ID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stored {
+		return ""
+	}
+	return s.id
+}
+
+// Get returns a session value.
+//
+// Parameters:
+//   - key: The value key.
+//
+// Returns:
+//   - any: The value.
+//   - bool: Whether the value exists.
+func (s *Session) Get(key string) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Set stores a session value. Values must be supported by the store; the
+// memory store accepts any value.
+//
+// Parameters:
+//   - key: The value key.
+//   - value: The value.
+func (s *Session) Set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values == nil {
+		s.values = map[string]any{}
+	}
+	s.values[key] = value
+	s.dirty = true
+}
+
+// Delete removes a session value.
+//
+// Parameters:
+//   - key: The value key.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	s.dirty = true
+}
+
+// Regenerate moves the session to a new ID, e.g. after login to prevent
+// session fixation.
+func (s *Session) Regenerate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.regenerate = true
+	s.dirty = true
+}
+
+// Destroy deletes the session and its cookie, e.g. on logout.
+func (s *Session) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.values)
+	s.destroyed = true
+}
+
+// SessionValue returns a session value of type T.
+//
+// Parameters:
+//   - s: The session.
+//   - key: The value key.
+//
+// Returns:
+//   - T: The value, or the zero value.
+//   - bool: Whether a value of type T exists.
+func SessionValue[T any](s *Session, key string) (T, bool) {
+	v, _ := s.Get(key)
+	t, ok := v.(T)
+	return t, ok
+}
+
+// sessionKey is the context key of the request session.
+type sessionKey struct{}
+
+// SessionFromContext returns the session stored by SessionMiddleware.
+//
+// Parameters:
+//   - ctx: The context.
+//
+// Returns:
+//   - *Session: The session.
+//   - bool: Whether a session was found.
+func SessionFromContext(ctx context.Context) (*Session, bool) {
+	s, ok := ctx.Value(sessionKey{}).(*Session)
+	return s, ok
+}
+
+// SessionFromRequest returns the session of a request handled by
+// SessionMiddleware.
+//
+// Parameters:
+//   - r: The HTTP request.
+//
+// Returns:
+//   - *Session: The session.
+//   - bool: Whether a session was found.
+func SessionFromRequest(r *http.Request) (*Session, bool) {
+	return SessionFromContext(r.Context())
+}
+
+// SessionOption configures SessionMiddleware.
+type SessionOption func(*sessionConfig)
+
+// sessionConfig holds the session settings.
+type sessionConfig struct {
+	store      SessionStore
+	cookieName string
+	ttl        time.Duration
+	cookie     *SecureCookie
+	insecure   bool
+}
+
+// WithSessionCookieName sets the session cookie name. The default is
+// "session_id".
+//
+// Parameters:
+//   - name: The cookie name.
+//
+// Returns:
+//   - SessionOption: The option.
+func WithSessionCookieName(name string) SessionOption {
+	return func(c *sessionConfig) { c.cookieName = name }
+}
+
+// WithSessionTTL sets the session lifetime, renewed on every save. The
+// default is 24 hours.
+//
+// Parameters:
+//   - ttl: The session lifetime.
+//
+// Returns:
+//   - SessionOption: The option.
+func WithSessionTTL(ttl time.Duration) SessionOption {
+	return func(c *sessionConfig) { c.ttl = ttl }
+}
+
+// WithSessionSecureCookie signs the session ID cookie with sc and uses its
+// cookie attributes.
+//
+// Parameters:
+//   - sc: The secure cookie codec.
+//
+// Returns:
+//   - SessionOption: The option.
+func WithSessionSecureCookie(sc *SecureCookie) SessionOption {
+	return func(c *sessionConfig) { c.cookie = sc }
+}
+
+// WithSessionInsecureCookie clears the Secure attribute of the plain
+// session cookie, e.g. for local development over HTTP.
+//
+// Returns:
+//   - SessionOption: The option.
+func WithSessionInsecureCookie() SessionOption {
+	return func(c *sessionConfig) { c.insecure = true }
+}
+
+// SessionMiddleware returns a middleware loading the client's session from
+// store and exposing it through SessionFromRequest. Modified sessions are
+// saved, and the session cookie set, just before the response header is
+// written. Unknown session IDs are never adopted: clients presenting one
+// get a fresh session. Store failures become a 500 internal_error.
+//
+// Parameters:
+//   - store: The session store.
+//   - opts: Optional session options.
+//
+// Returns:
+//   - Middleware: The session middleware.
+func SessionMiddleware(store SessionStore, opts ...SessionOption) Middleware {
+	cfg := sessionConfig{
+		store:      store,
+		cookieName: "session_id",
+		ttl:        24 * time.Hour,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s, err := cfg.load(r)
+			if err != nil {
+				writeAPIError(w, r, http.StatusInternalServerError,
+					apierror.NewAPIError("internal_error").
+						WithMessage("Internal server error"))
+				return
+			}
+			sw := &sessionWriter{ResponseWriter: w, r: r, commit: func() error {
+				return cfg.save(w, r, s)
+			}}
+			next.ServeHTTP(sw, r.WithContext(
+				context.WithValue(r.Context(), sessionKey{}, s),
+			))
+			if !sw.committed {
+				sw.WriteHeader(http.StatusOK)
+			}
+		})
+	}
+}
+
+// load returns the session of the request, or a new session.
+func (c *sessionConfig) load(r *http.Request) (*Session, error) {
+	var id string
+	if c.cookie != nil {
+		if value, err := c.cookie.Read(r, c.cookieName); err == nil {
+			id = string(value)
+		}
+	} else if cookie, err := r.Cookie(c.cookieName); err == nil {
+		id = cookie.Value
+	}
+	if id != "" {
+		values, err := c.store.Load(r.Context(), id)
+		if err != nil {
+			return nil, err
+		}
+		if values != nil {
+			return &Session{id: id, values: values, stored: true}, nil
+		}
+	}
+	return &Session{}, nil
+}
+
+// save persists a modified session and updates its cookie.
+func (c *sessionConfig) save(
+	w http.ResponseWriter, r *http.Request, s *Session,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ctx := r.Context()
+	if s.destroyed {
+		if !s.stored {
+			return nil
+		}
+		if err := c.store.Delete(ctx, s.id); err != nil {
+			return err
+		}
+		c.expireCookie(w)
+		return nil
+	}
+	if !s.dirty {
+		return nil
+	}
+	if s.stored && s.regenerate {
+		if err := c.store.Delete(ctx, s.id); err != nil {
+			return err
+		}
+	}
+	if !s.stored || s.regenerate {
+		id, err := newSessionID()
+		if err != nil {
+			return err
+		}
+		s.id = id
+	}
+	if err := c.store.Save(ctx, s.id, s.values, c.ttl); err != nil {
+		return err
+	}
+	s.stored, s.dirty, s.regenerate = true, false, false
+	return c.writeCookie(w, s.id)
+}
+
+// writeCookie sets the session cookie.
+func (c *sessionConfig) writeCookie(w http.ResponseWriter, id string) error {
+	if c.cookie != nil {
+		return c.cookie.Write(w, c.cookieName, []byte(id))
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     c.cookieName,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   int(c.ttl / time.Second),
+		Secure:   !c.insecure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// expireCookie deletes the session cookie.
+func (c *sessionConfig) expireCookie(w http.ResponseWriter) {
+	if c.cookie != nil {
+		c.cookie.Delete(w, c.cookieName)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     c.cookieName,
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   !c.insecure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// newSessionID generates a random session ID.
+func newSessionID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// sessionWriter saves the session before the response header is written.
+// If saving fails, the response is replaced by a 500 internal_error.
+type sessionWriter struct {
+	http.ResponseWriter
+	r         *http.Request
+	commit    func() error
+	committed bool
+	failed    bool
+}
+
+// WriteHeader saves the session and writes the header.
+func (w *sessionWriter) WriteHeader(status int) {
+	if !w.committed {
+		w.committed = true
+		if err := w.commit(); err != nil {
+			w.failed = true
+			writeAPIError(w.ResponseWriter, w.r, http.StatusInternalServerError,
+				apierror.NewAPIError("internal_error").
+					WithMessage("Internal server error"))
+			return
+		}
+	}
+	if w.failed {
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write saves the session if needed and writes b.
+func (w *sessionWriter) Write(b []byte) (int, error) {
+	if !w.committed {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.failed {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush saves the session if needed and flushes the response.
+func (w *sessionWriter) Flush() {
+	if !w.committed {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok && !w.failed {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer.
+func (w *sessionWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
@@ -0,0 +1,155 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingSessionStore fails every save.
+type failingSessionStore struct {
+	*MemorySessionStore
+}
+
+func (failingSessionStore) Save(
+	context.Context, string, map[string]any, time.Duration,
+) error {
+	return errors.New("store down")
+}
+
+func sessionTestHandler(store SessionStore, opts ...SessionOption) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/count", func(w http.ResponseWriter, r *http.Request) {
+		s, _ := SessionFromRequest(r)
+		n, _ := SessionValue[int](s, "count")
+		s.Set("count", n+1)
+		_, _ = w.Write([]byte(strconv.Itoa(n + 1)))
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		s, _ := SessionFromRequest(r)
+		s.Regenerate()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/logout", func(w http.ResponseWriter, r *http.Request) {
+		s, _ := SessionFromRequest(r)
+		s.Destroy()
+	})
+	mux.HandleFunc("/read", func(w http.ResponseWriter, r *http.Request) {
+		s, _ := SessionFromRequest(r)
+		_, _ = w.Write([]byte(s.ID()))
+	})
+	return SessionMiddleware(store, opts...)(mux)
+}
+
+func sessionRequest(
+	t *testing.T, h http.Handler, path string, cookie *http.Cookie,
+) (*httptest.ResponseRecorder, *http.Cookie) {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	if cookie != nil {
+		r.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	for _, c := range rec.Result().Cookies() {
+		return rec, c
+	}
+	return rec, nil
+}
+
+func TestSessionMiddleware(t *testing.T) {
+	store := NewMemorySessionStore()
+	h := sessionTestHandler(store)
+
+	// Reading an empty session sets no cookie.
+	rec, cookie := sessionRequest(t, h, "/read", nil)
+	assert.Equal(t, "", rec.Body.String())
+	assert.Nil(t, cookie)
+
+	rec, cookie = sessionRequest(t, h, "/count", nil)
+	require.NotNil(t, cookie)
+	assert.Equal(t, "1", rec.Body.String())
+	assert.Equal(t, "session_id", cookie.Name)
+	assert.True(t, cookie.HttpOnly)
+	assert.True(t, cookie.Secure)
+	assert.Equal(t, 86400, cookie.MaxAge)
+
+	rec, _ = sessionRequest(t, h, "/count", cookie)
+	assert.Equal(t, "2", rec.Body.String())
+	rec, _ = sessionRequest(t, h, "/read", cookie)
+	assert.Equal(t, cookie.Value, rec.Body.String())
+
+	// Regenerating moves the values to a new ID.
+	rec, renewed := sessionRequest(t, h, "/login", cookie)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	require.NotNil(t, renewed)
+	assert.NotEqual(t, cookie.Value, renewed.Value)
+	rec, _ = sessionRequest(t, h, "/count", cookie)
+	assert.Equal(t, "1", rec.Body.String(), "old ID must be gone")
+	rec, _ = sessionRequest(t, h, "/count", renewed)
+	assert.Equal(t, "3", rec.Body.String())
+
+	rec, expired := sessionRequest(t, h, "/logout", renewed)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, expired)
+	assert.Equal(t, -1, expired.MaxAge)
+	rec, _ = sessionRequest(t, h, "/count", renewed)
+	assert.Equal(t, "1", rec.Body.String())
+
+	// Unknown IDs are not adopted.
+	_, cookie = sessionRequest(t, h, "/count",
+		&http.Cookie{Name: "session_id", Value: "attacker-chosen"})
+	require.NotNil(t, cookie)
+	assert.NotEqual(t, "attacker-chosen", cookie.Value)
+}
+
+func TestSessionMiddleware_SecureCookie(t *testing.T) {
+	sc, err := NewSecureCookie([]CookieKey{{Hash: testHashKey}})
+	require.NoError(t, err)
+	h := sessionTestHandler(NewMemorySessionStore(), WithSessionSecureCookie(sc))
+
+	_, cookie := sessionRequest(t, h, "/count", nil)
+	require.NotNil(t, cookie)
+	rec, _ := sessionRequest(t, h, "/read", cookie)
+	id := rec.Body.String()
+	assert.NotEqual(t, id, cookie.Value)
+
+	rec, _ = sessionRequest(t, h, "/count",
+		&http.Cookie{Name: "session_id", Value: id})
+	assert.Equal(t, "1", rec.Body.String(), "unsigned IDs are ignored")
+}
+
+func TestSessionMiddleware_SaveError(t *testing.T) {
+	h := sessionTestHandler(failingSessionStore{NewMemorySessionStore()})
+	rec, cookie := sessionRequest(t, h, "/count", nil)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"id":"internal_error","message":"Internal server error"}`,
+		rec.Body.String())
+	assert.Nil(t, cookie)
+}
+
+func TestMemorySessionStore_Expiry(t *testing.T) {
+	store := NewMemorySessionStore()
+	now := time.Unix(1_700_000_000, 0)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	values := map[string]any{"a": 1}
+	require.NoError(t, store.Save(ctx, "id", values, time.Minute))
+	values["a"] = 2
+	loaded, err := store.Load(ctx, "id")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": 1}, loaded)
+
+	now = now.Add(time.Minute)
+	loaded, err = store.Load(ctx, "id")
+	require.NoError(t, err)
+	assert.Nil(t, loaded)
+}
@@ -0,0 +1,228 @@
+package endpoint
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// singleflightMaxBody is the largest response body shared with followers.
+const singleflightMaxBody = 1 << 20
+
+// SingleflightOption configures SingleflightMiddleware.
+type SingleflightOption func(*singleflightConfig)
+
+// singleflightConfig holds the singleflight settings.
+type singleflightConfig struct {
+	key func(r *http.Request) string
+}
+
+// WithSingleflightKey sets the function deriving the deduplication key.
+// Requests for which it returns "" are not deduplicated. The default key
+// is the method, host, request URI and the Accept and Accept-Language
+// headers, so negotiated formats and locales are kept apart, and requests
+// carrying Authorization or Cookie headers are not deduplicated, since
+// their responses may differ per caller.
+//
+// Parameters:
+//   - fn: Returns the key of a request.
+//
+// Returns:
+//   - SingleflightOption: The option.
+func WithSingleflightKey(fn func(r *http.Request) string) SingleflightOption {
+	return func(c *singleflightConfig) { c.key = fn }
+}
+
+// SingleflightMiddleware returns a middleware collapsing concurrent
+// identical GET and HEAD requests into one handler execution. The first
+// request runs the handler; requests arriving while it runs wait for it and
+// receive a copy of its status, headers and body.
+//
+// Responses that are streamed, set cookies, exceed 1MB or Vary on request
+// headers other than Accept, Accept-Language and Accept-Encoding are not
+// shared: waiting requests then run the handler themselves. Waiting requests whose
+// context ends return without writing.
+//
+// Parameters:
+//   - opts: Optional singleflight options.
+//
+// Returns:
+//   - Middleware: The singleflight middleware.
+func SingleflightMiddleware(opts ...SingleflightOption) Middleware {
+	cfg := singleflightConfig{key: defaultSingleflightKey}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	g := &flightGroup{calls: map[string]*flightCall{}}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := ""
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				key = cfg.key(r)
+			}
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			g.serve(key, w, r, next)
+		})
+	}
+}
+
+// defaultSingleflightKey keys anonymous requests by method, host, URI and
+// the negotiation headers.
+func defaultSingleflightKey(r *http.Request) string {
+	if r.Header.Get("Authorization") != "" || r.Header.Get("Cookie") != "" {
+		return ""
+	}
+	return strings.Join([]string{
+		r.Method, r.Host, r.URL.RequestURI(),
+		strings.Join(r.Header.Values("Accept"), ","),
+		strings.Join(r.Header.Values("Accept-Language"), ","),
+	}, "\n")
+}
+
+// singleflightVary lists the Vary headers accounted for by the default key,
+// or not affecting the handler's output in the case of Accept-Encoding.
+var singleflightVary = []string{"Accept", "Accept-Language", "Accept-Encoding"}
+
+// flightGroup tracks in-flight handler executions by key.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall is an in-flight execution. done is closed when it finishes;
+// shared is set if followers may replay the recorded response.
+type flightCall struct {
+	done   chan struct{}
+	shared bool
+	status int
+	header http.Header
+	body   []byte
+}
+
+// serve runs next for the first request of a key and replays its response
+// to concurrent followers.
+func (g *flightGroup) serve(
+	key string, w http.ResponseWriter, r *http.Request, next http.Handler,
+) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-c.done:
+		case <-r.Context().Done():
+			return
+		}
+		if !c.shared {
+			next.ServeHTTP(w, r)
+			return
+		}
+		hdr := w.Header()
+		for k, v := range c.header {
+			hdr[k] = append([]string(nil), v...)
+		}
+		w.WriteHeader(c.status)
+		_, _ = w.Write(c.body)
+		return
+	}
+	c := &flightCall{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	rec := &flightRecorder{ResponseWriter: w}
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		if rec.shareable() {
+			c.shared = true
+			c.status = rec.status
+			c.header = rec.header
+			c.body = rec.body
+		}
+		close(c.done)
+	}()
+	next.ServeHTTP(rec, r)
+	if rec.status == 0 {
+		rec.WriteHeader(http.StatusOK)
+	}
+	rec.complete = true
+}
+
+// flightRecorder passes a response through while recording it.
+type flightRecorder struct {
+	http.ResponseWriter
+	status   int
+	header   http.Header
+	body     []byte
+	tooLarge bool
+	flushed  bool
+	complete bool
+}
+
+// WriteHeader records the status and a snapshot of the headers.
+func (f *flightRecorder) WriteHeader(code int) {
+	if f.status == 0 && code >= 200 {
+		f.status = code
+		f.header = f.ResponseWriter.Header().Clone()
+	}
+	f.ResponseWriter.WriteHeader(code)
+}
+
+// Write records and writes the data.
+func (f *flightRecorder) Write(p []byte) (int, error) {
+	if f.status == 0 {
+		f.WriteHeader(http.StatusOK)
+	}
+	if !f.tooLarge {
+		if len(f.body)+len(p) > singleflightMaxBody {
+			f.tooLarge = true
+			f.body = nil
+		} else {
+			f.body = append(f.body, p...)
+		}
+	}
+	return f.ResponseWriter.Write(p)
+}
+
+// Flush marks the response as streamed, which is never shared.
+func (f *flightRecorder) Flush() {
+	f.flushed = true
+	if fl, ok := f.ResponseWriter.(http.Flusher); ok {
+		fl.Flush()
+	}
+}
+
+// Unwrap returns the underlying response writer.
+func (f *flightRecorder) Unwrap() http.ResponseWriter {
+	return f.ResponseWriter
+}
+
+// shareable reports whether followers may replay the response. Responses
+// of handlers that panicked are never shared.
+func (f *flightRecorder) shareable() bool {
+	return f.complete && !f.tooLarge && !f.flushed &&
+		f.header.Get("Set-Cookie") == "" && !f.variesOnOther()
+}
+
+// variesOnOther reports whether the response Varies on a request header
+// outside singleflightVary.
+func (f *flightRecorder) variesOnOther() bool {
+	for _, value := range f.header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if !slices.ContainsFunc(singleflightVary, func(v string) bool {
+				return strings.EqualFold(name, v)
+			}) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingleflightMiddleware(t *testing.T) {
+	var calls atomic.Int32
+	h := SingleflightMiddleware()(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			n := calls.Add(1)
+			time.Sleep(100 * time.Millisecond)
+			w.Header().Set("X-Query", r.URL.RawQuery)
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(strconv.Itoa(int(n))))
+		},
+	))
+
+	recs := make([]*httptest.ResponseRecorder, 5)
+	var wg sync.WaitGroup
+	for i := range recs {
+		recs[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.ServeHTTP(recs[i], httptest.NewRequest(http.MethodGet, "/report?q=1", nil))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, rec := range recs {
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, "1", rec.Body.String())
+		assert.Equal(t, "q=1", rec.Header().Get("X-Query"))
+	}
+
+	// Finished calls are not cached.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/report?q=1", nil))
+	assert.Equal(t, "2", rec.Body.String())
+}
+
+func TestSingleflightMiddleware_Bypass(t *testing.T) {
+	var calls int
+	h := SingleflightMiddleware()(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) { calls++ },
+	))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer x")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, 2, calls)
+}
+
+func TestDefaultSingleflightKey(t *testing.T) {
+	newRequest := func(host, accept, language string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/report", nil)
+		r.Host = host
+		r.Header.Set("Accept", accept)
+		r.Header.Set("Accept-Language", language)
+		return r
+	}
+	base := defaultSingleflightKey(newRequest("a.test", "application/json", "en"))
+	assert.Equal(t, base, defaultSingleflightKey(newRequest("a.test", "application/json", "en")))
+	assert.NotEqual(t, base, defaultSingleflightKey(newRequest("b.test", "application/json", "en")))
+	assert.NotEqual(t, base, defaultSingleflightKey(newRequest("a.test", "application/xml", "en")))
+	assert.NotEqual(t, base, defaultSingleflightKey(newRequest("a.test", "application/json", "fi")))
+}
+
+func TestFlightRecorder_Shareable(t *testing.T) {
+	testCases := []struct {
+		name  string
+		write func(w http.ResponseWriter)
+		want  bool
+	}{
+		{"Plain", func(w http.ResponseWriter) { _, _ = w.Write([]byte("x")) }, true},
+		{"Cookie", func(w http.ResponseWriter) {
+			http.SetCookie(w, &http.Cookie{Name: "a", Value: "b"})
+			_, _ = w.Write([]byte("x"))
+		}, false},
+		{"Flushed", func(w http.ResponseWriter) { w.(http.Flusher).Flush() }, false},
+		{"Too large", func(w http.ResponseWriter) {
+			_, _ = w.Write(make([]byte, singleflightMaxBody+1))
+		}, false},
+		{"Vary on negotiation", func(w http.ResponseWriter) {
+			w.Header().Add("Vary", "Accept, Accept-Language")
+			_, _ = w.Write([]byte("x"))
+		}, true},
+		{"Vary on other header", func(w http.ResponseWriter) {
+			w.Header().Add("Vary", "Accept, X-Tenant")
+			_, _ = w.Write([]byte("x"))
+		}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := &flightRecorder{ResponseWriter: httptest.NewRecorder()}
+			tc.write(rec)
+			rec.complete = true
+			assert.Equal(t, tc.want, rec.shareable())
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package endpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aatuh/pureapi-core/internal/shutdown"
+)
+
+// ErrSSEClosed is returned when sending on a closed SSE stream.
+var ErrSSEClosed = errors.New("sse: stream closed")
+
+// SSEEvent is a Server-Sent Event. Empty fields are omitted.
+type SSEEvent struct {
+	ID    string        // Event ID, sent back by clients as Last-Event-ID.
+	Event string        // Event type; clients default to "message".
+	Data  string        // Payload; may span several lines.
+	Retry time.Duration // Reconnection delay advised to the client.
+}
+
+// SSEOption configures an SSE stream.
+type SSEOption func(*SSEStream)
+
+// WithSSEHeartbeat sets the interval of comment lines sent to keep idle
+// connections open through proxies. Defaults to 15 seconds. Zero disables
+// heartbeats.
+//
+// Parameters:
+//   - interval: The heartbeat interval.
+//
+// Returns:
+//   - SSEOption: An SSE option function.
+func WithSSEHeartbeat(interval time.Duration) SSEOption {
+	return func(s *SSEStream) { s.heartbeat = interval }
+}
+
+// WithSSERetry sends a retry field when the stream opens, advising clients
+// how long to wait before reconnecting.
+//
+// Parameters:
+//   - retry: The reconnection delay.
+//
+// Returns:
+//   - SSEOption: An SSE option function.
+func WithSSERetry(retry time.Duration) SSEOption {
+	return func(s *SSEStream) { s.retry = retry }
+}
+
+// SSEStream writes Server-Sent Events to a response. Every event is flushed
+// immediately. The stream ends when the client disconnects, the serving
+// Handler begins graceful shutdown or Close is called; Context reports all
+// three. It is safe for concurrent use.
+type SSEStream struct {
+	w         http.ResponseWriter
+	rc        *http.ResponseController
+	ctx       context.Context
+	cancel    context.CancelFunc
+	mu        sync.Mutex
+	heartbeat time.Duration
+	retry     time.Duration
+	done      chan struct{}
+}
+
+// NewSSEStream starts an event stream on w. It writes the response headers
+// and starts the heartbeat; handlers then send events until Context is
+// done:
+//
+//	stream, err := endpoint.NewSSEStream(w, r)
+//	if err != nil {
+//		return nil, err
+//	}
+//	defer stream.Close()
+//	for {
+//		select {
+//		case <-stream.Context().Done():
+//			return nil, nil
+//		case msg := <-messages:
+//			if err := stream.SendJSON("message", msg); err != nil {
+//				return nil, nil
+//			}
+//		}
+//	}
+//
+// Parameters:
+//   - w: The HTTP response writer, which must support flushing.
+//   - r: The HTTP request.
+//   - opts: Optional SSE options.
+//
+// Returns:
+//   - *SSEStream: The event stream.
+//   - error: An error if the response cannot be flushed.
+func NewSSEStream(
+	w http.ResponseWriter, r *http.Request, opts ...SSEOption,
+) (*SSEStream, error) {
+	s := &SSEStream{
+		w:         w,
+		rc:        http.NewResponseController(w),
+		heartbeat: 15 * time.Second,
+		done:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.ctx, s.cancel = context.WithCancel(r.Context())
+	stop := context.AfterFunc(shutdown.FromContext(r.Context()), s.cancel)
+
+	hdr := w.Header()
+	hdr.Set("Content-Type", "text/event-stream")
+	hdr.Set("Cache-Control", "no-cache")
+	hdr.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	var err error
+	if s.retry > 0 {
+		err = s.write("retry: " + strconv.FormatInt(s.retry.Milliseconds(), 10) + "\n\n")
+	} else {
+		err = s.rc.Flush()
+	}
+	if err != nil {
+		stop()
+		s.cancel()
+		return nil, fmt.Errorf("NewSSEStream: %w", err)
+	}
+	go func() {
+		defer close(s.done)
+		defer stop()
+		s.keepAlive()
+	}()
+	return s, nil
+}
+
+// Context returns a context that is done when the stream ends.
+//
+// Returns:
+//   - context.Context: The stream context.
+func (s *SSEStream) Context() context.Context { return s.ctx }
+
+// Send writes an event and flushes it.
+//
+// Parameters:
+//   - ev: The event.
+//
+// Returns:
+//   - error: ErrSSEClosed if the stream ended, or a write error.
+func (s *SSEStream) Send(ev SSEEvent) error {
+	if strings.ContainsAny(ev.ID, "\r\n") || strings.ContainsAny(ev.Event, "\r\n") {
+		return errors.New("sse: event id and type must not contain newlines")
+	}
+	var b strings.Builder
+	if ev.ID != "" {
+		b.WriteString("id: " + ev.ID + "\n")
+	}
+	if ev.Event != "" {
+		b.WriteString("event: " + ev.Event + "\n")
+	}
+	if ev.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(ev.Retry.Milliseconds(), 10) + "\n")
+	}
+	data := strings.ReplaceAll(ev.Data, "\r\n", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	return s.write(b.String())
+}
+
+// SendJSON writes an event with v encoded as JSON as its data.
+//
+// Parameters:
+//   - event: The event type, or empty for the default type.
+//   - v: The value to encode.
+//
+// Returns:
+//   - error: An encoding error, ErrSSEClosed or a write error.
+func (s *SSEStream) SendJSON(event string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Send(SSEEvent{Event: event, Data: string(data)})
+}
+
+// Close ends the stream and stops the heartbeat. The response is complete
+// once the handler returns.
+func (s *SSEStream) Close() {
+	s.cancel()
+	<-s.done
+}
+
+// keepAlive sends heartbeat comments until the stream ends.
+func (s *SSEStream) keepAlive() {
+	if s.heartbeat <= 0 {
+		<-s.ctx.Done()
+		return
+	}
+	ticker := time.NewTicker(s.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if s.write(": keep-alive\n\n") != nil {
+				return
+			}
+		}
+	}
+}
+
+// write writes and flushes a frame, ending the stream on failure.
+func (s *SSEStream) write(frame string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx.Err() != nil {
+		return ErrSSEClosed
+	}
+	if _, err := s.w.Write([]byte(frame)); err != nil {
+		s.cancel()
+		return err
+	}
+	if err := s.rc.Flush(); err != nil {
+		s.cancel()
+		return err
+	}
+	return nil
+}
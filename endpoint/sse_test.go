@@ -0,0 +1,83 @@
+package endpoint
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-core/internal/shutdown"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEStream_Framing(t *testing.T) {
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/events", nil)
+
+	s, err := NewSSEStream(rec, r, WithSSEHeartbeat(0), WithSSERetry(2*time.Second))
+	require.NoError(t, err)
+	require.NoError(t, s.Send(SSEEvent{ID: "1", Event: "update", Data: "a\nb"}))
+	require.NoError(t, s.SendJSON("", map[string]int{"n": 1}))
+	assert.Error(t, s.Send(SSEEvent{Event: "bad\nname"}))
+	s.Close()
+	assert.ErrorIs(t, s.Send(SSEEvent{Data: "late"}), ErrSSEClosed)
+
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+	assert.True(t, rec.Flushed)
+	assert.Equal(t,
+		"retry: 2000\n\n"+
+			"id: 1\nevent: update\ndata: a\ndata: b\n\n"+
+			"data: {\"n\":1}\n\n",
+		rec.Body.String())
+}
+
+func TestSSEStream_Heartbeat(t *testing.T) {
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/events", nil)
+
+	s, err := NewSSEStream(rec, r, WithSSEHeartbeat(5*time.Millisecond))
+	require.NoError(t, err)
+	time.Sleep(30 * time.Millisecond)
+	s.Close()
+
+	assert.True(t, strings.HasPrefix(rec.Body.String(), ": keep-alive\n\n"))
+}
+
+func TestSSEStream_EndsOnShutdownAndDisconnect(t *testing.T) {
+	shutdownCtx, beginShutdown := context.WithCancel(context.Background())
+	r := httptest.NewRequest(http.MethodGet, "/events", nil)
+	r = r.WithContext(shutdown.WithContext(r.Context(), shutdownCtx))
+	s, err := NewSSEStream(httptest.NewRecorder(), r, WithSSEHeartbeat(0))
+	require.NoError(t, err)
+	beginShutdown()
+	select {
+	case <-s.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("stream not closed on shutdown")
+	}
+	s.Close()
+
+	reqCtx, disconnect := context.WithCancel(context.Background())
+	r = httptest.NewRequest(http.MethodGet, "/events", nil).WithContext(reqCtx)
+	s, err = NewSSEStream(httptest.NewRecorder(), r, WithSSEHeartbeat(0))
+	require.NoError(t, err)
+	disconnect()
+	select {
+	case <-s.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("stream not closed on disconnect")
+	}
+	s.Close()
+}
+
+type noFlushWriter struct{ http.ResponseWriter }
+
+func TestSSEStream_RequiresFlusher(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/events", nil)
+	_, err := NewSSEStream(noFlushWriter{httptest.NewRecorder()}, r)
+	assert.ErrorIs(t, err, http.ErrNotSupported)
+}
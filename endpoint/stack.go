@@ -1,16 +1,40 @@
 package endpoint
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
 	"sync"
 )
 
+// ErrDuplicateWrapper is returned by Merge with MergeError when both stacks
+// contain a wrapper with the same ID.
+var ErrDuplicateWrapper = errors.New("duplicate wrapper ID")
+
+// MergePolicy decides what Merge does with wrappers whose ID is already in
+// the stack.
+type MergePolicy int
+
+// Merge policies.
+const (
+	MergeSkip    MergePolicy = iota // Keep the existing wrapper.
+	MergeReplace                    // Replace the existing wrapper in place.
+	MergeError                      // Fail with ErrDuplicateWrapper.
+)
+
 // DefaultStack manages a list of middleware wrappers with concurrency safety
-// for editing the list.
+// for editing the list. Wrappers may belong to a named group, which keeps
+// related wrappers together and lets them be listed or removed at once.
 type DefaultStack struct {
 	mu       sync.RWMutex
 	wrappers []Wrapper
+	groups   []string // Group name of each wrapper, "" if ungrouped.
 }
 
+// DefaultStack implements the Middlewares interface.
+var _ Middlewares = (*DefaultStack)(nil)
+
 // DefaultStack implements the Stack interface.
 var _ Stack = (*DefaultStack)(nil)
 
@@ -25,6 +49,7 @@ func NewStack(wrappers ...Wrapper) *DefaultStack {
 	return &DefaultStack{
 		mu:       sync.RWMutex{},
 		wrappers: wrappers,
+		groups:   make([]string, len(wrappers)),
 	}
 }
 
@@ -54,6 +79,18 @@ func (s *DefaultStack) Middlewares() Middlewares {
 	return NewMiddlewares(middlewares...)
 }
 
+// Chain applies the middlewares of the stack to a handler, so a stack can be
+// used as the middlewares of an endpoint while keeping its wrapper metadata.
+//
+// Parameters:
+//   - h: The handler to wrap.
+//
+// Returns:
+//   - http.Handler: The wrapped handler.
+func (s *DefaultStack) Chain(h http.Handler) http.Handler {
+	return s.Middlewares().Chain(h)
+}
+
 // Clone creates a deep copy of the Stack.
 //
 // Returns:
@@ -64,6 +101,8 @@ func (s *DefaultStack) Clone() Stack {
 	newStack := &DefaultStack{}
 	newStack.wrappers = make([]Wrapper, len(s.wrappers))
 	copy(newStack.wrappers, s.wrappers)
+	newStack.groups = make([]string, len(s.groups))
+	copy(newStack.groups, s.groups)
 	return newStack
 }
 
@@ -78,7 +117,7 @@ func (s *DefaultStack) Clone() Stack {
 func (s *DefaultStack) AddWrapper(w Wrapper) Stack {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.wrappers = append(s.wrappers, w)
+	s.insert(len(s.wrappers), w, "")
 	return s
 }
 
@@ -101,14 +140,11 @@ func (s *DefaultStack) InsertBefore(
 	defer s.mu.Unlock()
 	for i, wrapper := range s.wrappers {
 		if wrapper.ID() == id {
-			s.wrappers = append(
-				s.wrappers[:i],
-				append([]Wrapper{w}, s.wrappers[i:]...)...,
-			)
+			s.insert(i, w, "")
 			return s, true
 		}
 	}
-	s.wrappers = append(s.wrappers, w)
+	s.insert(len(s.wrappers), w, "")
 	return s, false
 }
 
@@ -130,15 +166,11 @@ func (s *DefaultStack) InsertAfter(
 	defer s.mu.Unlock()
 	for i, wrapper := range s.wrappers {
 		if wrapper.ID() == id {
-			pos := i + 1
-			s.wrappers = append(
-				s.wrappers[:pos],
-				append([]Wrapper{w}, s.wrappers[pos:]...)...,
-			)
+			s.insert(i+1, w, "")
 			return s, true
 		}
 	}
-	s.wrappers = append(s.wrappers, w)
+	s.insert(len(s.wrappers), w, "")
 	return s, false
 }
 
@@ -156,9 +188,184 @@ func (s *DefaultStack) Remove(id string) (Stack, bool) {
 	defer s.mu.Unlock()
 	for i, wrapper := range s.wrappers {
 		if wrapper.ID() == id {
-			s.wrappers = append(s.wrappers[:i], s.wrappers[i+1:]...)
+			s.syncGroups()
+			s.wrappers = slices.Delete(s.wrappers, i, i+1)
+			s.groups = slices.Delete(s.groups, i, i+1)
 			return s, true
 		}
 	}
 	return s, false
 }
+
+// AddGroup adds wrappers to the named group. A new group is appended to the
+// end of the stack; wrappers of an existing group are inserted after its
+// last wrapper, keeping the group together.
+//
+// Parameters:
+//   - name: The group name.
+//   - wrappers: The wrappers to add.
+//
+// Returns:
+//   - Stack: The updated middleware stack.
+func (s *DefaultStack) AddGroup(name string, wrappers ...Wrapper) Stack {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, w := range wrappers {
+		s.addToGroup(name, w)
+	}
+	return s
+}
+
+// Group returns the wrappers of the named group in stack order.
+//
+// Parameters:
+//   - name: The group name.
+//
+// Returns:
+//   - []Wrapper: The wrappers of the group, nil if there is none.
+func (s *DefaultStack) Group(name string) []Wrapper {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []Wrapper
+	for i, group := range s.groups {
+		if group == name && name != "" {
+			out = append(out, s.wrappers[i])
+		}
+	}
+	return out
+}
+
+// Groups returns the names of the groups in the order they first appear in
+// the stack.
+//
+// Returns:
+//   - []string: The group names.
+func (s *DefaultStack) Groups() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []string
+	for _, group := range s.groups {
+		if group != "" && !slices.Contains(out, group) {
+			out = append(out, group)
+		}
+	}
+	return out
+}
+
+// RemoveGroup deletes all wrappers of the named group from the stack.
+// Returns true if any wrapper was removed; false otherwise.
+//
+// Parameters:
+//   - name: The group name.
+//
+// Returns:
+//   - Stack: The updated middleware stack.
+//   - bool: True if the group was found and removed; false otherwise.
+func (s *DefaultStack) RemoveGroup(name string) (Stack, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	found := false
+	for i := len(s.groups) - 1; i >= 0; i-- {
+		if s.groups[i] == name && name != "" {
+			s.wrappers = slices.Delete(s.wrappers, i, i+1)
+			s.groups = slices.Delete(s.groups, i, i+1)
+			found = true
+		}
+	}
+	return s, found
+}
+
+// Merge adds the wrappers of other to the stack in order. Wrappers whose ID
+// is not yet in the stack are added like AddWrapper, or like AddGroup if
+// they belong to a group of other; group membership is only known for
+// other stacks that are a *DefaultStack. Wrappers whose ID is already in
+// the stack are handled according to policy. With MergeError the stack is
+// left unchanged when an ID is duplicated.
+//
+// Example composing an organization-wide base stack with service-specific
+// wrappers:
+//
+//	stack := base.Clone()
+//	if _, err := stack.Merge(service, endpoint.MergeReplace); err != nil {
+//		return err
+//	}
+//
+// Parameters:
+//   - other: The stack to merge.
+//   - policy: The policy for duplicate wrapper IDs.
+//
+// Returns:
+//   - Stack: The updated middleware stack.
+//   - error: An error wrapping ErrDuplicateWrapper with MergeError.
+func (s *DefaultStack) Merge(other Stack, policy MergePolicy) (Stack, error) {
+	wrappers, groups := stackEntries(other)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if policy == MergeError {
+		seen := map[string]bool{}
+		for _, w := range s.wrappers {
+			seen[w.ID()] = true
+		}
+		for _, w := range wrappers {
+			if seen[w.ID()] {
+				return s, fmt.Errorf("merge stack: %w: %q", ErrDuplicateWrapper, w.ID())
+			}
+			seen[w.ID()] = true
+		}
+	}
+	for i, w := range wrappers {
+		idx := slices.IndexFunc(s.wrappers, func(existing Wrapper) bool {
+			return existing.ID() == w.ID()
+		})
+		switch {
+		case idx < 0:
+			s.addToGroup(groups[i], w)
+		case policy == MergeReplace:
+			s.wrappers[idx] = w
+		}
+	}
+	return s, nil
+}
+
+// insert inserts a wrapper of a group at position i. The caller must hold
+// the write lock.
+func (s *DefaultStack) insert(i int, w Wrapper, group string) {
+	s.syncGroups()
+	s.wrappers = slices.Insert(s.wrappers, i, w)
+	s.groups = slices.Insert(s.groups, i, group)
+}
+
+// syncGroups pads the group names of stacks not created by NewStack. The
+// caller must hold the write lock.
+func (s *DefaultStack) syncGroups() {
+	if n := len(s.wrappers) - len(s.groups); n > 0 {
+		s.groups = append(s.groups, make([]string, n)...)
+	}
+}
+
+// addToGroup inserts a wrapper after the last wrapper of its group, or
+// appends it. The caller must hold the write lock.
+func (s *DefaultStack) addToGroup(group string, w Wrapper) {
+	pos := len(s.wrappers)
+	for i := len(s.groups) - 1; i >= 0 && group != ""; i-- {
+		if s.groups[i] == group {
+			pos = i + 1
+			break
+		}
+	}
+	s.insert(pos, w, group)
+}
+
+// stackEntries returns a snapshot of the wrappers of a stack and their
+// group names.
+func stackEntries(stack Stack) ([]Wrapper, []string) {
+	if ds, ok := stack.(*DefaultStack); ok {
+		ds.mu.RLock()
+		defer ds.mu.RUnlock()
+		groups := make([]string, len(ds.wrappers))
+		copy(groups, ds.groups)
+		return slices.Clone(ds.wrappers), groups
+	}
+	wrappers := stack.Wrappers()
+	return wrappers, make([]string, len(wrappers))
+}
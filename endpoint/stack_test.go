@@ -177,3 +177,72 @@ func (s *StackTestSuite) TestRemove() {
 	// The stack remains unchanged.
 	s.Require().Len(updated.Wrappers(), 2)
 }
+
+// ids returns the IDs of wrappers.
+func ids(wrappers []Wrapper) []string {
+	out := make([]string, len(wrappers))
+	for i, w := range wrappers {
+		out[i] = w.ID()
+	}
+	return out
+}
+
+// TestGroups verifies that grouped wrappers stay together and can be
+// listed and removed by group.
+func (s *StackTestSuite) TestGroups() {
+	stack := NewStack(NewWrapper("w1", noopMiddleware))
+	stack.AddGroup("base",
+		NewWrapper("log", noopMiddleware), NewWrapper("trace", noopMiddleware),
+	)
+	stack.AddWrapper(NewWrapper("w2", noopMiddleware))
+	stack.AddGroup("base", NewWrapper("recover", noopMiddleware))
+
+	s.Equal([]string{"w1", "log", "trace", "recover", "w2"}, ids(stack.Wrappers()))
+	s.Equal([]string{"base"}, stack.Groups())
+	s.Equal([]string{"log", "trace", "recover"}, ids(stack.Group("base")))
+	s.Nil(stack.Group(""))
+
+	// Removing a single wrapper keeps the group names aligned.
+	_, found := stack.Remove("w1")
+	s.True(found)
+	s.Equal([]string{"log", "trace", "recover"}, ids(stack.Group("base")))
+
+	_, found = stack.RemoveGroup("base")
+	s.True(found)
+	s.Equal([]string{"w2"}, ids(stack.Wrappers()))
+	_, found = stack.RemoveGroup("base")
+	s.False(found)
+}
+
+// TestMerge verifies the merge policies for duplicate wrapper IDs.
+func (s *StackTestSuite) TestMerge() {
+	newBase := func() *DefaultStack {
+		base := NewStack()
+		base.AddGroup("base",
+			NewWrapper("log", noopMiddleware).WithData("base"),
+			NewWrapper("auth", noopMiddleware).WithData("base"),
+		)
+		base.AddWrapper(NewWrapper("tail", noopMiddleware))
+		return base
+	}
+	other := NewStack(NewWrapper("auth", noopMiddleware).WithData("service"))
+	other.AddGroup("base", NewWrapper("metrics", noopMiddleware))
+
+	stack := newBase()
+	_, err := stack.Merge(other, MergeSkip)
+	s.Require().NoError(err)
+	s.Equal([]string{"log", "auth", "metrics", "tail"}, ids(stack.Wrappers()))
+	s.Equal("base", stack.Wrappers()[1].Data())
+	s.Equal([]string{"log", "auth", "metrics"}, ids(stack.Group("base")))
+
+	stack = newBase()
+	_, err = stack.Merge(other, MergeReplace)
+	s.Require().NoError(err)
+	s.Equal([]string{"log", "auth", "metrics", "tail"}, ids(stack.Wrappers()))
+	s.Equal("service", stack.Wrappers()[1].Data())
+
+	stack = newBase()
+	_, err = stack.Merge(other, MergeError)
+	s.ErrorIs(err, ErrDuplicateWrapper)
+	s.Equal([]string{"log", "auth", "tail"}, ids(stack.Wrappers()))
+}
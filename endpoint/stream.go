@@ -0,0 +1,117 @@
+package endpoint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// Stream is an output streaming raw bytes, such as a file download.
+type Stream struct {
+	Reader      io.Reader // The content. Closed after writing if an io.Closer.
+	ContentType string    // Defaults to a type derived from Name or the content.
+	Name        string    // File name, used for the type and attachments.
+	ModTime     time.Time // Optional modification time for conditional requests.
+	Attachment  bool      // Asks clients to download rather than display.
+}
+
+// StreamOutput returns an output handler writing Stream outputs. A Stream,
+// *Stream, io.Reader or []byte output is copied to the response.
+//
+// When the content is an io.ReadSeeker and the status is 200, Range,
+// If-Range and conditional request headers are honored like
+// http.ServeContent does: single and multiple byte ranges are answered with
+// 206, unsatisfiable ones with 416, enabling resumable downloads and media
+// scrubbing. Other content is streamed whole with the given status. API
+// errors are written as apierror JSON.
+//
+// Returns:
+//   - OutputHandler: The stream output handler.
+func StreamOutput() OutputHandler {
+	return streamOutput{}
+}
+
+// streamOutput writes Stream outputs.
+type streamOutput struct{}
+
+// Handle streams the output or writes the error.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//   - out: The output value.
+//   - outputError: The error to render instead of out, if any.
+//   - statusCode: The response status code.
+//
+// Returns:
+//   - error: An error if the output is not a stream or writing fails.
+func (streamOutput) Handle(
+	w http.ResponseWriter,
+	r *http.Request,
+	out any,
+	outputError error,
+	statusCode int,
+) error {
+	if outputError != nil {
+		var apiErr apierror.APIError
+		if !errors.As(outputError, &apiErr) {
+			apiErr = apierror.NewAPIError("internal_error").
+				WithMessage("Internal server error")
+		}
+		writeAPIError(w, r, statusCode, apiErr)
+		return nil
+	}
+	s, err := asStream(out)
+	if err != nil {
+		return err
+	}
+	if c, ok := s.Reader.(io.Closer); ok {
+		defer c.Close()
+	}
+	h := w.Header()
+	if s.ContentType != "" {
+		h.Set("Content-Type", s.ContentType)
+	}
+	if s.Attachment {
+		params := map[string]string{}
+		if s.Name != "" {
+			params["filename"] = s.Name
+		}
+		h.Set("Content-Disposition", mime.FormatMediaType("attachment", params))
+	}
+	if rs, ok := s.Reader.(io.ReadSeeker); ok && statusCode == http.StatusOK {
+		http.ServeContent(w, r, s.Name, s.ModTime, rs)
+		return nil
+	}
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", "application/octet-stream")
+	}
+	w.WriteHeader(statusCode)
+	_, err = io.Copy(w, s.Reader)
+	return err
+}
+
+// asStream converts an output value to a Stream.
+func asStream(out any) (Stream, error) {
+	switch v := out.(type) {
+	case Stream:
+		if v.Reader != nil {
+			return v, nil
+		}
+	case *Stream:
+		if v != nil && v.Reader != nil {
+			return *v, nil
+		}
+	case io.Reader:
+		return Stream{Reader: v}, nil
+	case []byte:
+		return Stream{Reader: bytes.NewReader(v)}, nil
+	}
+	return Stream{}, fmt.Errorf("stream output: unsupported output %T", out)
+}
@@ -0,0 +1,122 @@
+package endpoint
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamOutput(t *testing.T) {
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	testCases := []struct {
+		name       string
+		out        func() any
+		outErr     error
+		header     http.Header
+		wantStatus int
+		wantBody   string
+		wantHeader http.Header
+	}{
+		{
+			name: "Whole file",
+			out: func() any {
+				return &Stream{Reader: strings.NewReader("0123456789"), Name: "data.txt"}
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "0123456789",
+			wantHeader: http.Header{
+				"Accept-Ranges": {"bytes"},
+				"Content-Type":  {"text/plain; charset=utf-8"},
+			},
+		},
+		{
+			name: "Range",
+			out: func() any {
+				return Stream{
+					Reader: bytes.NewReader([]byte("0123456789")), Name: "movie.mp4",
+					Attachment: true,
+				}
+			},
+			header:     http.Header{"Range": {"bytes=2-5"}},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "2345",
+			wantHeader: http.Header{
+				"Content-Range":       {"bytes 2-5/10"},
+				"Content-Type":        {"video/mp4"},
+				"Content-Disposition": {`attachment; filename=movie.mp4`},
+			},
+		},
+		{
+			name:       "Unsatisfiable range",
+			out:        func() any { return []byte("0123") },
+			header:     http.Header{"Range": {"bytes=10-"}},
+			wantStatus: http.StatusRequestedRangeNotSatisfiable,
+			wantHeader: http.Header{"Content-Range": {"bytes */4"}},
+		},
+		{
+			name: "Stale If-Range",
+			out: func() any {
+				return Stream{Reader: strings.NewReader("0123456789"), ModTime: modTime}
+			},
+			header: http.Header{
+				"Range":    {"bytes=0-1"},
+				"If-Range": {modTime.Add(-time.Hour).Format(http.TimeFormat)},
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "0123456789",
+		},
+		{
+			name: "Not seekable",
+			out: func() any {
+				return Stream{Reader: io.MultiReader(strings.NewReader("abc"))}
+			},
+			header:     http.Header{"Range": {"bytes=0-0"}},
+			wantStatus: http.StatusOK,
+			wantBody:   "abc",
+			wantHeader: http.Header{"Content-Type": {"application/octet-stream"}},
+		},
+		{
+			name:       "Error",
+			out:        func() any { return nil },
+			outErr:     errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"id":"internal_error","message":"Internal server error"}` + "\n",
+		},
+		{
+			name:       "Unsupported output",
+			out:        func() any { return 42 },
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHandler(
+				&dummyInputHandler{},
+				func(_ http.ResponseWriter, _ *http.Request, _ *string) (any, error) {
+					return tc.out(), tc.outErr
+				},
+				DefaultErrorHandler{}, StreamOutput(),
+			)
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tc.header {
+				r.Header[k] = v
+			}
+			rec := httptest.NewRecorder()
+			h.Handle(rec, r)
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			if tc.wantBody != "" {
+				assert.Equal(t, tc.wantBody, rec.Body.String())
+			}
+			for k, v := range tc.wantHeader {
+				assert.Equal(t, v, rec.Header()[k], k)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package endpoint
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// timeoutWriteGrace is the time granted after a route deadline for
+// finishing the response.
+const timeoutWriteGrace = 5 * time.Second
+
+// WithTimeout returns a middleware giving a route its own deadline. The
+// request context is canceled after d, and if the handler has not started
+// its response by then, a 504 deadline_exceeded API error is written, even
+// if the handler ignores its context or returns without writing. Writes
+// after the deadline fail with http.ErrHandlerTimeout; a response already
+// started is cut off. A handler panicking in time panics the middleware;
+// one panicking after the deadline is logged with its stack.
+//
+// The connection's read and write deadlines are moved to match, so the
+// timeout may be stricter or looser than the server's ReadTimeout and
+// WriteTimeout. A deadline already on the request context still applies
+// when it is earlier.
+//
+// Parameters:
+//   - d: The route timeout.
+//
+// Returns:
+//   - Middleware: The timeout middleware.
+func WithTimeout(d time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			deadline := time.Now().Add(d)
+			rc := http.NewResponseController(w)
+			_ = rc.SetReadDeadline(deadline)
+			_ = rc.SetWriteDeadline(deadline.Add(timeoutWriteGrace))
+
+			ctx, cancel := context.WithDeadline(r.Context(), deadline)
+			defer cancel()
+			tw := &timeoutWriter{w: w, r: r, header: http.Header{}}
+			done := make(chan struct{})
+			panicked := make(chan handlerPanic, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- handlerPanic{value: p, stack: debug.Stack()}
+					}
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+			select {
+			case <-done:
+			case p := <-panicked:
+				panic(p.value)
+			case <-ctx.Done():
+			}
+			if err := ctx.Err(); err != nil {
+				tw.timeout(err)
+				go logLatePanic(r, done, panicked)
+				return
+			}
+			tw.finish()
+		})
+	}
+}
+
+// handlerPanic is a panic recovered from a handler goroutine.
+type handlerPanic struct {
+	value any
+	stack []byte
+}
+
+// logLatePanic waits for a handler that outlived its deadline and logs its
+// panic, if any, with the stack. The panic cannot be re-raised since the
+// request has already been answered.
+func logLatePanic(
+	r *http.Request, done <-chan struct{}, panicked <-chan handlerPanic,
+) {
+	select {
+	case <-done:
+	case p := <-panicked:
+		log.Printf("endpoint: panic after timeout serving %s %s: %v\n%s",
+			r.Method, r.URL.Path, p.value, p.stack)
+	}
+}
+
+// timeoutWriter guards a response writer shared with a handler that may
+// outlive its deadline. The handler writes headers to its own map, which is
+// copied when the response starts.
+type timeoutWriter struct {
+	mu       sync.Mutex
+	w        http.ResponseWriter
+	r        *http.Request
+	header   http.Header
+	wrote    bool
+	timedOut bool
+}
+
+// Header returns the handler's header map.
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+// WriteHeader starts the response unless the deadline passed.
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.writeHeader(code)
+}
+
+// Write writes the data unless the deadline passed.
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.writeHeader(http.StatusOK)
+	return tw.w.Write(p)
+}
+
+// Flush flushes the response unless the deadline passed.
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	tw.writeHeader(http.StatusOK)
+	if f, ok := tw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// writeHeader copies the headers and writes the status once. The caller
+// must hold the lock.
+func (tw *timeoutWriter) writeHeader(code int) {
+	if tw.timedOut || tw.wrote {
+		return
+	}
+	if code >= 200 {
+		tw.wrote = true
+	}
+	dst := tw.w.Header()
+	for k, v := range tw.header {
+		dst[k] = append([]string(nil), v...)
+	}
+	tw.w.WriteHeader(code)
+}
+
+// finish writes the headers and the implicit 200 of a handler that
+// returned in time without starting its response.
+func (tw *timeoutWriter) finish() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.writeHeader(http.StatusOK)
+}
+
+// timeout stops further writes and writes the context error, 504 for a
+// deadline, if the response has not started.
+func (tw *timeoutWriter) timeout(err error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.wrote {
+		status, apiErr := defaultErrorRegistry.Handle(err)
+		writeAPIError(tw.w, tw.r, status, apiErr)
+	}
+	tw.timedOut = true
+}
@@ -0,0 +1,122 @@
+package endpoint
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTimeout(t *testing.T) {
+	testCases := []struct {
+		name   string
+		logic  func(w http.ResponseWriter, r *http.Request)
+		status int
+		body   string
+	}{
+		{
+			name: "In time",
+			logic: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("X-Test", "1")
+				_, _ = w.Write([]byte("ok"))
+			},
+			status: http.StatusOK,
+			body:   "ok",
+		},
+		{
+			name: "Headers without a body",
+			logic: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("X-Test", "1")
+			},
+			status: http.StatusOK,
+			body:   "",
+		},
+		{
+			name: "Context aware handler",
+			logic: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			},
+			status: http.StatusGatewayTimeout,
+			body:   `{"id":"deadline_exceeded","message":"Request deadline exceeded"}`,
+		},
+		{
+			name: "Handler ignoring its context",
+			logic: func(w http.ResponseWriter, _ *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+				_, err := w.Write([]byte("late"))
+				assert.ErrorIs(t, err, http.ErrHandlerTimeout)
+			},
+			status: http.StatusGatewayTimeout,
+			body:   `{"id":"deadline_exceeded","message":"Request deadline exceeded"}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := WithTimeout(50 * time.Millisecond)(http.HandlerFunc(tc.logic))
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Equal(t, tc.status, rec.Code)
+			if tc.status == http.StatusOK {
+				assert.Equal(t, tc.body, rec.Body.String())
+				assert.Equal(t, "1", rec.Header().Get("X-Test"))
+			} else {
+				assert.JSONEq(t, tc.body, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWithTimeout_Panic(t *testing.T) {
+	h := WithTimeout(time.Second)(http.HandlerFunc(
+		func(http.ResponseWriter, *http.Request) { panic("boom") },
+	))
+	assert.PanicsWithValue(t, "boom", func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWithTimeout_PanicAfterTimeout(t *testing.T) {
+	var logged syncBuffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	h := WithTimeout(20 * time.Millisecond)(http.HandlerFunc(
+		func(_ http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+			time.Sleep(20 * time.Millisecond)
+			panic("late boom")
+		},
+	))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	assert.Eventually(t, func() bool {
+		return strings.Contains(logged.String(), "panic after timeout serving GET /slow: late boom")
+	}, time.Second, 5*time.Millisecond)
+	assert.Contains(t, logged.String(), "goroutine")
+}
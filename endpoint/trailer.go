@@ -0,0 +1,148 @@
+package endpoint
+
+import (
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Trailer computes an HTTP trailer sent after the response body. The body
+// is written to the trailer as it is sent, so trailers such as checksums
+// can observe it.
+type Trailer interface {
+	io.Writer
+	Name() string  // The trailer field name.
+	Value() string // The value, called once the body is written.
+}
+
+// TrailerFunc creates the trailer of one request.
+type TrailerFunc func() Trailer
+
+// WithTrailers adds trailers to every response of the handler and returns
+// a new handler instance. The trailers are created when a request starts
+// and announced in the Trailer header, so their values can be set after the
+// output handler wrote the body. Clients receive them with chunked HTTP/1.1
+// or HTTP/2 responses.
+//
+//	handler = handler.WithTrailers(
+//		endpoint.ChecksumTrailer("X-Checksum-SHA256", sha256.New),
+//		endpoint.TimingTrailer("Server-Timing"),
+//	)
+//
+// Parameters:
+//   - trailers: The trailer constructors.
+//
+// Returns:
+//   - *DefaultHandler[Input]: A new handler instance.
+func (h *DefaultHandler[Input]) WithTrailers(
+	trailers ...TrailerFunc,
+) *DefaultHandler[Input] {
+	new := *h
+	new.trailers = append(append([]TrailerFunc(nil), h.trailers...), trailers...)
+	return &new
+}
+
+// ChecksumTrailer returns a trailer whose value is the base64 encoded hash
+// of the response body.
+//
+// Parameters:
+//   - name: The trailer field name.
+//   - newHash: Creates the hash, e.g. sha256.New.
+//
+// Returns:
+//   - TrailerFunc: The trailer constructor.
+func ChecksumTrailer(name string, newHash func() hash.Hash) TrailerFunc {
+	return func() Trailer {
+		return &checksumTrailer{name: name, hash: newHash()}
+	}
+}
+
+// checksumTrailer hashes the response body.
+type checksumTrailer struct {
+	name string
+	hash hash.Hash
+}
+
+func (c *checksumTrailer) Write(p []byte) (int, error) { return c.hash.Write(p) }
+func (c *checksumTrailer) Name() string                { return c.name }
+func (c *checksumTrailer) Value() string {
+	return base64.StdEncoding.EncodeToString(c.hash.Sum(nil))
+}
+
+// TimingTrailer returns a trailer with the time from the start of the
+// request to the end of the body, in the Server-Timing format, e.g.
+// "total;dur=12.345" for 12.345 milliseconds.
+//
+// Parameters:
+//   - name: The trailer field name, usually "Server-Timing".
+//
+// Returns:
+//   - TrailerFunc: The trailer constructor.
+func TimingTrailer(name string) TrailerFunc {
+	return func() Trailer {
+		return &timingTrailer{name: name, start: time.Now()}
+	}
+}
+
+// timingTrailer measures the request duration.
+type timingTrailer struct {
+	name  string
+	start time.Time
+}
+
+func (t *timingTrailer) Write(p []byte) (int, error) { return len(p), nil }
+func (t *timingTrailer) Name() string                { return t.name }
+func (t *timingTrailer) Value() string {
+	ms := float64(time.Since(t.start).Microseconds()) / 1000
+	return fmt.Sprintf("total;dur=%.3f", ms)
+}
+
+// trailerWriter feeds the response body to trailers and sets their values
+// when the response is finished.
+type trailerWriter struct {
+	http.ResponseWriter
+	trailers []Trailer
+}
+
+// newTrailerWriter creates the trailers and announces them.
+func newTrailerWriter(
+	w http.ResponseWriter, funcs []TrailerFunc,
+) *trailerWriter {
+	tw := &trailerWriter{ResponseWriter: w}
+	for _, fn := range funcs {
+		t := fn()
+		tw.trailers = append(tw.trailers, t)
+		w.Header().Add("Trailer", t.Name())
+	}
+	return tw
+}
+
+// Write writes the data and feeds it to the trailers.
+func (tw *trailerWriter) Write(p []byte) (int, error) {
+	n, err := tw.ResponseWriter.Write(p)
+	for _, t := range tw.trailers {
+		_, _ = t.Write(p[:n])
+	}
+	return n, err
+}
+
+// Flush flushes the wrapped writer, if it supports flushing.
+func (tw *trailerWriter) Flush() {
+	_ = http.NewResponseController(tw.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (tw *trailerWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
+
+// finish sets the trailer values.
+func (tw *trailerWriter) finish() {
+	h := tw.ResponseWriter.Header()
+	for _, t := range tw.trailers {
+		h.Set(t.Name(), t.Value())
+	}
+}
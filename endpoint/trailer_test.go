@@ -0,0 +1,42 @@
+package endpoint
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultHandler_WithTrailers(t *testing.T) {
+	var flushed bool
+	h := NewHandler(
+		&dummyInputHandler{},
+		func(w http.ResponseWriter, _ *http.Request, _ *string) (any, error) {
+			_, flushed = w.(http.Flusher)
+			return "hello", nil
+		},
+		DefaultErrorHandler{}, JSONOutput(),
+	).WithTrailers(
+		ChecksumTrailer("X-Checksum", sha256.New),
+		TimingTrailer("Server-Timing"),
+	)
+	srv := httptest.NewServer(http.HandlerFunc(h.Handle))
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	assert.True(t, flushed, "the logic writer implements http.Flusher")
+	sum := sha256.Sum256(body)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(sum[:]), res.Trailer.Get("X-Checksum"))
+	assert.True(t, strings.HasPrefix(res.Trailer.Get("Server-Timing"), "total;dur="))
+}
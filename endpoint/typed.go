@@ -0,0 +1,286 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/aatuh/pureapi-core/event"
+)
+
+// TypedLogicFn is a handler logic function returning a typed output.
+type TypedLogicFn[Input, Output any] func(
+	w http.ResponseWriter, r *http.Request, i *Input,
+) (Output, error)
+
+// TypedOutputHandler writes typed endpoint responses. On errors out is the
+// zero value and outputError is set.
+type TypedOutputHandler[Output any] interface {
+	Handle(
+		w http.ResponseWriter,
+		r *http.Request,
+		out Output,
+		outputError error,
+		statusCode int,
+	) error
+}
+
+// TypedOutput adapts an OutputHandler, such as JSONOutput or
+// NegotiatingOutput, to a typed output handler. For a *Response or Response
+// output the body is passed on; its status, headers and cookies are applied
+// by the handler.
+//
+// Parameters:
+//   - outputHandler: The output handler to adapt.
+//
+// Returns:
+//   - TypedOutputHandler[Output]: The typed output handler.
+func TypedOutput[Output any](
+	outputHandler OutputHandler,
+) TypedOutputHandler[Output] {
+	return typedOutput[Output]{outputHandler: outputHandler}
+}
+
+// typedOutput passes typed outputs to an untyped output handler.
+type typedOutput[Output any] struct {
+	outputHandler OutputHandler
+}
+
+// Handle writes the output with the wrapped handler.
+func (o typedOutput[Output]) Handle(
+	w http.ResponseWriter,
+	r *http.Request,
+	out Output,
+	outputError error,
+	statusCode int,
+) error {
+	var v any
+	if outputError == nil {
+		v = out
+		if resp, ok := asResponse(v); ok {
+			v = resp.Body
+		}
+	}
+	return o.outputHandler.Handle(w, r, v, outputError, statusCode)
+}
+
+// outputAdapter passes the outputs of a typed pipeline to a typed output
+// handler.
+type outputAdapter[Output any] struct {
+	outputHandler TypedOutputHandler[Output]
+}
+
+// Handle writes the output with the typed handler. It returns an error if
+// out is not an Output.
+func (o outputAdapter[Output]) Handle(
+	w http.ResponseWriter,
+	r *http.Request,
+	out any,
+	outputError error,
+	statusCode int,
+) error {
+	typed, ok := asOutput[Output](out)
+	if !ok {
+		return fmt.Errorf("output of type %T is not %T", out, typed)
+	}
+	return o.outputHandler.Handle(w, r, typed, outputError, statusCode)
+}
+
+// asOutput converts a pipeline output to Output. DefaultHandler passes the
+// body of a returned Response on, so for a Response Output the body is
+// wrapped again; its status, headers and cookies are already applied.
+func asOutput[Output any](out any) (Output, bool) {
+	var typed Output
+	if out == nil {
+		return typed, true
+	}
+	if typed, ok := out.(Output); ok {
+		return typed, true
+	}
+	var v any
+	switch any(typed).(type) {
+	case *Response:
+		v = NewResponse(out)
+	case Response:
+		v = Response{Body: out}
+	default:
+		return typed, false
+	}
+	return v.(Output), true
+}
+
+// TypedHandler is an endpoint pipeline whose logic returns a typed output,
+// so mismatches between logic and output handler fail to compile. It runs
+// the same steps as DefaultHandler.
+type TypedHandler[Input, Output any] struct {
+	handler *DefaultHandler[Input]
+}
+
+var _ Handler[any] = (*TypedHandler[any, any])(nil)
+
+// NewTypedHandler creates a new typed handler.
+//
+// Parameters:
+//   - inputHandler: The input handler for processing request input.
+//   - logicFn: The handler logic function for business logic.
+//   - errorHandler: The error handler for mapping errors to API responses.
+//   - outputHandler: The typed output handler for writing responses.
+//
+// Returns:
+//   - *TypedHandler[Input, Output]: A new TypedHandler instance.
+func NewTypedHandler[Input, Output any](
+	inputHandler InputHandler[Input],
+	logicFn TypedLogicFn[Input, Output],
+	errorHandler ErrorHandler,
+	outputHandler TypedOutputHandler[Output],
+) *TypedHandler[Input, Output] {
+	return &TypedHandler[Input, Output]{
+		handler: NewHandler(
+			inputHandler,
+			func(w http.ResponseWriter, r *http.Request, i *Input) (any, error) {
+				return logicFn(w, r, i)
+			},
+			errorHandler,
+			outputAdapter[Output]{outputHandler: outputHandler},
+		),
+	}
+}
+
+// WithEmitterLogger adds an emitter logger to the handler and returns a new
+// handler instance.
+//
+// Parameters:
+//   - emitterLogger: The emitter logger to set.
+//
+// Returns:
+//   - *TypedHandler[Input, Output]: A new handler instance.
+func (h *TypedHandler[Input, Output]) WithEmitterLogger(
+	emitterLogger event.EventEmitter,
+) *TypedHandler[Input, Output] {
+	return &TypedHandler[Input, Output]{
+		handler: h.handler.WithEmitterLogger(emitterLogger),
+	}
+}
+
+// WithSuccessStatus sets the status code of successful responses, like
+// DefaultHandler.WithSuccessStatus, and returns a new handler instance.
+//
+// Parameters:
+//   - status: The success status code.
+//
+// Returns:
+//   - *TypedHandler[Input, Output]: A new handler instance.
+func (h *TypedHandler[Input, Output]) WithSuccessStatus(
+	status int,
+) *TypedHandler[Input, Output] {
+	return &TypedHandler[Input, Output]{
+		handler: h.handler.WithSuccessStatus(status),
+	}
+}
+
+// WithErrorRenderer sets the renderer of the handler's error bodies, like
+// DefaultHandler.WithErrorRenderer, and returns a new handler instance.
+//
+// Parameters:
+//   - renderer: The error renderer.
+//
+// Returns:
+//   - *TypedHandler[Input, Output]: A new handler instance.
+func (h *TypedHandler[Input, Output]) WithErrorRenderer(
+	renderer ErrorRenderer,
+) *TypedHandler[Input, Output] {
+	return &TypedHandler[Input, Output]{
+		handler: h.handler.WithErrorRenderer(renderer),
+	}
+}
+
+// WithBodyReplay buffers request bodies of up to maxBytes for replay, like
+// DefaultHandler.WithBodyReplay, and returns a new handler instance.
+//
+// Parameters:
+//   - maxBytes: The maximum body size in bytes.
+//
+// Returns:
+//   - *TypedHandler[Input, Output]: A new handler instance.
+func (h *TypedHandler[Input, Output]) WithBodyReplay(
+	maxBytes int64,
+) *TypedHandler[Input, Output] {
+	return &TypedHandler[Input, Output]{
+		handler: h.handler.WithBodyReplay(maxBytes),
+	}
+}
+
+// WithBeforeLogic adds hooks running before the logic, like
+// DefaultHandler.WithBeforeLogic, and returns a new handler instance.
+//
+// Parameters:
+//   - hooks: The hooks to add.
+//
+// Returns:
+//   - *TypedHandler[Input, Output]: A new handler instance.
+func (h *TypedHandler[Input, Output]) WithBeforeLogic(
+	hooks ...BeforeLogicHook[Input],
+) *TypedHandler[Input, Output] {
+	return &TypedHandler[Input, Output]{
+		handler: h.handler.WithBeforeLogic(hooks...),
+	}
+}
+
+// WithAfterLogic adds hooks running after the logic, like
+// DefaultHandler.WithAfterLogic, and returns a new handler instance. The
+// output passed to the hooks is an Output, and replacements must be too.
+//
+// Parameters:
+//   - hooks: The hooks to add.
+//
+// Returns:
+//   - *TypedHandler[Input, Output]: A new handler instance.
+func (h *TypedHandler[Input, Output]) WithAfterLogic(
+	hooks ...AfterLogicHook[Input],
+) *TypedHandler[Input, Output] {
+	return &TypedHandler[Input, Output]{
+		handler: h.handler.WithAfterLogic(hooks...),
+	}
+}
+
+// WithOnError adds hooks observing the pipeline errors, like
+// DefaultHandler.WithOnError, and returns a new handler instance.
+//
+// Parameters:
+//   - hooks: The hooks to add.
+//
+// Returns:
+//   - *TypedHandler[Input, Output]: A new handler instance.
+func (h *TypedHandler[Input, Output]) WithOnError(
+	hooks ...ErrorHook,
+) *TypedHandler[Input, Output] {
+	return &TypedHandler[Input, Output]{
+		handler: h.handler.WithOnError(hooks...),
+	}
+}
+
+// WithTrailers adds response trailers, like DefaultHandler.WithTrailers,
+// and returns a new handler instance.
+//
+// Parameters:
+//   - trailers: The trailer constructors.
+//
+// Returns:
+//   - *TypedHandler[Input, Output]: A new handler instance.
+func (h *TypedHandler[Input, Output]) WithTrailers(
+	trailers ...TrailerFunc,
+) *TypedHandler[Input, Output] {
+	return &TypedHandler[Input, Output]{
+		handler: h.handler.WithTrailers(trailers...),
+	}
+}
+
+// Handle executes the pipeline.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+func (h *TypedHandler[Input, Output]) Handle(
+	w http.ResponseWriter, r *http.Request,
+) {
+	h.handler.Handle(w, r)
+}
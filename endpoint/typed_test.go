@@ -0,0 +1,93 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typedTestOutput struct {
+	Greeting string `json:"greeting"`
+}
+
+// recordingTypedOutput records the typed output it receives.
+type recordingTypedOutput struct {
+	out    typedTestOutput
+	err    error
+	status int
+}
+
+func (o *recordingTypedOutput) Handle(
+	w http.ResponseWriter, _ *http.Request,
+	out typedTestOutput, outputError error, statusCode int,
+) error {
+	o.out, o.err, o.status = out, outputError, statusCode
+	w.WriteHeader(statusCode)
+	return nil
+}
+
+func TestTypedHandler(t *testing.T) {
+	logic := func(_ http.ResponseWriter, _ *http.Request, in *jsonTestInput) (typedTestOutput, error) {
+		if in.Name == "" {
+			return typedTestOutput{}, invalidInput("name is required")
+		}
+		return typedTestOutput{Greeting: "hello " + in.Name}, nil
+	}
+
+	out := &recordingTypedOutput{}
+	h := NewTypedHandler(JSONInput[jsonTestInput](), logic, DefaultErrorHandler{}, out)
+	h.Handle(httptest.NewRecorder(), jsonRequest(`{"name":"Go"}`))
+	assert.Equal(t, typedTestOutput{Greeting: "hello Go"}, out.out)
+	assert.Equal(t, http.StatusOK, out.status)
+
+	h.Handle(httptest.NewRecorder(), jsonRequest(`{}`))
+	assert.Equal(t, typedTestOutput{}, out.out)
+	assert.Equal(t, http.StatusBadRequest, out.status)
+	assert.Error(t, out.err)
+}
+
+func TestTypedOutput(t *testing.T) {
+	logic := func(_ http.ResponseWriter, _ *http.Request, in *jsonTestInput) (*typedTestOutput, error) {
+		if in.Age < 0 {
+			return nil, errors.New("boom")
+		}
+		return &typedTestOutput{Greeting: "hi"}, nil
+	}
+	h := NewTypedHandler(JSONInput[jsonTestInput](), logic, DefaultErrorHandler{},
+		TypedOutput[*typedTestOutput](JSONOutput()))
+
+	rec := httptest.NewRecorder()
+	h.Handle(rec, jsonRequest(`{"name":"Go"}`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"greeting":"hi"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	h.Handle(rec, jsonRequest(`{"age":-1}`))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"id":"internal_error","message":"Internal server error"}`, rec.Body.String())
+}
+
+func TestTypedOutput_Response(t *testing.T) {
+	logic := func(_ http.ResponseWriter, _ *http.Request, in *jsonTestInput) (*Response, error) {
+		return NewResponse(typedTestOutput{Greeting: "hi " + in.Name}).
+			WithStatus(http.StatusCreated).
+			WithHeader("Location", "/greetings/1"), nil
+	}
+	h := NewTypedHandler(JSONInput[jsonTestInput](), logic, DefaultErrorHandler{},
+		TypedOutput[*Response](JSONOutput()))
+
+	rec := httptest.NewRecorder()
+	h.Handle(rec, jsonRequest(`{"name":"Go"}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/greetings/1", rec.Header().Get("Location"))
+	assert.JSONEq(t, `{"greeting":"hi Go"}`, rec.Body.String())
+}
+
+func TestOutputAdapter_TypeMismatch(t *testing.T) {
+	adapter := outputAdapter[typedTestOutput]{outputHandler: &recordingTypedOutput{}}
+	err := adapter.Handle(httptest.NewRecorder(), jsonRequest(`{}`), 42, nil, http.StatusOK)
+	assert.EqualError(t, err, "output of type int is not endpoint.typedTestOutput")
+}
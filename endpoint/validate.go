@@ -0,0 +1,109 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// ErrIDValidation is the ID of errors returned by input validation.
+const ErrIDValidation = "validation_error"
+
+// Validator is implemented by inputs that validate themselves. The default
+// handler calls Validate after decoding the input.
+type Validator interface {
+	Validate() error
+}
+
+// ContextValidator is implemented by inputs whose validation needs the
+// request context, e.g. to query a database.
+type ContextValidator interface {
+	Validate(ctx context.Context) error
+}
+
+// ValidationError is passed to the ErrorHandler when an input fails
+// validation. Field errors joined into the validation error are exposed as
+// its data, which DefaultErrorHandler maps to 400.
+type ValidationError struct {
+	Err    error        // Error returned by Validate.
+	Fields []FieldError // Field errors found in Err.
+}
+
+var _ apierror.APIError = (*ValidationError)(nil)
+
+// Error returns the validation error message.
+func (e *ValidationError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the error returned by Validate.
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// ID returns the validation_error ID.
+func (e *ValidationError) ID() string { return ErrIDValidation }
+
+// Data returns the field errors, or nil if there are none.
+func (e *ValidationError) Data() any {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e.Fields
+}
+
+// Message returns the validation error message. Messages of field errors
+// are left to Data.
+func (e *ValidationError) Message() string {
+	if len(e.Fields) > 0 {
+		return "invalid input fields"
+	}
+	return e.Err.Error()
+}
+
+// Origin returns an empty origin.
+func (e *ValidationError) Origin() string { return "" }
+
+// Error returns the field error message so field errors can be returned
+// from Validate, alone or combined with errors.Join.
+func (e FieldError) Error() string { return e.Field + ": " + e.Message }
+
+// validateInput runs the input's validation, if any. API errors returned
+// by Validate are passed on unchanged, other errors are wrapped in a
+// ValidationError.
+func validateInput(ctx context.Context, in any) error {
+	var err error
+	switch v := in.(type) {
+	case ContextValidator:
+		err = v.Validate(ctx)
+	case Validator:
+		err = v.Validate()
+	default:
+		return nil
+	}
+	if err == nil {
+		return nil
+	}
+	var apiErr apierror.APIError
+	if errors.As(err, &apiErr) {
+		return err
+	}
+	return &ValidationError{Err: err, Fields: collectFieldErrors(err)}
+}
+
+// collectFieldErrors returns the field errors in an error tree.
+func collectFieldErrors(err error) []FieldError {
+	switch e := err.(type) {
+	case FieldError:
+		return []FieldError{e}
+	case *FieldError:
+		return []FieldError{*e}
+	case interface{ Unwrap() []error }:
+		var out []FieldError
+		for _, inner := range e.Unwrap() {
+			out = append(out, collectFieldErrors(inner)...)
+		}
+		return out
+	}
+	if inner := errors.Unwrap(err); inner != nil {
+		return collectFieldErrors(inner)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package endpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validatedInput struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func (v *validatedInput) Validate() error {
+	var errs []error
+	if v.Name == "" {
+		errs = append(errs, FieldError{Field: "name", Message: "is required"})
+	}
+	if v.Age < 0 {
+		errs = append(errs, FieldError{Field: "age", Message: "must not be negative"})
+	}
+	return errors.Join(errs...)
+}
+
+type ctxKey struct{}
+
+type ctxValidatedInput struct {
+	Name string `json:"name"`
+}
+
+func (v ctxValidatedInput) Validate(ctx context.Context) error {
+	if taken, _ := ctx.Value(ctxKey{}).(string); taken == v.Name {
+		return errors.New("name is taken")
+	}
+	return nil
+}
+
+type apiValidatedInput struct{}
+
+func (apiValidatedInput) Validate() error {
+	return apierror.NewAPIError("conflict").WithMessage("duplicate")
+}
+
+func serveValidated[In any](t *testing.T, r *http.Request) (*httptest.ResponseRecorder, bool) {
+	t.Helper()
+	called := false
+	h := NewHandler(JSONInput[In](),
+		func(_ http.ResponseWriter, _ *http.Request, _ *In) (any, error) {
+			called = true
+			return "ok", nil
+		},
+		DefaultErrorHandler{}, JSONOutput(),
+	)
+	rec := httptest.NewRecorder()
+	h.Handle(rec, r)
+	return rec, called
+}
+
+func jsonRequest(body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	return r
+}
+
+func TestValidate_FieldErrors(t *testing.T) {
+	rec, called := serveValidated[validatedInput](t, jsonRequest(`{"age":-1}`))
+	assert.False(t, called)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var body struct {
+		ID      string       `json:"id"`
+		Message string       `json:"message"`
+		Data    []FieldError `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, ErrIDValidation, body.ID)
+	assert.Equal(t, []FieldError{
+		{Field: "name", Message: "is required"},
+		{Field: "age", Message: "must not be negative"},
+	}, body.Data)
+
+	rec, called = serveValidated[validatedInput](t, jsonRequest(`{"name":"Go"}`))
+	assert.True(t, called)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestValidate_Context(t *testing.T) {
+	r := jsonRequest(`{"name":"Go"}`)
+	r = r.WithContext(context.WithValue(r.Context(), ctxKey{}, "Go"))
+	rec, called := serveValidated[ctxValidatedInput](t, r)
+	assert.False(t, called)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"message":"name is taken"`)
+}
+
+func TestValidate_APIErrorPassedOn(t *testing.T) {
+	rec, called := serveValidated[apiValidatedInput](t, jsonRequest(`{}`))
+	assert.False(t, called)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestValidationError_Unwrap(t *testing.T) {
+	cause := errors.New("bad")
+	err := validateInput(context.Background(), &validatedInput{Name: "x", Age: -1})
+	var vErr *ValidationError
+	require.ErrorAs(t, err, &vErr)
+	assert.Len(t, vErr.Fields, 1)
+
+	vErr = &ValidationError{Err: cause}
+	assert.ErrorIs(t, vErr, cause)
+	assert.Nil(t, vErr.Data())
+	assert.Equal(t, "bad", vErr.Message())
+}
+
+func TestValidationError_Parse(t *testing.T) {
+	err := &ValidationError{
+		Err:    errors.New("invalid"),
+		Fields: []FieldError{{Field: "name", Message: "is required"}},
+	}
+	data, jerr := json.Marshal(apierror.APIErrorFrom(err))
+	require.NoError(t, jerr)
+
+	parsed, perr := apierror.ParseAPIError(data)
+	require.NoError(t, perr)
+	assert.Equal(t, ErrIDValidation, parsed.ID())
+	assert.Equal(t, err.Fields, parsed.Data())
+}
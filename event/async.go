@@ -0,0 +1,218 @@
+package event
+
+import "context"
+
+// AsyncOption configures an AsyncEmitter.
+type AsyncOption func(*asyncConfig)
+
+// asyncConfig holds the async emitter settings.
+type asyncConfig struct {
+	size         int
+	backpressure Backpressure
+}
+
+// WithQueueSize sets how many events may wait for delivery, 1024 by
+// default.
+//
+// Parameters:
+//   - size: The queue size.
+//
+// Returns:
+//   - AsyncOption: The option.
+func WithQueueSize(size int) AsyncOption {
+	return func(c *asyncConfig) { c.size = size }
+}
+
+// WithBackpressure sets the policy for events emitted while the queue is
+// full, DropNewest by default.
+//
+// Parameters:
+//   - policy: The backpressure policy.
+//
+// Returns:
+//   - AsyncOption: The option.
+func WithBackpressure(policy Backpressure) AsyncOption {
+	return func(c *asyncConfig) { c.backpressure = policy }
+}
+
+// AsyncEmitter wraps an EventEmitter, queueing the emitted events and
+// passing them on from a background goroutine, so slow listeners do not
+// delay the emitting code:
+//
+//	async := event.NewAsyncEmitter(emitter,
+//		event.WithQueueSize(4096), event.WithBackpressure(event.DropOldest),
+//	)
+//	defer async.Close()
+//
+// Events are passed on one at a time, in emission order, with the context
+// they were emitted with. When the queue is full the backpressure policy
+// decides between blocking and dropping; Dropped reports the dropped
+// events for monitoring. Panics of the wrapped emitter are recovered and
+// the event dropped, so one bad listener does not stop the delivery.
+// Listener methods apply to the wrapped emitter.
+type AsyncEmitter struct {
+	emitter EventEmitter
+	queue   *eventQueue
+	stopped chan struct{}
+}
+
+// AsyncEmitter implements EventEmitter, ContextEmitter and StatsProvider.
+var (
+	_ EventEmitter   = (*AsyncEmitter)(nil)
+	_ ContextEmitter = (*AsyncEmitter)(nil)
+	_ StatsProvider  = (*AsyncEmitter)(nil)
+)
+
+// NewAsyncEmitter creates an asynchronous wrapper of an emitter and starts
+// its delivery goroutine. Non-positive option values select the defaults.
+//
+// Parameters:
+//   - emitter: The emitter receiving the events.
+//   - opts: Optional async options.
+//
+// Returns:
+//   - *AsyncEmitter: A new AsyncEmitter instance.
+func NewAsyncEmitter(emitter EventEmitter, opts ...AsyncOption) *AsyncEmitter {
+	var cfg asyncConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.size <= 0 {
+		cfg.size = 1024
+	}
+	a := &AsyncEmitter{
+		emitter: emitter,
+		queue:   newEventQueue(cfg.size, cfg.backpressure),
+		stopped: make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// Emit queues an event for delivery. Nil events are ignored; events
+// emitted after Close are dropped.
+//
+// Parameters:
+//   - event: The event to emit.
+func (a *AsyncEmitter) Emit(event *Event) {
+	if event == nil {
+		return
+	}
+	a.queue.push(event)
+}
+
+// EmitCtx queues an event originating from ctx for delivery.
+//
+// Parameters:
+//   - ctx: The originating context.
+//   - event: The event to emit.
+func (a *AsyncEmitter) EmitCtx(ctx context.Context, event *Event) {
+	if event == nil {
+		return
+	}
+	a.Emit(event.WithContext(ctx))
+}
+
+// Close stops accepting events and waits for the queued ones to be
+// delivered. Emit calls blocked by the Block policy return, dropping their
+// events. Later calls do nothing.
+//
+// Returns:
+//   - error: Always nil.
+func (a *AsyncEmitter) Close() error {
+	if a.queue.close() {
+		<-a.stopped
+	}
+	return nil
+}
+
+// Dropped returns the number of events dropped since the emitter was
+// created.
+//
+// Returns:
+//   - uint64: The number of dropped events.
+func (a *AsyncEmitter) Dropped() uint64 {
+	return a.queue.droppedCount()
+}
+
+// Stats returns the stats of the wrapped emitter, if it is a
+// StatsProvider, with the events dropped by the queue added to Dropped.
+//
+// Returns:
+//   - EmitterStats: The stats snapshot.
+func (a *AsyncEmitter) Stats() EmitterStats {
+	var stats EmitterStats
+	if p, ok := a.emitter.(StatsProvider); ok {
+		stats = p.Stats()
+	}
+	if stats.ByType == nil {
+		stats.ByType = map[EventType]uint64{}
+	}
+	stats.Dropped += a.Dropped()
+	return stats
+}
+
+// RegisterListener registers a listener on the wrapped emitter.
+//
+// Parameters:
+//   - eventType: The event type to listen for.
+//   - callback: The callback.
+//
+// Returns:
+//   - EventEmitter: The async emitter, for chaining.
+func (a *AsyncEmitter) RegisterListener(
+	eventType EventType, callback EventCallback,
+) EventEmitter {
+	a.emitter.RegisterListener(eventType, callback)
+	return a
+}
+
+// RemoveListener removes a listener from the wrapped emitter.
+//
+// Parameters:
+//   - eventType: The event type the listener was registered for.
+//   - id: The listener ID.
+func (a *AsyncEmitter) RemoveListener(eventType EventType, id string) {
+	a.emitter.RemoveListener(eventType, id)
+}
+
+// RegisterGlobalListener registers a global listener on the wrapped
+// emitter.
+//
+// Parameters:
+//   - callback: The callback.
+//
+// Returns:
+//   - EventEmitter: The async emitter, for chaining.
+func (a *AsyncEmitter) RegisterGlobalListener(
+	callback EventCallback,
+) EventEmitter {
+	a.emitter.RegisterGlobalListener(callback)
+	return a
+}
+
+// RemoveGlobalListener removes a global listener from the wrapped emitter.
+//
+// Parameters:
+//   - id: The listener ID.
+func (a *AsyncEmitter) RemoveGlobalListener(id string) {
+	a.emitter.RemoveGlobalListener(id)
+}
+
+// run delivers the queued events until the emitter is closed and drained.
+func (a *AsyncEmitter) run() {
+	defer close(a.stopped)
+	for {
+		event, ok := a.queue.next()
+		if !ok {
+			return
+		}
+		a.deliver(event)
+	}
+}
+
+// deliver passes an event on, recovering panics.
+func (a *AsyncEmitter) deliver(event *Event) {
+	defer func() { _ = recover() }()
+	EmitCtx(a.emitter, event.Context(), event)
+}
@@ -0,0 +1,146 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gatedRecorder records event messages, holding the first delivery until
+// the gate is opened.
+type gatedRecorder struct {
+	started chan struct{}
+	gate    chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	got     []string
+}
+
+// newGatedRecorder creates a gated recorder listening on an emitter.
+func newGatedRecorder(emitter *DefaultEventEmitter) *gatedRecorder {
+	r := &gatedRecorder{
+		started: make(chan struct{}),
+		gate:    make(chan struct{}),
+	}
+	emitter.AddGlobalListener(func(ev *Event) {
+		r.once.Do(func() {
+			close(r.started)
+			<-r.gate
+		})
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.got = append(r.got, ev.Message)
+	})
+	return r
+}
+
+// messages returns the recorded messages.
+func (r *gatedRecorder) messages() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.got
+}
+
+// TestAsyncEmitterBackpressure tests the drop policies with a full queue.
+func TestAsyncEmitterBackpressure(t *testing.T) {
+	tests := []struct {
+		policy Backpressure
+		want   []string
+	}{
+		{DropNewest, []string{"1", "2", "3"}},
+		{DropOldest, []string{"1", "3", "4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			emitter := NewEventEmitter()
+			rec := newGatedRecorder(emitter)
+			a := NewAsyncEmitter(emitter,
+				WithQueueSize(2), WithBackpressure(tt.policy))
+			a.Emit(NewEvent("a", "1"))
+			<-rec.started
+			for _, m := range []string{"2", "3", "4"} {
+				a.Emit(NewEvent("a", m))
+			}
+			a.Emit(nil)
+			close(rec.gate)
+			assert.NoError(t, a.Close())
+			assert.NoError(t, a.Close())
+			a.Emit(NewEvent("a", "5"))
+
+			assert.Equal(t, tt.want, rec.messages())
+			assert.Equal(t, uint64(2), a.Dropped())
+		})
+	}
+}
+
+// TestAsyncEmitterBlock tests that the Block policy waits for room in the
+// queue instead of dropping events.
+func TestAsyncEmitterBlock(t *testing.T) {
+	emitter := NewEventEmitter()
+	rec := newGatedRecorder(emitter)
+	a := NewAsyncEmitter(emitter, WithQueueSize(1), WithBackpressure(Block))
+	a.Emit(NewEvent("a", "1"))
+	<-rec.started
+	a.Emit(NewEvent("a", "2"))
+	emitted := make(chan struct{})
+	go func() {
+		a.Emit(NewEvent("a", "3"))
+		close(emitted)
+	}()
+	select {
+	case <-emitted:
+		t.Fatal("Emit did not block on a full queue")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(rec.gate)
+	<-emitted
+	assert.NoError(t, a.Close())
+
+	assert.Equal(t, []string{"1", "2", "3"}, rec.messages())
+	assert.Equal(t, uint64(0), a.Dropped())
+}
+
+// TestAsyncEmitterCloseUnblocks tests that Close releases blocked Emit
+// calls, dropping their events.
+func TestAsyncEmitterCloseUnblocks(t *testing.T) {
+	emitter := NewEventEmitter()
+	rec := newGatedRecorder(emitter)
+	a := NewAsyncEmitter(emitter, WithQueueSize(1), WithBackpressure(Block))
+	a.Emit(NewEvent("a", "1"))
+	<-rec.started
+	a.Emit(NewEvent("a", "2"))
+	emitted := make(chan struct{})
+	go func() {
+		a.Emit(NewEvent("a", "3"))
+		close(emitted)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	// Close the queue directly: Close would wait for the gated delivery.
+	a.queue.close()
+	<-emitted
+	close(rec.gate)
+	<-a.stopped
+
+	assert.Equal(t, []string{"1", "2"}, rec.messages())
+	assert.Equal(t, uint64(1), a.Dropped())
+}
+
+// TestAsyncEmitterContext tests that events keep their context and that
+// listener panics do not stop the delivery.
+func TestAsyncEmitterContext(t *testing.T) {
+	emitter := NewEventEmitter()
+	got := make(chan any, 1)
+	emitter.AddListener("panic", func(ev *Event) { panic("boom") })
+	emitter.AddListener("ctx", func(ev *Event) {
+		got <- ev.Context().Value(ctxKey{})
+	})
+	a := NewAsyncEmitter(emitter)
+	defer a.Close()
+	a.Emit(NewEvent("panic", ""))
+	ctx := context.WithValue(context.Background(), ctxKey{}, "v")
+	EmitCtx(a, ctx, NewEvent("ctx", ""))
+	assert.Equal(t, "v", <-got)
+}
@@ -0,0 +1,222 @@
+package event
+
+import (
+	"sync"
+	"time"
+)
+
+// BatchFunc delivers a batch of events, e.g. to a log shipper or a queue.
+// Batches are delivered one at a time, in emission order.
+type BatchFunc func(events []*Event)
+
+// BatchOption configures a BatchSink.
+type BatchOption func(*batchConfig)
+
+// batchConfig holds the batching settings.
+type batchConfig struct {
+	size         int
+	interval     time.Duration
+	maxPending   int
+	backpressure Backpressure
+}
+
+// WithBatchSize sets the number of events delivered at most per batch,
+// 100 by default. A batch is delivered as soon as it is full.
+//
+// Parameters:
+//   - size: The batch size.
+//
+// Returns:
+//   - BatchOption: The option.
+func WithBatchSize(size int) BatchOption {
+	return func(c *batchConfig) { c.size = size }
+}
+
+// WithFlushInterval sets how often pending events are delivered when no
+// batch fills up, 1 second by default.
+//
+// Parameters:
+//   - interval: The flush interval.
+//
+// Returns:
+//   - BatchOption: The option.
+func WithFlushInterval(interval time.Duration) BatchOption {
+	return func(c *batchConfig) { c.interval = interval }
+}
+
+// WithMaxPending sets how many events may wait for delivery, 10 batches by
+// default. Events emitted beyond it, e.g. while the delivery is slow, are
+// handled by the backpressure policy; by default they are dropped and
+// counted by Dropped, so emitting never blocks.
+//
+// Parameters:
+//   - n: The maximum number of pending events.
+//
+// Returns:
+//   - BatchOption: The option.
+func WithMaxPending(n int) BatchOption {
+	return func(c *batchConfig) { c.maxPending = n }
+}
+
+// WithBatchBackpressure sets the policy for events emitted while
+// WithMaxPending events wait for delivery, DropNewest by default.
+//
+// Parameters:
+//   - policy: The backpressure policy.
+//
+// Returns:
+//   - BatchOption: The option.
+func WithBatchBackpressure(policy Backpressure) BatchOption {
+	return func(c *batchConfig) { c.backpressure = policy }
+}
+
+// BatchSink is an EventEmitter buffering events and delivering them in
+// batches from a background goroutine, when a batch is full or the flush
+// interval passes, to ship them to external systems without per-event
+// overhead:
+//
+//	sink := event.NewBatchSink(func(events []*event.Event) {
+//		_ = shipper.Send(events)
+//	}, event.WithBatchSize(500), event.WithFlushInterval(5*time.Second))
+//	defer sink.Close()
+//	emitter.AddGlobalListener(sink.Emit)
+//
+// A sink has no listeners of its own; its listener methods do nothing.
+// Close it to deliver the pending events and stop the goroutine.
+type BatchSink struct {
+	deliver BatchFunc
+	cfg     batchConfig
+	pending *eventQueue
+
+	deliverMu sync.Mutex
+	full      chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+// BatchSink implements EventEmitter.
+var _ EventEmitter = (*BatchSink)(nil)
+
+// NewBatchSink creates a batch sink and starts its delivery goroutine.
+// Non-positive option values select the defaults.
+//
+// Parameters:
+//   - deliver: The batch delivery callback.
+//   - opts: Optional batch options.
+//
+// Returns:
+//   - *BatchSink: A new BatchSink instance.
+func NewBatchSink(deliver BatchFunc, opts ...BatchOption) *BatchSink {
+	var cfg batchConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.size <= 0 {
+		cfg.size = 100
+	}
+	if cfg.interval <= 0 {
+		cfg.interval = time.Second
+	}
+	if cfg.maxPending <= 0 {
+		cfg.maxPending = 10 * cfg.size
+	}
+	s := &BatchSink{
+		deliver: deliver,
+		cfg:     cfg,
+		pending: newEventQueue(cfg.maxPending, cfg.backpressure),
+		full:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Emit buffers an event for delivery. Nil events are ignored; events
+// emitted after Close are dropped, and events beyond the pending limit are
+// handled by the backpressure policy.
+//
+// Parameters:
+//   - event: The event to emit.
+func (s *BatchSink) Emit(event *Event) {
+	if event == nil {
+		return
+	}
+	if s.pending.push(event) >= s.cfg.size {
+		select {
+		case s.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush delivers the pending events and waits for the delivery.
+func (s *BatchSink) Flush() {
+	s.deliverMu.Lock()
+	defer s.deliverMu.Unlock()
+	events := s.pending.take()
+	for len(events) > 0 {
+		n := min(len(events), s.cfg.size)
+		s.deliver(events[:n:n])
+		events = events[n:]
+	}
+}
+
+// Close stops the delivery goroutine after delivering the pending events.
+// Later calls do nothing.
+//
+// Returns:
+//   - error: Always nil.
+func (s *BatchSink) Close() error {
+	if !s.pending.close() {
+		return nil
+	}
+	close(s.done)
+	<-s.stopped
+	return nil
+}
+
+// Dropped returns the number of events dropped since the sink was created.
+//
+// Returns:
+//   - uint64: The number of dropped events.
+func (s *BatchSink) Dropped() uint64 {
+	return s.pending.droppedCount()
+}
+
+// RegisterListener does nothing.
+func (s *BatchSink) RegisterListener(
+	eventType EventType, callback EventCallback,
+) EventEmitter {
+	return s
+}
+
+// RemoveListener does nothing.
+func (s *BatchSink) RemoveListener(eventType EventType, id string) {}
+
+// RegisterGlobalListener does nothing.
+func (s *BatchSink) RegisterGlobalListener(
+	callback EventCallback,
+) EventEmitter {
+	return s
+}
+
+// RemoveGlobalListener does nothing.
+func (s *BatchSink) RemoveGlobalListener(id string) {}
+
+// run delivers batches until the sink is closed.
+func (s *BatchSink) run() {
+	defer close(s.stopped)
+	ticker := time.NewTicker(s.cfg.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.full:
+		case <-s.done:
+			s.Flush()
+			return
+		}
+		s.Flush()
+	}
+}
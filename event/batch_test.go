@@ -0,0 +1,94 @@
+package event
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// batchRecorder records delivered batches.
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches [][]string
+}
+
+// deliver records a batch.
+func (b *batchRecorder) deliver(events []*Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var batch []string
+	for _, ev := range events {
+		batch = append(batch, ev.Message)
+	}
+	b.batches = append(b.batches, batch)
+}
+
+// get returns the recorded batches.
+func (b *batchRecorder) get() [][]string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.batches
+}
+
+// TestBatchSinkSize tests that full batches are delivered without waiting
+// for the interval.
+func TestBatchSinkSize(t *testing.T) {
+	var rec batchRecorder
+	s := NewBatchSink(rec.deliver, WithBatchSize(2), WithFlushInterval(time.Hour))
+	defer s.Close()
+	s.Emit(NewEvent("a", "1"))
+	s.Emit(NewEvent("a", "2"))
+	assert.Eventually(t, func() bool { return len(rec.get()) == 1 },
+		time.Second, time.Millisecond)
+	assert.Equal(t, [][]string{{"1", "2"}}, rec.get())
+}
+
+// TestBatchSinkInterval tests that pending events are delivered after the
+// flush interval.
+func TestBatchSinkInterval(t *testing.T) {
+	var rec batchRecorder
+	s := NewBatchSink(rec.deliver, WithFlushInterval(10*time.Millisecond))
+	defer s.Close()
+	s.Emit(NewEvent("a", "1"))
+	assert.Eventually(t, func() bool { return len(rec.get()) == 1 },
+		time.Second, time.Millisecond)
+	assert.Equal(t, [][]string{{"1"}}, rec.get())
+}
+
+// TestBatchSinkClose tests that Close delivers the pending events in
+// batches and that later events are dropped.
+func TestBatchSinkClose(t *testing.T) {
+	var rec batchRecorder
+	s := NewBatchSink(rec.deliver, WithBatchSize(2),
+		WithFlushInterval(time.Hour), WithMaxPending(3))
+	// Block delivery so events stay pending.
+	s.deliverMu.Lock()
+	for _, m := range []string{"1", "2", "3", "4"} {
+		s.Emit(NewEvent("a", m))
+	}
+	s.Emit(nil)
+	s.deliverMu.Unlock()
+	assert.NoError(t, s.Close())
+	assert.NoError(t, s.Close())
+	s.Emit(NewEvent("a", "5"))
+
+	assert.Equal(t, [][]string{{"1", "2"}, {"3"}}, rec.get())
+	assert.Equal(t, uint64(2), s.Dropped())
+}
+
+// TestBatchSinkDropOldest tests that the DropOldest policy keeps the newest
+// pending events.
+func TestBatchSinkDropOldest(t *testing.T) {
+	var rec batchRecorder
+	s := NewBatchSink(rec.deliver, WithFlushInterval(time.Hour),
+		WithMaxPending(2), WithBatchBackpressure(DropOldest))
+	for _, m := range []string{"1", "2", "3"} {
+		s.Emit(NewEvent("a", m))
+	}
+	assert.NoError(t, s.Close())
+
+	assert.Equal(t, [][]string{{"2", "3"}}, rec.get())
+	assert.Equal(t, uint64(1), s.Dropped())
+}
@@ -0,0 +1,47 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ctxKey is a test context key.
+type ctxKey struct{}
+
+// plainEmitter is an emitter without EmitCtx.
+type plainEmitter struct {
+	NoopEventEmitter
+	events []*Event
+}
+
+// Emit records the event.
+func (p *plainEmitter) Emit(event *Event) {
+	p.events = append(p.events, event)
+}
+
+// TestEmitCtx tests that listeners receive the originating context, with
+// and without ContextEmitter support.
+func TestEmitCtx(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKey{}, "trace-1")
+
+	e := NewEventEmitter()
+	var got []any
+	e.AddGlobalListener(func(ev *Event) {
+		got = append(got, ev.Context().Value(ctxKey{}))
+	})
+	EmitCtx(e, ctx, NewEvent("a", ""))
+	e.Emit(NewEvent("a", ""))
+	EmitCtx(NewCounterEmitter(e), ctx, NewEvent("a", ""))
+	EmitCtx(e, ctx, nil)
+	assert.Equal(t, []any{"trace-1", nil, "trace-1"}, got)
+
+	p := &plainEmitter{}
+	ev := NewEvent("a", "")
+	EmitCtx(p, ctx, ev)
+	if assert.Len(t, p.events, 1) {
+		assert.Equal(t, "trace-1", p.events[0].Context().Value(ctxKey{}))
+	}
+	assert.Equal(t, context.Background(), ev.Context())
+}
@@ -0,0 +1,278 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// labelNamePattern matches valid Prometheus label names.
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// CounterOption configures a CounterEmitter.
+type CounterOption func(*counterConfig)
+
+// counterConfig holds the counter settings.
+type counterConfig struct {
+	name   string
+	labels []string
+}
+
+// WithCounterName sets the metric name, "events_total" by default.
+//
+// Parameters:
+//   - name: The metric name.
+//
+// Returns:
+//   - CounterOption: The option.
+func WithCounterName(name string) CounterOption {
+	return func(c *counterConfig) { c.name = name }
+}
+
+// WithCounterLabels adds labels taken from the map[string]any data of
+// events, such as "status" or "method". Events without a key count with
+// an empty label value. Keep to keys with few distinct values: every
+// combination becomes a series, so raw paths or IDs make the metric grow
+// without bound.
+//
+// Parameters:
+//   - keys: The data keys, which are also the label names.
+//
+// Returns:
+//   - CounterOption: The option.
+func WithCounterLabels(keys ...string) CounterOption {
+	return func(c *counterConfig) { c.labels = append(c.labels, keys...) }
+}
+
+// CounterEmitter wraps an EventEmitter, counting the emitted events by
+// type and data labels before passing them on. It serves the counts in the
+// Prometheus text format, so events become metrics without listeners in
+// every service:
+//
+//	counter := event.NewCounterEmitter(
+//		event.NewEventEmitter(), event.WithCounterLabels("status"),
+//	)
+//	mux.Handle("GET /metrics", counter)
+//	server := pureapi.NewServer(pureapi.WithEventEmitter(counter))
+type CounterEmitter struct {
+	emitter EventEmitter
+	cfg     counterConfig
+	mu      sync.Mutex
+	counts  map[string]*eventCount
+}
+
+// eventCount is the count of one label combination.
+type eventCount struct {
+	values []string
+	n      uint64
+}
+
+// CounterEmitter implements EventEmitter, ContextEmitter and http.Handler.
+var (
+	_ EventEmitter   = (*CounterEmitter)(nil)
+	_ ContextEmitter = (*CounterEmitter)(nil)
+	_ http.Handler   = (*CounterEmitter)(nil)
+)
+
+// NewCounterEmitter creates a counting wrapper of an emitter. It panics on
+// label names that are not valid Prometheus label names, or that repeat,
+// since that is a programming error.
+//
+// Parameters:
+//   - emitter: The emitter receiving the events.
+//   - opts: Optional counter options.
+//
+// Returns:
+//   - *CounterEmitter: A new CounterEmitter instance.
+func NewCounterEmitter(
+	emitter EventEmitter, opts ...CounterOption,
+) *CounterEmitter {
+	cfg := counterConfig{name: "events_total"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	names := append([]string{"type"}, cfg.labels...)
+	if !labelNamePattern.MatchString(cfg.name) {
+		panic(fmt.Sprintf("event: NewCounterEmitter: invalid name %q", cfg.name))
+	}
+	for i, name := range names {
+		if !labelNamePattern.MatchString(name) || slices.Contains(names[:i], name) {
+			panic(fmt.Sprintf("event: NewCounterEmitter: invalid label %q", name))
+		}
+	}
+	return &CounterEmitter{
+		emitter: emitter,
+		cfg:     cfg,
+		counts:  map[string]*eventCount{},
+	}
+}
+
+// Emit counts the event and passes it to the wrapped emitter. Nil events
+// are ignored.
+//
+// Parameters:
+//   - event: The event to emit.
+func (c *CounterEmitter) Emit(event *Event) {
+	if event == nil {
+		return
+	}
+	c.count(event)
+	c.emitter.Emit(event)
+}
+
+// count increments the count of an event.
+func (c *CounterEmitter) count(event *Event) {
+	values := c.labelValues(event)
+	key := strings.Join(values, "\x00")
+	c.mu.Lock()
+	count, ok := c.counts[key]
+	if !ok {
+		count = &eventCount{values: values}
+		c.counts[key] = count
+	}
+	count.n++
+	c.mu.Unlock()
+}
+
+// EmitCtx counts an event originating from ctx and passes it on with
+// EmitCtx.
+//
+// Parameters:
+//   - ctx: The originating context.
+//   - event: The event to emit.
+func (c *CounterEmitter) EmitCtx(ctx context.Context, event *Event) {
+	if event == nil {
+		return
+	}
+	c.count(event)
+	EmitCtx(c.emitter, ctx, event)
+}
+
+// Count returns the number of events emitted with a type and label values.
+//
+// Parameters:
+//   - eventType: The event type.
+//   - labelValues: The values of the labels of WithCounterLabels, in
+//     order.
+//
+// Returns:
+//   - uint64: The number of events.
+func (c *CounterEmitter) Count(
+	eventType EventType, labelValues ...string,
+) uint64 {
+	key := strings.Join(append([]string{string(eventType)}, labelValues...), "\x00")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if count, ok := c.counts[key]; ok {
+		return count.n
+	}
+	return 0
+}
+
+// ServeHTTP writes the counts in the Prometheus text exposition format,
+// sorted by label values.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+func (c *CounterEmitter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	counts := make([]eventCount, 0, len(c.counts))
+	for _, count := range c.counts {
+		counts = append(counts, *count)
+	}
+	c.mu.Unlock()
+	slices.SortFunc(counts, func(a, b eventCount) int {
+		return slices.Compare(a.values, b.values)
+	})
+
+	names := append([]string{"type"}, c.cfg.labels...)
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s Events emitted, by type.\n", c.cfg.name)
+	fmt.Fprintf(&b, "# TYPE %s counter\n", c.cfg.name)
+	for _, count := range counts {
+		b.WriteString(c.cfg.name)
+		b.WriteByte('{')
+		for i, name := range names {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(&b, "%s=\"%s\"", name, escapeLabelValue(count.values[i]))
+		}
+		b.WriteString("} ")
+		b.WriteString(strconv.FormatUint(count.n, 10))
+		b.WriteByte('\n')
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(b.String()))
+}
+
+// RegisterListener registers a listener on the wrapped emitter.
+//
+// Parameters:
+//   - eventType: The event type to listen to.
+//   - callback: The callback.
+//
+// Returns:
+//   - EventEmitter: The counter emitter, for chaining.
+func (c *CounterEmitter) RegisterListener(
+	eventType EventType, callback EventCallback,
+) EventEmitter {
+	c.emitter.RegisterListener(eventType, callback)
+	return c
+}
+
+// RemoveListener removes a listener from the wrapped emitter.
+//
+// Parameters:
+//   - eventType: The event type the listener was registered for.
+//   - id: The listener ID.
+func (c *CounterEmitter) RemoveListener(eventType EventType, id string) {
+	c.emitter.RemoveListener(eventType, id)
+}
+
+// RegisterGlobalListener registers a global listener on the wrapped
+// emitter.
+//
+// Parameters:
+//   - callback: The callback.
+//
+// Returns:
+//   - EventEmitter: The counter emitter, for chaining.
+func (c *CounterEmitter) RegisterGlobalListener(
+	callback EventCallback,
+) EventEmitter {
+	c.emitter.RegisterGlobalListener(callback)
+	return c
+}
+
+// RemoveGlobalListener removes a global listener from the wrapped emitter.
+//
+// Parameters:
+//   - id: The listener ID.
+func (c *CounterEmitter) RemoveGlobalListener(id string) {
+	c.emitter.RemoveGlobalListener(id)
+}
+
+// labelValues returns the event type and the label values of an event.
+func (c *CounterEmitter) labelValues(event *Event) []string {
+	values := make([]string, 1+len(c.cfg.labels))
+	values[0] = string(event.Type)
+	data, _ := event.Data.(map[string]any)
+	for i, key := range c.cfg.labels {
+		if v, ok := data[key]; ok && v != nil {
+			values[i+1] = fmt.Sprint(v)
+		}
+	}
+	return values
+}
+
+// escapeLabelValue escapes a label value for the text format.
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,52 @@
+package event
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCounterEmitter tests that events are counted by type and labels and
+// passed to the wrapped emitter.
+func TestCounterEmitter(t *testing.T) {
+	inner := NewEventEmitter()
+	var seen int
+	c := NewCounterEmitter(inner, WithCounterName("api_events_total"),
+		WithCounterLabels("status", "method"))
+	c.RegisterGlobalListener(func(*Event) { seen++ })
+
+	c.Emit(NewEvent("handled", "").WithData(map[string]any{"status": 200, "method": "GET"}))
+	c.Emit(NewEvent("handled", "").WithData(map[string]any{"status": 200, "method": "GET"}))
+	c.Emit(NewEvent("handled", "").WithData(map[string]any{"status": 404, "method": "GET"}))
+	c.Emit(NewEvent("start", `a "quoted"`+"\n"))
+	c.Emit(NewEvent(`we"ird`, ""))
+	c.Emit(nil)
+
+	assert.Equal(t, 5, seen)
+	assert.Equal(t, uint64(2), c.Count("handled", "200", "GET"))
+	assert.Equal(t, uint64(1), c.Count("handled", "404", "GET"))
+	assert.Equal(t, uint64(1), c.Count("start", "", ""))
+	assert.Equal(t, uint64(0), c.Count("handled", "500", "GET"))
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `# HELP api_events_total Events emitted, by type.
+# TYPE api_events_total counter
+api_events_total{type="handled",status="200",method="GET"} 2
+api_events_total{type="handled",status="404",method="GET"} 1
+api_events_total{type="start",status="",method=""} 1
+api_events_total{type="we\"ird",status="",method=""} 1
+`, rec.Body.String())
+}
+
+// TestNewCounterEmitterPanics tests that invalid names panic.
+func TestNewCounterEmitterPanics(t *testing.T) {
+	inner := NewNoopEventEmitter()
+	assert.Panics(t, func() { NewCounterEmitter(inner, WithCounterName("bad-name")) })
+	assert.Panics(t, func() { NewCounterEmitter(inner, WithCounterLabels("a.b")) })
+	assert.Panics(t, func() { NewCounterEmitter(inner, WithCounterLabels("type")) })
+	assert.NotPanics(t, func() { NewCounterEmitter(inner, WithCounterLabels("status")) })
+}
@@ -0,0 +1,358 @@
+package event
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ListenerOption configures a listener registered with AddListener or
+// AddGlobalListener.
+type ListenerOption func(*listener)
+
+// WithPriority sets the priority of a listener, 0 by default. Listeners
+// with a higher priority are called before those with a lower one among
+// the listeners of the same type, pattern, or among the global listeners;
+// listeners of equal priority are called in registration order.
+//
+// Parameters:
+//   - priority: The priority.
+//
+// Returns:
+//   - ListenerOption: The option.
+func WithPriority(priority int) ListenerOption {
+	return func(l *listener) { l.priority = priority }
+}
+
+// Once makes a listener remove itself after its first event, e.g. for a
+// startup-complete hook. It is called once even if events are emitted
+// concurrently.
+//
+// Returns:
+//   - ListenerOption: The option.
+func Once() ListenerOption {
+	return func(l *listener) { l.fired = &atomic.Bool{} }
+}
+
+// listener is a registered callback and its ID.
+type listener struct {
+	id       string
+	callback EventCallback
+	priority int
+	key      EventType    // Type or pattern, for removing once listeners.
+	global   bool         // Whether it is a global listener.
+	fired    *atomic.Bool // Set for once listeners when called.
+}
+
+// newListener creates a listener with the options applied.
+func newListener(
+	id string, callback EventCallback, opts []ListenerOption,
+) listener {
+	l := listener{id: id, callback: callback}
+	for _, opt := range opts {
+		opt(&l)
+	}
+	return l
+}
+
+// insertListener returns a copy of ls with l inserted after the listeners
+// of a higher or equal priority.
+func insertListener(ls []listener, l listener) []listener {
+	i := slices.IndexFunc(ls, func(o listener) bool {
+		return o.priority < l.priority
+	})
+	if i < 0 {
+		i = len(ls)
+	}
+	return slices.Insert(slices.Clone(ls), i, l)
+}
+
+// DefaultEventEmitter is an in-memory EventEmitter calling listeners
+// synchronously, in the goroutine emitting the event. It is safe for
+// concurrent use; listeners may register and remove listeners, including
+// themselves, while an event is emitted.
+type DefaultEventEmitter struct {
+	mu        sync.RWMutex
+	listeners map[EventType][]listener
+	patterns  map[EventType][]listener
+	global    []listener
+	nextID    uint64
+
+	transformers []EventTransformer
+	stats        emitterStats
+}
+
+// DefaultEventEmitter implements EventEmitter, ContextEmitter and
+// StatsProvider.
+var (
+	_ EventEmitter   = (*DefaultEventEmitter)(nil)
+	_ ContextEmitter = (*DefaultEventEmitter)(nil)
+	_ StatsProvider  = (*DefaultEventEmitter)(nil)
+)
+
+// NewEventEmitter creates a new in-memory event emitter:
+//
+//	emitter := event.NewEventEmitter()
+//	id := emitter.AddListener(server.EventPanic, func(e *event.Event) {
+//		log.Println(e.Message)
+//	})
+//	defer emitter.RemoveListener(server.EventPanic, id)
+//
+// Returns:
+//   - *DefaultEventEmitter: A new DefaultEventEmitter instance.
+func NewEventEmitter() *DefaultEventEmitter {
+	return &DefaultEventEmitter{
+		listeners: map[EventType][]listener{},
+		patterns:  map[EventType][]listener{},
+	}
+}
+
+// MatchEventType reports whether an event type matches a listener pattern.
+// A pattern ending in "*" matches the types starting with the rest of it,
+// so "event_shutdown*" matches "event_shutdown" and
+// "event_shutdown_started", and "*" matches every type. Other patterns
+// match their type only.
+//
+// Parameters:
+//   - pattern: The listener pattern.
+//   - eventType: The event type.
+//
+// Returns:
+//   - bool: Whether the event type matches.
+func MatchEventType(pattern EventType, eventType EventType) bool {
+	prefix, ok := strings.CutSuffix(string(pattern), "*")
+	if !ok {
+		return pattern == eventType
+	}
+	return strings.HasPrefix(string(eventType), prefix)
+}
+
+// isPattern reports whether an event type is a wildcard pattern.
+func isPattern(eventType EventType) bool {
+	return strings.HasSuffix(string(eventType), "*")
+}
+
+// byType returns the listener map for an event type or pattern.
+func (e *DefaultEventEmitter) byType(eventType EventType) map[EventType][]listener {
+	if isPattern(eventType) {
+		return e.patterns
+	}
+	return e.listeners
+}
+
+// AddListener registers a callback for events of a type. The type may be
+// a pattern such as "event_shutdown*" or "*", see MatchEventType, so
+// monitoring code need not list every event type:
+//
+//	emitter.AddListener(server.EventStart, markReady, event.Once())
+//
+// Parameters:
+//   - eventType: The event type or pattern to listen to.
+//   - callback: The callback.
+//   - opts: Optional listener options, such as WithPriority and Once.
+//
+// Returns:
+//   - string: The listener ID, for RemoveListener.
+func (e *DefaultEventEmitter) AddListener(
+	eventType EventType, callback EventCallback, opts ...ListenerOption,
+) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	l := newListener(e.newID(), callback, opts)
+	l.key = eventType
+	// Listener slices are replaced, never modified, so Emit can iterate a
+	// snapshot without holding the lock.
+	m := e.byType(eventType)
+	m[eventType] = insertListener(m[eventType], l)
+	return l.id
+}
+
+// AddGlobalListener registers a callback for events of every type.
+//
+// Parameters:
+//   - callback: The callback.
+//   - opts: Optional listener options, such as WithPriority and Once.
+//
+// Returns:
+//   - string: The listener ID, for RemoveGlobalListener.
+func (e *DefaultEventEmitter) AddGlobalListener(
+	callback EventCallback, opts ...ListenerOption,
+) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	l := newListener(e.newID(), callback, opts)
+	l.global = true
+	e.global = insertListener(e.global, l)
+	return l.id
+}
+
+// RegisterListener registers a callback for events of a type or pattern.
+// Use AddListener to get the listener ID.
+//
+// Parameters:
+//   - eventType: The event type or pattern to listen to.
+//   - callback: The callback.
+//
+// Returns:
+//   - EventEmitter: The emitter, for chaining.
+func (e *DefaultEventEmitter) RegisterListener(
+	eventType EventType, callback EventCallback,
+) EventEmitter {
+	e.AddListener(eventType, callback)
+	return e
+}
+
+// RegisterGlobalListener registers a callback for events of every type. Use
+// AddGlobalListener to get the listener ID.
+//
+// Parameters:
+//   - callback: The callback.
+//
+// Returns:
+//   - EventEmitter: The emitter, for chaining.
+func (e *DefaultEventEmitter) RegisterGlobalListener(
+	callback EventCallback,
+) EventEmitter {
+	e.AddGlobalListener(callback)
+	return e
+}
+
+// RemoveListener removes a listener of an event type or pattern. Unknown
+// IDs, and IDs registered for another type or pattern, are ignored.
+//
+// Parameters:
+//   - eventType: The event type or pattern the listener was registered for.
+//   - id: The listener ID.
+func (e *DefaultEventEmitter) RemoveListener(eventType EventType, id string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	m := e.byType(eventType)
+	ls := removeListener(m[eventType], id)
+	if len(ls) == 0 {
+		delete(m, eventType)
+		return
+	}
+	m[eventType] = ls
+}
+
+// RemoveGlobalListener removes a global listener. Unknown IDs are ignored.
+//
+// Parameters:
+//   - id: The listener ID.
+func (e *DefaultEventEmitter) RemoveGlobalListener(id string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.global = removeListener(e.global, id)
+}
+
+// Emit runs the transformers installed with Use on the event, then calls
+// the listeners of the event type, then those of matching patterns, then
+// the global listeners. Listeners of a type or pattern are called by
+// priority, then in registration order, patterns in lexical order.
+// Listeners registered or removed during the call take effect from the
+// next event. Nil events, and events dropped by a transformer, are
+// ignored.
+//
+// Parameters:
+//   - event: The event to emit.
+func (e *DefaultEventEmitter) Emit(event *Event) {
+	if event == nil {
+		return
+	}
+	e.mu.RLock()
+	transformers := e.transformers
+	e.mu.RUnlock()
+	if event = transform(event, transformers); event == nil {
+		e.stats.drop()
+		return
+	}
+	e.mu.RLock()
+	typed, global := e.listeners[event.Type], e.global
+	var matched [][]listener
+	for _, pattern := range slices.Sorted(maps.Keys(e.patterns)) {
+		if MatchEventType(pattern, event.Type) {
+			matched = append(matched, e.patterns[pattern])
+		}
+	}
+	e.mu.RUnlock()
+	start := time.Now()
+	defer func() { e.stats.dispatched(event.Type, time.Since(start)) }()
+	for _, l := range typed {
+		e.call(l, event)
+	}
+	for _, ls := range matched {
+		for _, l := range ls {
+			e.call(l, event)
+		}
+	}
+	for _, l := range global {
+		e.call(l, event)
+	}
+}
+
+// call calls a listener, removing once listeners first. Panics are
+// counted and passed on.
+func (e *DefaultEventEmitter) call(l listener, event *Event) {
+	if l.fired != nil {
+		if !l.fired.CompareAndSwap(false, true) {
+			return
+		}
+		if l.global {
+			e.RemoveGlobalListener(l.id)
+		} else {
+			e.RemoveListener(l.key, l.id)
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			e.stats.listenerError()
+			panic(r)
+		}
+	}()
+	l.callback(event)
+}
+
+// Stats returns the self-metrics of the emitter: the events passed to the
+// listeners, by type and in total, the events dropped by transformers,
+// the panicking listener calls, and the average time spent calling the
+// listeners of an event.
+//
+// Returns:
+//   - EmitterStats: The stats snapshot.
+func (e *DefaultEventEmitter) Stats() EmitterStats {
+	return e.stats.snapshot()
+}
+
+// EmitCtx emits an event originating from ctx, which listeners read with
+// Event.Context.
+//
+// Parameters:
+//   - ctx: The originating context.
+//   - event: The event to emit.
+func (e *DefaultEventEmitter) EmitCtx(ctx context.Context, event *Event) {
+	if event == nil {
+		return
+	}
+	e.Emit(event.WithContext(ctx))
+}
+
+// newID returns a listener ID unique within the emitter. The caller must
+// hold the lock.
+func (e *DefaultEventEmitter) newID() string {
+	e.nextID++
+	return strconv.FormatUint(e.nextID, 10)
+}
+
+// removeListener returns a copy of ls without the listener with the ID.
+func removeListener(ls []listener, id string) []listener {
+	i := slices.IndexFunc(ls, func(l listener) bool { return l.id == id })
+	if i < 0 {
+		return ls
+	}
+	return slices.Delete(slices.Clone(ls), i, i+1)
+}
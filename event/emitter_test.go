@@ -0,0 +1,197 @@
+package event
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEventEmitterListeners tests that typed listeners receive events of
+// their type only and global listeners receive all events, in order.
+func TestEventEmitterListeners(t *testing.T) {
+	e := NewEventEmitter()
+	var calls []string
+	e.AddListener("a", func(ev *Event) { calls = append(calls, "a1:"+ev.Message) })
+	e.AddGlobalListener(func(ev *Event) { calls = append(calls, "g:"+ev.Message) })
+	e.RegisterListener("a", func(ev *Event) { calls = append(calls, "a2:"+ev.Message) }).
+		RegisterListener("b", func(ev *Event) { calls = append(calls, "b:"+ev.Message) })
+
+	e.Emit(NewEvent("a", "x"))
+	e.Emit(NewEvent("b", "y"))
+	e.Emit(NewEvent("c", "z"))
+	e.Emit(nil)
+
+	assert.Equal(t, []string{"a1:x", "a2:x", "g:x", "b:y", "g:y", "g:z"}, calls)
+}
+
+// TestEventEmitterRemoveListener tests that listeners are removed by ID
+// and type.
+func TestEventEmitterRemoveListener(t *testing.T) {
+	e := NewEventEmitter()
+	var calls []string
+	id1 := e.AddListener("a", func(*Event) { calls = append(calls, "a1") })
+	id2 := e.AddListener("a", func(*Event) { calls = append(calls, "a2") })
+	gid := e.AddGlobalListener(func(*Event) { calls = append(calls, "g") })
+	assert.NotEqual(t, id1, id2)
+	assert.NotEqual(t, id2, gid)
+
+	e.RemoveListener("b", id1)
+	e.RemoveListener("a", "unknown")
+	e.RemoveGlobalListener(id1)
+	e.Emit(NewEvent("a", ""))
+	assert.Equal(t, []string{"a1", "a2", "g"}, calls)
+
+	calls = nil
+	e.RemoveListener("a", id1)
+	e.RemoveGlobalListener(gid)
+	e.Emit(NewEvent("a", ""))
+	assert.Equal(t, []string{"a2"}, calls)
+
+	calls = nil
+	e.RemoveListener("a", id2)
+	e.Emit(NewEvent("a", ""))
+	assert.Empty(t, calls)
+}
+
+// TestEventEmitterRemoveDuringEmit tests that a listener removing itself
+// does not affect the event being emitted.
+func TestEventEmitterRemoveDuringEmit(t *testing.T) {
+	e := NewEventEmitter()
+	count := 0
+	var id string
+	id = e.AddListener("a", func(*Event) {
+		count++
+		e.RemoveListener("a", id)
+	})
+	e.AddListener("a", func(*Event) { count++ })
+
+	e.Emit(NewEvent("a", ""))
+	assert.Equal(t, 2, count)
+	e.Emit(NewEvent("a", ""))
+	assert.Equal(t, 3, count)
+}
+
+// TestEventEmitterConcurrent tests concurrent registration and emission.
+func TestEventEmitterConcurrent(t *testing.T) {
+	e := NewEventEmitter()
+	var mu sync.Mutex
+	count := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := e.AddListener("a", func(*Event) {
+				mu.Lock()
+				count++
+				mu.Unlock()
+			})
+			e.Emit(NewEvent("a", ""))
+			e.RemoveListener("a", id)
+		}()
+	}
+	wg.Wait()
+	assert.GreaterOrEqual(t, count, 10)
+	e.Emit(NewEvent("a", ""))
+	assert.GreaterOrEqual(t, count, 10)
+}
+
+// TestMatchEventType tests wildcard pattern matching.
+func TestMatchEventType(t *testing.T) {
+	assert.True(t, MatchEventType("*", "event_start"))
+	assert.True(t, MatchEventType("event_shutdown*", "event_shutdown"))
+	assert.True(t, MatchEventType("event_shutdown*", "event_shutdown_started"))
+	assert.False(t, MatchEventType("event_shutdown*", "event_start"))
+	assert.True(t, MatchEventType("event_start", "event_start"))
+	assert.False(t, MatchEventType("event_start", "event_start_x"))
+}
+
+// TestEventEmitterPatternListeners tests that pattern listeners receive
+// matching events and are removed by pattern.
+func TestEventEmitterPatternListeners(t *testing.T) {
+	e := NewEventEmitter()
+	var calls []string
+	e.AddGlobalListener(func(ev *Event) { calls = append(calls, "g") })
+	id := e.AddListener("event_shutdown*", func(ev *Event) {
+		calls = append(calls, "prefix:"+string(ev.Type))
+	})
+	e.AddListener("*", func(ev *Event) { calls = append(calls, "all") })
+	e.AddListener("event_shutdown", func(ev *Event) { calls = append(calls, "exact") })
+
+	e.Emit(NewEvent("event_shutdown", ""))
+	e.Emit(NewEvent("event_shutdown_started", ""))
+	e.Emit(NewEvent("event_start", ""))
+	assert.Equal(t, []string{
+		"exact", "all", "prefix:event_shutdown", "g",
+		"all", "prefix:event_shutdown_started", "g",
+		"all", "g",
+	}, calls)
+
+	calls = nil
+	e.RemoveListener("event_shutdown", id)
+	e.Emit(NewEvent("event_shutdown_started", ""))
+	assert.Equal(t, []string{"all", "prefix:event_shutdown_started", "g"}, calls)
+
+	calls = nil
+	e.RemoveListener("event_shutdown*", id)
+	e.Emit(NewEvent("event_shutdown_started", ""))
+	assert.Equal(t, []string{"all", "g"}, calls)
+}
+
+// TestEventEmitterPriority tests that listeners are called by priority,
+// then in registration order.
+func TestEventEmitterPriority(t *testing.T) {
+	e := NewEventEmitter()
+	var calls []string
+	add := func(name string, opts ...ListenerOption) {
+		e.AddListener("a", func(*Event) { calls = append(calls, name) }, opts...)
+	}
+	add("default1")
+	add("low", WithPriority(-1))
+	add("high", WithPriority(10))
+	add("default2")
+	add("mid", WithPriority(5))
+	e.AddGlobalListener(func(*Event) { calls = append(calls, "g-low") })
+	e.AddGlobalListener(func(*Event) { calls = append(calls, "g-high") }, WithPriority(1))
+
+	e.Emit(NewEvent("a", ""))
+	assert.Equal(t, []string{
+		"high", "mid", "default1", "default2", "low", "g-high", "g-low",
+	}, calls)
+}
+
+// TestEventEmitterOnce tests that once listeners are called for their
+// first event only, even when events are emitted concurrently.
+func TestEventEmitterOnce(t *testing.T) {
+	e := NewEventEmitter()
+	var typed, pattern, global, every int
+	e.AddListener("a", func(*Event) { typed++ }, Once())
+	e.AddListener("a*", func(*Event) { pattern++ }, Once())
+	e.AddGlobalListener(func(*Event) { global++ }, Once(), WithPriority(1))
+	id := e.AddGlobalListener(func(*Event) { every++ })
+
+	e.Emit(NewEvent("b", ""))
+	e.Emit(NewEvent("a", ""))
+	e.Emit(NewEvent("a", ""))
+	assert.Equal(t, []int{1, 1, 1, 3}, []int{typed, pattern, global, every})
+	e.RemoveGlobalListener(id)
+
+	var mu sync.Mutex
+	count := 0
+	e.AddListener("c", func(*Event) {
+		mu.Lock()
+		count++
+		mu.Unlock()
+	}, Once())
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.Emit(NewEvent("c", ""))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, count)
+}
@@ -1,5 +1,13 @@
 package event
 
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
 // EventType represents the type of event.
 type EventType string
 
@@ -8,8 +16,16 @@ type Event struct {
 	Type    EventType
 	Message string
 	Data    any
+	Time    time.Time // Creation time, with a monotonic clock reading.
+	Seq     uint64    // Process-wide creation sequence number, from 1.
+	Source  string    // Import path of the package creating the event.
+
+	ctx context.Context
 }
 
+// eventSeq is the sequence number of the last event created.
+var eventSeq atomic.Uint64
+
 // WithData sets the data of the event. It returns a new event with the data
 // set.
 //
@@ -36,7 +52,79 @@ type EventEmitter interface {
 	RemoveGlobalListener(id string)
 }
 
-// NewEvent creates a new event.
+// ContextEmitter is implemented by emitters handling the context of events
+// themselves. Emitters without it get the context through EmitCtx, stored
+// in the event. Types embedding an emitter with EmitCtx and overriding Emit
+// must override EmitCtx too, or context events bypass their Emit.
+type ContextEmitter interface {
+	EmitCtx(ctx context.Context, event *Event)
+}
+
+// EmitCtx emits an event originating from ctx, e.g. a request context, so
+// listeners can read it with Event.Context. It calls the EmitCtx method of
+// emitters implementing ContextEmitter, and otherwise emits the event with
+// the context set, so it works with every emitter. Nil events are ignored.
+//
+// Parameters:
+//   - emitter: The emitter.
+//   - ctx: The originating context.
+//   - event: The event to emit.
+func EmitCtx(emitter EventEmitter, ctx context.Context, event *Event) {
+	if event == nil {
+		return
+	}
+	if ce, ok := emitter.(ContextEmitter); ok {
+		ce.EmitCtx(ctx, event)
+		return
+	}
+	emitter.Emit(event.WithContext(ctx))
+}
+
+// WithSource sets the source of the event, e.g. a component name within a
+// package. It returns a new event with the source set.
+//
+// Parameters:
+//   - source: The source to set.
+//
+// Returns:
+//   - *Event: A new Event instance with the source set.
+func (event *Event) WithSource(source string) *Event {
+	new := *event
+	new.Source = source
+	return &new
+}
+
+// WithContext sets the context the event originates from, e.g. that of the
+// request being served. It returns a new event with the context set.
+//
+// Parameters:
+//   - ctx: The context to set.
+//
+// Returns:
+//   - *Event: A new Event instance with the context set.
+func (event *Event) WithContext(ctx context.Context) *Event {
+	new := *event
+	new.ctx = ctx
+	return &new
+}
+
+// Context returns the context the event originates from, so listeners can
+// read request IDs, trace IDs and deadlines. It is context.Background() for
+// events emitted without one.
+//
+// Returns:
+//   - context.Context: The event context.
+func (event *Event) Context() context.Context {
+	if event.ctx == nil {
+		return context.Background()
+	}
+	return event.ctx
+}
+
+// NewEvent creates a new event. The creation time, the next sequence
+// number and the import path of the calling package are set, so consumers
+// can order and correlate events: the sequence orders events created
+// within a process even when their times are equal.
 //
 // Parameters:
 //   - eventType: The type of the event.
@@ -45,11 +133,41 @@ type EventEmitter interface {
 // Returns:
 //   - *Event: A new Event instance.
 func NewEvent(eventType EventType, message string) *Event {
+	return newEvent(eventType, message, 2)
+}
+
+// newEvent creates an event whose source is the package of the function
+// skip frames up the stack.
+func newEvent(eventType EventType, message string, skip int) *Event {
 	return &Event{
 		Type:    eventType,
 		Message: message,
 		Data:    nil,
+		Time:    time.Now(),
+		Seq:     eventSeq.Add(1),
+		Source:  callerPackage(skip + 1),
+	}
+}
+
+// callerPackage returns the import path of the package of the function
+// skip frames up the stack, or "" if unknown.
+func callerPackage(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return ""
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+	// Function names are the package path, a dot, then the function,
+	// e.g. "github.com/org/mod/pkg.(*T).Method".
+	name := fn.Name()
+	slash := strings.LastIndexByte(name, '/')
+	if dot := strings.IndexByte(name[slash+1:], '.'); dot >= 0 {
+		return name[:slash+1+dot]
 	}
+	return name
 }
 
 // NoopEventEmitter is a no-op implementation of EventEmitter.
@@ -88,10 +206,3 @@ func NewEmitterLogger(eventEmitter EventEmitter,
 	loggerFactoryFn func(params ...any) any) EventEmitter {
 	return NewNoopEventEmitter()
 }
-
-// NewEventEmitter creates a new event emitter.
-// This is a placeholder function that returns a noop emitter.
-// In a real implementation, this would create a proper event emitter.
-func NewEventEmitter() EventEmitter {
-	return NewNoopEventEmitter()
-}
@@ -0,0 +1,55 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewEvent tests that events get a time, an increasing sequence number
+// and the calling package as source.
+func TestNewEvent(t *testing.T) {
+	before := time.Now()
+	a := NewEvent("a", "first")
+	b := NewEvent("b", "second").WithData(1)
+
+	assert.False(t, a.Time.Before(before))
+	assert.False(t, b.Time.Before(a.Time))
+	assert.Greater(t, a.Seq, uint64(0))
+	assert.Greater(t, b.Seq, a.Seq)
+	assert.Equal(t, "github.com/aatuh/pureapi-core/event", a.Source)
+	assert.Equal(t, a.Seq+1, b.Seq)
+
+	c := b.WithSource("event/test")
+	assert.Equal(t, "event/test", c.Source)
+	assert.Equal(t, b.Seq, c.Seq)
+	assert.Equal(t, "github.com/aatuh/pureapi-core/event", b.Source)
+}
+
+// TestSimpleSeverityEmitterSource tests that severity events get the
+// source of the calling package and their creation time.
+func TestSimpleSeverityEmitterSource(t *testing.T) {
+	var got []*Event
+	e := NewEventEmitter()
+	e.AddGlobalListener(func(ev *Event) { got = append(got, ev) })
+	s := NewSimpleSeverityEmitter(e)
+	s.EmitInfo("a", "info")
+	s.EmitWithSeverity("a", "custom", "notice")
+
+	if assert.Len(t, got, 2) {
+		for _, ev := range got {
+			assert.Equal(t, "github.com/aatuh/pureapi-core/event", ev.Source)
+			assert.NotZero(t, ev.Seq)
+		}
+		data := got[0].Data.(map[string]any)
+		assert.Equal(t, "info", data["severity"])
+		assert.Equal(t, got[0].Time.Format(time.RFC3339Nano), data["timestamp"])
+	}
+}
+
+// TestCallerPackage tests package extraction from function names.
+func TestCallerPackage(t *testing.T) {
+	assert.Equal(t, "github.com/aatuh/pureapi-core/event", callerPackage(1))
+	assert.Equal(t, "", callerPackage(100))
+}
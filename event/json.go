@@ -0,0 +1,57 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// jsonEvent is the JSON form of an event.
+type jsonEvent struct {
+	Type    EventType  `json:"type"`
+	Message string     `json:"message"`
+	Data    any        `json:"data,omitempty"`
+	Time    *time.Time `json:"time,omitempty"`
+	Seq     uint64     `json:"seq,omitempty"`
+	Source  string     `json:"source,omitempty"`
+}
+
+// EncodeJSON encodes an event as a JSON object with the keys "type",
+// "message", "data", "time", "seq" and "source", the last four omitted
+// when unset. Errors in the data are written as their messages, since
+// most errors encode as empty objects; data that cannot be encoded as
+// JSON is written with fmt.Sprint, so encoding never fails.
+//
+// Parameters:
+//   - event: The event to encode.
+//
+// Returns:
+//   - json.RawMessage: The encoded event.
+func EncodeJSON(event *Event) json.RawMessage {
+	je := jsonEvent{
+		Type: event.Type, Message: event.Message, Data: event.Data,
+		Seq: event.Seq, Source: event.Source,
+	}
+	if !event.Time.IsZero() {
+		je.Time = &event.Time
+	}
+	switch data := event.Data.(type) {
+	case error:
+		je.Data = data.Error()
+	case map[string]any:
+		safe := make(map[string]any, len(data))
+		for k, v := range data {
+			if err, ok := v.(error); ok {
+				v = err.Error()
+			}
+			safe[k] = v
+		}
+		je.Data = safe
+	}
+	b, err := json.Marshal(je)
+	if err != nil {
+		je.Data = fmt.Sprint(event.Data)
+		b, _ = json.Marshal(je)
+	}
+	return b
+}
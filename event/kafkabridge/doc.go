@@ -0,0 +1,24 @@
+// Package kafkabridge publishes events to Kafka topics as JSON, for
+// inter-service eventing built on the event.EventEmitter interface.
+//
+// The package does not depend on a Kafka client: it writes through the
+// Producer interface, which takes a few lines to implement with the client
+// of your choice, e.g. github.com/segmentio/kafka-go:
+//
+//	type producer struct{ w *kafka.Writer }
+//
+//	func (p producer) Produce(
+//		ctx context.Context, topic string, key, value []byte,
+//	) error {
+//		return p.w.WriteMessages(ctx, kafka.Message{
+//			Topic: topic, Key: key, Value: value,
+//		})
+//	}
+//
+//	emitter := kafkabridge.NewEmitter(producer{w: &kafka.Writer{
+//		Addr: kafka.TCP("localhost:9092"), Async: true,
+//	}})
+//
+// Events are produced in the goroutine emitting them, so use an
+// asynchronous producer to keep slow brokers out of request latency.
+package kafkabridge
@@ -0,0 +1,171 @@
+package kafkabridge
+
+import (
+	"context"
+	"maps"
+
+	"github.com/aatuh/pureapi-core/event"
+)
+
+// Producer writes a message to a Kafka topic.
+type Producer interface {
+	Produce(ctx context.Context, topic string, key, value []byte) error
+}
+
+// Option configures an Emitter.
+type Option func(*config)
+
+// config holds the emitter settings.
+type config struct {
+	topic   string
+	topics  map[event.EventType]string
+	key     func(ev *event.Event) []byte
+	onError func(ev *event.Event, err error)
+}
+
+// WithTopic sets the topic of event types without an explicit topic,
+// "events" by default.
+//
+// Parameters:
+//   - topic: The default topic.
+//
+// Returns:
+//   - Option: The option.
+func WithTopic(topic string) Option {
+	return func(c *config) { c.topic = topic }
+}
+
+// WithTopics maps event types to topics, overriding the default topic.
+//
+// Parameters:
+//   - topics: The topics by event type.
+//
+// Returns:
+//   - Option: The option.
+func WithTopics(topics map[event.EventType]string) Option {
+	return func(c *config) { maps.Copy(c.topics, topics) }
+}
+
+// WithKey sets the function deriving the message key of events, which
+// decides their partition. By default the key is the event type, so
+// events of a type keep their order.
+//
+// Parameters:
+//   - fn: The key function.
+//
+// Returns:
+//   - Option: The option.
+func WithKey(fn func(ev *event.Event) []byte) Option {
+	return func(c *config) { c.key = fn }
+}
+
+// WithErrorHandler sets the callback of failed writes. Without it,
+// failures are dropped.
+//
+// Parameters:
+//   - fn: The error callback.
+//
+// Returns:
+//   - Option: The option.
+func WithErrorHandler(fn func(ev *event.Event, err error)) Option {
+	return func(c *config) { c.onError = fn }
+}
+
+// Emitter is an event.EventEmitter writing every event, encoded with
+// event.EncodeJSON, to the topic of its type. It has no listeners of its
+// own; its listener methods do nothing.
+type Emitter struct {
+	producer Producer
+	cfg      config
+}
+
+// Emitter implements event.EventEmitter and event.ContextEmitter.
+var (
+	_ event.EventEmitter   = (*Emitter)(nil)
+	_ event.ContextEmitter = (*Emitter)(nil)
+)
+
+// NewEmitter creates an emitter writing with producer.
+//
+// Parameters:
+//   - producer: The producer.
+//   - opts: Optional emitter options.
+//
+// Returns:
+//   - *Emitter: A new Emitter instance.
+func NewEmitter(producer Producer, opts ...Option) *Emitter {
+	cfg := config{
+		topic:  "events",
+		topics: map[event.EventType]string{},
+		key:    func(ev *event.Event) []byte { return []byte(ev.Type) },
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Emitter{producer: producer, cfg: cfg}
+}
+
+// Topic returns the topic events of a type are written to.
+//
+// Parameters:
+//   - eventType: The event type.
+//
+// Returns:
+//   - string: The topic.
+func (e *Emitter) Topic(eventType event.EventType) string {
+	if topic, ok := e.cfg.topics[eventType]; ok {
+		return topic
+	}
+	return e.cfg.topic
+}
+
+// Emit writes the event with its context. Nil events are ignored.
+//
+// Parameters:
+//   - ev: The event to write.
+func (e *Emitter) Emit(ev *event.Event) {
+	if ev == nil {
+		return
+	}
+	e.EmitCtx(ev.Context(), ev)
+}
+
+// EmitCtx writes an event originating from ctx. The producer gets ctx
+// without its cancellation, so events emitted as a request ends are still
+// written. Nil events are ignored.
+//
+// Parameters:
+//   - ctx: The originating context.
+//   - ev: The event to write.
+func (e *Emitter) EmitCtx(ctx context.Context, ev *event.Event) {
+	if ev == nil {
+		return
+	}
+	err := e.producer.Produce(
+		context.WithoutCancel(ctx), e.Topic(ev.Type), e.cfg.key(ev),
+		event.EncodeJSON(ev),
+	)
+	if err != nil && e.cfg.onError != nil {
+		e.cfg.onError(ev, err)
+	}
+}
+
+// RegisterListener does nothing.
+func (e *Emitter) RegisterListener(
+	eventType event.EventType, callback event.EventCallback,
+) event.EventEmitter {
+	return e
+}
+
+// RemoveListener does nothing.
+func (e *Emitter) RemoveListener(eventType event.EventType, id string) {}
+
+// RegisterGlobalListener does nothing.
+func (e *Emitter) RegisterGlobalListener(
+	callback event.EventCallback,
+) event.EventEmitter {
+	return e
+}
+
+// RemoveGlobalListener does nothing.
+func (e *Emitter) RemoveGlobalListener(id string) {}
@@ -0,0 +1,82 @@
+package kafkabridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ctxKey is a test context key.
+type ctxKey struct{}
+
+// message is a produced message.
+type message struct {
+	ctx   context.Context
+	topic string
+	key   string
+	value []byte
+}
+
+// fakeProducer records produced messages.
+type fakeProducer struct {
+	messages []message
+	err      error
+}
+
+// Produce records a message.
+func (f *fakeProducer) Produce(
+	ctx context.Context, topic string, key, value []byte,
+) error {
+	f.messages = append(f.messages, message{
+		ctx: ctx, topic: topic, key: string(key), value: value,
+	})
+	return f.err
+}
+
+// TestEmitter tests that events are written as JSON to their topics with
+// their context.
+func TestEmitter(t *testing.T) {
+	p := &fakeProducer{}
+	e := NewEmitter(p, WithTopic("app-events"),
+		WithTopics(map[event.EventType]string{"event_panic": "alerts"}))
+
+	ctx, cancel := context.WithCancel(
+		context.WithValue(context.Background(), ctxKey{}, "trace-1"),
+	)
+	cancel()
+	event.EmitCtx(e, ctx, event.NewEvent("event_handled", "ok"))
+	e.Emit(event.NewEvent("event_panic", "boom"))
+	e.Emit(nil)
+
+	require.Len(t, p.messages, 2)
+	m := p.messages[0]
+	assert.Equal(t, "app-events", m.topic)
+	assert.Equal(t, "event_handled", m.key)
+	assert.Equal(t, "trace-1", m.ctx.Value(ctxKey{}))
+	assert.NoError(t, m.ctx.Err())
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(m.value, &got))
+	assert.Equal(t, "ok", got["message"])
+	assert.Equal(t, "alerts", p.messages[1].topic)
+}
+
+// TestEmitterKeyAndErrors tests custom keys and write failures.
+func TestEmitterKeyAndErrors(t *testing.T) {
+	p := &fakeProducer{err: errors.New("broker down")}
+	var failed []error
+	e := NewEmitter(p,
+		WithKey(func(ev *event.Event) []byte { return []byte(ev.Message) }),
+		WithErrorHandler(func(ev *event.Event, err error) {
+			failed = append(failed, err)
+		}),
+	)
+	e.Emit(event.NewEvent("a", "order-1"))
+	assert.Equal(t, "order-1", p.messages[0].key)
+	assert.Equal(t, "events", p.messages[0].topic)
+	assert.Equal(t, []error{p.err}, failed)
+}
@@ -0,0 +1,146 @@
+package event
+
+import (
+	"context"
+	"slices"
+)
+
+// PanicHandler observes a panic raised by a target of a MultiEmitter.
+type PanicHandler func(target EventEmitter, event *Event, recovered any)
+
+// MultiEmitter fans out every event to several emitters, e.g. a logger, a
+// metrics counter and a ring buffer. A target panicking while handling an
+// event does not keep the event from the other targets, nor reach the
+// emitting code; the panic is passed to the OnPanic handler, if any, and
+// dropped otherwise.
+type MultiEmitter struct {
+	targets []EventEmitter
+	onPanic PanicHandler
+}
+
+// MultiEmitter implements EventEmitter and ContextEmitter.
+var (
+	_ EventEmitter   = (*MultiEmitter)(nil)
+	_ ContextEmitter = (*MultiEmitter)(nil)
+)
+
+// Multi creates an emitter fanning out events to the targets, in order.
+// Listeners registered on it are registered on the first target:
+//
+//	emitter := event.Multi(event.NewEventEmitter(), counter, sink)
+//
+// Parameters:
+//   - emitters: The target emitters. Nil emitters are skipped.
+//
+// Returns:
+//   - *MultiEmitter: A new MultiEmitter instance.
+func Multi(emitters ...EventEmitter) *MultiEmitter {
+	return &MultiEmitter{
+		targets: slices.DeleteFunc(slices.Clone(emitters), func(e EventEmitter) bool {
+			return e == nil
+		}),
+	}
+}
+
+// OnPanic sets the handler of panics raised by targets, e.g. to log them.
+//
+// Parameters:
+//   - handler: The panic handler.
+//
+// Returns:
+//   - *MultiEmitter: The emitter, for chaining.
+func (m *MultiEmitter) OnPanic(handler PanicHandler) *MultiEmitter {
+	m.onPanic = handler
+	return m
+}
+
+// Emit passes the event to every target.
+//
+// Parameters:
+//   - event: The event to emit.
+func (m *MultiEmitter) Emit(event *Event) {
+	if event == nil {
+		return
+	}
+	m.EmitCtx(event.Context(), event)
+}
+
+// EmitCtx passes an event originating from ctx to every target.
+//
+// Parameters:
+//   - ctx: The originating context.
+//   - event: The event to emit.
+func (m *MultiEmitter) EmitCtx(ctx context.Context, event *Event) {
+	if event == nil {
+		return
+	}
+	for _, target := range m.targets {
+		m.emit(target, ctx, event)
+	}
+}
+
+// emit passes an event to one target, recovering its panics.
+func (m *MultiEmitter) emit(
+	target EventEmitter, ctx context.Context, event *Event,
+) {
+	defer func() {
+		if p := recover(); p != nil && m.onPanic != nil {
+			m.onPanic(target, event, p)
+		}
+	}()
+	EmitCtx(target, ctx, event)
+}
+
+// RegisterListener registers a listener on the first target.
+//
+// Parameters:
+//   - eventType: The event type or pattern to listen to.
+//   - callback: The callback.
+//
+// Returns:
+//   - EventEmitter: The multi emitter, for chaining.
+func (m *MultiEmitter) RegisterListener(
+	eventType EventType, callback EventCallback,
+) EventEmitter {
+	if len(m.targets) > 0 {
+		m.targets[0].RegisterListener(eventType, callback)
+	}
+	return m
+}
+
+// RemoveListener removes a listener from the first target.
+//
+// Parameters:
+//   - eventType: The event type or pattern the listener was registered for.
+//   - id: The listener ID.
+func (m *MultiEmitter) RemoveListener(eventType EventType, id string) {
+	if len(m.targets) > 0 {
+		m.targets[0].RemoveListener(eventType, id)
+	}
+}
+
+// RegisterGlobalListener registers a global listener on the first target.
+//
+// Parameters:
+//   - callback: The callback.
+//
+// Returns:
+//   - EventEmitter: The multi emitter, for chaining.
+func (m *MultiEmitter) RegisterGlobalListener(
+	callback EventCallback,
+) EventEmitter {
+	if len(m.targets) > 0 {
+		m.targets[0].RegisterGlobalListener(callback)
+	}
+	return m
+}
+
+// RemoveGlobalListener removes a global listener from the first target.
+//
+// Parameters:
+//   - id: The listener ID.
+func (m *MultiEmitter) RemoveGlobalListener(id string) {
+	if len(m.targets) > 0 {
+		m.targets[0].RemoveGlobalListener(id)
+	}
+}
@@ -0,0 +1,65 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// panickingEmitter panics on every event.
+type panickingEmitter struct{ NoopEventEmitter }
+
+// Emit panics.
+func (panickingEmitter) Emit(*Event) { panic("target down") }
+
+// TestMulti tests that events reach every target, in spite of panics.
+func TestMulti(t *testing.T) {
+	first, last := &plainEmitter{}, NewEventEmitter()
+	var got []any
+	last.AddGlobalListener(func(ev *Event) {
+		got = append(got, ev.Context().Value(ctxKey{}))
+	})
+	bad := &panickingEmitter{}
+	var panics []any
+	m := Multi(first, nil, bad, last).OnPanic(
+		func(target EventEmitter, ev *Event, recovered any) {
+			assert.Same(t, bad, target)
+			panics = append(panics, recovered)
+		},
+	)
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "v")
+	assert.NotPanics(t, func() {
+		m.Emit(NewEvent("a", ""))
+		EmitCtx(m, ctx, NewEvent("a", ""))
+		m.Emit(nil)
+	})
+	assert.Len(t, first.events, 2)
+	assert.Equal(t, "v", first.events[1].Context().Value(ctxKey{}))
+	assert.Equal(t, []any{nil, "v"}, got)
+	assert.Equal(t, []any{"target down", "target down"}, panics)
+
+	assert.NotPanics(t, func() { Multi(bad).Emit(NewEvent("a", "")) })
+}
+
+// TestMultiListeners tests that listeners are registered on the first
+// target.
+func TestMultiListeners(t *testing.T) {
+	first, second := NewEventEmitter(), NewEventEmitter()
+	m := Multi(first, second)
+	calls := 0
+	m.RegisterListener("a", func(*Event) { calls++ }).
+		RegisterGlobalListener(func(*Event) { calls++ })
+	m.Emit(NewEvent("a", ""))
+	assert.Equal(t, 2, calls)
+
+	m.RemoveListener("a", "1")
+	m.RemoveGlobalListener("2")
+	m.Emit(NewEvent("a", ""))
+	assert.Equal(t, 2, calls)
+
+	assert.NotPanics(t, func() {
+		Multi().RegisterListener("a", func(*Event) {}).Emit(NewEvent("a", ""))
+	})
+}
@@ -0,0 +1,15 @@
+// Package natsbridge publishes events to NATS subjects as JSON, for
+// inter-service eventing built on the event.EventEmitter interface.
+//
+// The package does not depend on a NATS client: it publishes through the
+// Publisher interface, which *nats.Conn of github.com/nats-io/nats.go
+// implements as is.
+//
+// Example:
+//
+//	nc, _ := nats.Connect(nats.DefaultURL)
+//	emitter := event.Multi(
+//		event.NewEventEmitter(),
+//		natsbridge.NewEmitter(nc, natsbridge.WithSubjectPrefix("orders.events.")),
+//	)
+package natsbridge
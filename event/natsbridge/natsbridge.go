@@ -0,0 +1,133 @@
+package natsbridge
+
+import (
+	"maps"
+
+	"github.com/aatuh/pureapi-core/event"
+)
+
+// Publisher publishes a message to a NATS subject.
+type Publisher interface {
+	Publish(subject string, data []byte) error
+}
+
+// Option configures an Emitter.
+type Option func(*config)
+
+// config holds the emitter settings.
+type config struct {
+	prefix   string
+	subjects map[event.EventType]string
+	onError  func(ev *event.Event, err error)
+}
+
+// WithSubjectPrefix sets the prefix of the subjects of event types without
+// an explicit subject, "events." by default: events of type
+// "event_panic" are published to "events.event_panic".
+//
+// Parameters:
+//   - prefix: The subject prefix.
+//
+// Returns:
+//   - Option: The option.
+func WithSubjectPrefix(prefix string) Option {
+	return func(c *config) { c.prefix = prefix }
+}
+
+// WithSubjects maps event types to subjects, overriding the prefix.
+//
+// Parameters:
+//   - subjects: The subjects by event type.
+//
+// Returns:
+//   - Option: The option.
+func WithSubjects(subjects map[event.EventType]string) Option {
+	return func(c *config) { maps.Copy(c.subjects, subjects) }
+}
+
+// WithErrorHandler sets the callback of failed publishes. Without it,
+// failures are dropped.
+//
+// Parameters:
+//   - fn: The error callback.
+//
+// Returns:
+//   - Option: The option.
+func WithErrorHandler(fn func(ev *event.Event, err error)) Option {
+	return func(c *config) { c.onError = fn }
+}
+
+// Emitter is an event.EventEmitter publishing every event, encoded with
+// event.EncodeJSON, to the subject of its type. It has no listeners of its
+// own; its listener methods do nothing.
+type Emitter struct {
+	pub Publisher
+	cfg config
+}
+
+// Emitter implements event.EventEmitter.
+var _ event.EventEmitter = (*Emitter)(nil)
+
+// NewEmitter creates an emitter publishing with pub.
+//
+// Parameters:
+//   - pub: The publisher, e.g. a *nats.Conn.
+//   - opts: Optional emitter options.
+//
+// Returns:
+//   - *Emitter: A new Emitter instance.
+func NewEmitter(pub Publisher, opts ...Option) *Emitter {
+	cfg := config{prefix: "events.", subjects: map[event.EventType]string{}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Emitter{pub: pub, cfg: cfg}
+}
+
+// Subject returns the subject events of a type are published to.
+//
+// Parameters:
+//   - eventType: The event type.
+//
+// Returns:
+//   - string: The subject.
+func (e *Emitter) Subject(eventType event.EventType) string {
+	if subject, ok := e.cfg.subjects[eventType]; ok {
+		return subject
+	}
+	return e.cfg.prefix + string(eventType)
+}
+
+// Emit publishes the event. Nil events are ignored.
+//
+// Parameters:
+//   - ev: The event to publish.
+func (e *Emitter) Emit(ev *event.Event) {
+	if ev == nil {
+		return
+	}
+	err := e.pub.Publish(e.Subject(ev.Type), event.EncodeJSON(ev))
+	if err != nil && e.cfg.onError != nil {
+		e.cfg.onError(ev, err)
+	}
+}
+
+// RegisterListener does nothing.
+func (e *Emitter) RegisterListener(
+	eventType event.EventType, callback event.EventCallback,
+) event.EventEmitter {
+	return e
+}
+
+// RemoveListener does nothing.
+func (e *Emitter) RemoveListener(eventType event.EventType, id string) {}
+
+// RegisterGlobalListener does nothing.
+func (e *Emitter) RegisterGlobalListener(
+	callback event.EventCallback,
+) event.EventEmitter {
+	return e
+}
+
+// RemoveGlobalListener does nothing.
+func (e *Emitter) RemoveGlobalListener(id string) {}
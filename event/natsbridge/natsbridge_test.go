@@ -0,0 +1,62 @@
+package natsbridge
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// message is a published message.
+type message struct {
+	subject string
+	data    []byte
+}
+
+// fakePublisher records published messages.
+type fakePublisher struct {
+	messages []message
+	err      error
+}
+
+// Publish records a message.
+func (f *fakePublisher) Publish(subject string, data []byte) error {
+	f.messages = append(f.messages, message{subject: subject, data: data})
+	return f.err
+}
+
+// TestEmitter tests that events are published as JSON to their subjects.
+func TestEmitter(t *testing.T) {
+	pub := &fakePublisher{}
+	e := NewEmitter(pub, WithSubjectPrefix("svc."),
+		WithSubjects(map[event.EventType]string{"event_panic": "alerts.panic"}))
+
+	e.Emit(event.NewEvent("event_start", "up").WithData(map[string]any{"addr": ":80"}))
+	e.Emit(event.NewEvent("event_panic", "boom"))
+	e.Emit(nil)
+
+	require.Len(t, pub.messages, 2)
+	assert.Equal(t, "svc.event_start", pub.messages[0].subject)
+	assert.Equal(t, "alerts.panic", pub.messages[1].subject)
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(pub.messages[0].data, &got))
+	assert.Equal(t, "event_start", got["type"])
+	assert.Equal(t, "up", got["message"])
+	assert.Equal(t, map[string]any{"addr": ":80"}, got["data"])
+	assert.Equal(t, "events.x", NewEmitter(pub).Subject("x"))
+}
+
+// TestEmitterErrors tests that publish failures reach the error handler.
+func TestEmitterErrors(t *testing.T) {
+	pub := &fakePublisher{err: errors.New("disconnected")}
+	var failed []error
+	e := NewEmitter(pub, WithErrorHandler(func(ev *event.Event, err error) {
+		failed = append(failed, err)
+	}))
+	e.Emit(event.NewEvent("a", ""))
+	assert.Equal(t, []error{pub.err}, failed)
+	assert.NotPanics(t, func() { NewEmitter(pub).Emit(event.NewEvent("a", "")) })
+}
@@ -0,0 +1,128 @@
+package event
+
+import "sync"
+
+// Backpressure is the policy of an asynchronous emitter whose queue is
+// full.
+type Backpressure int
+
+// Backpressure policies.
+const (
+	// DropNewest drops the event being emitted, keeping the queued ones.
+	DropNewest Backpressure = iota
+	// DropOldest drops the oldest queued event to make room.
+	DropOldest
+	// Block makes Emit wait for room in the queue, slowing the emitting
+	// code down to the delivery rate.
+	Block
+)
+
+// String returns the name of the policy.
+//
+// Returns:
+//   - string: The policy name.
+func (b Backpressure) String() string {
+	switch b {
+	case DropNewest:
+		return "drop-newest"
+	case DropOldest:
+		return "drop-oldest"
+	case Block:
+		return "block"
+	}
+	return "unknown"
+}
+
+// eventQueue is a bounded event queue applying a backpressure policy.
+type eventQueue struct {
+	mu      sync.Mutex
+	changed *sync.Cond // Broadcast on every push, take and close.
+	items   []*Event
+	max     int
+	policy  Backpressure
+	dropped uint64
+	closed  bool
+}
+
+// newEventQueue creates a queue of at most max events.
+func newEventQueue(max int, policy Backpressure) *eventQueue {
+	q := &eventQueue{max: max, policy: policy}
+	q.changed = sync.NewCond(&q.mu)
+	return q
+}
+
+// push queues an event, applying the policy when the queue is full. It
+// returns the queue length, or 0 if the event was dropped.
+func (q *eventQueue) push(event *Event) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.policy == Block {
+		for len(q.items) >= q.max && !q.closed {
+			q.changed.Wait()
+		}
+	}
+	switch {
+	case q.closed:
+		q.dropped++
+		return 0
+	case len(q.items) < q.max:
+	case q.policy == DropOldest:
+		q.items[0] = nil
+		q.items = q.items[1:]
+		q.dropped++
+	default:
+		q.dropped++
+		return 0
+	}
+	q.items = append(q.items, event)
+	q.changed.Broadcast()
+	return len(q.items)
+}
+
+// take removes and returns all queued events.
+func (q *eventQueue) take() []*Event {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	items := q.items
+	q.items = nil
+	q.changed.Broadcast()
+	return items
+}
+
+// next removes and returns the oldest event, waiting for one to be
+// queued. It returns false if the queue is closed and empty.
+func (q *eventQueue) next() (*Event, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) == 0 && !q.closed {
+		q.changed.Wait()
+	}
+	if len(q.items) == 0 {
+		return nil, false
+	}
+	event := q.items[0]
+	q.items[0] = nil
+	q.items = q.items[1:]
+	q.changed.Broadcast()
+	return event, true
+}
+
+// close stops accepting events and wakes blocked callers. It returns
+// false if the queue was already closed.
+func (q *eventQueue) close() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return false
+	}
+	q.closed = true
+	q.changed.Broadcast()
+	return true
+}
+
+// droppedCount returns the number of dropped events.
+func (q *eventQueue) droppedCount() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
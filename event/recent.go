@@ -0,0 +1,102 @@
+package event
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// RecentEvents keeps the last events recorded in a ring buffer, so
+// operators can inspect recent panics, 404s and shutdown activity without
+// external infrastructure. It is opt-in: register Record as a listener,
+// and serve the events from an admin endpoint:
+//
+//	recent := event.NewRecentEvents(200)
+//	emitter.AddGlobalListener(recent.Record)
+//	h := server.NewHandler(emitter, debug.WithEvents("/debug/events", recent, nil))
+type RecentEvents struct {
+	mu     sync.Mutex
+	events []*Event
+	next   int
+	full   bool
+}
+
+// RecentEvents implements http.Handler.
+var _ http.Handler = (*RecentEvents)(nil)
+
+// NewRecentEvents creates a ring buffer of the last n events. It panics if
+// n is not positive, since that is a programming error.
+//
+// Parameters:
+//   - n: The number of events kept.
+//
+// Returns:
+//   - *RecentEvents: A new RecentEvents instance.
+func NewRecentEvents(n int) *RecentEvents {
+	if n <= 0 {
+		panic("event: NewRecentEvents: size must be positive")
+	}
+	return &RecentEvents{events: make([]*Event, n)}
+}
+
+// Record adds an event, replacing the oldest one when the buffer is full.
+// Nil events are ignored.
+//
+// Parameters:
+//   - event: The event to record.
+func (r *RecentEvents) Record(event *Event) {
+	if event == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[r.next] = event
+	r.next = (r.next + 1) % len(r.events)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Events returns the recorded events, oldest first.
+//
+// Returns:
+//   - []*Event: The events.
+func (r *RecentEvents) Events() []*Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]*Event(nil), r.events[:r.next]...)
+	}
+	return append(
+		append([]*Event(nil), r.events[r.next:]...), r.events[:r.next]...,
+	)
+}
+
+// ServeHTTP writes the recorded events as a JSON array, newest first. The
+// "type" query parameter keeps the events matching a type or pattern, see
+// MatchEventType, and "limit" caps their number. Events are encoded with
+// EncodeJSON.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - req: The HTTP request.
+func (r *RecentEvents) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	pattern := EventType(req.URL.Query().Get("type"))
+	limit, err := strconv.Atoi(req.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = len(r.events)
+	}
+	events := r.Events()
+	out := []json.RawMessage{}
+	for i := len(events) - 1; i >= 0 && len(out) < limit; i-- {
+		ev := events[i]
+		if pattern != "" && !MatchEventType(pattern, ev.Type) {
+			continue
+		}
+		out = append(out, EncodeJSON(ev))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(out)
+}
@@ -0,0 +1,70 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRecentEvents tests that the ring buffer keeps the last events in
+// order.
+func TestRecentEvents(t *testing.T) {
+	r := NewRecentEvents(3)
+	assert.Empty(t, r.Events())
+	for i := 1; i <= 5; i++ {
+		r.Record(NewEvent("a", fmt.Sprint(i)))
+	}
+	r.Record(nil)
+	var got []string
+	for _, ev := range r.Events() {
+		got = append(got, ev.Message)
+	}
+	assert.Equal(t, []string{"3", "4", "5"}, got)
+	assert.Panics(t, func() { NewRecentEvents(0) })
+}
+
+// TestRecentEventsServeHTTP tests the JSON output, filtering and limit.
+func TestRecentEventsServeHTTP(t *testing.T) {
+	r := NewRecentEvents(10)
+	e := NewEventEmitter()
+	e.AddGlobalListener(r.Record)
+	// Literal events have no time, sequence or source.
+	e.Emit(&Event{Type: "event_panic", Message: "boom", Data: map[string]any{
+		"panic": errors.New("nil map"),
+	}})
+	e.Emit(&Event{Type: "event_not_found", Message: "404"})
+	e.Emit(&Event{
+		Type: "event_shutdown", Message: "bye", Data: unencodableData{},
+	})
+
+	get := func(query string) string {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/"+query, nil))
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		return rec.Body.String()
+	}
+	assert.JSONEq(t, `[
+		{"type":"event_shutdown","message":"bye","data":"unencodable"},
+		{"type":"event_not_found","message":"404"}
+	]`, get("?type=event_*&limit=2"))
+	assert.JSONEq(t, `[
+		{"type":"event_panic","message":"boom","data":{"panic":"nil map"}}
+	]`, get("?type=event_panic"))
+	assert.JSONEq(t, `[]`, get("?type=other"))
+
+	ev := NewEvent("event_start", "hi")
+	e.Emit(ev)
+	assert.Contains(t, get("?limit=1"), fmt.Sprintf(
+		`"seq":%d,"source":"github.com/aatuh/pureapi-core/event"`, ev.Seq,
+	))
+}
+
+// unencodableData is event data that cannot be encoded as JSON.
+type unencodableData struct{ C chan int }
+
+// String returns the text form of the data.
+func (unencodableData) String() string { return "unencodable" }
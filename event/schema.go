@@ -0,0 +1,231 @@
+package event
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// FieldSchema describes a key of the map[string]any data of an event.
+type FieldSchema struct {
+	Name        string `json:"name"`                  // The data key.
+	Type        string `json:"type,omitempty"`        // Go type, "" for any.
+	Required    bool   `json:"required,omitempty"`    // Whether it must be set.
+	Description string `json:"description,omitempty"` // What it holds.
+}
+
+// Schema documents an event type and the data its events carry.
+type Schema struct {
+	Type        EventType     `json:"type"`             // The event type.
+	Description string        `json:"description"`      // When it is emitted.
+	Fields      []FieldSchema `json:"fields,omitempty"` // The data keys.
+}
+
+// schemas holds the schemas declared with DeclareEvent.
+var schemas = struct {
+	sync.RWMutex
+	byType map[EventType]Schema
+}{byType: map[EventType]Schema{}}
+
+// DeclareEvent declares an event type in the program-wide event catalog,
+// documenting what a service emits. Declaring a type again replaces its
+// schema. Declare types during initialization:
+//
+//	var EventOrderPlaced = event.DeclareEvent(event.Schema{
+//		Type:        "order_placed",
+//		Description: "An order was placed.",
+//		Fields: []event.FieldSchema{
+//			{Name: "order_id", Type: "string", Required: true},
+//			{Name: "total", Type: "int64"},
+//		},
+//	})
+//
+// Field types are Go type names as printed by reflect, such as "int",
+// "time.Duration" or "*endpoint.AuthFailure"; "error" accepts any error.
+//
+// Parameters:
+//   - schema: The schema.
+//
+// Returns:
+//   - EventType: The declared event type.
+func DeclareEvent(schema Schema) EventType {
+	schema.Fields = slices.Clone(schema.Fields)
+	schemas.Lock()
+	schemas.byType[schema.Type] = schema
+	schemas.Unlock()
+	return schema.Type
+}
+
+// Schemas returns the declared schemas sorted by event type.
+//
+// Returns:
+//   - []Schema: The schemas.
+func Schemas() []Schema {
+	schemas.RLock()
+	list := make([]Schema, 0, len(schemas.byType))
+	for _, s := range schemas.byType {
+		list = append(list, s)
+	}
+	schemas.RUnlock()
+	slices.SortFunc(list, func(a, b Schema) int { return cmp.Compare(a.Type, b.Type) })
+	return list
+}
+
+// LookupSchema returns the schema declared for an event type.
+//
+// Parameters:
+//   - eventType: The event type.
+//
+// Returns:
+//   - Schema: The schema.
+//   - bool: Whether a schema was declared.
+func LookupSchema(eventType EventType) (Schema, bool) {
+	schemas.RLock()
+	defer schemas.RUnlock()
+	s, ok := schemas.byType[eventType]
+	return s, ok
+}
+
+// ValidateEvent checks an event against the schema of its type: required
+// fields must be set and fields must have their declared type. Data keys
+// the schema does not list are allowed. Events of undeclared types, and
+// schemas without fields, accept any data.
+//
+// Parameters:
+//   - event: The event to check.
+//
+// Returns:
+//   - error: An error listing the problems, or nil if the event is valid.
+func ValidateEvent(event *Event) error {
+	schema, ok := LookupSchema(event.Type)
+	if !ok || len(schema.Fields) == 0 {
+		return nil
+	}
+	data, ok := event.Data.(map[string]any)
+	if !ok && event.Data != nil {
+		return fmt.Errorf(
+			"event %q: data is %T, not map[string]any", event.Type, event.Data,
+		)
+	}
+	var errs []error
+	for _, f := range schema.Fields {
+		v, ok := data[f.Name]
+		switch {
+		case !ok:
+			if f.Required {
+				errs = append(errs, fmt.Errorf("missing field %q", f.Name))
+			}
+		case !fieldTypeMatches(f.Type, v):
+			errs = append(errs, fmt.Errorf(
+				"field %q is %T, not %s", f.Name, v, f.Type,
+			))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("event %q: %w", event.Type, errors.Join(errs...))
+	}
+	return nil
+}
+
+// ValidateEvents returns a transformer checking events with ValidateEvent
+// and passing the invalid ones to onInvalid, meant for development and
+// tests, e.g. to fail a test on malformed events:
+//
+//	emitter.Use(event.ValidateEvents(func(ev *event.Event, err error) {
+//		t.Error(err)
+//	}))
+//
+// Events pass on whether valid or not.
+//
+// Parameters:
+//   - onInvalid: The callback receiving invalid events and their errors.
+//
+// Returns:
+//   - EventTransformer: The transformer.
+func ValidateEvents(onInvalid func(event *Event, err error)) EventTransformer {
+	return func(event *Event) *Event {
+		if err := ValidateEvent(event); err != nil {
+			onInvalid(event, err)
+		}
+		return event
+	}
+}
+
+// ExportSchemasJSON writes the event catalog as a JSON array of schemas.
+//
+// Parameters:
+//   - w: The destination writer.
+//
+// Returns:
+//   - error: An error if writing fails.
+func ExportSchemasJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Schemas())
+}
+
+// ExportSchemasMarkdown writes the event catalog as a Markdown document
+// with a summary table and a section per event type listing its fields.
+//
+// Parameters:
+//   - w: The destination writer.
+//
+// Returns:
+//   - error: An error if writing fails.
+func ExportSchemasMarkdown(w io.Writer) error {
+	list := Schemas()
+	var b strings.Builder
+	b.WriteString("# Events\n\n| Type | Description |\n| --- | --- |\n")
+	for _, s := range list {
+		fmt.Fprintf(&b, "| `%s` | %s |\n", s.Type, markdownCell(s.Description))
+	}
+	for _, s := range list {
+		fmt.Fprintf(&b, "\n## %s\n\n", s.Type)
+		if s.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", s.Description)
+		}
+		if len(s.Fields) == 0 {
+			continue
+		}
+		b.WriteString("| Field | Type | Required | Description |\n")
+		b.WriteString("| --- | --- | --- | --- |\n")
+		for _, f := range s.Fields {
+			typ, required := "any", "no"
+			if f.Type != "" {
+				typ = f.Type
+			}
+			if f.Required {
+				required = "yes"
+			}
+			fmt.Fprintf(&b, "| `%s` | `%s` | %s | %s |\n",
+				f.Name, typ, required, markdownCell(f.Description))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// errorType is the reflect type of the error interface.
+var errorType = reflect.TypeFor[error]()
+
+// fieldTypeMatches reports whether a data value has a declared type.
+func fieldTypeMatches(typ string, v any) bool {
+	switch typ {
+	case "", "any":
+		return true
+	case "error":
+		return v != nil && reflect.TypeOf(v).Implements(errorType)
+	}
+	return v != nil && reflect.TypeOf(v).String() == typ
+}
+
+// markdownCell escapes text for a Markdown table cell.
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,104 @@
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDeclareEvent tests declaring and listing schemas.
+func TestDeclareEvent(t *testing.T) {
+	typ := DeclareEvent(Schema{Type: "test_b", Description: "B."})
+	DeclareEvent(Schema{Type: "test_a", Description: "A."})
+	assert.Equal(t, EventType("test_b"), typ)
+
+	var types []EventType
+	for _, s := range Schemas() {
+		types = append(types, s.Type)
+	}
+	assert.Subset(t, types, []EventType{"test_a", "test_b"})
+	assert.IsIncreasing(t, types)
+
+	s, ok := LookupSchema("test_a")
+	assert.True(t, ok)
+	assert.Equal(t, "A.", s.Description)
+	_, ok = LookupSchema("test_missing")
+	assert.False(t, ok)
+}
+
+// TestValidateEvent tests validating event data against schemas.
+func TestValidateEvent(t *testing.T) {
+	DeclareEvent(Schema{
+		Type: "test_validate",
+		Fields: []FieldSchema{
+			{Name: "status", Type: "int", Required: true},
+			{Name: "duration", Type: "time.Duration"},
+			{Name: "err", Type: "error"},
+			{Name: "extra"},
+		},
+	})
+	valid := NewEvent("test_validate", "").WithData(map[string]any{
+		"status": 200, "duration": time.Second, "err": errors.New("x"),
+		"extra": []int{1}, "unlisted": true,
+	})
+	assert.NoError(t, ValidateEvent(valid))
+	assert.NoError(t, ValidateEvent(NewEvent("test_undeclared", "").WithData(1)))
+
+	err := ValidateEvent(NewEvent("test_validate", "").WithData(map[string]any{
+		"duration": 1, "err": "x",
+	}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `event "test_validate"`)
+	assert.Contains(t, err.Error(), `missing field "status"`)
+	assert.Contains(t, err.Error(), `field "duration" is int, not time.Duration`)
+	assert.Contains(t, err.Error(), `field "err" is string, not error`)
+
+	err = ValidateEvent(NewEvent("test_validate", "").WithData("text"))
+	assert.EqualError(t, err,
+		`event "test_validate": data is string, not map[string]any`)
+
+	var invalid []*Event
+	e := NewEventEmitter().Use(ValidateEvents(func(ev *Event, err error) {
+		invalid = append(invalid, ev)
+	}))
+	calls := 0
+	e.AddGlobalListener(func(*Event) { calls++ })
+	e.Emit(valid)
+	e.Emit(NewEvent("test_validate", ""))
+	assert.Equal(t, 2, calls)
+	assert.Len(t, invalid, 1)
+}
+
+// TestExportSchemas tests the JSON and Markdown catalog exports.
+func TestExportSchemas(t *testing.T) {
+	DeclareEvent(Schema{
+		Type:        "test_export",
+		Description: "Exported | piped.",
+		Fields: []FieldSchema{
+			{Name: "id", Type: "string", Required: true, Description: "The ID."},
+			{Name: "any"},
+		},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, ExportSchemasJSON(&buf))
+	var list []Schema
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &list))
+	s, ok := LookupSchema("test_export")
+	require.True(t, ok)
+	assert.Contains(t, list, s)
+
+	buf.Reset()
+	require.NoError(t, ExportSchemasMarkdown(&buf))
+	md := buf.String()
+	assert.Contains(t, md, "# Events\n\n| Type | Description |\n")
+	assert.Contains(t, md, "| `test_export` | Exported \\| piped. |\n")
+	assert.Contains(t, md, "## test_export\n\nExported | piped.\n\n"+
+		"| Field | Type | Required | Description |\n| --- | --- | --- | --- |\n"+
+		"| `id` | `string` | yes | The ID. |\n| `any` | `any` | no |  |\n")
+}
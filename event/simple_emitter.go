@@ -1,5 +1,7 @@
 package event
 
+import "time"
+
 // SimpleSeverityEmitter provides a simple way to emit events with severity
 type SimpleSeverityEmitter struct {
 	emitter EventEmitter
@@ -15,43 +17,47 @@ func NewSimpleSeverityEmitter(emitter EventEmitter) *SimpleSeverityEmitter {
 // EmitWithSeverity emits an event with severity information in the data
 func (e *SimpleSeverityEmitter) EmitWithSeverity(eventType EventType,
 	message string, severity string) {
-	event := &Event{
-		Type:    eventType,
-		Message: message,
-		Data: map[string]any{
-			"severity":  severity,
-			"timestamp": "now", // You can add actual timestamp here
-		},
+	e.emit(eventType, message, severity)
+}
+
+// emit emits an event with severity information. It must be called
+// directly by the exported methods, for the event source.
+func (e *SimpleSeverityEmitter) emit(eventType EventType, message string,
+	severity string) {
+	event := newEvent(eventType, message, 3)
+	event.Data = map[string]any{
+		"severity":  severity,
+		"timestamp": event.Time.Format(time.RFC3339Nano),
 	}
 	e.emitter.Emit(event)
 }
 
 // EmitDebug emits a debug level event
 func (e *SimpleSeverityEmitter) EmitDebug(eventType EventType, message string) {
-	e.EmitWithSeverity(eventType, message, "debug")
+	e.emit(eventType, message, "debug")
 }
 
 // EmitInfo emits an info level event
 func (e *SimpleSeverityEmitter) EmitInfo(eventType EventType, message string) {
-	e.EmitWithSeverity(eventType, message, "info")
+	e.emit(eventType, message, "info")
 }
 
 // EmitWarn emits a warning level event
 func (e *SimpleSeverityEmitter) EmitWarn(eventType EventType, message string) {
-	e.EmitWithSeverity(eventType, message, "warn")
+	e.emit(eventType, message, "warn")
 }
 
 // EmitError emits an error level event
 func (e *SimpleSeverityEmitter) EmitError(eventType EventType, message string) {
-	e.EmitWithSeverity(eventType, message, "error")
+	e.emit(eventType, message, "error")
 }
 
 // EmitFatal emits a fatal level event
 func (e *SimpleSeverityEmitter) EmitFatal(eventType EventType, message string) {
-	e.EmitWithSeverity(eventType, message, "fatal")
+	e.emit(eventType, message, "fatal")
 }
 
 // EmitTrace emits a trace level event
 func (e *SimpleSeverityEmitter) EmitTrace(eventType EventType, message string) {
-	e.EmitWithSeverity(eventType, message, "trace")
+	e.emit(eventType, message, "trace")
 }
@@ -0,0 +1,201 @@
+package event
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SlogOption configures a SlogEmitter.
+type SlogOption func(*slogConfig)
+
+// slogLevel is the level of the events matching a pattern.
+type slogLevel struct {
+	pattern EventType
+	level   slog.Level
+}
+
+// slogConfig holds the slog emitter settings.
+type slogConfig struct {
+	levels []slogLevel
+}
+
+// WithSlogLevel sets the level of events whose type matches the pattern,
+// see MatchEventType, and whose data has no severity. The first matching
+// pattern wins.
+//
+// Parameters:
+//   - pattern: The event type or pattern.
+//   - level: The log level.
+//
+// Returns:
+//   - SlogOption: The option.
+func WithSlogLevel(pattern EventType, level slog.Level) SlogOption {
+	return func(c *slogConfig) {
+		c.levels = append(c.levels, slogLevel{pattern: pattern, level: level})
+	}
+}
+
+// SlogEmitter is an EventEmitter writing every event as a log record
+// through log/slog, so services standardized on slog get structured logs
+// of the events without glue:
+//
+//	emitter := event.Multi(
+//		event.NewEventEmitter(), event.NewSlogEmitter(slog.Default()),
+//	)
+//
+// Records carry the event message, time and context, and the attributes
+// "event_type", "event_seq" and "event_source". The keys of map[string]any
+// data become attributes, sorted; other data becomes the "data" attribute.
+// The level comes from the "severity" data key, as set by
+// SimpleSeverityEmitter, then from WithSlogLevel; otherwise it is Error
+// for events with an error under the "err", "error" or "panic" key, and
+// Info for the others. A SlogEmitter has no listeners of its own; its
+// listener methods do nothing.
+type SlogEmitter struct {
+	logger *slog.Logger
+	cfg    slogConfig
+}
+
+// SlogEmitter implements EventEmitter and ContextEmitter.
+var (
+	_ EventEmitter   = (*SlogEmitter)(nil)
+	_ ContextEmitter = (*SlogEmitter)(nil)
+)
+
+// NewSlogEmitter creates an emitter logging events with logger.
+//
+// Parameters:
+//   - logger: The logger, slog.Default() if nil.
+//   - opts: Optional slog emitter options.
+//
+// Returns:
+//   - *SlogEmitter: A new SlogEmitter instance.
+func NewSlogEmitter(logger *slog.Logger, opts ...SlogOption) *SlogEmitter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	var cfg slogConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &SlogEmitter{logger: logger, cfg: cfg}
+}
+
+// Emit logs the event with its context. Nil events are ignored.
+//
+// Parameters:
+//   - event: The event to log.
+func (s *SlogEmitter) Emit(event *Event) {
+	if event == nil {
+		return
+	}
+	s.EmitCtx(event.Context(), event)
+}
+
+// EmitCtx logs an event originating from ctx, which slog handlers may read
+// trace IDs from. Nil events are ignored.
+//
+// Parameters:
+//   - ctx: The originating context.
+//   - event: The event to log.
+func (s *SlogEmitter) EmitCtx(ctx context.Context, event *Event) {
+	if event == nil {
+		return
+	}
+	level := s.level(event)
+	h := s.logger.Handler()
+	if !h.Enabled(ctx, level) {
+		return
+	}
+	t := event.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	record := slog.NewRecord(t, level, event.Message, 0)
+	record.AddAttrs(slog.String("event_type", string(event.Type)))
+	if event.Seq != 0 {
+		record.AddAttrs(slog.Uint64("event_seq", event.Seq))
+	}
+	if event.Source != "" {
+		record.AddAttrs(slog.String("event_source", event.Source))
+	}
+	switch data := event.Data.(type) {
+	case nil:
+	case map[string]any:
+		keys := make([]string, 0, len(data))
+		for k := range data {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			record.AddAttrs(slog.Any(k, data[k]))
+		}
+	default:
+		record.AddAttrs(slog.Any("data", data))
+	}
+	_ = h.Handle(ctx, record)
+}
+
+// level returns the log level of an event.
+func (s *SlogEmitter) level(event *Event) slog.Level {
+	data, _ := event.Data.(map[string]any)
+	if severity, ok := data["severity"].(string); ok {
+		if level, ok := severityLevel(severity); ok {
+			return level
+		}
+	}
+	for _, l := range s.cfg.levels {
+		if MatchEventType(l.pattern, event.Type) {
+			return l.level
+		}
+	}
+	for _, key := range []string{"err", "error", "panic"} {
+		if data[key] != nil {
+			return slog.LevelError
+		}
+	}
+	return slog.LevelInfo
+}
+
+// severityLevel maps a severity to a log level. Trace and fatal, which
+// slog lacks, are placed below Debug and above Error.
+func severityLevel(severity string) (slog.Level, bool) {
+	switch strings.ToLower(severity) {
+	case SeverityTrace:
+		return slog.LevelDebug - 4, true
+	case SeverityDebug:
+		return slog.LevelDebug, true
+	case SeverityInfo:
+		return slog.LevelInfo, true
+	case SeverityWarn:
+		return slog.LevelWarn, true
+	case SeverityError:
+		return slog.LevelError, true
+	case SeverityFatal:
+		return slog.LevelError + 4, true
+	}
+	return 0, false
+}
+
+// RegisterListener does nothing.
+func (s *SlogEmitter) RegisterListener(
+	eventType EventType, callback EventCallback,
+) EventEmitter {
+	return s
+}
+
+// RemoveListener does nothing.
+func (s *SlogEmitter) RemoveListener(eventType EventType, id string) {}
+
+// RegisterGlobalListener does nothing.
+func (s *SlogEmitter) RegisterGlobalListener(
+	callback EventCallback,
+) EventEmitter {
+	return s
+}
+
+// RemoveGlobalListener does nothing.
+func (s *SlogEmitter) RemoveGlobalListener(id string) {}
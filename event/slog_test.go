@@ -0,0 +1,94 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSlogEmitter tests that events are logged with their attributes and
+// levels.
+func TestSlogEmitter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug - 4,
+	}))
+	s := NewSlogEmitter(logger, WithSlogLevel("noisy*", slog.LevelDebug))
+
+	s.Emit(NewEvent("event_handled", "done").WithData(map[string]any{
+		"status": 200, "method": "GET",
+	}))
+	s.Emit(NewEvent("event_error", "failed").WithData(map[string]any{
+		"err": errors.New("boom"),
+	}))
+	s.Emit(NewEvent("noisy_tick", "tick").WithData(42))
+	NewSimpleSeverityEmitter(s).EmitWarn("custom", "careful")
+	NewSimpleSeverityEmitter(s).EmitTrace("custom", "detail")
+	s.Emit(nil)
+
+	var records []map[string]any
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var r map[string]any
+		require.NoError(t, dec.Decode(&r))
+		records = append(records, r)
+	}
+	require.Len(t, records, 5)
+
+	r := records[0]
+	assert.Equal(t, "INFO", r["level"])
+	assert.Equal(t, "done", r["msg"])
+	assert.Equal(t, "event_handled", r["event_type"])
+	assert.Equal(t, "github.com/aatuh/pureapi-core/event", r["event_source"])
+	assert.NotZero(t, r["event_seq"])
+	assert.NotEmpty(t, r["time"])
+	assert.Equal(t, float64(200), r["status"])
+	assert.Equal(t, "GET", r["method"])
+
+	assert.Equal(t, "ERROR", records[1]["level"])
+	assert.Equal(t, "boom", records[1]["err"])
+	assert.Equal(t, "DEBUG", records[2]["level"])
+	assert.Equal(t, float64(42), records[2]["data"])
+	assert.Equal(t, "WARN", records[3]["level"])
+	assert.Equal(t, "DEBUG-4", records[4]["level"])
+}
+
+// TestSlogEmitterContext tests that disabled levels are skipped and that
+// handlers receive the event context.
+func TestSlogEmitterContext(t *testing.T) {
+	h := &ctxHandler{}
+	s := NewSlogEmitter(slog.New(h))
+	ctx := context.WithValue(context.Background(), ctxKey{}, "trace-1")
+	EmitCtx(s, ctx, NewEvent("a", ""))
+	s.Emit(NewEvent("a", "").WithData(map[string]any{"severity": "debug"}))
+	assert.Equal(t, []any{"trace-1"}, h.values)
+}
+
+// ctxHandler records a context value of the handled records, at Info and
+// above.
+type ctxHandler struct {
+	values []any
+}
+
+// Enabled reports whether the level is Info or above.
+func (h *ctxHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= slog.LevelInfo
+}
+
+// Handle records the context value.
+func (h *ctxHandler) Handle(ctx context.Context, _ slog.Record) error {
+	h.values = append(h.values, ctx.Value(ctxKey{}))
+	return nil
+}
+
+// WithAttrs returns the handler.
+func (h *ctxHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+// WithGroup returns the handler.
+func (h *ctxHandler) WithGroup(string) slog.Handler { return h }
@@ -0,0 +1,85 @@
+package event
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+// EmitterStats is a snapshot of the self-metrics of an emitter, for
+// monitoring the event pipeline itself.
+type EmitterStats struct {
+	// Emitted is the number of events passed to the listeners.
+	Emitted uint64
+	// ByType is the number of emitted events by event type.
+	ByType map[EventType]uint64
+	// Dropped is the number of events dropped before reaching the
+	// listeners, by transformers or a full queue.
+	Dropped uint64
+	// ListenerErrors is the number of listener calls that panicked.
+	ListenerErrors uint64
+	// AvgDispatch is the average time spent calling the listeners of an
+	// event.
+	AvgDispatch time.Duration
+}
+
+// StatsProvider is implemented by emitters reporting self-metrics.
+type StatsProvider interface {
+	Stats() EmitterStats
+}
+
+// emitterStats accumulates emitter self-metrics. The zero value is ready
+// for use.
+type emitterStats struct {
+	mu             sync.Mutex
+	emitted        uint64
+	byType         map[EventType]uint64
+	dropped        uint64
+	listenerErrors uint64
+	dispatch       time.Duration
+}
+
+// dispatched records an event passed to the listeners in d.
+func (s *emitterStats) dispatched(eventType EventType, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.byType == nil {
+		s.byType = map[EventType]uint64{}
+	}
+	s.emitted++
+	s.byType[eventType]++
+	s.dispatch += d
+}
+
+// drop records a dropped event.
+func (s *emitterStats) drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropped++
+}
+
+// listenerError records a panicking listener call.
+func (s *emitterStats) listenerError() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listenerErrors++
+}
+
+// snapshot returns the current stats.
+func (s *emitterStats) snapshot() EmitterStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := EmitterStats{
+		Emitted:        s.emitted,
+		ByType:         maps.Clone(s.byType),
+		Dropped:        s.dropped,
+		ListenerErrors: s.listenerErrors,
+	}
+	if stats.ByType == nil {
+		stats.ByType = map[EventType]uint64{}
+	}
+	if s.emitted > 0 {
+		stats.AvgDispatch = s.dispatch / time.Duration(s.emitted)
+	}
+	return stats
+}
@@ -0,0 +1,57 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEmitterStats tests the self-metrics of the default emitter.
+func TestEmitterStats(t *testing.T) {
+	e := NewEventEmitter()
+	assert.Equal(t, EmitterStats{ByType: map[EventType]uint64{}}, e.Stats())
+
+	e.Use(DropEvents("dropped"))
+	e.AddListener("boom", func(ev *Event) { panic("boom") })
+	e.Emit(NewEvent("a", ""))
+	e.Emit(NewEvent("a", ""))
+	e.Emit(NewEvent("b", ""))
+	e.Emit(NewEvent("dropped", ""))
+	e.Emit(nil)
+	assert.Panics(t, func() { e.Emit(NewEvent("boom", "")) })
+
+	stats := e.Stats()
+	assert.Equal(t, uint64(4), stats.Emitted)
+	assert.Equal(t, map[EventType]uint64{"a": 2, "b": 1, "boom": 1},
+		stats.ByType)
+	assert.Equal(t, uint64(1), stats.Dropped)
+	assert.Equal(t, uint64(1), stats.ListenerErrors)
+	assert.GreaterOrEqual(t, stats.AvgDispatch, time.Duration(0))
+
+	// Snapshots are not changed by later events.
+	e.Emit(NewEvent("a", ""))
+	assert.Equal(t, uint64(2), stats.ByType["a"])
+}
+
+// TestAsyncEmitterStats tests that the async emitter adds its queue drops
+// to the stats of the wrapped emitter.
+func TestAsyncEmitterStats(t *testing.T) {
+	emitter := NewEventEmitter()
+	rec := newGatedRecorder(emitter)
+	a := NewAsyncEmitter(emitter, WithQueueSize(1))
+	a.Emit(NewEvent("a", "1"))
+	<-rec.started
+	a.Emit(NewEvent("a", "2"))
+	a.Emit(NewEvent("a", "3"))
+	close(rec.gate)
+	assert.NoError(t, a.Close())
+
+	stats := a.Stats()
+	assert.Equal(t, uint64(2), stats.Emitted)
+	assert.Equal(t, uint64(1), stats.Dropped)
+
+	plain := NewAsyncEmitter(NewNoopEventEmitter())
+	assert.NoError(t, plain.Close())
+	assert.Equal(t, EmitterStats{ByType: map[EventType]uint64{}}, plain.Stats())
+}
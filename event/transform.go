@@ -0,0 +1,157 @@
+package event
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+)
+
+// RedactedValue replaces the data values removed by RedactEventData.
+const RedactedValue = "[REDACTED]"
+
+// EventTransformer rewrites an event before it reaches the listeners. It
+// returns the event to pass on, or nil to drop it. Events are shared with
+// the emitting code, so transformers return modified copies instead of
+// changing them.
+type EventTransformer func(event *Event) *Event
+
+// Use installs transformers run on every emitted event, in order, before
+// the listeners are called, so event policy such as enrichment, filtering
+// and redaction lives in one place:
+//
+//	hostname, _ := os.Hostname()
+//	emitter.Use(
+//		event.DropEvents("event_request_size"),
+//		event.EnrichEvents(map[string]any{"host": hostname}),
+//		event.RedactEventData("authorization", "password"),
+//	)
+//
+// Parameters:
+//   - transformers: The transformers to append.
+//
+// Returns:
+//   - *DefaultEventEmitter: The emitter, for chaining.
+func (e *DefaultEventEmitter) Use(
+	transformers ...EventTransformer,
+) *DefaultEventEmitter {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.transformers = append(slices.Clip(e.transformers), transformers...)
+	return e
+}
+
+// transform runs the transformers on an event, stopping if one drops it.
+func transform(event *Event, transformers []EventTransformer) *Event {
+	for _, t := range transformers {
+		if event = t(event); event == nil {
+			return nil
+		}
+	}
+	return event
+}
+
+// DropEvents returns a transformer dropping events whose type matches one
+// of the patterns, see MatchEventType.
+//
+// Parameters:
+//   - patterns: The event types or patterns to drop.
+//
+// Returns:
+//   - EventTransformer: The transformer.
+func DropEvents(patterns ...EventType) EventTransformer {
+	patterns = slices.Clone(patterns)
+	return func(event *Event) *Event {
+		for _, p := range patterns {
+			if MatchEventType(p, event.Type) {
+				return nil
+			}
+		}
+		return event
+	}
+}
+
+// Sample returns a transformer passing on a fraction of the events whose
+// type matches the pattern, see MatchEventType, chosen at random, so
+// high-volume events can be reduced while rare events, such as panics,
+// always pass:
+//
+//	emitter.Use(event.Sample(server.EventNotFound, 0.01))
+//
+// Events of other types pass unchanged. Emitters wrapping the sampled
+// emitter, such as a CounterEmitter, still see every event.
+//
+// Parameters:
+//   - pattern: The event type or pattern to sample.
+//   - rate: The fraction of events passed on, from 0 to 1.
+//
+// Returns:
+//   - EventTransformer: The transformer.
+func Sample(pattern EventType, rate float64) EventTransformer {
+	return func(event *Event) *Event {
+		if !MatchEventType(pattern, event.Type) || rate >= 1 {
+			return event
+		}
+		if rate <= 0 || rand.Float64() >= rate {
+			return nil
+		}
+		return event
+	}
+}
+
+// EnrichEvents returns a transformer adding fields, such as the host or
+// service name, to the data of events. Events without data get a map of
+// the fields; the fields are merged into map[string]any data, keeping
+// values already set. Other data is left as is.
+//
+// Parameters:
+//   - fields: The fields to add.
+//
+// Returns:
+//   - EventTransformer: The transformer.
+func EnrichEvents(fields map[string]any) EventTransformer {
+	fields = maps.Clone(fields)
+	return func(event *Event) *Event {
+		switch data := event.Data.(type) {
+		case nil:
+			return event.WithData(maps.Clone(fields))
+		case map[string]any:
+			merged := maps.Clone(fields)
+			maps.Copy(merged, data)
+			return event.WithData(merged)
+		}
+		return event
+	}
+}
+
+// RedactEventData returns a transformer replacing the values of data keys
+// with RedactedValue in events with map[string]any data, e.g. to keep
+// credentials out of logs. Other data is left as is.
+//
+// Parameters:
+//   - keys: The data keys to redact.
+//
+// Returns:
+//   - EventTransformer: The transformer.
+func RedactEventData(keys ...string) EventTransformer {
+	keys = slices.Clone(keys)
+	return func(event *Event) *Event {
+		data, ok := event.Data.(map[string]any)
+		if !ok {
+			return event
+		}
+		var redacted map[string]any
+		for _, k := range keys {
+			if _, ok := data[k]; !ok {
+				continue
+			}
+			if redacted == nil {
+				redacted = maps.Clone(data)
+			}
+			redacted[k] = RedactedValue
+		}
+		if redacted == nil {
+			return event
+		}
+		return event.WithData(redacted)
+	}
+}
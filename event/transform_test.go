@@ -0,0 +1,73 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEventEmitterUse tests that transformers run in order before the
+// listeners and can drop events.
+func TestEventEmitterUse(t *testing.T) {
+	e := NewEventEmitter()
+	var got []*Event
+	e.AddGlobalListener(func(ev *Event) { got = append(got, ev) })
+	e.Use(
+		DropEvents("noisy*"),
+		EnrichEvents(map[string]any{"service": "api", "host": "h1"}),
+	).Use(RedactEventData("password"))
+
+	data := map[string]any{"user": "bob", "password": "secret", "host": "h2"}
+	ev := NewEvent("login", "ok").WithData(data)
+	e.Emit(ev)
+	e.Emit(NewEvent("noisy_tick", ""))
+	e.Emit(NewEvent("start", ""))
+
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, map[string]any{
+			"user": "bob", "password": RedactedValue,
+			"host": "h2", "service": "api",
+		}, got[0].Data)
+		assert.Equal(t, map[string]any{"service": "api", "host": "h1"}, got[1].Data)
+	}
+	assert.Equal(t, "secret", data["password"])
+	assert.Len(t, data, 3)
+}
+
+// TestEventTransformersOtherData tests that data other than maps is left
+// as is.
+func TestEventTransformersOtherData(t *testing.T) {
+	ev := NewEvent("a", "").WithData("text")
+	assert.Same(t, ev, EnrichEvents(map[string]any{"k": 1})(ev))
+	assert.Same(t, ev, RedactEventData("k")(ev))
+
+	ev = NewEvent("a", "").WithData(map[string]any{"x": 1})
+	assert.Same(t, ev, RedactEventData("k")(ev))
+	assert.Same(t, ev, DropEvents("b", "c*")(ev))
+	assert.Nil(t, DropEvents("a")(ev))
+}
+
+// TestSample tests that matching events are passed on at the sample rate
+// and other events always.
+func TestSample(t *testing.T) {
+	count := func(tr EventTransformer, eventType EventType) int {
+		n := 0
+		for i := 0; i < 10000; i++ {
+			if tr(NewEvent(eventType, "")) != nil {
+				n++
+			}
+		}
+		return n
+	}
+	assert.Equal(t, 10000, count(Sample("not_found", 0), "panic"))
+	assert.Equal(t, 0, count(Sample("not_found", 0), "not_found"))
+	assert.Equal(t, 10000, count(Sample("not_found", 1), "not_found"))
+	assert.InDelta(t, 1000, count(Sample("not_*", 0.1), "not_found"), 200)
+
+	e := NewEventEmitter().Use(Sample("noisy", 0))
+	calls := 0
+	e.AddGlobalListener(func(*Event) { calls++ })
+	e.Emit(NewEvent("noisy", ""))
+	e.Emit(NewEvent("event_panic", ""))
+	assert.Equal(t, 1, calls)
+}
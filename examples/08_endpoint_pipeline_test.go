@@ -1,7 +1,6 @@
 package examples
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -16,7 +15,7 @@ func Test_EndpointPipeline_JSON(t *testing.T) {
 	type In struct{ Name string }
 
 	// JSON input handler
-	ih := inputJSON[In]()
+	ih := pureapi.JSONInput[In]()
 
 	// Business logic: error if empty name
 	logic := pureapi.HandlerLogicFn[In](
@@ -34,7 +33,7 @@ func Test_EndpointPipeline_JSON(t *testing.T) {
 	})
 
 	// JSON output
-	oh := outputJSON()
+	oh := pureapi.JSONOutput()
 
 	h := pureapi.NewHandler(ih, logic, eh, oh)
 
@@ -63,28 +62,6 @@ func Test_EndpointPipeline_JSON(t *testing.T) {
 
 // Helpers for the pipeline example.
 
-func inputJSON[T any]() pureapi.InputHandler[T] {
-	return inputHandlerFunc[T](func(
-		w http.ResponseWriter, r *http.Request,
-	) (*T, error) {
-		var v T
-		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
-			return nil, err
-		}
-		return &v, nil
-	})
-}
-
-type inputHandlerFunc[T any] func(
-	w http.ResponseWriter, r *http.Request,
-) (*T, error)
-
-func (f inputHandlerFunc[T]) Handle(
-	w http.ResponseWriter, r *http.Request,
-) (*T, error) {
-	return f(w, r)
-}
-
 func errorMapper(
 	mapper func(error) (int, pureapi.APIError),
 ) pureapi.ErrorHandler {
@@ -100,33 +77,3 @@ func (f errorHandlerFunc) Handle(
 ) (int, pureapi.APIError) {
 	return f(err)
 }
-
-func outputJSON() pureapi.OutputHandler {
-	return outputHandlerFunc(
-		func(
-			w http.ResponseWriter,
-			_ *http.Request,
-			out any,
-			outErr error,
-			status int,
-		) error {
-			if outErr != nil {
-				w.WriteHeader(status)
-				_ = json.NewEncoder(w).
-					Encode(pureapi.APIErrorFrom(outErr.(pureapi.APIError)))
-				return nil
-			}
-			w.WriteHeader(status)
-			return json.NewEncoder(w).Encode(out)
-		})
-}
-
-type outputHandlerFunc func(
-	w http.ResponseWriter, r *http.Request, out any, outErr error, status int,
-) error
-
-func (f outputHandlerFunc) Handle(
-	w http.ResponseWriter, r *http.Request, out any, outErr error, status int,
-) error {
-	return f(w, r, out, outErr, status)
-}
@@ -0,0 +1,351 @@
+package bind
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Lookup returns the values for a name and whether the name is present.
+type Lookup func(name string) ([]string, bool)
+
+// FieldError describes a value that could not be bound to a field.
+type FieldError struct {
+	Field string // Tag name of the field.
+	Value string // Offending value.
+	Err   error  // Conversion error.
+}
+
+// Error returns the error message.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+// Unwrap returns the conversion error.
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// Errors aggregates the field errors of a bind operation.
+type Errors []*FieldError
+
+// Error returns the joined error messages.
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the field errors.
+func (e Errors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fe := range e {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// ErrUnsupportedType is returned for field types without a conversion.
+var ErrUnsupportedType = errors.New("unsupported field type")
+
+// Struct binds values to the fields of the struct dst points to. Fields are
+// selected by the tag name; fields tagged "-" or without the tag are
+// skipped, embedded structs are bound recursively. Names missing from
+// lookup leave the field untouched.
+//
+// Parameters:
+//   - dst: Pointer to the destination struct.
+//   - tag: The struct tag selecting field names, e.g. "form".
+//   - lookup: Returns the values for a name.
+//
+// Returns:
+//   - error: Errors with one FieldError per failed field, or nil.
+func Struct(dst any, tag string, lookup Lookup) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() ||
+		rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind: destination must be a non-nil struct pointer, got %T", dst)
+	}
+	var errs Errors
+	for _, f := range Fields(rv.Elem().Type(), tag) {
+		values, ok := lookup(f.Name)
+		if !ok || len(values) == 0 {
+			continue
+		}
+		field := rv.Elem().FieldByIndex(f.Index)
+		if err := Set(field, values); err != nil {
+			errs = append(errs, &FieldError{
+				Field: f.Name, Value: strings.Join(values, ","), Err: err,
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Field is a struct field selected by a tag.
+type Field struct {
+	Name  string // Tag name.
+	Index []int  // Index sequence for reflect.Value.FieldByIndex.
+	Type  reflect.Type
+}
+
+type fieldsKey struct {
+	t   reflect.Type
+	tag string
+}
+
+var fieldsCache sync.Map // fieldsKey -> []Field
+
+// Fields returns the fields of a struct type tagged with tag, including
+// those of embedded structs.
+//
+// Parameters:
+//   - t: The struct type.
+//   - tag: The struct tag name.
+//
+// Returns:
+//   - []Field: The tagged fields.
+func Fields(t reflect.Type, tag string) []Field {
+	key := fieldsKey{t: t, tag: tag}
+	if v, ok := fieldsCache.Load(key); ok {
+		return v.([]Field)
+	}
+	fields := collectFields(t, tag, nil)
+	fieldsCache.Store(key, fields)
+	return fields
+}
+
+// collectFields walks the struct fields.
+func collectFields(t reflect.Type, tag string, index []int) []Field {
+	var out []Field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		idx := append(append([]int{}, index...), i)
+		name, _, _ := strings.Cut(sf.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				out = append(out, collectFields(sf.Type, tag, idx)...)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		out = append(out, Field{Name: name, Index: idx, Type: sf.Type})
+	}
+	return out
+}
+
+// Converter converts a raw value to a value of its registered type.
+type Converter func(s string) (any, error)
+
+var converters sync.Map // reflect.Type -> Converter
+
+// RegisterConverter registers the conversion of values to type t for the
+// whole program, taking precedence over the built-in conversions. The
+// converter must return a value assignable to t. Register converters
+// during initialization.
+//
+// Parameters:
+//   - t: The type.
+//   - fn: The converter.
+func RegisterConverter(t reflect.Type, fn Converter) {
+	converters.Store(t, fn)
+}
+
+// converter returns the converter registered for t.
+func converter(t reflect.Type) (Converter, bool) {
+	fn, ok := converters.Load(t)
+	if !ok {
+		return nil, false
+	}
+	return fn.(Converter), true
+}
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	timeType            = reflect.TypeFor[time.Time]()
+)
+
+// Set converts values and stores them in v. Slices receive every value,
+// other types the first one. Supported are types with a converter
+// registered with RegisterConverter, strings, booleans (including "on" and
+// "off"), integers, unsigned integers, floats, time.Duration, time.Time
+// (RFC 3339), encoding.TextUnmarshaler implementations, pointers to and
+// slices of them.
+//
+// Parameters:
+//   - v: The settable destination value.
+//   - values: The raw values.
+//
+// Returns:
+//   - error: An error if a value cannot be converted.
+func Set(v reflect.Value, values []string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	if isList(v.Type()) {
+		out := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, s := range values {
+			if err := SetString(out.Index(i), s); err != nil {
+				return err
+			}
+		}
+		v.Set(out)
+		return nil
+	}
+	return SetString(v, values[0])
+}
+
+// SetString converts a single value and stores it in v.
+//
+// Parameters:
+//   - v: The settable destination value.
+//   - s: The raw value.
+//
+// Returns:
+//   - error: An error if the value cannot be converted.
+func SetString(v reflect.Value, s string) error {
+	if fn, ok := converter(v.Type()); ok {
+		value, err := fn(s)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			v.SetZero()
+			return nil
+		}
+		v.Set(reflect.ValueOf(value))
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		ptr := reflect.New(v.Type().Elem())
+		if err := SetString(ptr.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+	if implementsText(v.Type()) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).
+			UnmarshalText([]byte(s))
+	}
+	switch v.Type() {
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		v.SetInt(int64(d))
+		return nil
+	case timeType:
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("invalid RFC 3339 time %q", s)
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := parseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", s)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("%w %s", ErrUnsupportedType, v.Type())
+	}
+	return nil
+}
+
+// parseBool parses a boolean, accepting the "on" and "off" values sent by
+// HTML checkboxes in addition to those of strconv.ParseBool.
+func parseBool(s string) (bool, error) {
+	switch s {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean %q", s)
+	}
+	return b, nil
+}
+
+// ElemType returns the type Set converts single values to for a field of
+// type t: the element type of slices and pointers.
+//
+// Parameters:
+//   - t: The field type.
+//
+// Returns:
+//   - reflect.Type: The type of single values.
+func ElemType(t reflect.Type) reflect.Type {
+	t = derefType(t)
+	if isList(t) {
+		t = derefType(t.Elem())
+	}
+	return t
+}
+
+// derefType returns the element type of pointer types without their own
+// conversion.
+func derefType(t reflect.Type) reflect.Type {
+	if t.Kind() != reflect.Pointer {
+		return t
+	}
+	if _, ok := converter(t); ok {
+		return t
+	}
+	return t.Elem()
+}
+
+// isList reports whether values of type t are bound from every value:
+// slices without their own conversion.
+func isList(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice || implementsText(t) {
+		return false
+	}
+	_, ok := converter(t)
+	return !ok
+}
+
+// implementsText reports whether *t implements encoding.TextUnmarshaler.
+func implementsText(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
@@ -0,0 +1,130 @@
+package bind
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Paging struct {
+	Page int `form:"page"`
+}
+
+type bindTarget struct {
+	Paging
+	Name     string        `form:"name"`
+	Active   bool          `form:"active"`
+	Score    float64       `form:"score"`
+	Count    *uint8        `form:"count"`
+	Tags     []string      `form:"tag"`
+	IDs      []int64       `form:"id"`
+	Timeout  time.Duration `form:"timeout"`
+	Since    time.Time     `form:"since"`
+	IP       net.IP        `form:"ip"`
+	Skipped  string        `form:"-"`
+	Untagged string
+	hidden   string `form:"hidden"`
+}
+
+func lookupValues(v url.Values) Lookup {
+	return func(name string) ([]string, bool) {
+		vals, ok := v[name]
+		return vals, ok
+	}
+}
+
+func TestStruct(t *testing.T) {
+	values := url.Values{
+		"page": {"3"}, "name": {"Go"}, "active": {"true"}, "score": {"1.5"},
+		"count": {"7"}, "tag": {"a", "b"}, "id": {"1", "2"}, "timeout": {"2s"},
+		"since": {"2024-01-02T03:04:05Z"}, "ip": {"192.0.2.1"},
+		"Skipped": {"x"}, "Untagged": {"x"}, "hidden": {"x"},
+	}
+	var dst bindTarget
+	require.NoError(t, Struct(&dst, "form", lookupValues(values)))
+
+	assert.Equal(t, 3, dst.Page)
+	assert.Equal(t, "Go", dst.Name)
+	assert.True(t, dst.Active)
+	assert.Equal(t, 1.5, dst.Score)
+	require.NotNil(t, dst.Count)
+	assert.Equal(t, uint8(7), *dst.Count)
+	assert.Equal(t, []string{"a", "b"}, dst.Tags)
+	assert.Equal(t, []int64{1, 2}, dst.IDs)
+	assert.Equal(t, 2*time.Second, dst.Timeout)
+	assert.Equal(t, 2024, dst.Since.Year())
+	assert.Equal(t, "192.0.2.1", dst.IP.String())
+	assert.Empty(t, dst.Skipped)
+	assert.Empty(t, dst.Untagged)
+	assert.Empty(t, dst.hidden)
+}
+
+func TestStruct_Errors(t *testing.T) {
+	values := url.Values{"page": {"x"}, "count": {"300"}, "name": {"ok"}}
+	var dst bindTarget
+	err := Struct(&dst, "form", lookupValues(values))
+
+	var errs Errors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 2)
+	assert.Equal(t, "page", errs[0].Field)
+	assert.Equal(t, "count", errs[1].Field)
+	assert.Equal(t, "300", errs[1].Value)
+	assert.True(t, strings.Contains(err.Error(), "invalid integer"))
+	assert.Equal(t, "ok", dst.Name, "valid fields are still bound")
+}
+
+func TestStruct_InvalidDestination(t *testing.T) {
+	assert.Error(t, Struct(bindTarget{}, "form", lookupValues(nil)))
+	assert.Error(t, Struct((*bindTarget)(nil), "form", lookupValues(nil)))
+}
+
+func TestSetString_Unsupported(t *testing.T) {
+	var dst struct {
+		M map[string]string `form:"m"`
+	}
+	err := Struct(&dst, "form", lookupValues(url.Values{"m": {"x"}}))
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+}
+
+func TestElemType(t *testing.T) {
+	assert.Equal(t, reflect.TypeFor[int](), ElemType(reflect.TypeFor[int]()))
+	assert.Equal(t, reflect.TypeFor[int](), ElemType(reflect.TypeFor[*int]()))
+	assert.Equal(t, reflect.TypeFor[int64](), ElemType(reflect.TypeFor[[]*int64]()))
+	assert.Equal(t, reflect.TypeFor[net.IP](), ElemType(reflect.TypeFor[net.IP]()))
+}
+
+type cents int64
+
+func TestRegisterConverter(t *testing.T) {
+	RegisterConverter(reflect.TypeFor[cents](), func(s string) (any, error) {
+		whole, frac, _ := strings.Cut(s, ".")
+		n, err := strconv.ParseInt(whole+frac, 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid amount")
+		}
+		return cents(n), nil
+	})
+	values := url.Values{"price": {"1.50"}, "prices": {"2.00", "3.25"}}
+	var dst struct {
+		Price  *cents  `form:"price"`
+		Prices []cents `form:"prices"`
+	}
+	require.NoError(t, Struct(&dst, "form", lookupValues(values)))
+
+	require.NotNil(t, dst.Price)
+	assert.Equal(t, cents(150), *dst.Price)
+	assert.Equal(t, []cents{200, 325}, dst.Prices)
+	assert.Equal(t, reflect.TypeFor[cents](), ElemType(reflect.TypeFor[[]*cents]()))
+
+	err := Struct(&dst, "form", lookupValues(url.Values{"price": {"x"}}))
+	assert.ErrorContains(t, err, "invalid amount")
+}
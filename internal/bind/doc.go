@@ -0,0 +1,7 @@
+// Package bind converts string values from forms, queries, route parameters
+// and headers into typed struct fields selected by struct tags.
+//
+// It is shared by the input handlers of the endpoint package and the query
+// decoders, so all of them accept the same field types and report failures
+// the same way.
+package bind
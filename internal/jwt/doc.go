@@ -0,0 +1,9 @@
+// Package jwt parses JSON Web Tokens and verifies their signatures with the
+// standard library, keeping the module free of third-party dependencies.
+//
+// Supported algorithms are HS256/384/512, RS256/384/512, PS256/384/512,
+// ES256/384/512 and EdDSA. Keys are []byte for HMAC, *rsa.PublicKey,
+// *ecdsa.PublicKey and ed25519.PublicKey; a key of the wrong type for the
+// token's algorithm is rejected, which prevents algorithm confusion.
+// Validating claims is left to the caller.
+package jwt
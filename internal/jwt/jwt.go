@@ -0,0 +1,236 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha256" // Register SHA-256 for crypto.Hash.
+	_ "crypto/sha512" // Register SHA-384 and SHA-512 for crypto.Hash.
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Token errors.
+var (
+	ErrMalformed = errors.New("jwt: malformed token")
+	ErrSignature = errors.New("jwt: invalid signature")
+	ErrAlgorithm = errors.New("jwt: unsupported algorithm")
+)
+
+// ecdsaBits are the curve sizes of the ECDSA algorithms.
+var ecdsaBits = map[string]int{"ES256": 256, "ES384": 384, "ES512": 521}
+
+// Header is the JOSE header of a token.
+type Header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// Token is a parsed, not yet verified, token.
+type Token struct {
+	Header    Header
+	Claims    map[string]any
+	signed    []byte
+	signature []byte
+}
+
+// Parse splits and decodes a compact serialized token without verifying
+// it.
+func Parse(token string) (*Token, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+	var t Token
+	if err := decodeSegment(parts[0], &t.Header); err != nil {
+		return nil, err
+	}
+	if err := decodeSegment(parts[1], &t.Claims); err != nil {
+		return nil, err
+	}
+	if t.Claims == nil {
+		return nil, ErrMalformed
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	t.signed = []byte(parts[0] + "." + parts[1])
+	t.signature = sig
+	return &t, nil
+}
+
+// Verify checks the token signature with key.
+func (t *Token) Verify(key any) error {
+	switch alg := t.Header.Alg; alg {
+	case "HS256", "HS384", "HS512":
+		secret, ok := key.([]byte)
+		if !ok {
+			return keyError(alg, key)
+		}
+		mac := hmac.New(hashOf(alg).New, secret)
+		mac.Write(t.signed)
+		if !hmac.Equal(mac.Sum(nil), t.signature) {
+			return ErrSignature
+		}
+		return nil
+	case "RS256", "RS384", "RS512", "PS256", "PS384", "PS512":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return keyError(alg, key)
+		}
+		h := hashOf(alg)
+		digest := sum(h, t.signed)
+		var err error
+		if alg[0] == 'P' {
+			err = rsa.VerifyPSS(pub, h, digest, t.signature,
+				&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			err = rsa.VerifyPKCS1v15(pub, h, digest, t.signature)
+		}
+		if err != nil {
+			return ErrSignature
+		}
+		return nil
+	case "ES256", "ES384", "ES512":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return keyError(alg, key)
+		}
+		bits := pub.Curve.Params().BitSize
+		if bits != ecdsaBits[alg] {
+			return keyError(alg, key)
+		}
+		size := (bits + 7) / 8
+		if len(t.signature) != 2*size {
+			return ErrSignature
+		}
+		r := new(big.Int).SetBytes(t.signature[:size])
+		s := new(big.Int).SetBytes(t.signature[size:])
+		if !ecdsa.Verify(pub, sum(hashOf(alg), t.signed), r, s) {
+			return ErrSignature
+		}
+		return nil
+	case "EdDSA":
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return keyError(alg, key)
+		}
+		if !ed25519.Verify(pub, t.signed, t.signature) {
+			return ErrSignature
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w %q", ErrAlgorithm, alg)
+	}
+}
+
+// Sign creates a compact serialized token with an HMAC secret or a
+// *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey. It exists
+// mainly for tests and internal service tokens.
+func Sign(header Header, claims map[string]any, key any) (string, error) {
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := []byte(base64.RawURLEncoding.EncodeToString(h) + "." +
+		base64.RawURLEncoding.EncodeToString(c))
+	sig, err := sign(header.Alg, signed, key)
+	if err != nil {
+		return "", err
+	}
+	return string(signed) + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// sign signs data with the algorithm and key.
+func sign(alg string, data []byte, key any) ([]byte, error) {
+	switch alg {
+	case "HS256", "HS384", "HS512":
+		secret, ok := key.([]byte)
+		if !ok {
+			return nil, keyError(alg, key)
+		}
+		mac := hmac.New(hashOf(alg).New, secret)
+		mac.Write(data)
+		return mac.Sum(nil), nil
+	case "RS256", "RS384", "RS512", "PS256", "PS384", "PS512":
+		priv, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, keyError(alg, key)
+		}
+		h := hashOf(alg)
+		if alg[0] == 'P' {
+			return rsa.SignPSS(rand.Reader, priv, h, sum(h, data),
+				&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		return rsa.SignPKCS1v15(rand.Reader, priv, h, sum(h, data))
+	case "ES256", "ES384", "ES512":
+		priv, ok := key.(*ecdsa.PrivateKey)
+		if !ok || priv.Curve.Params().BitSize != ecdsaBits[alg] {
+			return nil, keyError(alg, key)
+		}
+		r, s, err := ecdsa.Sign(rand.Reader, priv, sum(hashOf(alg), data))
+		if err != nil {
+			return nil, err
+		}
+		size := (ecdsaBits[alg] + 7) / 8
+		sig := make([]byte, 2*size)
+		r.FillBytes(sig[:size])
+		s.FillBytes(sig[size:])
+		return sig, nil
+	case "EdDSA":
+		priv, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, keyError(alg, key)
+		}
+		return ed25519.Sign(priv, data), nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrAlgorithm, alg)
+}
+
+// decodeSegment decodes a base64url JSON segment into v.
+func decodeSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return ErrMalformed
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrMalformed
+	}
+	return nil
+}
+
+// hashOf returns the hash of an algorithm by its size suffix.
+func hashOf(alg string) crypto.Hash {
+	switch alg[len(alg)-3:] {
+	case "384":
+		return crypto.SHA384
+	case "512":
+		return crypto.SHA512
+	}
+	return crypto.SHA256
+}
+
+// sum hashes data.
+func sum(h crypto.Hash, data []byte) []byte {
+	hh := h.New()
+	hh.Write(data)
+	return hh.Sum(nil)
+}
+
+// keyError reports a key unsuitable for an algorithm.
+func keyError(alg string, key any) error {
+	return fmt.Errorf("jwt: key of type %T cannot be used with %s", key, alg)
+}
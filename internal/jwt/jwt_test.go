@@ -0,0 +1,135 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ec384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	secret := []byte("secret")
+
+	testCases := []struct {
+		alg       string
+		priv, pub any
+	}{
+		{"HS256", secret, secret},
+		{"HS512", secret, secret},
+		{"RS256", rsaKey, &rsaKey.PublicKey},
+		{"PS384", rsaKey, &rsaKey.PublicKey},
+		{"ES256", ecKey, &ecKey.PublicKey},
+		{"ES384", ec384, &ec384.PublicKey},
+		{"EdDSA", edPriv, edPub},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.alg, func(t *testing.T) {
+			token, err := Sign(Header{Alg: tc.alg, Kid: "k1"},
+				map[string]any{"sub": "ada"}, tc.priv)
+			require.NoError(t, err)
+
+			parsed, err := Parse(token)
+			require.NoError(t, err)
+			assert.Equal(t, "k1", parsed.Header.Kid)
+			assert.Equal(t, "ada", parsed.Claims["sub"])
+			require.NoError(t, parsed.Verify(tc.pub))
+
+			parts := strings.Split(token, ".")
+			forged, err := Sign(Header{Alg: tc.alg, Kid: "k1"},
+				map[string]any{"sub": "eve"}, tc.priv)
+			require.NoError(t, err)
+			tampered := strings.Join([]string{
+				parts[0], strings.Split(forged, ".")[1], parts[2],
+			}, ".")
+			parsed, err = Parse(tampered)
+			require.NoError(t, err)
+			assert.ErrorIs(t, parsed.Verify(tc.pub), ErrSignature)
+		})
+	}
+}
+
+func TestVerify_KeyMismatch(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	// An RSA public key must not be usable as an HMAC secret.
+	token, err := Sign(Header{Alg: "HS256"}, map[string]any{}, []byte("x"))
+	require.NoError(t, err)
+	parsed, err := Parse(token)
+	require.NoError(t, err)
+	assert.EqualError(t, parsed.Verify(&rsaKey.PublicKey),
+		"jwt: key of type *rsa.PublicKey cannot be used with HS256")
+
+	parsed.Header.Alg = "ES256"
+	assert.EqualError(t, parsed.Verify(&ecKey.PublicKey),
+		"jwt: key of type *ecdsa.PublicKey cannot be used with ES256")
+
+	parsed.Header.Alg = "none"
+	assert.ErrorIs(t, parsed.Verify(nil), ErrAlgorithm)
+}
+
+func TestParse_Malformed(t *testing.T) {
+	seg := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	for _, token := range []string{
+		"",
+		"a.b",
+		"!.e30.sig",
+		seg(`{"alg":"HS256"}`) + "." + seg("null") + ".",
+		seg(`{"alg":"HS256"}`) + "." + seg("[]") + ".",
+		seg(`{"alg":"HS256"}`) + "." + seg("{}") + ".!",
+	} {
+		_, err := Parse(token)
+		assert.True(t, errors.Is(err, ErrMalformed), token)
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	b64 := base64.RawURLEncoding.EncodeToString
+
+	data := fmt.Sprintf(`{"keys":[
+		{"kty":"RSA","kid":"rsa","n":"%s","e":"AQAB"},
+		{"kty":"EC","kid":"ec","crv":"P-256","x":"%s","y":"%s"},
+		{"kty":"OKP","kid":"ed","crv":"Ed25519","x":"%s"},
+		{"kty":"oct","kid":"hmac","k":"%s"},
+		{"kty":"RSA","kid":"enc","use":"enc","n":"x","e":"AQAB"},
+		{"kty":"unknown","kid":"other"}
+	]}`,
+		b64([]byte{0xc5, 0x01}),
+		b64(ecKey.X.Bytes()), b64(ecKey.Y.Bytes()),
+		b64(edPub), b64([]byte("secret")))
+
+	keys, err := ParseJWKS([]byte(data))
+	require.NoError(t, err)
+	assert.Len(t, keys, 4)
+	assert.Equal(t, 65537, keys["rsa"].(*rsa.PublicKey).E)
+	assert.True(t, ecKey.PublicKey.Equal(keys["ec"]))
+	assert.Equal(t, edPub, keys["ed"])
+	assert.Equal(t, []byte("secret"), keys["hmac"])
+
+	_, err = ParseJWKS([]byte(`{"keys":[{"kty":"EC","kid":"bad","crv":"P-256","x":"AQ","y":"AQ"}]}`))
+	assert.EqualError(t, err, `jwt: key "bad": point not on curve`)
+}
@@ -0,0 +1,546 @@
+package msgpack
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// maxDepth bounds the nesting of arrays and maps.
+const maxDepth = 100
+
+// ErrSyntax is returned for malformed MessagePack data.
+var ErrSyntax = errors.New("msgpack: malformed data")
+
+// TypeError describes a value that cannot be stored in the destination.
+type TypeError struct {
+	Field string       // Dotted path of the field, empty for the root.
+	Value string       // MessagePack type of the value, e.g. "string".
+	Type  reflect.Type // Destination type.
+}
+
+// Error returns the error message.
+func (e *TypeError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("msgpack: cannot decode %s into %s", e.Value, e.Type)
+	}
+	return fmt.Sprintf(
+		"msgpack: cannot decode %s into field %s of type %s",
+		e.Value, e.Field, e.Type,
+	)
+}
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// Unmarshal decodes a single MessagePack value into the value v points to.
+// Arrays and maps decode into interface values as []any and map[string]any
+// (map[any]any for non-string keys), integers as int64 or uint64.
+//
+// Parameters:
+//   - data: The encoded value.
+//   - v: A non-nil pointer to the destination.
+//
+// Returns:
+//   - error: ErrSyntax for malformed data, a *TypeError for mismatched
+//     types.
+func Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("msgpack: destination must be a non-nil pointer, got %T", v)
+	}
+	d := &decoder{data: data}
+	val, err := d.value(0)
+	if err != nil {
+		return err
+	}
+	if d.pos != len(d.data) {
+		return fmt.Errorf("%w: trailing data", ErrSyntax)
+	}
+	return assign(rv.Elem(), val, "")
+}
+
+// decoder parses MessagePack into generic values.
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+// ext is an extension value other than a timestamp.
+type ext struct {
+	typ  int8
+	data []byte
+}
+
+// take returns the next n bytes.
+func (d *decoder) take(n int) ([]byte, error) {
+	if n < 0 || n > len(d.data)-d.pos {
+		return nil, fmt.Errorf("%w: unexpected end of input", ErrSyntax)
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// uint reads a big-endian unsigned integer of n bytes.
+func (d *decoder) uint(n int) (uint64, error) {
+	b, err := d.take(n)
+	if err != nil {
+		return 0, err
+	}
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u, nil
+}
+
+// length reads a length of n bytes.
+func (d *decoder) length(n int) (int, error) {
+	u, err := d.uint(n)
+	return int(u), err
+}
+
+// value parses the next value.
+func (d *decoder) value(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("%w: nesting exceeds depth %d", ErrSyntax, maxDepth)
+	}
+	b, err := d.take(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xe0 == 0xa0:
+		return d.str(int(c & 0x1f))
+	case c&0xf0 == 0x90:
+		return d.array(int(c&0x0f), depth)
+	case c&0xf0 == 0x80:
+		return d.mapValue(int(c&0x0f), depth)
+	}
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return d.uint(1 << (c - 0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		n := 1 << (c - 0xd0)
+		u, err := d.uint(n)
+		if err != nil {
+			return nil, err
+		}
+		shift := 64 - 8*n
+		return int64(u<<shift) >> shift, nil
+	case 0xca:
+		u, err := d.uint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := d.uint(8)
+		return math.Float64frombits(u), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.length(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.str(n)
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.length(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.take(n)
+		return append([]byte{}, b...), err
+	case 0xdc, 0xdd:
+		n, err := d.length(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(n, depth)
+	case 0xde, 0xdf:
+		n, err := d.length(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.mapValue(n, depth)
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.ext(1 << (c - 0xd4))
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.length(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.ext(n)
+	}
+	return nil, fmt.Errorf("%w: invalid type byte 0x%02x", ErrSyntax, c)
+}
+
+// str reads a string of n bytes.
+func (d *decoder) str(n int) (string, error) {
+	b, err := d.take(n)
+	return string(b), err
+}
+
+// array reads n values.
+func (d *decoder) array(n, depth int) ([]any, error) {
+	if n > len(d.data)-d.pos {
+		return nil, fmt.Errorf("%w: unexpected end of input", ErrSyntax)
+	}
+	out := make([]any, n)
+	for i := range out {
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// mapValue reads n key-value pairs.
+func (d *decoder) mapValue(n, depth int) (any, error) {
+	if 2*n > len(d.data)-d.pos {
+		return nil, fmt.Errorf("%w: unexpected end of input", ErrSyntax)
+	}
+	keys, vals := make([]any, n), make([]any, n)
+	allStrings := true
+	for i := 0; i < n; i++ {
+		k, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := k.(string); !ok {
+			allStrings = false
+		}
+		keys[i], vals[i] = k, v
+	}
+	if allStrings {
+		m := make(map[string]any, n)
+		for i, k := range keys {
+			m[k.(string)] = vals[i]
+		}
+		return m, nil
+	}
+	m := make(map[any]any, n)
+	for i, k := range keys {
+		if k != nil && !reflect.TypeOf(k).Comparable() {
+			return nil, fmt.Errorf("%w: unhashable map key", ErrSyntax)
+		}
+		m[k] = vals[i]
+	}
+	return m, nil
+}
+
+// ext reads an extension value with n data bytes.
+func (d *decoder) ext(n int) (any, error) {
+	t, err := d.take(1)
+	if err != nil {
+		return nil, err
+	}
+	data, err := d.take(n)
+	if err != nil {
+		return nil, err
+	}
+	if int8(t[0]) != timestampExt {
+		return ext{typ: int8(t[0]), data: data}, nil
+	}
+	switch n {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(data)), 0).UTC(), nil
+	case 8:
+		u := binary.BigEndian.Uint64(data)
+		return time.Unix(int64(u&(1<<34-1)), int64(u>>34)).UTC(), nil
+	case 12:
+		nsec := binary.BigEndian.Uint32(data)
+		sec := int64(binary.BigEndian.Uint64(data[4:]))
+		return time.Unix(sec, int64(nsec)).UTC(), nil
+	}
+	return nil, fmt.Errorf("%w: invalid timestamp length %d", ErrSyntax, n)
+}
+
+// kindName names the MessagePack type of a generic value.
+func kindName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "bool"
+	case int64, uint64:
+		return "integer"
+	case float64:
+		return "float"
+	case string:
+		return "string"
+	case []byte:
+		return "binary"
+	case []any:
+		return "array"
+	case map[string]any, map[any]any:
+		return "map"
+	case time.Time:
+		return "timestamp"
+	}
+	return "extension"
+}
+
+// assign stores a generic value in dst.
+func assign(dst reflect.Value, v any, path string) error {
+	mismatch := func() error {
+		return &TypeError{Field: path, Value: kindName(v), Type: dst.Type()}
+	}
+	if v == nil {
+		switch dst.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+			dst.SetZero()
+		}
+		return nil
+	}
+	if dst.Kind() == reflect.Pointer {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return assign(dst.Elem(), v, path)
+	}
+	if dst.Type() == timeType {
+		switch t := v.(type) {
+		case time.Time:
+			dst.Set(reflect.ValueOf(t))
+			return nil
+		case string:
+			parsed, err := time.Parse(time.RFC3339Nano, t)
+			if err != nil {
+				return mismatch()
+			}
+			dst.Set(reflect.ValueOf(parsed))
+			return nil
+		}
+		return mismatch()
+	}
+	if s, ok := v.(string); ok && dst.Kind() != reflect.Interface &&
+		reflect.PointerTo(dst.Type()).Implements(textUnmarshalerType) {
+		return dst.Addr().Interface().(encoding.TextUnmarshaler).
+			UnmarshalText([]byte(s))
+	}
+	switch dst.Kind() {
+	case reflect.Interface:
+		if dst.NumMethod() != 0 {
+			return mismatch()
+		}
+		dst.Set(reflect.ValueOf(v))
+	case reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return mismatch()
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		switch x := v.(type) {
+		case int64:
+			n = x
+		case uint64:
+			if x > math.MaxInt64 {
+				return mismatch()
+			}
+			n = int64(x)
+		default:
+			return mismatch()
+		}
+		if dst.OverflowInt(n) {
+			return mismatch()
+		}
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		var n uint64
+		switch x := v.(type) {
+		case int64:
+			if x < 0 {
+				return mismatch()
+			}
+			n = uint64(x)
+		case uint64:
+			n = x
+		default:
+			return mismatch()
+		}
+		if dst.OverflowUint(n) {
+			return mismatch()
+		}
+		dst.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		switch x := v.(type) {
+		case float64:
+			dst.SetFloat(x)
+		case int64:
+			dst.SetFloat(float64(x))
+		case uint64:
+			dst.SetFloat(float64(x))
+		default:
+			return mismatch()
+		}
+	case reflect.String:
+		switch x := v.(type) {
+		case string:
+			dst.SetString(x)
+		case []byte:
+			dst.SetString(string(x))
+		default:
+			return mismatch()
+		}
+	case reflect.Slice:
+		if dst.Type().Elem().Kind() == reflect.Uint8 {
+			switch x := v.(type) {
+			case []byte:
+				dst.SetBytes(x)
+				return nil
+			case string:
+				dst.SetBytes([]byte(x))
+				return nil
+			}
+		}
+		arr, ok := v.([]any)
+		if !ok {
+			return mismatch()
+		}
+		out := reflect.MakeSlice(dst.Type(), len(arr), len(arr))
+		for i, item := range arr {
+			if err := assign(out.Index(i), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(out)
+	case reflect.Array:
+		arr, ok := v.([]any)
+		if !ok || len(arr) > dst.Len() {
+			return mismatch()
+		}
+		for i, item := range arr {
+			if err := assign(dst.Index(i), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		return assignMap(dst, v, path, mismatch)
+	case reflect.Struct:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return mismatch()
+		}
+		return assignStruct(dst, m, path)
+	default:
+		return mismatch()
+	}
+	return nil
+}
+
+// assignMap stores a generic map in a map value.
+func assignMap(dst reflect.Value, v any, path string, mismatch func() error) error {
+	var entries map[any]any
+	switch m := v.(type) {
+	case map[string]any:
+		entries = make(map[any]any, len(m))
+		for k, val := range m {
+			entries[k] = val
+		}
+	case map[any]any:
+		entries = m
+	default:
+		return mismatch()
+	}
+	if dst.IsNil() {
+		dst.Set(reflect.MakeMapWithSize(dst.Type(), len(entries)))
+	}
+	kt, vt := dst.Type().Key(), dst.Type().Elem()
+	for k, val := range entries {
+		kv := reflect.New(kt).Elem()
+		if err := assign(kv, k, path); err != nil {
+			return err
+		}
+		vv := reflect.New(vt).Elem()
+		if err := assign(vv, val, joinPath(path, fmt.Sprint(k))); err != nil {
+			return err
+		}
+		dst.SetMapIndex(kv, vv)
+	}
+	return nil
+}
+
+// assignStruct stores a generic map in a struct, matching field names
+// exactly first and case-insensitively otherwise, like encoding/json.
+func assignStruct(dst reflect.Value, m map[string]any, path string) error {
+	fields := cachedFields(dst.Type())
+	for key, val := range m {
+		f, ok := findField(fields, key)
+		if !ok {
+			continue
+		}
+		fv, err := settableField(dst, f.index)
+		if err != nil {
+			return err
+		}
+		if err := assign(fv, val, joinPath(path, f.name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findField returns the field encoded under key.
+func findField(fields []field, key string) (field, bool) {
+	for _, f := range fields {
+		if f.name == key {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// settableField returns a nested field, allocating nil embedded pointers.
+func settableField(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf(
+						"msgpack: cannot set embedded pointer to unexported struct %s",
+						v.Type().Elem(),
+					)
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// joinPath appends a field name to a path.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,9 @@
+// Package msgpack encodes and decodes MessagePack, keeping the module free
+// of third-party dependencies.
+//
+// Go values map to MessagePack like they map to JSON in encoding/json:
+// structs become maps keyed by the "msgpack" struct tag, falling back to
+// the "json" tag and then the field name, with omitempty and "-" honored.
+// time.Time uses the timestamp extension type, []byte the bin family and
+// encoding.TextMarshaler implementations are written as strings.
+package msgpack
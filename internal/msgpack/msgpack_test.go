@@ -0,0 +1,184 @@
+package msgpack
+
+import (
+	"errors"
+	"math"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal_Encodings(t *testing.T) {
+	testCases := []struct {
+		name string
+		v    any
+		want []byte
+	}{
+		{"Nil", nil, []byte{0xc0}},
+		{"True", true, []byte{0xc3}},
+		{"Positive fixint", 7, []byte{0x07}},
+		{"Negative fixint", -3, []byte{0xfd}},
+		{"Uint8", 200, []byte{0xcc, 0xc8}},
+		{"Int16", -300, []byte{0xd1, 0xfe, 0xd4}},
+		{"Uint32", uint32(70000), []byte{0xce, 0x00, 0x01, 0x11, 0x70}},
+		{"Float64", 1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"Fixstr", "hi", []byte{0xa2, 'h', 'i'}},
+		{"Str8", strings.Repeat("a", 32), append([]byte{0xd9, 32}, strings.Repeat("a", 32)...)},
+		{"Bin", []byte{1, 2}, []byte{0xc4, 2, 1, 2}},
+		{"Fixarray", []int{1, 2}, []byte{0x92, 1, 2}},
+		{"Nil slice", []int(nil), []byte{0xc0}},
+		{"Sorted map", map[string]int{"b": 2, "a": 1}, []byte{0x82, 0xa1, 'a', 1, 0xa1, 'b', 2}},
+		{"Timestamp32", time.Unix(1, 0), []byte{0xd6, 0xff, 0, 0, 0, 1}},
+		{"Text marshaler", netip.MustParseAddr("10.0.0.1"), append([]byte{0xa8}, "10.0.0.1"...)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Marshal(tc.v)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+type inner struct {
+	Note string `json:"note"`
+}
+
+type Embedded struct {
+	Source string `msgpack:"source"`
+}
+
+type record struct {
+	Embedded
+	ID       int64             `json:"id"`
+	Name     string            `msgpack:"name" json:"ignored"`
+	Score    float32           `json:"score"`
+	Tags     []string          `json:"tags,omitempty"`
+	Attrs    map[string]int    `json:"attrs"`
+	Raw      []byte            `json:"raw"`
+	At       time.Time         `json:"at"`
+	Inner    *inner            `json:"inner"`
+	Any      any               `json:"any"`
+	Addr     netip.Addr        `json:"addr"`
+	Skipped  string            `json:"-"`
+	Counts   [2]uint16         `json:"counts"`
+	Optional *int              `json:"optional"`
+	Nested   map[string][]bool `json:"nested"`
+	private  int
+}
+
+func TestRoundTrip(t *testing.T) {
+	in := record{
+		Embedded: Embedded{Source: "api"},
+		ID:       math.MinInt64,
+		Name:     "Go",
+		Score:    2.5,
+		Attrs:    map[string]int{"a": 1},
+		Raw:      []byte{0, 1, 2},
+		At:       time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC),
+		Inner:    &inner{Note: strings.Repeat("x", 300)},
+		Any:      []any{"x", int64(1), map[string]any{"k": true}},
+		Addr:     netip.MustParseAddr("::1"),
+		Skipped:  "secret",
+		Counts:   [2]uint16{1, 65535},
+		Nested:   map[string][]bool{"n": {true, false}},
+		private:  1,
+	}
+	data, err := Marshal(in)
+	require.NoError(t, err)
+
+	var out record
+	require.NoError(t, Unmarshal(data, &out))
+	in.Skipped, in.private = "", 0
+	assert.Equal(t, in, out)
+
+	var generic map[string]any
+	require.NoError(t, Unmarshal(data, &generic))
+	assert.Equal(t, "api", generic["source"])
+	assert.Equal(t, "Go", generic["name"])
+	assert.NotContains(t, generic, "tags")
+	assert.NotContains(t, generic, "Skipped")
+}
+
+func TestUnmarshal_Timestamps(t *testing.T) {
+	for _, ts := range []time.Time{
+		time.Unix(1, 0).UTC(),
+		time.Unix(1<<33, 5).UTC(),
+		time.Unix(-10, 7).UTC(),
+	} {
+		data, err := Marshal(ts)
+		require.NoError(t, err)
+		var out time.Time
+		require.NoError(t, Unmarshal(data, &out))
+		assert.True(t, ts.Equal(out), "%s != %s", ts, out)
+	}
+}
+
+func TestUnmarshal_CaseInsensitive(t *testing.T) {
+	data, err := Marshal(map[string]any{"NAME": "Go", "unknown": 1})
+	require.NoError(t, err)
+	var out record
+	require.NoError(t, Unmarshal(data, &out))
+	assert.Equal(t, "Go", out.Name)
+}
+
+func TestUnmarshal_Errors(t *testing.T) {
+	var out record
+	testCases := []struct {
+		name    string
+		data    []byte
+		dst     any
+		wantErr string
+		syntax  bool
+	}{
+		{name: "Truncated", data: []byte{0x92, 1}, dst: &out, syntax: true},
+		{name: "Trailing", data: []byte{0x01, 0x02}, dst: new(int), syntax: true},
+		{name: "Invalid byte", data: []byte{0xc1}, dst: new(int), syntax: true},
+		{name: "Huge length", data: []byte{0xdd, 0xff, 0xff, 0xff, 0xff}, dst: new([]int), syntax: true},
+		{name: "Type mismatch", data: mustMarshal(t, map[string]any{"id": "x"}), dst: &out,
+			wantErr: "msgpack: cannot decode string into field id of type int64"},
+		{name: "Nested mismatch", data: mustMarshal(t, map[string]any{"inner": map[string]any{"note": 1}}), dst: &out,
+			wantErr: "msgpack: cannot decode integer into field inner.note of type string"},
+		{name: "Overflow", data: mustMarshal(t, 300), dst: new(uint8),
+			wantErr: "msgpack: cannot decode integer into uint8"},
+		{name: "Negative unsigned", data: mustMarshal(t, -1), dst: new(uint),
+			wantErr: "msgpack: cannot decode integer into uint"},
+		{name: "Not a pointer", data: []byte{0x01}, dst: 1,
+			wantErr: "msgpack: destination must be a non-nil pointer, got int"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Unmarshal(tc.data, tc.dst)
+			require.Error(t, err)
+			if tc.syntax {
+				assert.True(t, errors.Is(err, ErrSyntax), err.Error())
+				return
+			}
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestUnmarshal_DepthLimit(t *testing.T) {
+	data := append([]byte(strings.Repeat("\x91", maxDepth+2)), 0x01)
+	var v any
+	assert.ErrorIs(t, Unmarshal(data, &v), ErrSyntax)
+}
+
+func TestMarshal_Unsupported(t *testing.T) {
+	_, err := Marshal(make(chan int))
+	assert.EqualError(t, err, "msgpack: unsupported type chan int")
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := Marshal(v)
+	require.NoError(t, err)
+	return data
+}
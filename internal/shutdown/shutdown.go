@@ -0,0 +1,38 @@
+// Package shutdown carries the graceful shutdown signal of a server in
+// request contexts.
+//
+// The server package stores the signal for every request it serves and
+// packages such as endpoint read it, without depending on each other.
+package shutdown
+
+import "context"
+
+// ctxKey is the context key of the shutdown context.
+type ctxKey struct{}
+
+// WithContext returns a copy of ctx carrying the shutdown context.
+//
+// Parameters:
+//   - ctx: The request context.
+//   - shutdown: A context cancelled when graceful shutdown begins.
+//
+// Returns:
+//   - context.Context: The context carrying shutdown.
+func WithContext(ctx, shutdown context.Context) context.Context {
+	return context.WithValue(ctx, ctxKey{}, shutdown)
+}
+
+// FromContext returns the shutdown context stored in ctx. Without one the
+// returned context is never cancelled.
+//
+// Parameters:
+//   - ctx: The request context.
+//
+// Returns:
+//   - context.Context: The shutdown context.
+func FromContext(ctx context.Context) context.Context {
+	if v, ok := ctx.Value(ctxKey{}).(context.Context); ok {
+		return v
+	}
+	return context.Background()
+}
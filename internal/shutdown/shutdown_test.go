@@ -0,0 +1,20 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	if FromContext(context.Background()).Done() != nil {
+		t.Fatal("expected a context that is never cancelled")
+	}
+	sd, cancel := context.WithCancel(context.Background())
+	ctx := WithContext(context.Background(), sd)
+	cancel()
+	select {
+	case <-FromContext(ctx).Done():
+	default:
+		t.Fatal("expected the stored shutdown context")
+	}
+}
@@ -0,0 +1,19 @@
+// Package jsonschema describes and validates JSON values with a subset of
+// JSON Schema.
+//
+// Schemas are parsed from JSON or reflected from Go types with the naming
+// rules of encoding/json: fields without omitempty are required, pointers
+// are nullable and named structs can be collected as reusable definitions.
+// Constraints are added with the "jsonschema" struct tag:
+//
+//	type CreateUser struct {
+//		Name  string `json:"name" jsonschema:"minLength=1,maxLength=64"`
+//		Age   int    `json:"age,omitempty" jsonschema:"minimum=0"`
+//		Role  string `json:"role" jsonschema:"enum=admin|member"`
+//	}
+//
+// The validator supports type, nullable, enum, minimum, maximum, minLength,
+// maxLength, pattern, format (date-time, date, email, uuid), items,
+// minItems, maxItems, properties, required, additionalProperties, not, and
+// local $ref pointers into $defs, definitions or components/schemas.
+package jsonschema
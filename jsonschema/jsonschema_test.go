@@ -0,0 +1,121 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Address struct {
+	City string `json:"city" jsonschema:"minLength=1"`
+}
+
+type CreateUser struct {
+	Name     string         `json:"name" jsonschema:"minLength=2,maxLength=5"`
+	Age      int            `json:"age,omitempty" jsonschema:"minimum=0,maximum=150"`
+	Role     string         `json:"role" jsonschema:"enum=admin|member"`
+	Email    *string        `json:"email,omitempty" jsonschema:"format=email"`
+	Tags     []string       `json:"tags,omitempty" jsonschema:"maxItems=2"`
+	Address  Address        `json:"address"`
+	Labels   map[string]int `json:"labels,omitempty"`
+	Born     time.Time      `json:"born,omitempty"`
+	ID       int64          `path:"id"`
+	Ignored  string         `json:"-"`
+	internal string
+	Extra    map[string]string `json:"extra,omitempty"`
+}
+
+func TestFromType(t *testing.T) {
+	s := FromType(reflect.TypeFor[CreateUser]())
+	assert.Equal(t, "object", s.Type)
+	assert.Equal(t, []string{"name", "role", "address", "ID"}, s.Required)
+	assert.Equal(t, []any{"admin", "member"}, s.Properties["role"].Enum)
+	assert.True(t, s.Properties["email"].Nullable)
+	assert.Equal(t, "date-time", s.Properties["born"].Format)
+	assert.NotContains(t, s.Properties, "Ignored")
+	assert.NotContains(t, s.Properties, "internal")
+
+	r := &Reflector{RefPrefix: "#/$defs/"}
+	s = r.Reflect(reflect.TypeFor[CreateUser]())
+	assert.Equal(t, "#/$defs/CreateUser", s.Ref)
+	assert.Contains(t, r.Definitions["CreateUser"].Properties, "ID")
+	assert.Equal(t, "#/$defs/Address", r.Definitions["CreateUser"].Properties["address"].Ref)
+
+	s = r.Object(reflect.TypeFor[*CreateUser](), "path")
+	assert.Equal(t, "object", s.Type)
+	assert.NotContains(t, s.Properties, "ID")
+	assert.Equal(t, "#/$defs/Address", s.Properties["address"].Ref)
+}
+
+func TestValidate_Reflected(t *testing.T) {
+	s := (&Reflector{}).Object(reflect.TypeFor[CreateUser](), "path")
+
+	errs, err := s.ValidateJSON([]byte(`{"name":"Ann","role":"admin","address":{"city":"Oslo"}}`))
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+
+	errs, err = s.ValidateJSON([]byte(`{
+		"name": "A", "age": 200.5, "role": "root", "email": "nope",
+		"tags": ["a", "b", "c"], "address": {"city": ""}, "labels": {"x": "y"}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, []Error{
+		{Path: "address.city", Message: "must be at least 1 characters"},
+		{Path: "age", Message: "must be of type integer"},
+		{Path: "email", Message: "must be an email address"},
+		{Path: "labels.x", Message: "must be of type integer"},
+		{Path: "name", Message: "must be at least 2 characters"},
+		{Path: "role", Message: `must be one of "admin", "member"`},
+		{Path: "tags", Message: "must have at most 2 items"},
+	}, errs)
+
+	errs, _ = s.ValidateJSON([]byte(`{"name":null,"address":{}}`))
+	assert.Equal(t, []Error{
+		{Path: "role", Message: "is required"},
+		{Path: "address.city", Message: "is required"},
+		{Path: "name", Message: "must not be null"},
+	}, errs)
+
+	errs, _ = s.ValidateJSON([]byte(`[]`))
+	assert.Equal(t, []Error{{Message: "must be of type object"}}, errs)
+
+	_, err = s.ValidateJSON([]byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestParse(t *testing.T) {
+	s := MustParse([]byte(`{
+		"$defs": {"id": {"type": "string", "format": "uuid"}},
+		"type": "object",
+		"required": ["id"],
+		"properties": {
+			"id": {"$ref": "#/$defs/id"},
+			"count": {"type": ["integer", "null"], "enum": [1, 2]},
+			"code": {"type": "string", "pattern": "^[A-Z]{3}$"}
+		},
+		"additionalProperties": false
+	}`))
+	assert.True(t, s.Properties["count"].Nullable)
+
+	errs := s.Validate(map[string]any{
+		"id": "x", "count": json.Number("3"), "code": "abc", "other": true,
+	})
+	assert.Equal(t, []Error{
+		{Path: "code", Message: `must match pattern "^[A-Z]{3}$"`},
+		{Path: "count", Message: "must be one of 1, 2"},
+		{Path: "id", Message: "must be a UUID"},
+		{Path: "other", Message: "is not allowed"},
+	}, errs)
+
+	errs = s.Validate(map[string]any{
+		"id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8", "count": nil,
+	})
+	assert.Empty(t, errs)
+
+	_, err := Parse([]byte(`{"type": 5}`))
+	assert.Error(t, err)
+}
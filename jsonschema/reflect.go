@@ -0,0 +1,297 @@
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	durationType      = reflect.TypeFor[time.Duration]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	unsafeNameChars   = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+)
+
+// Reflector builds schemas from Go types.
+type Reflector struct {
+	// RefPrefix enables definitions: named structs are added to Definitions
+	// and referenced as RefPrefix+name, e.g. "#/components/schemas/". When
+	// empty, structs are inlined.
+	RefPrefix string
+	// Definitions receives the named struct schemas when RefPrefix is set.
+	Definitions map[string]*Schema
+	// Overrides replaces the schemas of specific types.
+	Overrides map[reflect.Type]*Schema
+
+	names    map[reflect.Type]string
+	visiting map[reflect.Type]bool
+}
+
+// FromType returns the inlined schema of a type.
+//
+// Parameters:
+//   - t: The Go type.
+//
+// Returns:
+//   - *Schema: The schema.
+func FromType(t reflect.Type) *Schema {
+	return (&Reflector{}).Reflect(t)
+}
+
+// Reflect returns the schema of a type.
+//
+// Parameters:
+//   - t: The Go type.
+//
+// Returns:
+//   - *Schema: The schema.
+func (r *Reflector) Reflect(t reflect.Type) *Schema {
+	if s, ok := r.Overrides[t]; ok {
+		c := *s
+		return &c
+	}
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "string", Format: "duration"}
+	case rawMessageType:
+		return &Schema{}
+	}
+	if t.Implements(textMarshalerType) ||
+		reflect.PointerTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := r.Reflect(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		s := &Schema{Type: "array", Items: r.Reflect(t.Elem())}
+		if t.Kind() == reflect.Slice {
+			s.Nullable = true
+		}
+		return s
+	case reflect.Map:
+		return &Schema{
+			Type:                 "object",
+			Nullable:             true,
+			AdditionalProperties: r.Reflect(t.Elem()),
+		}
+	case reflect.Struct:
+		if t.Name() != "" && r.RefPrefix != "" {
+			return &Schema{Ref: r.RefPrefix + r.define(t)}
+		}
+		if r.visiting[t] {
+			return &Schema{} // recursive type without definitions
+		}
+		if r.visiting == nil {
+			r.visiting = map[reflect.Type]bool{}
+		}
+		r.visiting[t] = true
+		defer delete(r.visiting, t)
+		return r.structSchema(t)
+	}
+	return &Schema{}
+}
+
+// define adds a named struct to the definitions and returns its name.
+func (r *Reflector) define(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+	if r.names == nil {
+		r.names = map[reflect.Type]string{}
+	}
+	if r.Definitions == nil {
+		r.Definitions = map[string]*Schema{}
+	}
+	name := unsafeNameChars.ReplaceAllString(t.Name(), "_")
+	if _, taken := r.Definitions[name]; taken {
+		pkg := t.PkgPath()
+		name = unsafeNameChars.ReplaceAllString(
+			pkg[strings.LastIndex(pkg, "/")+1:]+"."+t.Name(), "_",
+		)
+	}
+	r.names[t] = name
+	r.Definitions[name] = &Schema{} // placeholder for recursive types
+	r.Definitions[name] = r.structSchema(t)
+	return name
+}
+
+// Object returns the inline object schema of a struct type, even when it
+// is named. Fields carrying any of skipTags are left out, e.g. fields bound
+// to path or query parameters; nested types are reflected as usual.
+//
+// Parameters:
+//   - t: The struct type, or a pointer to it.
+//   - skipTags: Tags marking fields to leave out.
+//
+// Returns:
+//   - *Schema: The object schema, or the type's schema for non-structs.
+func (r *Reflector) Object(t reflect.Type, skipTags ...string) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return r.Reflect(t)
+	}
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	r.addFields(s, t, skipTags)
+	return s
+}
+
+// structSchema returns the object schema of a struct.
+func (r *Reflector) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	r.addFields(s, t, nil)
+	return s
+}
+
+// addFields adds the JSON fields of a struct to an object schema.
+func (r *Reflector) addFields(s *Schema, t reflect.Type, skipTags []string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || hasAnyTag(f, skipTags) {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				r.addFields(s, ft, skipTags)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fs := r.Reflect(f.Type)
+		if c := f.Tag.Get("jsonschema"); c != "" {
+			fs = applyConstraints(fs, c)
+		}
+		s.Properties[name] = fs
+		if !strings.Contains(opts, "omitempty") &&
+			!strings.Contains(opts, "omitzero") &&
+			f.Type.Kind() != reflect.Pointer {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// hasAnyTag reports whether a field carries one of the tags.
+func hasAnyTag(f reflect.StructField, tags []string) bool {
+	for _, tag := range tags {
+		if _, ok := f.Tag.Lookup(tag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// applyConstraints applies "jsonschema" tag constraints to a field schema.
+func applyConstraints(s *Schema, tag string) *Schema {
+	for _, part := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "minimum":
+			s.Minimum = parseFloat(value)
+		case "maximum":
+			s.Maximum = parseFloat(value)
+		case "minLength":
+			s.MinLength = parseInt(value)
+		case "maxLength":
+			s.MaxLength = parseInt(value)
+		case "minItems":
+			s.MinItems = parseInt(value)
+		case "maxItems":
+			s.MaxItems = parseInt(value)
+		case "pattern":
+			s.Pattern = value
+		case "format":
+			s.Format = value
+		case "description":
+			s.Description = value
+		case "enum":
+			for _, v := range strings.Split(value, "|") {
+				s.Enum = append(s.Enum, enumValue(s.Type, v))
+			}
+		}
+	}
+	return s
+}
+
+// enumValue converts an enum tag value to the schema type.
+func enumValue(typ, v string) any {
+	switch typ {
+	case "integer", "number":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+// parseFloat parses a tag number, returning nil when malformed.
+func parseFloat(v string) *float64 {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil
+	}
+	return &f
+}
+
+// parseInt parses a tag integer, returning nil when malformed.
+func parseInt(v string) *int {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+// String returns the schema as JSON.
+func (s *Schema) String() string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Sprintf("jsonschema: %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,112 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Schema is a JSON Schema. The boolean schemas true and false parse to an
+// empty schema and a schema with an empty not, respectively.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Not                  *Schema            `json:"not,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+	Components           *Components        `json:"components,omitempty"`
+}
+
+// Components holds OpenAPI style reusable schemas, so $refs into
+// "#/components/schemas/" resolve.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// Parse parses a JSON Schema document.
+//
+// Parameters:
+//   - data: The JSON Schema document.
+//
+// Returns:
+//   - *Schema: The parsed schema.
+//   - error: An error if the document is not a valid schema.
+func Parse(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("jsonschema: %w", err)
+	}
+	return &s, nil
+}
+
+// MustParse is like Parse but panics on error. Use it for schemas embedded
+// in the program.
+//
+// Parameters:
+//   - data: The JSON Schema document.
+//
+// Returns:
+//   - *Schema: The parsed schema.
+func MustParse(data []byte) *Schema {
+	s, err := Parse(data)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// UnmarshalJSON parses a schema object or a boolean schema.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "true":
+		*s = Schema{}
+		return nil
+	case "false":
+		*s = Schema{Not: &Schema{}}
+		return nil
+	}
+	type plain Schema
+	aux := struct {
+		*plain
+		Type json.RawMessage `json:"type,omitempty"`
+	}{plain: (*plain)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.Type) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(aux.Type, &s.Type); err == nil {
+		return nil
+	}
+	// A list of types is supported for a single type optionally with null.
+	var types []string
+	if err := json.Unmarshal(aux.Type, &types); err != nil {
+		return fmt.Errorf("invalid type %s", aux.Type)
+	}
+	for _, t := range types {
+		switch {
+		case t == "null":
+			s.Nullable = true
+		case s.Type == "":
+			s.Type = t
+		default:
+			return fmt.Errorf("unsupported type list %s", aux.Type)
+		}
+	}
+	return nil
+}
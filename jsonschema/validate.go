@@ -0,0 +1,363 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Error describes a value violating a schema.
+type Error struct {
+	Path    string // Location of the value, e.g. "items[2].name"; empty for the root.
+	Message string // Description of the violation.
+}
+
+// Error returns the error message.
+func (e Error) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// ValidateJSON validates a JSON document against the schema.
+//
+// Parameters:
+//   - data: The JSON document.
+//
+// Returns:
+//   - []Error: The violations, empty if the document is valid.
+//   - error: An error if data is not valid JSON.
+func (s *Schema) ValidateJSON(data []byte) ([]Error, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return s.Validate(v), nil
+}
+
+// Validate validates a value decoded from JSON, with numbers as float64 or
+// json.Number.
+//
+// Parameters:
+//   - v: The decoded value.
+//
+// Returns:
+//   - []Error: The violations, empty if the value is valid.
+func (s *Schema) Validate(v any) []Error {
+	vs := &validation{root: s}
+	vs.validate(s, v, "", 0)
+	return vs.errs
+}
+
+// maxRefDepth bounds $ref resolution to catch reference cycles.
+const maxRefDepth = 64
+
+// validation collects the errors of one validation run.
+type validation struct {
+	root *Schema
+	errs []Error
+}
+
+// fail records a violation.
+func (vs *validation) fail(path, format string, args ...any) {
+	vs.errs = append(vs.errs, Error{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// validate checks v against s.
+func (vs *validation) validate(s *Schema, v any, path string, depth int) {
+	if s.Ref != "" {
+		if depth > maxRefDepth {
+			vs.fail(path, "schema reference %q is too deep", s.Ref)
+			return
+		}
+		target := vs.resolve(s.Ref)
+		if target == nil {
+			vs.fail(path, "unresolvable schema reference %q", s.Ref)
+			return
+		}
+		vs.validate(target, v, path, depth+1)
+		return
+	}
+	if s.Not != nil {
+		sub := &validation{root: vs.root}
+		sub.validate(s.Not, v, path, depth)
+		if len(sub.errs) == 0 {
+			vs.fail(path, "is not allowed")
+			return
+		}
+	}
+	if v == nil {
+		if s.Type != "" && !s.Nullable {
+			vs.fail(path, "must not be null")
+		}
+		return
+	}
+	if s.Type != "" && !hasType(v, s.Type) {
+		vs.fail(path, "must be of type %s", s.Type)
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(v, s.Enum) {
+		vs.fail(path, "must be one of %s", enumList(s.Enum))
+	}
+	switch t := v.(type) {
+	case string:
+		vs.validateString(s, t, path)
+	case json.Number, float64:
+		vs.validateNumber(s, toFloat(t), path)
+	case []any:
+		vs.validateArray(s, t, path, depth)
+	case map[string]any:
+		vs.validateObject(s, t, path, depth)
+	}
+}
+
+// resolve returns the schema a local reference points to.
+func (vs *validation) resolve(ref string) *Schema {
+	for _, prefix := range []struct {
+		p    string
+		defs map[string]*Schema
+	}{
+		{"#/$defs/", vs.root.Defs},
+		{"#/definitions/", vs.root.Definitions},
+		{"#/components/schemas/", vs.components()},
+	} {
+		if name, ok := strings.CutPrefix(ref, prefix.p); ok {
+			return prefix.defs[name]
+		}
+	}
+	if ref == "#" {
+		return vs.root
+	}
+	return nil
+}
+
+// components returns the component schemas of the root.
+func (vs *validation) components() map[string]*Schema {
+	if vs.root.Components == nil {
+		return nil
+	}
+	return vs.root.Components.Schemas
+}
+
+// validateString checks string constraints.
+func (vs *validation) validateString(s *Schema, v, path string) {
+	n := utf8.RuneCountInString(v)
+	if s.MinLength != nil && n < *s.MinLength {
+		vs.fail(path, "must be at least %d characters", *s.MinLength)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		vs.fail(path, "must be at most %d characters", *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		re, err := compilePattern(s.Pattern)
+		if err != nil {
+			vs.fail(path, "invalid pattern %q in schema", s.Pattern)
+		} else if !re.MatchString(v) {
+			vs.fail(path, "must match pattern %q", s.Pattern)
+		}
+	}
+	if msg := checkFormat(s.Format, v); msg != "" {
+		vs.fail(path, "%s", msg)
+	}
+}
+
+// validateNumber checks numeric constraints.
+func (vs *validation) validateNumber(s *Schema, v float64, path string) {
+	if s.Minimum != nil && v < *s.Minimum {
+		vs.fail(path, "must be at least %s", formatFloat(*s.Minimum))
+	}
+	if s.Maximum != nil && v > *s.Maximum {
+		vs.fail(path, "must be at most %s", formatFloat(*s.Maximum))
+	}
+}
+
+// validateArray checks array constraints and items.
+func (vs *validation) validateArray(s *Schema, v []any, path string, depth int) {
+	if s.MinItems != nil && len(v) < *s.MinItems {
+		vs.fail(path, "must have at least %d items", *s.MinItems)
+	}
+	if s.MaxItems != nil && len(v) > *s.MaxItems {
+		vs.fail(path, "must have at most %d items", *s.MaxItems)
+	}
+	if s.Items == nil {
+		return
+	}
+	for i, item := range v {
+		vs.validate(s.Items, item, path+"["+strconv.Itoa(i)+"]", depth)
+	}
+}
+
+// validateObject checks required and additional properties.
+func (vs *validation) validateObject(
+	s *Schema, v map[string]any, path string, depth int,
+) {
+	for _, name := range s.Required {
+		if _, ok := v[name]; !ok {
+			vs.fail(joinPath(path, name), "is required")
+		}
+	}
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if ps, ok := s.Properties[k]; ok {
+			vs.validate(ps, v[k], joinPath(path, k), depth)
+		} else if s.AdditionalProperties != nil {
+			vs.validate(s.AdditionalProperties, v[k], joinPath(path, k), depth)
+		}
+	}
+}
+
+// joinPath appends a property name to a path.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// hasType reports whether a decoded value has the JSON Schema type.
+func hasType(v any, typ string) bool {
+	switch typ {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		switch v.(type) {
+		case json.Number, float64:
+			return true
+		}
+	case "integer":
+		switch v.(type) {
+		case json.Number, float64:
+			f := toFloat(v)
+			return f == math.Trunc(f) && !math.IsInf(f, 0)
+		}
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "null":
+		return v == nil
+	}
+	return false
+}
+
+// toFloat converts a decoded number.
+func toFloat(v any) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case json.Number:
+		f, _ := n.Float64()
+		return f
+	case int:
+		return float64(n)
+	}
+	return math.NaN()
+}
+
+// inEnum reports whether v equals one of the enum values.
+func inEnum(v any, enum []any) bool {
+	for _, e := range enum {
+		if isNumber(e) && isNumber(v) {
+			if toFloat(e) == toFloat(v) {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNumber reports whether v is a number as decoded from JSON or parsed
+// from a tag.
+func isNumber(v any) bool {
+	switch v.(type) {
+	case json.Number, float64, int:
+		return true
+	}
+	return false
+}
+
+// enumList formats enum values for messages.
+func enumList(enum []any) string {
+	parts := make([]string, len(enum))
+	for i, e := range enum {
+		data, _ := json.Marshal(e)
+		parts[i] = string(data)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatFloat formats a limit without trailing zeros.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+var (
+	patternCache sync.Map // string -> *regexp.Regexp
+	uuidPattern  = regexp.MustCompile(
+		`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`,
+	)
+)
+
+// compilePattern compiles and caches a pattern.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.Store(pattern, re)
+	return re, nil
+}
+
+// checkFormat validates the known string formats and returns a message on
+// failure. Unknown formats are accepted.
+func checkFormat(format, v string) string {
+	switch format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, v); err != nil {
+			return "must be an RFC 3339 date-time"
+		}
+	case "date":
+		if _, err := time.Parse(time.DateOnly, v); err != nil {
+			return "must be a date (YYYY-MM-DD)"
+		}
+	case "email":
+		if addr, err := mail.ParseAddress(v); err != nil || addr.Address != v {
+			return "must be an email address"
+		}
+	case "uuid":
+		if !uuidPattern.MatchString(v) {
+			return "must be a UUID"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,19 @@
+// Package openapi generates OpenAPI 3 documents from endpoints.
+//
+// Operations are derived from the endpoint URL and method, the Operation
+// attached with endpoint.DefaultEndpoint.WithOperation, and the wrapper IDs
+// and access rules (x-middlewares and x-access) of middleware stacks. Input and output types are reflected into schemas:
+// fields tagged "path", "query" and "header" become parameters, the
+// remaining fields the request body.
+//
+// Example:
+//
+//	h := endpoint.NewTypedHandler(endpoint.BindInput[GetUser](), getUser,
+//		endpoint.DefaultErrorHandler{}, endpoint.TypedOutput[User](endpoint.JSONOutput()))
+//	ep := endpoint.NewEndpoint("/users/:id", http.MethodGet).
+//		WithOperation(endpoint.OperationOf(h)).
+//		WithHandler(h.Handle)
+//
+//	doc := openapi.Generate(openapi.Info{Title: "Users", Version: "1.0.0"}, ep)
+//	handler.Register(openapi.Endpoints(doc))
+package openapi
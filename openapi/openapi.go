@@ -0,0 +1,278 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/aatuh/pureapi-core/internal/bind"
+)
+
+// Version is the OpenAPI version of generated documents.
+const Version = "3.0.3"
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server is a base URL of the API.
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lower-case HTTP methods to operations.
+type PathItem map[string]*Operation
+
+// Operation describes one endpoint.
+type Operation struct {
+	OperationID string                `json:"operationId,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Middlewares []string              `json:"x-middlewares,omitempty"`
+	Access      []endpoint.AccessRule `json:"x-access,omitempty"`
+}
+
+// Parameter is a path, query or header parameter.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody describes the request body.
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes a response.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds reusable schemas.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// errorSchemaName is the component name of the API error schema.
+const errorSchemaName = "Error"
+
+// Generate builds a document describing the endpoints. Endpoints are
+// documented by their endpoint.Operation if they implement
+// endpoint.Documented; others only list their path parameters.
+//
+// Parameters:
+//   - info: The API description.
+//   - endpoints: The endpoints to describe.
+//
+// Returns:
+//   - *Document: The OpenAPI document.
+func Generate(info Info, endpoints ...endpoint.Endpoint) *Document {
+	g := newGenerator()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]PathItem{},
+	}
+	for _, ep := range endpoints {
+		path, params := convertPath(ep.URL())
+		item := doc.Paths[path]
+		if item == nil {
+			item = PathItem{}
+			doc.Paths[path] = item
+		}
+		item[strings.ToLower(ep.Method())] = g.operation(ep, params)
+	}
+	doc.Components.Schemas = g.schemas
+	return doc
+}
+
+// operation describes an endpoint.
+func (g *generator) operation(
+	ep endpoint.Endpoint, pathParams []string,
+) *Operation {
+	var meta endpoint.Operation
+	if d, ok := ep.(endpoint.Documented); ok {
+		meta = d.Operation()
+	}
+	op := &Operation{
+		OperationID: meta.ID,
+		Summary:     meta.Summary,
+		Description: meta.Description,
+		Tags:        meta.Tags,
+		Deprecated:  meta.Deprecated,
+		Responses: map[string]Response{
+			"default": {
+				Description: "Error",
+				Content: jsonContent(
+					&Schema{Ref: schemaPrefix + errorSchemaName},
+				),
+			},
+		},
+	}
+	if s, ok := ep.Middlewares().(interface{ Wrappers() []endpoint.Wrapper }); ok {
+		for _, w := range s.Wrappers() {
+			op.Middlewares = append(op.Middlewares, w.ID())
+		}
+	}
+	op.Access = endpoint.AccessRules(ep.Middlewares())
+	op.Parameters, op.RequestBody = g.input(meta.Input, ep.Method(), pathParams)
+	ok := Response{Description: http.StatusText(http.StatusOK)}
+	if meta.Output != nil {
+		ok.Content = jsonContent(g.schema(meta.Output))
+	}
+	op.Responses["200"] = ok
+	return op
+}
+
+// input derives parameters and the request body from the input type.
+func (g *generator) input(
+	t reflect.Type, method string, pathParams []string,
+) ([]Parameter, *RequestBody) {
+	var params []Parameter
+	declared := map[string]bool{}
+	st := t
+	for st != nil && st.Kind() == reflect.Pointer {
+		st = st.Elem()
+	}
+	if st != nil && st.Kind() == reflect.Struct {
+		for _, in := range []string{"path", "query", "header"} {
+			for _, f := range bind.Fields(st, in) {
+				params = append(params, Parameter{
+					Name:     f.Name,
+					In:       in,
+					Required: in == "path",
+					Schema:   g.schema(f.Type),
+				})
+				if in == "path" {
+					declared[f.Name] = true
+				}
+			}
+		}
+	}
+	for _, name := range pathParams {
+		if !declared[name] {
+			params = append(params, Parameter{
+				Name: name, In: "path", Required: true,
+				Schema: &Schema{Type: "string"},
+			})
+		}
+	}
+	if st == nil || !hasBody(method) {
+		return params, nil
+	}
+	return params, g.requestBody(st)
+}
+
+// requestBody describes the body fields of an input type.
+func (g *generator) requestBody(t reflect.Type) *RequestBody {
+	if t.Kind() != reflect.Struct {
+		return &RequestBody{Required: true, Content: jsonContent(g.schema(t))}
+	}
+	// Prefer JSON unless the type only declares form fields or files.
+	if form := g.formSchema(t); form != nil &&
+		(hasFiles(t) || len(bind.Fields(t, "json")) == 0) {
+		mediaType := "application/x-www-form-urlencoded"
+		if hasFiles(t) {
+			mediaType = "multipart/form-data"
+		}
+		return &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{mediaType: {Schema: form}},
+		}
+	}
+	body := g.bodySchema(t)
+	if len(body.Properties) == 0 {
+		return nil
+	}
+	return &RequestBody{Required: true, Content: jsonContent(body)}
+}
+
+// formSchema returns the schema of "form" tagged fields, or nil if the type
+// has none.
+func (g *generator) formSchema(t reflect.Type) *Schema {
+	fields := bind.Fields(t, "form")
+	if len(fields) == 0 {
+		return nil
+	}
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, f := range fields {
+		s.Properties[f.Name] = g.schema(f.Type)
+	}
+	return s
+}
+
+// hasFiles reports whether a type has uploaded file fields.
+func hasFiles(t reflect.Type) bool {
+	for _, f := range bind.Fields(t, "form") {
+		if f.Type == uploadedFileType || f.Type == uploadedFileSliceType {
+			return true
+		}
+	}
+	return false
+}
+
+// hasBody reports whether requests of the method carry a body.
+func hasBody(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return true
+	}
+	return false
+}
+
+// convertPath turns a router pattern into an OpenAPI path template and
+// returns the names of its parameters.
+func convertPath(pattern string) (string, []string) {
+	segs := strings.Split(pattern, "/")
+	var params []string
+	for i, seg := range segs {
+		var name string
+		switch {
+		case strings.HasPrefix(seg, ":"), strings.HasPrefix(seg, "*"):
+			name = seg[1:]
+		case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}"):
+			name = seg[1 : len(seg)-1]
+		default:
+			continue
+		}
+		if name == "" {
+			continue
+		}
+		segs[i] = "{" + name + "}"
+		params = append(params, name)
+	}
+	return strings.Join(segs, "/"), params
+}
+
+// jsonContent returns JSON content with the schema.
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: s}}
+}
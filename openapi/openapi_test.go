@@ -0,0 +1,141 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Address struct {
+	City string `json:"city"`
+}
+
+type User struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Email     *string   `json:"email,omitempty"`
+	Tags      []string  `json:"tags"`
+	Address   Address   `json:"address"`
+	Manager   *User     `json:"manager,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	secret    string
+}
+
+type UpdateUser struct {
+	ID     int64  `path:"id"`
+	DryRun bool   `query:"dry_run"`
+	APIKey string `header:"X-Api-Key"`
+	Name   string `json:"name"`
+	Note   string `json:"note,omitempty"`
+}
+
+type Upload struct {
+	Title string                 `form:"title"`
+	File  *endpoint.UploadedFile `form:"file"`
+}
+
+func updateUser(
+	_ http.ResponseWriter, _ *http.Request, in *UpdateUser,
+) (User, error) {
+	return User{ID: in.ID, Name: in.Name}, nil
+}
+
+func testEndpoints() []endpoint.Endpoint {
+	h := endpoint.NewTypedHandler(endpoint.BindInput[UpdateUser](), updateUser,
+		endpoint.DefaultErrorHandler{}, endpoint.TypedOutput[User](endpoint.JSONOutput()))
+	op := endpoint.OperationOf(h)
+	op.ID, op.Summary, op.Tags = "updateUser", "Update a user", []string{"users"}
+
+	upload := endpoint.NewHandler(endpoint.MultipartInput[Upload](),
+		func(http.ResponseWriter, *http.Request, *Upload) (any, error) { return nil, nil },
+		endpoint.DefaultErrorHandler{}, endpoint.JSONOutput())
+
+	stack := endpoint.NewStack(endpoint.NewWrapper("auth",
+		func(next http.Handler) http.Handler { return next }),
+		endpoint.RequireAccess(endpoint.AccessRule{Scopes: []string{"users:write"}}))
+
+	return []endpoint.Endpoint{
+		endpoint.NewEndpoint("/users/:id", http.MethodPut).
+			WithOperation(op).WithHandler(h.Handle).WithMiddlewares(stack),
+		endpoint.NewEndpoint("/uploads", http.MethodPost).
+			WithOperation(endpoint.OperationOf(upload)).WithHandler(upload.Handle),
+		endpoint.NewEndpoint("/files/*path", http.MethodGet),
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	doc := Generate(Info{Title: "Test", Version: "1.0.0"}, testEndpoints()...)
+	assert.Equal(t, Version, doc.OpenAPI)
+
+	op := doc.Paths["/users/{id}"]["put"]
+	require.NotNil(t, op)
+	assert.Equal(t, "updateUser", op.OperationID)
+	assert.Equal(t, []string{"users"}, op.Tags)
+	assert.Equal(t, []string{"auth", "authorize"}, op.Middlewares)
+	assert.Equal(t, []endpoint.AccessRule{{Scopes: []string{"users:write"}}}, op.Access)
+	assert.Equal(t, []Parameter{
+		{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "integer", Format: "int64"}},
+		{Name: "dry_run", In: "query", Schema: &Schema{Type: "boolean"}},
+		{Name: "X-Api-Key", In: "header", Schema: &Schema{Type: "string"}},
+	}, op.Parameters)
+
+	require.NotNil(t, op.RequestBody)
+	body := op.RequestBody.Content["application/json"].Schema
+	assert.Equal(t, []string{"name"}, body.Required)
+	assert.Len(t, body.Properties, 2, "parameter fields are not part of the body")
+	assert.Equal(t, "#/components/schemas/User",
+		op.Responses["200"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/Error",
+		op.Responses["default"].Content["application/json"].Schema.Ref)
+
+	user := doc.Components.Schemas["User"]
+	require.NotNil(t, user)
+	assert.ElementsMatch(t, []string{"id", "name", "tags", "address", "created_at"}, user.Required)
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, user.Properties["created_at"])
+	assert.Equal(t, "#/components/schemas/User", user.Properties["manager"].Ref)
+	assert.True(t, user.Properties["email"].Nullable)
+	assert.NotContains(t, user.Properties, "secret")
+	assert.NotNil(t, doc.Components.Schemas["Address"])
+
+	upload := doc.Paths["/uploads"]["post"]
+	require.NotNil(t, upload)
+	form := upload.RequestBody.Content["multipart/form-data"].Schema
+	require.NotNil(t, form)
+	assert.Equal(t, &Schema{Type: "string", Format: "binary"}, form.Properties["file"])
+	assert.Nil(t, upload.Responses["200"].Content, "untyped output")
+
+	files := doc.Paths["/files/{path}"]["get"]
+	require.NotNil(t, files)
+	assert.Equal(t, []Parameter{
+		{Name: "path", In: "path", Required: true, Schema: &Schema{Type: "string"}},
+	}, files.Parameters)
+	assert.Nil(t, files.RequestBody)
+}
+
+func TestEndpoints(t *testing.T) {
+	doc := Generate(Info{Title: "Test", Version: "1.0.0"}, testEndpoints()...)
+	eps := Endpoints(doc, WithSpecPath("/api/openapi.json"))
+	require.Len(t, eps, 2)
+	assert.Equal(t, "/api/openapi.json", eps[0].URL())
+	assert.Equal(t, "/docs", eps[1].URL())
+
+	rec := httptest.NewRecorder()
+	eps[0].Handler()(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &decoded))
+	assert.Equal(t, "3.0.3", decoded["openapi"])
+
+	rec = httptest.NewRecorder()
+	eps[1].Handler()(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.True(t, strings.Contains(rec.Body.String(), `url: "/api/openapi.json"`))
+
+	assert.Len(t, Endpoints(doc, WithUIPath("")), 1)
+}
@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"reflect"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/aatuh/pureapi-core/jsonschema"
+)
+
+// Schema is a JSON Schema subset as used by OpenAPI 3.0.
+type Schema = jsonschema.Schema
+
+// schemaPrefix is the reference prefix of component schemas.
+const schemaPrefix = "#/components/schemas/"
+
+var (
+	uploadedFileType      = reflect.TypeFor[*endpoint.UploadedFile]()
+	uploadedFileSliceType = reflect.TypeFor[[]*endpoint.UploadedFile]()
+)
+
+// generator reflects types into schemas, collecting named structs as
+// components.
+type generator struct {
+	reflector *jsonschema.Reflector
+	schemas   map[string]*Schema
+}
+
+// newGenerator returns a generator with the API error component.
+func newGenerator() *generator {
+	schemas := map[string]*Schema{
+		errorSchemaName: {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"id":      {Type: "string"},
+				"message": {Type: "string"},
+				"data":    {},
+				"origin":  {Type: "string"},
+			},
+			Required: []string{"id"},
+		},
+	}
+	return &generator{
+		reflector: &jsonschema.Reflector{
+			RefPrefix:   schemaPrefix,
+			Definitions: schemas,
+			Overrides: map[reflect.Type]*Schema{
+				uploadedFileType: {Type: "string", Format: "binary"},
+			},
+		},
+		schemas: schemas,
+	}
+}
+
+// schema returns the schema of a type. Named structs are referenced.
+func (g *generator) schema(t reflect.Type) *Schema {
+	return g.reflector.Reflect(t)
+}
+
+// bodySchema returns the inline object schema of an input struct, leaving
+// out fields bound to path, query or header parameters.
+func (g *generator) bodySchema(t reflect.Type) *Schema {
+	return g.reflector.Object(t, "path", "query", "header")
+}
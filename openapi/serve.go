@@ -0,0 +1,128 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+)
+
+// ServeOption configures the documentation endpoints.
+type ServeOption func(*serveConfig)
+
+// serveConfig holds the documentation endpoint settings.
+type serveConfig struct {
+	specPath string
+	uiPath   string
+}
+
+// WithSpecPath sets the path of the JSON document. Defaults to
+// "/openapi.json".
+//
+// Parameters:
+//   - path: The document path.
+//
+// Returns:
+//   - ServeOption: A serve option function.
+func WithSpecPath(path string) ServeOption {
+	return func(c *serveConfig) { c.specPath = path }
+}
+
+// WithUIPath sets the path of the Swagger UI page. Defaults to "/docs".
+// An empty path disables the UI.
+//
+// Parameters:
+//   - path: The UI path.
+//
+// Returns:
+//   - ServeOption: A serve option function.
+func WithUIPath(path string) ServeOption {
+	return func(c *serveConfig) { c.uiPath = path }
+}
+
+// Endpoints returns GET endpoints serving the document as JSON and a
+// Swagger UI page loading it. The UI loads its assets from a public CDN.
+//
+// Parameters:
+//   - doc: The document to serve.
+//   - opts: Optional serve options.
+//
+// Returns:
+//   - []endpoint.Endpoint: The documentation endpoints.
+func Endpoints(doc *Document, opts ...ServeOption) []endpoint.Endpoint {
+	cfg := serveConfig{specPath: "/openapi.json", uiPath: "/docs"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	endpoints := []endpoint.Endpoint{
+		endpoint.NewEndpoint(cfg.specPath, http.MethodGet).
+			WithHandler(Handler(doc).ServeHTTP),
+	}
+	if cfg.uiPath != "" {
+		endpoints = append(endpoints,
+			endpoint.NewEndpoint(cfg.uiPath, http.MethodGet).
+				WithHandler(UIHandler(cfg.specPath).ServeHTTP),
+		)
+	}
+	return endpoints
+}
+
+// Handler returns a handler serving the document as JSON. The document is
+// encoded once; later changes to it are not served.
+//
+// Parameters:
+//   - doc: The document to serve.
+//
+// Returns:
+//   - http.Handler: The document handler.
+func Handler(doc *Document) http.Handler {
+	body, err := json.Marshal(doc)
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if err != nil {
+			http.Error(w, "openapi: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write(body)
+	})
+}
+
+// swaggerUIPage is the Swagger UI page; %s is the document URL as a
+// JavaScript string literal.
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>API documentation</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+<script>
+window.onload = function () {
+  window.ui = SwaggerUIBundle({url: %s, dom_id: "#swagger-ui"});
+};
+</script>
+</body>
+</html>
+`
+
+// UIHandler returns a handler serving a Swagger UI page for the document
+// at specURL.
+//
+// Parameters:
+//   - specURL: The URL of the JSON document.
+//
+// Returns:
+//   - http.Handler: The UI handler.
+func UIHandler(specURL string) http.Handler {
+	// JSON string escaping also escapes <, > and & for script contexts.
+	literal, _ := json.Marshal(specURL)
+	page := fmt.Sprintf(swaggerUIPage, literal)
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(page))
+	})
+}
@@ -41,7 +41,10 @@
 package pureapi
 
 import (
+	"context"
+	"io/fs"
 	"net/http"
+	"time"
 
 	"github.com/aatuh/pureapi-core/apierror"
 	"github.com/aatuh/pureapi-core/endpoint"
@@ -146,6 +149,37 @@ func (r *registeredEndpoint) WithHandler(h http.HandlerFunc) endpoint.Endpoint {
 	return r
 }
 
+// BodyLimit returns the request body limit of the registered endpoint.
+//
+// Returns:
+//   - int64: The limit in bytes, 0 if the server-wide limit applies.
+func (r *registeredEndpoint) BodyLimit() int64 {
+	if bl, ok := r.ep.(endpoint.BodyLimiter); ok {
+		return bl.BodyLimit()
+	}
+	return 0
+}
+
+// WithBodyLimit updates the request body limit of the registered endpoint,
+// overriding the server-wide limit. Use a negative value to disable it.
+//
+// Parameters:
+//   - limit: The maximum request body size in bytes.
+//
+// Returns:
+//   - endpoint.Endpoint: The updated endpoint for chaining.
+func (r *registeredEndpoint) WithBodyLimit(limit int64) endpoint.Endpoint {
+	de, ok := r.ep.(*endpoint.DefaultEndpoint)
+	if !ok {
+		return r
+	}
+	oldURL, oldMethod := r.ep.URL(), r.ep.Method()
+	r.ep = de.WithBodyLimit(limit)
+	r.s.Unregister(oldMethod, oldURL)
+	r.s.Register([]endpoint.Endpoint{r.ep})
+	return r
+}
+
 // ServerOption configures the underlying HTTP handler.
 type ServerOption = server.HandlerOption
 
@@ -237,6 +271,27 @@ func (s *Server) Delete(path string, fn http.HandlerFunc) endpoint.Endpoint {
 	return &registeredEndpoint{s: s.h, ep: ep}
 }
 
+// StaticOption configures a static file endpoint.
+type StaticOption = server.StaticOption
+
+// Static registers a GET route serving files from fsys below prefix and
+// returns the created endpoint for chaining.
+//
+// Parameters:
+//   - prefix: The URL prefix, e.g. "/assets".
+//   - fsys: The file system to serve, e.g. os.DirFS or embed.FS.
+//   - opts: Optional static options.
+//
+// Returns:
+//   - endpoint.Endpoint: The created endpoint for method chaining.
+func (s *Server) Static(
+	prefix string, fsys fs.FS, opts ...StaticOption,
+) endpoint.Endpoint {
+	ep := server.Static(prefix, fsys, opts...)
+	s.h.Register([]endpoint.Endpoint{ep})
+	return &registeredEndpoint{s: s.h, ep: ep}
+}
+
 // WithRouter sets the router to use.
 //
 // Parameters:
@@ -264,6 +319,90 @@ func WithCustomNotFound(h http.Handler) ServerOption { return server.WithNotFoun
 //   - ServerOption: A server option function.
 func WithBodyLimit(limit int64) ServerOption { return server.WithBodyLimit(limit) }
 
+// CompressionOption configures response compression.
+type CompressionOption = server.CompressionOption
+
+// WithCompression enables gzip response compression for compressible
+// content types.
+//
+// Parameters:
+//   - opts: Optional compression options.
+//
+// Returns:
+//   - ServerOption: A server option function.
+func WithCompression(opts ...CompressionOption) ServerOption {
+	return server.WithCompression(opts...)
+}
+
+// CIDR is an IP network used by the IP filter and trusted proxies.
+type CIDR = server.CIDR
+
+// WithTrustedProxies sets reverse proxies whose X-Forwarded-For header is
+// trusted when resolving the client IP.
+//
+// Parameters:
+//   - proxies: The trusted proxy networks.
+//
+// Returns:
+//   - ServerOption: A server option function.
+func WithTrustedProxies(proxies []CIDR) ServerOption {
+	return server.WithTrustedProxies(proxies)
+}
+
+// WithIPFilter rejects clients outside allow or inside deny with 403.
+//
+// Parameters:
+//   - allow: Networks allowed to access the server, empty allows all.
+//   - deny: Networks denied access.
+//
+// Returns:
+//   - ServerOption: A server option function.
+func WithIPFilter(allow, deny []CIDR) ServerOption {
+	return server.WithIPFilter(allow, deny)
+}
+
+// WithPanicDebug renders panic values and stacks in 500 responses. Only
+// enable it in development.
+//
+// Parameters:
+//   - enabled: Whether to render panic details.
+//
+// Returns:
+//   - ServerOption: A server option function.
+func WithPanicDebug(enabled bool) ServerOption { return server.WithPanicDebug(enabled) }
+
+// WithSizeEvents emits a server.EventRequestSize event per request with the
+// request and response byte counts.
+//
+// Returns:
+//   - ServerOption: A server option function.
+func WithSizeEvents() ServerOption { return server.WithSizeEvents() }
+
+// WithMicroCache caches successful GET responses for a short TTL.
+//
+// Parameters:
+//   - ttl: How long responses are served from the cache.
+//
+// Returns:
+//   - ServerOption: A server option function.
+func WithMicroCache(ttl time.Duration) ServerOption { return server.WithMicroCache(ttl) }
+
+// ErrorRenderer renders responses for server-level failures such as 404,
+// 405, 413 and panics.
+type ErrorRenderer = server.ErrorRenderer
+
+// WithErrorRenderer sets the renderer for server-level failures. Use
+// server.JSONErrorRenderer{} to answer them with apierror JSON.
+//
+// Parameters:
+//   - renderer: The error renderer to use.
+//
+// Returns:
+//   - ServerOption: A server option function.
+func WithErrorRenderer(renderer ErrorRenderer) ServerOption {
+	return server.WithErrorRenderer(renderer)
+}
+
 // WithQueryDecoder sets the query decoder to use.
 //
 // Parameters:
@@ -273,6 +412,17 @@ func WithBodyLimit(limit int64) ServerOption { return server.WithBodyLimit(limit
 //   - ServerOption: A server option function.
 func WithQueryDecoder(d querydec.Decoder) ServerOption { return server.WithQueryDecoder(d) }
 
+// WithStructDecoder sets the decoder of QueryAs.
+//
+// Parameters:
+//   - d: The struct decoder to use.
+//
+// Returns:
+//   - ServerOption: A server option function.
+func WithStructDecoder(d querydec.StructDecoder) ServerOption {
+	return server.WithStructDecoder(d)
+}
+
 // WithEventEmitter sets a custom event emitter for the server.
 func WithEventEmitter(em event.EventEmitter) ServerOption { return server.WithEventEmitter(em) }
 
@@ -291,6 +441,30 @@ func NewBuiltinRouter() router.Router { return router.NewBuiltinRouter() }
 //   - map[string]any: The decoded query parameters.
 func QueryMap(r *http.Request) map[string]any { return server.QueryMap(r) }
 
+// Query exposes the decoded query parameters and the error of the query
+// decoder, such as the 400 invalid_query error of querydec.ParamDecoder.
+//
+// Parameters:
+//   - r: The HTTP request.
+//
+// Returns:
+//   - map[string]any: The decoded query parameters.
+//   - error: The error of the query decoder.
+func Query(r *http.Request) (map[string]any, error) { return server.Query(r) }
+
+// QueryAs decodes the query of a request into a struct with the configured
+// struct decoder, caching the result in the request context.
+//
+// Parameters:
+//   - r: The HTTP request.
+//
+// Returns:
+//   - T: The decoded struct.
+//   - error: The 400 invalid_query API error, or an error of the decoder.
+func QueryAs[T any](r *http.Request) (T, error) {
+	return server.QueryAs[T](r)
+}
+
 // RouteParams exposes route parameters extracted by the router.
 //
 // Parameters:
@@ -311,9 +485,32 @@ type HandlerLogicFn[T any] func(http.ResponseWriter, *http.Request, *T) (any, er
 // ErrorHandler maps errors to API errors and status codes.
 type ErrorHandler = endpoint.ErrorHandler
 
+// ErrorRegistry maps errors to status codes and public API errors.
+type ErrorRegistry = endpoint.ErrorRegistry
+
+// NewErrorRegistry returns an error registry with the default mappings.
+//
+// Returns:
+//   - *ErrorRegistry: A new ErrorRegistry instance.
+func NewErrorRegistry() *ErrorRegistry { return endpoint.NewErrorRegistry() }
+
 // OutputHandler writes the response.
 type OutputHandler = endpoint.OutputHandler
 
+// Response lets handler logic set the status, headers and cookies of the
+// success response.
+type Response = endpoint.Response
+
+// NewResponse creates a response with the body and the handler's success
+// status.
+//
+// Parameters:
+//   - body: The value passed to the output handler.
+//
+// Returns:
+//   - *Response: A new Response instance.
+func NewResponse(body any) *Response { return endpoint.NewResponse(body) }
+
 // NewHandler constructs the default endpoint handler pipeline.
 //
 // Parameters:
@@ -335,6 +532,48 @@ func NewHandler[T any](
 	)
 }
 
+// TypedLogicFn is a handler logic function returning a typed output.
+type TypedLogicFn[T, O any] func(http.ResponseWriter, *http.Request, *T) (O, error)
+
+// TypedOutputHandler writes typed endpoint responses.
+type TypedOutputHandler[O any] interface {
+	endpoint.TypedOutputHandler[O]
+}
+
+// NewTypedHandler constructs an endpoint handler pipeline whose logic
+// returns a typed output.
+//
+// Parameters:
+//   - ih: The input handler for processing request input.
+//   - lf: The typed handler logic function.
+//   - eh: The error handler for mapping errors to API responses.
+//   - oh: The typed output handler for writing responses.
+//
+// Returns:
+//   - endpoint.Handler[T]: A new handler instance.
+func NewTypedHandler[T, O any](
+	ih InputHandler[T], lf TypedLogicFn[T, O], eh ErrorHandler,
+	oh TypedOutputHandler[O],
+) endpoint.Handler[T] {
+	return endpoint.NewTypedHandler(
+		asEndpointInputHandler(ih),
+		endpoint.TypedLogicFn[T, O](lf),
+		eh,
+		oh,
+	)
+}
+
+// TypedOutput adapts an output handler such as JSONOutput to a typed one.
+//
+// Parameters:
+//   - oh: The output handler to adapt.
+//
+// Returns:
+//   - TypedOutputHandler[O]: The typed output handler.
+func TypedOutput[O any](oh OutputHandler) TypedOutputHandler[O] {
+	return endpoint.TypedOutput[O](oh)
+}
+
 func asEndpointInputHandler[T any](ih InputHandler[T]) endpoint.InputHandler[T] {
 	if ih == nil {
 		return nil
@@ -346,6 +585,209 @@ func asEndpointInputHandler[T any](ih InputHandler[T]) endpoint.InputHandler[T]
 	return v
 }
 
+// JSONInputOption configures the built-in JSON input handler.
+type JSONInputOption = endpoint.JSONInputOption
+
+// JSONInput returns the built-in JSON input handler.
+//
+// Parameters:
+//   - opts: Optional JSON input options.
+//
+// Returns:
+//   - InputHandler[T]: The JSON input handler.
+func JSONInput[T any](opts ...JSONInputOption) InputHandler[T] {
+	return endpoint.JSONInput[T](opts...)
+}
+
+// FieldError describes an input field that failed to bind or validate.
+type FieldError = endpoint.FieldError
+
+// Validator is implemented by inputs validated after decoding.
+type Validator = endpoint.Validator
+
+// ContextValidator is implemented by inputs validated with the request
+// context after decoding.
+type ContextValidator = endpoint.ContextValidator
+
+// ValidationError is the error passed to the ErrorHandler when an input
+// fails validation.
+type ValidationError = endpoint.ValidationError
+
+// FormInput returns the built-in form-urlencoded input handler.
+//
+// Returns:
+//   - InputHandler[T]: The form input handler.
+func FormInput[T any]() InputHandler[T] {
+	return endpoint.FormInput[T]()
+}
+
+// BindInputOption configures the built-in binding input handler.
+type BindInputOption = endpoint.BindInputOption
+
+// BindInput returns the built-in input handler binding the body, route
+// params, query parameters and headers by struct tags.
+//
+// Parameters:
+//   - opts: Optional bind input options.
+//
+// Returns:
+//   - InputHandler[T]: The binding input handler.
+func BindInput[T any](opts ...BindInputOption) InputHandler[T] {
+	return endpoint.BindInput[T](opts...)
+}
+
+// ComposeInput returns an input handler merging the results of several
+// input handlers, later handlers taking precedence.
+//
+// Parameters:
+//   - handlers: The input handlers, lowest precedence first.
+//
+// Returns:
+//   - InputHandler[T]: The composite input handler.
+func ComposeInput[T any](handlers ...InputHandler[T]) InputHandler[T] {
+	ihs := make([]endpoint.InputHandler[T], len(handlers))
+	for i, ih := range handlers {
+		ihs[i] = asEndpointInputHandler(ih)
+	}
+	return endpoint.ComposeInput(ihs...)
+}
+
+// SanitizeOption configures SanitizeInput.
+type SanitizeOption = endpoint.SanitizeOption
+
+// SanitizeInput returns an input handler cleaning the string fields tagged
+// "sanitize" after decoding and before validation.
+//
+// Parameters:
+//   - ih: The input handler decoding the input.
+//   - opts: Optional sanitize options.
+//
+// Returns:
+//   - InputHandler[T]: The sanitizing input handler.
+func SanitizeInput[T any](ih InputHandler[T], opts ...SanitizeOption) InputHandler[T] {
+	return endpoint.SanitizeInput(asEndpointInputHandler(ih), opts...)
+}
+
+// UploadedFile describes a file part of a multipart request.
+type UploadedFile = endpoint.UploadedFile
+
+// MultipartInputOption configures the built-in multipart input handler.
+type MultipartInputOption = endpoint.MultipartInputOption
+
+// MultipartInput returns the built-in multipart/form-data input handler.
+//
+// Parameters:
+//   - opts: Optional multipart input options.
+//
+// Returns:
+//   - InputHandler[T]: The multipart input handler.
+func MultipartInput[T any](opts ...MultipartInputOption) InputHandler[T] {
+	return endpoint.MultipartInput[T](opts...)
+}
+
+// StreamedPart is a multipart part passed to a StreamMultipartInput
+// callback.
+type StreamedPart = endpoint.StreamedPart
+
+// StreamMultipartInput returns a multipart/form-data input handler passing
+// each part to fn as it arrives instead of buffering it.
+//
+// Parameters:
+//   - fn: The part callback.
+//   - opts: Optional multipart input options.
+//
+// Returns:
+//   - InputHandler[T]: The streaming multipart input handler.
+func StreamMultipartInput[T any](
+	fn func(ctx context.Context, in *T, part *StreamedPart) error,
+	opts ...MultipartInputOption,
+) InputHandler[T] {
+	return endpoint.StreamMultipartInput(endpoint.PartFunc[T](fn), opts...)
+}
+
+// SSEStream writes Server-Sent Events to a response.
+type SSEStream = endpoint.SSEStream
+
+// SSEEvent is a Server-Sent Event.
+type SSEEvent = endpoint.SSEEvent
+
+// SSEOption configures an SSE stream.
+type SSEOption = endpoint.SSEOption
+
+// NewSSEStream starts an event stream that ends on client disconnect or
+// graceful shutdown.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+//   - opts: Optional SSE options.
+//
+// Returns:
+//   - *SSEStream: The event stream.
+//   - error: An error if the response cannot be flushed.
+func NewSSEStream(
+	w http.ResponseWriter, r *http.Request, opts ...SSEOption,
+) (*SSEStream, error) {
+	return endpoint.NewSSEStream(w, r, opts...)
+}
+
+// PageRequest is the pagination requested by a client.
+type PageRequest = endpoint.PageRequest
+
+// PaginationOption configures page request parsing.
+type PaginationOption = endpoint.PaginationOption
+
+// ParsePageRequest reads page, per_page and cursor from the decoded query
+// of the request, falling back to the raw query outside a Server.
+//
+// Parameters:
+//   - r: The HTTP request.
+//   - opts: Optional pagination options.
+//
+// Returns:
+//   - PageRequest: The page request.
+//   - error: An API error if a parameter is malformed.
+func ParsePageRequest(
+	r *http.Request, opts ...PaginationOption,
+) (PageRequest, error) {
+	query := server.QueryMap(r)
+	if query == nil {
+		query, _ = querydec.PlainDecoder{}.Decode(r.URL.Query())
+	}
+	return endpoint.ParsePageRequest(query, opts...)
+}
+
+// Encoder serializes response values for one media type.
+type Encoder = endpoint.Encoder
+
+// JSONOutput returns an output handler that always writes JSON.
+//
+// Returns:
+//   - OutputHandler: The JSON output handler.
+func JSONOutput() OutputHandler { return endpoint.JSONOutput() }
+
+// NegotiatingOutput returns an output handler choosing the encoder from the
+// Accept header, answering 406 when none matches.
+//
+// Parameters:
+//   - encoders: The available encoders, in order of preference.
+//
+// Returns:
+//   - OutputHandler: The negotiating output handler.
+func NegotiatingOutput(encoders ...Encoder) OutputHandler {
+	return endpoint.NegotiatingOutput(encoders...)
+}
+
+// Stream is an output streaming raw bytes, such as a file download.
+type Stream = endpoint.Stream
+
+// StreamOutput returns an output handler writing Stream outputs, honoring
+// Range headers when the content is seekable.
+//
+// Returns:
+//   - OutputHandler: The stream output handler.
+func StreamOutput() OutputHandler { return endpoint.StreamOutput() }
+
 // Middleware wraps an http.Handler.
 type Middleware = endpoint.Middleware
 
@@ -399,3 +841,23 @@ func NewAPIError(id string) *apierror.DefaultAPIError { return apierror.NewAPIEr
 // Returns:
 //   - *apierror.DefaultAPIError: The converted API error.
 func APIErrorFrom(err APIError) *apierror.DefaultAPIError { return apierror.APIErrorFrom(err) }
+
+// ParseAPIError parses an API error response body.
+//
+// Parameters:
+//   - data: The response body.
+//
+// Returns:
+//   - *apierror.DefaultAPIError: The parsed API error.
+//   - error: An error if data is not an API error.
+func ParseAPIError(data []byte) (*apierror.DefaultAPIError, error) {
+	return apierror.ParseAPIError(data)
+}
+
+// RegisterErrorStatus maps an API error ID to an HTTP status code for all
+// error handlers.
+//
+// Parameters:
+//   - id: The error ID.
+//   - status: The HTTP status code.
+func RegisterErrorStatus(id string, status int) { apierror.RegisterStatus(id, status) }
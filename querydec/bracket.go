@@ -0,0 +1,144 @@
+package querydec
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// defaultMaxDepth is the nesting depth limit of a zero BracketDecoder.
+const defaultMaxDepth = 8
+
+// BracketDecoder implements the bracket syntax of REST filtering
+// conventions, decoding `?filter[name]=x&filter[age][gte]=30` into nested
+// maps:
+//
+//	map[string]any{"filter": map[string]any{
+//		"name": "x",
+//		"age":  map[string]any{"gte": "30"},
+//	}}
+//
+// A trailing "[]" collects the values into a []string even if there is
+// one, `?id[]=1&id[]=2` giving map[string]any{"id": []string{"1", "2"}}.
+// Other leaves hold a string, or a []string for repeated parameters, as
+// with PlainDecoder. Keys that are malformed, nest deeper than MaxDepth,
+// or use a name both as a value and as a map are rejected.
+//
+// With Dots set, the dot notation of clients that cannot send brackets
+// nests too: `?filter.age.gte=30` decodes like `?filter[age][gte]=30`,
+// and the notations mix, as in `?filter.age[gte]=30`. Dots within
+// brackets are kept.
+type BracketDecoder struct {
+	// MaxDepth limits the number of nested segments of a key, 8 if zero.
+	MaxDepth int
+	// Dots enables the dot notation.
+	Dots bool
+}
+
+// BracketDecoder implements Decoder.
+var _ Decoder = BracketDecoder{}
+
+// Decode converts URL values to a nested map.
+//
+// Parameters:
+//   - v: The URL values to decode.
+//
+// Returns:
+//   - map[string]any: The decoded query parameters.
+//   - error: An error if a key is malformed or conflicts with another.
+func (d BracketDecoder) Decode(v url.Values) (map[string]any, error) {
+	maxDepth := d.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultMaxDepth
+	}
+	out := make(map[string]any, len(v))
+	// Sorted keys make conflict errors deterministic.
+	for _, key := range slices.Sorted(maps.Keys(v)) {
+		path, list, err := splitBracketKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if d.Dots {
+			if path, err = splitDots(key, path); err != nil {
+				return nil, err
+			}
+		}
+		if len(path)-1 > maxDepth {
+			return nil, fmt.Errorf(
+				"querydec: key %q nests deeper than %d", key, maxDepth,
+			)
+		}
+		var value any = v[key]
+		if !list && len(v[key]) == 1 {
+			value = v[key][0]
+		}
+		if err := setPath(out, path, value); err != nil {
+			return nil, fmt.Errorf("querydec: key %q: %w", key, err)
+		}
+	}
+	return out, nil
+}
+
+// splitBracketKey splits "a[b][c]" into its path and reports a trailing
+// "[]".
+func splitBracketKey(key string) ([]string, bool, error) {
+	name, rest, ok := strings.Cut(key, "[")
+	if !ok {
+		return []string{key}, false, nil
+	}
+	if name == "" {
+		return nil, false, fmt.Errorf("querydec: key %q has no name", key)
+	}
+	path := []string{name}
+	rest = "[" + rest
+	list := false
+	for rest != "" {
+		if list || rest[0] != '[' {
+			return nil, false, fmt.Errorf("querydec: malformed key %q", key)
+		}
+		seg, after, ok := strings.Cut(rest[1:], "]")
+		if !ok || strings.Contains(seg, "[") {
+			return nil, false, fmt.Errorf("querydec: malformed key %q", key)
+		}
+		if seg == "" {
+			list = true
+		} else {
+			path = append(path, seg)
+		}
+		rest = after
+	}
+	return path, list, nil
+}
+
+// splitDots splits the name of a key path on dots.
+func splitDots(key string, path []string) ([]string, error) {
+	names := strings.Split(path[0], ".")
+	if slices.Contains(names, "") {
+		return nil, fmt.Errorf("querydec: malformed key %q", key)
+	}
+	return append(names, path[1:]...), nil
+}
+
+// setPath stores value in m under path, creating the intermediate maps.
+func setPath(m map[string]any, path []string, value any) error {
+	for _, seg := range path[:len(path)-1] {
+		switch next := m[seg].(type) {
+		case nil:
+			child := map[string]any{}
+			m[seg] = child
+			m = child
+		case map[string]any:
+			m = next
+		default:
+			return fmt.Errorf("%q is both a value and a map", seg)
+		}
+	}
+	last := path[len(path)-1]
+	if _, ok := m[last]; ok {
+		return fmt.Errorf("%q is given more than once", last)
+	}
+	m[last] = value
+	return nil
+}
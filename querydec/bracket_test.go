@@ -0,0 +1,99 @@
+package querydec
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBracketDecoder_Decode(t *testing.T) {
+	values, _ := url.ParseQuery(
+		"filter[name]=x&filter[age][gte]=30&filter[age][lt]=65" +
+			"&id[]=1&id[]=2&one[]=a&sort=name&tag=a&tag=b",
+	)
+
+	result, err := BracketDecoder{}.Decode(values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]any{
+		"filter": map[string]any{
+			"name": "x",
+			"age":  map[string]any{"gte": "30", "lt": "65"},
+		},
+		"id":   []string{"1", "2"},
+		"one":  []string{"a"},
+		"sort": "name",
+		"tag":  []string{"a", "b"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestBracketDecoder_Decode_Errors(t *testing.T) {
+	tests := map[string]string{
+		"malformed":     "filter[name=x",
+		"trailing text": "filter[name]x=1",
+		"nested open":   "filter[a[b]]=1",
+		"no name":       "[name]=x",
+		"after list":    "id[][x]=1",
+		"conflict":      "filter=x&filter[name]=y",
+		"duplicate":     "id=1&id[]=2",
+		"too deep":      "a[1][2][3][4][5][6][7][8][9]=x",
+	}
+	for name, query := range tests {
+		values, _ := url.ParseQuery(query)
+		if _, err := (BracketDecoder{}).Decode(values); err == nil {
+			t.Fatalf("%s: expected an error for %q", name, query)
+		}
+	}
+
+	values, _ := url.ParseQuery("a[1][2][3]=x")
+	_, err := BracketDecoder{MaxDepth: 2}.Decode(values)
+	if err == nil || !strings.Contains(err.Error(), "deeper than 2") {
+		t.Fatalf("Expected a depth error, got %v", err)
+	}
+}
+
+func TestBracketDecoder_Decode_Dots(t *testing.T) {
+	values, _ := url.ParseQuery(
+		"filter.name=x&filter.age[gte]=30&filter[age][lt]=65" +
+			"&fields[a.b]=c&id.list[]=1&sort=name",
+	)
+
+	result, err := BracketDecoder{Dots: true}.Decode(values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]any{
+		"filter": map[string]any{
+			"name": "x",
+			"age":  map[string]any{"gte": "30", "lt": "65"},
+		},
+		"fields": map[string]any{"a.b": "c"},
+		"id":     map[string]any{"list": []string{"1"}},
+		"sort":   "name",
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+
+	plain, err := BracketDecoder{}.Decode(url.Values{"a.b": {"1"}})
+	if err != nil || plain["a.b"] != "1" {
+		t.Fatalf("Expected dots to be kept by default, got %v, %v", plain, err)
+	}
+
+	for _, query := range []string{
+		"a..b=1", ".a=1", "a.=1", "a.b=1&a.b.c=2",
+		"a.1.2.3.4.5.6.7.8.9=x",
+	} {
+		values, _ := url.ParseQuery(query)
+		if _, err := (BracketDecoder{Dots: true}).Decode(values); err == nil {
+			t.Fatalf("Expected an error for %q", query)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package querydec
+
+import (
+	"errors"
+	"maps"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aatuh/pureapi-core/internal/bind"
+)
+
+// Kind is the type a CoercingDecoder converts a query parameter to.
+type Kind struct {
+	t reflect.Type
+}
+
+// Kinds of the common parameter types.
+var (
+	KindString = KindOf[string]()
+	KindInt    = KindOf[int64]()
+	KindFloat  = KindOf[float64]()
+	KindBool   = KindOf[bool]()
+	KindTime   = KindOf[time.Time]()
+	KindUUID   = KindOf[UUID]()
+)
+
+// KindOf returns the kind of the values of type T. T is one of the types
+// supported by DecodeInto, e.g. an encoding.TextUnmarshaler.
+//
+// Returns:
+//   - Kind: The kind.
+func KindOf[T any]() Kind {
+	return Kind{t: reflect.TypeFor[T]()}
+}
+
+// String returns the name of the type of the kind.
+//
+// Returns:
+//   - string: The type name.
+func (k Kind) String() string {
+	if k.t == nil {
+		return "<nil>"
+	}
+	return k.t.String()
+}
+
+// errNotScalar is the error of parameters decoded into maps.
+var errNotScalar = errors.New("expected a value, not an object")
+
+// CoercingDecoder converts the values of declared keys to typed values,
+// so handlers get an int64 or a time.Time instead of strings:
+//
+//	d := querydec.NewCoercingDecoder(map[string]querydec.Kind{
+//		"page":  querydec.KindInt,
+//		"since":  querydec.KindTime,
+//		"owner":  querydec.KindUUID,
+//	}, nil)
+//
+// A key holding one value becomes a value of its kind, a key holding
+// several a slice of it, e.g. []int64. Conversions are those of
+// DecodeInto. The other keys are left as decoded by the wrapped decoder.
+// All failures are reported together as Errors, with one FieldError per
+// key.
+type CoercingDecoder struct {
+	next   Decoder
+	schema map[string]Kind
+	keys   []string // Sorted schema keys, for deterministic errors.
+}
+
+// CoercingDecoder implements Decoder.
+var _ Decoder = (*CoercingDecoder)(nil)
+
+// NewCoercingDecoder creates a decoder coercing the keys of schema.
+//
+// Parameters:
+//   - schema: The kinds by query parameter name.
+//   - next: The decoder producing the values, PlainDecoder if nil.
+//
+// Returns:
+//   - *CoercingDecoder: A new CoercingDecoder instance.
+func NewCoercingDecoder(
+	schema map[string]Kind, next Decoder,
+) *CoercingDecoder {
+	if next == nil {
+		next = PlainDecoder{}
+	}
+	schema = maps.Clone(schema)
+	return &CoercingDecoder{
+		next:   next,
+		schema: schema,
+		keys:   slices.Sorted(maps.Keys(schema)),
+	}
+}
+
+// CoercingDecoderFor creates a decoder coercing the keys of the "query"
+// tags of the struct T to the types of their fields, or of their elements
+// for slices and pointers, declaring the schema once for DecodeInto and
+// the query map.
+//
+// Parameters:
+//   - next: The decoder producing the values, PlainDecoder if nil.
+//
+// Returns:
+//   - *CoercingDecoder: A new CoercingDecoder instance.
+func CoercingDecoderFor[T any](next Decoder) *CoercingDecoder {
+	schema := map[string]Kind{}
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Struct {
+		for _, f := range bind.Fields(t, "query") {
+			schema[f.Name] = Kind{t: bind.ElemType(f.Type)}
+		}
+	}
+	return NewCoercingDecoder(schema, next)
+}
+
+// Decode decodes v with the wrapped decoder and coerces the schema keys.
+//
+// Parameters:
+//   - v: The URL values to decode.
+//
+// Returns:
+//   - map[string]any: The decoded query parameters.
+//   - error: Errors with one FieldError per failed key, or an error of the
+//     wrapped decoder.
+func (d *CoercingDecoder) Decode(v url.Values) (map[string]any, error) {
+	out, err := d.next.Decode(v)
+	if err != nil {
+		return nil, err
+	}
+	var errs Errors
+	for _, key := range d.keys {
+		raw, ok := out[key]
+		if !ok {
+			continue
+		}
+		value, err := coerce(raw, d.schema[key].t)
+		if err != nil {
+			errs = append(errs, &FieldError{
+				Field: key, Value: rawString(raw), Err: err,
+			})
+			continue
+		}
+		out[key] = value
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return out, nil
+}
+
+// coerce converts a decoded value, a string or a []string, to t or a
+// slice of it.
+func coerce(raw any, t reflect.Type) (any, error) {
+	if t == nil {
+		return raw, nil
+	}
+	switch raw := raw.(type) {
+	case string:
+		v := reflect.New(t).Elem()
+		if err := bind.SetString(v, raw); err != nil {
+			return nil, err
+		}
+		return v.Interface(), nil
+	case []string:
+		v := reflect.MakeSlice(reflect.SliceOf(t), len(raw), len(raw))
+		for i, s := range raw {
+			if err := bind.SetString(v.Index(i), s); err != nil {
+				return nil, err
+			}
+		}
+		return v.Interface(), nil
+	}
+	return nil, errNotScalar
+}
+
+// rawString returns the raw form of a decoded value for errors.
+func rawString(raw any) string {
+	switch raw := raw.(type) {
+	case string:
+		return raw
+	case []string:
+		return strings.Join(raw, ",")
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+package querydec
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCoercingDecoder_Decode(t *testing.T) {
+	d := NewCoercingDecoder(map[string]Kind{
+		"page":   KindInt,
+		"ratio":  KindFloat,
+		"active": KindBool,
+		"since":  KindTime,
+		"owner":  KindUUID,
+		"id":     KindInt,
+		"name":   KindString,
+	}, nil)
+
+	values, _ := url.ParseQuery(
+		"page=2&ratio=0.5&active=true&since=2024-01-02T03:04:05Z" +
+			"&owner=123E4567-E89B-12D3-A456-426614174000&id=1&id=2&name=x&sort=y",
+	)
+	result, err := d.Decode(values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	owner, _ := ParseUUID("123e4567-e89b-12d3-a456-426614174000")
+	expected := map[string]any{
+		"page":   int64(2),
+		"ratio":  0.5,
+		"active": true,
+		"since":  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		"owner":  owner,
+		"id":     []int64{1, 2},
+		"name":   "x",
+		"sort":   "y",
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestCoercingDecoder_Decode_Errors(t *testing.T) {
+	d := NewCoercingDecoder(map[string]Kind{
+		"page":   KindInt,
+		"owner":  KindUUID,
+		"filter": KindString,
+		"ok":     KindBool,
+	}, BracketDecoder{})
+
+	values, _ := url.ParseQuery("page=x&owner=nope&filter[a]=1&ok=1")
+	_, err := d.Decode(values)
+
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected Errors, got %v", err)
+	}
+	var fields []string
+	for _, fe := range errs {
+		fields = append(fields, fe.Field)
+	}
+	if !reflect.DeepEqual(fields, []string{"filter", "owner", "page"}) {
+		t.Fatalf("Expected sorted field errors, got %v", errs)
+	}
+	if errs[2].Value != "x" {
+		t.Fatalf("Expected the raw value, got %q", errs[2].Value)
+	}
+}
+
+func TestCoercingDecoderFor(t *testing.T) {
+	d := CoercingDecoderFor[typedQuery](nil)
+
+	values, _ := url.ParseQuery("page=2&active=on&tag=a&timeout=1m&Ignored=x")
+	result, err := d.Decode(values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]any{
+		"page":    2,
+		"active":  true,
+		"tag":     "a",
+		"timeout": time.Minute,
+		"Ignored": "x",
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestUUID(t *testing.T) {
+	u, err := ParseUUID("123E4567-E89B-12D3-A456-426614174000")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if u.String() != "123e4567-e89b-12d3-a456-426614174000" {
+		t.Fatalf("Expected the canonical form, got %s", u)
+	}
+
+	for _, s := range []string{
+		"", "123e4567e89b12d3a456426614174000",
+		"123e4567-e89b-12d3-a456-42661417400g",
+		"123e4567-e89b-12d3-a456_426614174000",
+	} {
+		if _, err := ParseUUID(s); err == nil {
+			t.Fatalf("Expected an error for %q", s)
+		}
+	}
+}
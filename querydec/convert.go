@@ -0,0 +1,30 @@
+package querydec
+
+import (
+	"reflect"
+
+	"github.com/aatuh/pureapi-core/internal/bind"
+)
+
+// RegisterConverter registers the conversion of query values to type T
+// for the whole program. DecodeInto, the coercing decoders and the "query",
+// "form", "path" and "header" inputs of the endpoint package use it before
+// their built-in conversions, so applications decode their own types:
+//
+//	func init() {
+//		querydec.RegisterConverter(func(s string) (CountryCode, error) {
+//			return ParseCountryCode(s)
+//		})
+//	}
+//
+// Pointers to and slices of T are converted too. Registering a type
+// again replaces its converter. Register converters during
+// initialization.
+//
+// Parameters:
+//   - fn: The converter.
+func RegisterConverter[T any](fn func(s string) (T, error)) {
+	bind.RegisterConverter(reflect.TypeFor[T](), func(s string) (any, error) {
+		return fn(s)
+	})
+}
@@ -0,0 +1,54 @@
+package querydec
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// countryCode is a type with a registered converter.
+type countryCode string
+
+func init() {
+	RegisterConverter(func(s string) (countryCode, error) {
+		if len(s) != 2 {
+			return "", errors.New("invalid country code")
+		}
+		return countryCode(strings.ToUpper(s)), nil
+	})
+}
+
+func TestRegisterConverter(t *testing.T) {
+	type query struct {
+		Country   countryCode   `query:"country"`
+		Countries []countryCode `query:"in"`
+	}
+	values := url.Values{"country": {"fi"}, "in": {"se", "no"}}
+
+	result, err := DecodeInto[query](values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := query{Country: "FI", Countries: []countryCode{"SE", "NO"}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, result)
+	}
+
+	coerced, err := CoercingDecoderFor[query](nil).Decode(values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(coerced["in"], []countryCode{"SE", "NO"}) {
+		t.Fatalf("Expected converted values, got %v", coerced)
+	}
+
+	_, err = NewCoercingDecoder(map[string]Kind{
+		"country": KindOf[countryCode](),
+	}, nil).Decode(url.Values{"country": {"fin"}})
+	var errs Errors
+	if !errors.As(err, &errs) || errs[0].Err.Error() != "invalid country code" {
+		t.Fatalf("Expected the converter error, got %v", err)
+	}
+}
@@ -0,0 +1,394 @@
+package querydec
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+)
+
+// Logical operators of filter expressions.
+const (
+	FilterAnd = ";"
+	FilterOr  = ","
+)
+
+// maxFilterDepth limits the nesting of parenthesized filter groups.
+const maxFilterDepth = 32
+
+// Filter is a node of a parsed RSQL/FIQL filter expression, a *Logical or
+// a *Comparison.
+type Filter interface {
+	// String returns the node in the RSQL syntax.
+	String() string
+	filter()
+}
+
+// Logical is the conjunction or disjunction of filters.
+type Logical struct {
+	Op       string // FilterAnd or FilterOr.
+	Operands []Filter
+}
+
+// Comparison compares a selector, such as a field name, to arguments.
+// The operator is one of "==", "!=", "<", "<=", ">", ">=", "=in=",
+// "=out=", or another FIQL operator of the "=name=" form; the FIQL
+// aliases "=lt=", "=le=", "=gt=" and "=ge=" are normalized to the
+// symbols. Only "=in=" and "=out=" take several arguments.
+type Comparison struct {
+	Selector string
+	Operator string
+	Args     []string
+}
+
+// Logical and Comparison implement Filter.
+var (
+	_ Filter = (*Logical)(nil)
+	_ Filter = (*Comparison)(nil)
+)
+
+func (*Logical) filter()    {}
+func (*Comparison) filter() {}
+
+// String returns the node in the RSQL syntax.
+//
+// Returns:
+//   - string: The expression.
+func (l *Logical) String() string {
+	parts := make([]string, len(l.Operands))
+	for i, op := range l.Operands {
+		parts[i] = op.String()
+		// ";" binds tighter than ",", so only disjunctions need groups.
+		if sub, ok := op.(*Logical); ok && sub.Op == FilterOr {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, l.Op)
+}
+
+// String returns the node in the RSQL syntax.
+//
+// Returns:
+//   - string: The expression.
+func (c *Comparison) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = quoteFilterValue(arg)
+	}
+	if len(args) == 1 && !isListOperator(c.Operator) {
+		return c.Selector + c.Operator + args[0]
+	}
+	return c.Selector + c.Operator + "(" + strings.Join(args, ",") + ")"
+}
+
+// FilterSyntaxError is the error of a malformed filter expression.
+type FilterSyntaxError struct {
+	Offset  int // Byte offset of the error in the expression.
+	Message string
+}
+
+// Error returns the error message.
+func (e *FilterSyntaxError) Error() string {
+	return fmt.Sprintf("%s at offset %d", e.Message, e.Offset)
+}
+
+// ParseFilter parses an RSQL/FIQL filter expression, such as
+// `name==joe;age>30` or `status=in=(open,pending),(owner=="Jane Doe")`,
+// into its syntax tree. ";" binds tighter than ","; parentheses group.
+// Arguments are quoted with ' or " when they contain reserved characters
+// or spaces, with \ escaping the next character.
+//
+// Parameters:
+//   - s: The expression.
+//
+// Returns:
+//   - Filter: The syntax tree.
+//   - error: A *FilterSyntaxError if the expression is malformed.
+func ParseFilter(s string) (Filter, error) {
+	p := filterParser{s: s}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(s) {
+		return nil, p.errorf("unexpected %q", s[p.pos])
+	}
+	return f, nil
+}
+
+// filterParser is a recursive descent parser of filter expressions.
+type filterParser struct {
+	s     string
+	pos   int
+	depth int
+}
+
+// errorf returns a syntax error at the current position.
+func (p *filterParser) errorf(format string, args ...any) error {
+	return &FilterSyntaxError{
+		Offset: p.pos, Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// peek returns the current byte, or 0 at the end.
+func (p *filterParser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+// parseOr parses operands separated by ",".
+func (p *filterParser) parseOr() (Filter, error) {
+	return p.parseLogical(FilterOr, p.parseAnd)
+}
+
+// parseAnd parses operands separated by ";".
+func (p *filterParser) parseAnd() (Filter, error) {
+	return p.parseLogical(FilterAnd, p.parseConstraint)
+}
+
+// parseLogical parses operands separated by op.
+func (p *filterParser) parseLogical(
+	op string, operand func() (Filter, error),
+) (Filter, error) {
+	var operands []Filter
+	for {
+		f, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, f)
+		if p.peek() != op[0] {
+			break
+		}
+		p.pos++
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return &Logical{Op: op, Operands: operands}, nil
+}
+
+// parseConstraint parses a group or a comparison.
+func (p *filterParser) parseConstraint() (Filter, error) {
+	if p.peek() != '(' {
+		return p.parseComparison()
+	}
+	if p.depth == maxFilterDepth {
+		return nil, p.errorf("groups nest deeper than %d", maxFilterDepth)
+	}
+	p.pos++
+	p.depth++
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != ')' {
+		return nil, p.errorf("expected ')'")
+	}
+	p.pos++
+	p.depth--
+	return f, nil
+}
+
+// parseComparison parses a selector, an operator and arguments.
+func (p *filterParser) parseComparison() (Filter, error) {
+	selector := p.readUnreserved()
+	if selector == "" {
+		return nil, p.errorf("expected a selector")
+	}
+	op, err := p.parseOperator()
+	if err != nil {
+		return nil, err
+	}
+	c := &Comparison{Selector: selector, Operator: op}
+	if p.peek() != '(' {
+		arg, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		c.Args = []string{arg}
+		return c, nil
+	}
+	if !isListOperator(op) {
+		return nil, p.errorf("operator %s takes one argument", op)
+	}
+	p.pos++
+	for {
+		arg, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		c.Args = append(c.Args, arg)
+		if p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	if p.peek() != ')' {
+		return nil, p.errorf("expected ')'")
+	}
+	p.pos++
+	return c, nil
+}
+
+// operatorAliases maps the FIQL comparison operators to symbols.
+var operatorAliases = map[string]string{
+	"=lt=": "<", "=le=": "<=", "=gt=": ">", "=ge=": ">=",
+}
+
+// parseOperator parses a comparison operator.
+func (p *filterParser) parseOperator() (string, error) {
+	rest := p.s[p.pos:]
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(rest, op) {
+			p.pos += len(op)
+			return op, nil
+		}
+	}
+	if strings.HasPrefix(rest, "=") {
+		name := strings.TrimLeft(rest[1:], "abcdefghijklmnopqrstuvwxyz")
+		n := len(rest) - len(name)
+		if n > 1 && strings.HasPrefix(name, "=") {
+			op := rest[:n+1]
+			p.pos += len(op)
+			if alias, ok := operatorAliases[op]; ok {
+				return alias, nil
+			}
+			return op, nil
+		}
+	}
+	return "", p.errorf("expected an operator")
+}
+
+// parseValue parses an unreserved or quoted argument.
+func (p *filterParser) parseValue() (string, error) {
+	quote := p.peek()
+	if quote != '\'' && quote != '"' {
+		value := p.readUnreserved()
+		if value == "" {
+			return "", p.errorf("expected an argument")
+		}
+		return value, nil
+	}
+	start := p.pos
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c == '\\' && p.pos < len(p.s):
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	p.pos = start
+	return "", p.errorf("unterminated quoted argument")
+}
+
+// readUnreserved reads a run of unreserved characters.
+func (p *filterParser) readUnreserved() string {
+	start := p.pos
+	for p.pos < len(p.s) && !isReserved(p.s[p.pos]) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// isReserved reports whether c has a meaning in filter expressions.
+func isReserved(c byte) bool {
+	return strings.IndexByte(`"'();,=!~<> `, c) >= 0 || c < ' '
+}
+
+// isListOperator reports whether an operator takes a list of arguments.
+func isListOperator(op string) bool {
+	return op == "=in=" || op == "=out="
+}
+
+// quoteFilterValue quotes an argument with reserved characters.
+func quoteFilterValue(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return r < 0x80 && isReserved(byte(r))
+	}) < 0 {
+		return s
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// FilterDecoder parses a query parameter holding an RSQL/FIQL filter
+// expression, for advanced search endpoints:
+//
+//	?filter=status=in=(open,pending);created>2024-01-01
+//
+// The parameter becomes a Filter, see ParseFilter, which SQLFilter turns
+// into a SQL condition. The other parameters are decoded by the wrapped
+// decoder. Malformed expressions are reported as Errors with the
+// *FilterSyntaxError of the parameter.
+type FilterDecoder struct {
+	param string
+	next  Decoder
+}
+
+// FilterDecoder implements Decoder.
+var _ Decoder = (*FilterDecoder)(nil)
+
+// NewFilterDecoder creates a filter decoder.
+//
+// Parameters:
+//   - param: The filter parameter name, "filter" if empty.
+//   - next: The decoder of the other parameters, PlainDecoder if nil.
+//
+// Returns:
+//   - *FilterDecoder: A new FilterDecoder instance.
+func NewFilterDecoder(param string, next Decoder) *FilterDecoder {
+	if param == "" {
+		param = "filter"
+	}
+	if next == nil {
+		next = PlainDecoder{}
+	}
+	return &FilterDecoder{param: param, next: next}
+}
+
+// Decode parses the filter parameter and decodes the others with the
+// wrapped decoder.
+//
+// Parameters:
+//   - v: The URL values to decode.
+//
+// Returns:
+//   - map[string]any: The decoded query parameters.
+//   - error: Errors with the FieldError of the filter parameter, or an
+//     error of the wrapped decoder.
+func (d *FilterDecoder) Decode(v url.Values) (map[string]any, error) {
+	values, ok := v[d.param]
+	if !ok {
+		return d.next.Decode(v)
+	}
+	if len(values) != 1 {
+		return nil, Errors{{
+			Field: d.param, Value: strings.Join(values, ","),
+			Err: errors.New("expected one filter expression"),
+		}}
+	}
+	f, err := ParseFilter(values[0])
+	if err != nil {
+		return nil, Errors{{Field: d.param, Value: values[0], Err: err}}
+	}
+	rest := maps.Clone(v)
+	delete(rest, d.param)
+	out, err := d.next.Decode(rest)
+	if err != nil {
+		return nil, err
+	}
+	out[d.param] = f
+	return out, nil
+}
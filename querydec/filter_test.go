@@ -0,0 +1,119 @@
+package querydec
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	f, err := ParseFilter(
+		`name==joe;age>30,status=in=(open,"on hold");(x=gt=1,y=le=2)`,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := &Logical{Op: FilterOr, Operands: []Filter{
+		&Logical{Op: FilterAnd, Operands: []Filter{
+			&Comparison{Selector: "name", Operator: "==", Args: []string{"joe"}},
+			&Comparison{Selector: "age", Operator: ">", Args: []string{"30"}},
+		}},
+		&Logical{Op: FilterAnd, Operands: []Filter{
+			&Comparison{
+				Selector: "status", Operator: "=in=",
+				Args: []string{"open", "on hold"},
+			},
+			&Logical{Op: FilterOr, Operands: []Filter{
+				&Comparison{Selector: "x", Operator: ">", Args: []string{"1"}},
+				&Comparison{Selector: "y", Operator: "<=", Args: []string{"2"}},
+			}},
+		}},
+	}}
+	if !reflect.DeepEqual(f, expected) {
+		t.Fatalf("Expected %v, got %v", expected, f)
+	}
+	want := `name==joe;age>30,status=in=(open,"on hold");(x>1,y<=2)`
+	if f.String() != want {
+		t.Fatalf("Expected %s, got %s", want, f.String())
+	}
+}
+
+func TestParseFilter_Values(t *testing.T) {
+	f, err := ParseFilter(`q=='it\'s';n!="a\"b";tag=like=go*`)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ops := f.(*Logical).Operands
+	if got := ops[0].(*Comparison).Args[0]; got != "it's" {
+		t.Fatalf("Expected an unescaped value, got %q", got)
+	}
+	if got := ops[1].(*Comparison).Args[0]; got != `a"b` {
+		t.Fatalf("Expected an unescaped value, got %q", got)
+	}
+	if got := ops[2].(*Comparison).Operator; got != "=like=" {
+		t.Fatalf("Expected a custom operator, got %q", got)
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	tests := map[string]int{
+		"":            0,
+		"name":        4,
+		"name=":       4,
+		"name==":      6,
+		"name==(a,b)": 6,
+		"a==1;":       5,
+		"(a==1":       5,
+		"a==1)":       4,
+		`a=="x`:       3,
+		"a=in=(1,2":   9,
+		"a==1 ;b==2":  4,
+		"a!1":         1,
+	}
+	for expr, offset := range tests {
+		_, err := ParseFilter(expr)
+		var syntaxErr *FilterSyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("%q: expected a syntax error, got %v", expr, err)
+		}
+		if syntaxErr.Offset != offset {
+			t.Fatalf("%q: expected offset %d, got %v", expr, offset, err)
+		}
+	}
+}
+
+func TestFilterDecoder_Decode(t *testing.T) {
+	d := NewFilterDecoder("", nil)
+
+	values, _ := url.ParseQuery("filter=age>30&sort=name")
+	result, err := d.Decode(values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]any{
+		"filter": &Comparison{Selector: "age", Operator: ">", Args: []string{"30"}},
+		"sort":   "name",
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+
+	if result, err := d.Decode(url.Values{"q": {"x"}}); err != nil ||
+		result["q"] != "x" {
+		t.Fatalf("Expected the other parameters only, got %v, %v", result, err)
+	}
+
+	var errs Errors
+	_, err = d.Decode(url.Values{"filter": {"age>"}})
+	if !errors.As(err, &errs) || errs[0].Field != "filter" {
+		t.Fatalf("Expected a filter field error, got %v", err)
+	}
+	_, err = d.Decode(url.Values{"filter": {"a==1", "b==2"}})
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected a filter field error, got %v", err)
+	}
+}
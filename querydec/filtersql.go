@@ -0,0 +1,138 @@
+package querydec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sqlOperators maps the comparison operators to SQL.
+var sqlOperators = map[string]string{
+	"==": "=", "!=": "<>", "<": "<", "<=": "<=", ">": ">", ">=": ">=",
+	"=in=": "IN", "=out=": "NOT IN",
+}
+
+// SQLFilter translates filters into SQL conditions for a WHERE clause,
+// safely: selectors are mapped to columns through an allowlist and
+// arguments are passed as query arguments, never spliced into the SQL:
+//
+//	f := querydec.SQLFilter{Columns: map[string]string{
+//		"name": "u.name",
+//		"age":  "u.age",
+//	}}
+//	where, args, err := f.Translate(filter) // "u.name = ? AND u.age > ?"
+//	rows, err := db.QueryContext(ctx, "SELECT ... WHERE "+where, args...)
+//
+// Arguments compare literally; there are no wildcards.
+type SQLFilter struct {
+	// Columns maps selectors to column expressions. Other selectors are
+	// rejected.
+	Columns map[string]string
+	// Placeholder returns the placeholder of the n-th argument, from 1,
+	// e.g. "$1" for PostgreSQL. Nil gives "?".
+	Placeholder func(n int) string
+	// Value converts the arguments of a selector, e.g. to integers for
+	// numeric columns. Nil passes them as strings.
+	Value func(selector, arg string) (any, error)
+}
+
+// DollarPlaceholder returns the PostgreSQL placeholder "$n".
+//
+// Parameters:
+//   - n: The argument number, from 1.
+//
+// Returns:
+//   - string: The placeholder.
+func DollarPlaceholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// Translate returns the SQL condition of a filter and its arguments.
+//
+// Parameters:
+//   - f: The filter.
+//
+// Returns:
+//   - string: The SQL condition.
+//   - []any: The query arguments.
+//   - error: An error for unknown selectors or operators, or failed
+//     conversions.
+func (s SQLFilter) Translate(f Filter) (string, []any, error) {
+	var args []any
+	where, err := s.translate(f, &args)
+	if err != nil {
+		return "", nil, err
+	}
+	return where, args, nil
+}
+
+// translate appends the arguments of f to args and returns its condition.
+func (s SQLFilter) translate(f Filter, args *[]any) (string, error) {
+	switch f := f.(type) {
+	case *Logical:
+		join := " AND "
+		if f.Op == FilterOr {
+			join = " OR "
+		}
+		parts := make([]string, len(f.Operands))
+		for i, op := range f.Operands {
+			part, err := s.translate(op, args)
+			if err != nil {
+				return "", err
+			}
+			if _, ok := op.(*Logical); ok {
+				part = "(" + part + ")"
+			}
+			parts[i] = part
+		}
+		return strings.Join(parts, join), nil
+	case *Comparison:
+		return s.comparison(f, args)
+	}
+	return "", fmt.Errorf("querydec: unsupported filter node %T", f)
+}
+
+// comparison appends the arguments of c to args and returns its
+// condition.
+func (s SQLFilter) comparison(c *Comparison, args *[]any) (string, error) {
+	column, ok := s.Columns[c.Selector]
+	if !ok {
+		return "", fmt.Errorf("querydec: cannot filter by %q", c.Selector)
+	}
+	op, ok := sqlOperators[c.Operator]
+	if !ok {
+		return "", fmt.Errorf("querydec: unsupported operator %s", c.Operator)
+	}
+	if len(c.Args) == 0 || (len(c.Args) > 1 && !isListOperator(c.Operator)) {
+		return "", fmt.Errorf(
+			"querydec: %s%s has %d arguments", c.Selector, c.Operator, len(c.Args),
+		)
+	}
+	placeholders := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		var value any = arg
+		if s.Value != nil {
+			v, err := s.Value(c.Selector, arg)
+			if err != nil {
+				return "", fmt.Errorf("querydec: %s: %w", c.Selector, err)
+			}
+			value = v
+		}
+		*args = append(*args, value)
+		placeholders[i] = s.placeholder(len(*args))
+	}
+	if isListOperator(c.Operator) {
+		return fmt.Sprintf(
+			"%s %s (%s)", column, op, strings.Join(placeholders, ", "),
+		), nil
+	}
+	return column + " " + op + " " + placeholders[0], nil
+}
+
+// placeholder returns the placeholder of the n-th argument.
+func (s SQLFilter) placeholder(n int) string {
+	if s.Placeholder == nil {
+		return "?"
+	}
+	return s.Placeholder(n)
+}
@@ -0,0 +1,74 @@
+package querydec
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestSQLFilter_Translate(t *testing.T) {
+	f, err := ParseFilter("name==joe;age>30,status=out=(closed,spam);(a!=1,b<=2)")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	s := SQLFilter{
+		Columns: map[string]string{
+			"name": "u.name", "age": "u.age", "status": "status",
+			"a": "a", "b": "b",
+		},
+		Placeholder: DollarPlaceholder,
+		Value: func(selector, arg string) (any, error) {
+			if selector == "age" {
+				return strconv.Atoi(arg)
+			}
+			return arg, nil
+		},
+	}
+
+	where, args, err := s.Translate(f)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := "(u.name = $1 AND u.age > $2) OR " +
+		"(status NOT IN ($3, $4) AND (a <> $5 OR b <= $6))"
+	if where != expected {
+		t.Fatalf("Expected %s, got %s", expected, where)
+	}
+	if !reflect.DeepEqual(args, []any{"joe", 30, "closed", "spam", "1", "2"}) {
+		t.Fatalf("Unexpected arguments %v", args)
+	}
+}
+
+func TestSQLFilter_Translate_Errors(t *testing.T) {
+	s := SQLFilter{
+		Columns: map[string]string{"age": "age"},
+		Value: func(selector, arg string) (any, error) {
+			return strconv.Atoi(arg)
+		},
+	}
+
+	for _, expr := range []string{
+		"name==joe", "age=like=1", "age==x", "age==1;name==joe",
+	} {
+		f, err := ParseFilter(expr)
+		if err != nil {
+			t.Fatalf("%q: expected no parse error, got %v", expr, err)
+		}
+		if _, _, err := s.Translate(f); err == nil {
+			t.Fatalf("%q: expected a translation error", expr)
+		}
+	}
+
+	where, args, err := s.Translate(&Comparison{
+		Selector: "age", Operator: "=in=", Args: []string{"1"},
+	})
+	if err != nil || where != "age IN (?)" || !reflect.DeepEqual(args, []any{1}) {
+		t.Fatalf("Unexpected translation %q %v %v", where, args, err)
+	}
+	if _, _, err := s.Translate(&Comparison{
+		Selector: "age", Operator: "==",
+	}); err == nil {
+		t.Fatal("Expected an error for a comparison without arguments")
+	}
+}
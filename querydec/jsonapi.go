@@ -0,0 +1,254 @@
+package querydec
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SortField is a field of a JSON:API sort parameter.
+type SortField struct {
+	Field string
+	Desc  bool // Set by a "-" prefix.
+}
+
+// JSONAPIPage is the pagination of a JSON:API query.
+type JSONAPIPage struct {
+	Number int    // 1-based page number, from page[number].
+	Size   int    // Page size, from page[size].
+	Cursor string // Opaque cursor, from page[cursor].
+}
+
+// JSONAPIQuery is a query following the JSON:API conventions.
+type JSONAPIQuery struct {
+	// Filter holds the filter[...] parameters, nested as by BracketDecoder.
+	Filter map[string]any
+	// Sort is the sort=-created,name order.
+	Sort []SortField
+	// Page is the page[...] pagination.
+	Page JSONAPIPage
+	// Fields holds the fields[type]=a,b sparse fieldsets by type.
+	Fields map[string][]string
+	// Include is the include=author,comments.author relationship list.
+	Include []string
+}
+
+// Offset returns the number of items before the requested page.
+//
+// Returns:
+//   - int: The item offset.
+func (q JSONAPIQuery) Offset() int {
+	return (q.Page.Number - 1) * q.Page.Size
+}
+
+// Limit returns the number of items of the requested page.
+//
+// Returns:
+//   - int: The page size.
+func (q JSONAPIQuery) Limit() int {
+	return q.Page.Size
+}
+
+// JSONAPIDecoder decodes queries following the JSON:API conventions:
+//
+//	?filter[status]=open&sort=-created,name&page[number]=2&page[size]=50
+//	&fields[articles]=title,body&include=author
+//
+// Parse returns the query as a JSONAPIQuery. Decode, for use as the
+// server query decoder, returns the members under their names as typed
+// values: "filter" a map[string]any, "sort" a []SortField, "page" a
+// JSONAPIPage, "fields" a map[string][]string and "include" a []string.
+// Other parameters are decoded as by BracketDecoder. Invalid members are
+// reported together as Errors.
+type JSONAPIDecoder struct {
+	// DefaultPageSize is the page size without page[size], 20 if zero.
+	DefaultPageSize int
+	// MaxPageSize caps page[size], 100 if zero.
+	MaxPageSize int
+	// SortFields restricts the sortable fields, e.g. to indexed columns.
+	// Nil allows any field.
+	SortFields []string
+}
+
+// JSONAPIDecoder implements Decoder.
+var _ Decoder = JSONAPIDecoder{}
+
+// Decode converts URL values to a map of typed JSON:API members.
+//
+// Parameters:
+//   - v: The URL values to decode.
+//
+// Returns:
+//   - map[string]any: The decoded query parameters.
+//   - error: Errors with one FieldError per invalid member, or an error
+//     if a key is malformed.
+func (d JSONAPIDecoder) Decode(v url.Values) (map[string]any, error) {
+	out, q, err := d.parse(v)
+	if err != nil {
+		return nil, err
+	}
+	out["filter"] = q.Filter
+	out["sort"] = q.Sort
+	out["page"] = q.Page
+	out["fields"] = q.Fields
+	out["include"] = q.Include
+	return out, nil
+}
+
+// Parse converts URL values to a JSON:API query.
+//
+// Parameters:
+//   - v: The URL values to parse.
+//
+// Returns:
+//   - JSONAPIQuery: The query.
+//   - error: Errors with one FieldError per invalid member, or an error
+//     if a key is malformed.
+func (d JSONAPIDecoder) Parse(v url.Values) (JSONAPIQuery, error) {
+	_, q, err := d.parse(v)
+	return q, err
+}
+
+// parse decodes v with the bracket syntax and extracts the members.
+func (d JSONAPIDecoder) parse(
+	v url.Values,
+) (map[string]any, JSONAPIQuery, error) {
+	out, err := BracketDecoder{}.Decode(v)
+	if err != nil {
+		return nil, JSONAPIQuery{}, err
+	}
+	q := JSONAPIQuery{
+		Filter: map[string]any{},
+		Fields: map[string][]string{},
+		Page:   JSONAPIPage{Number: 1, Size: d.DefaultPageSize},
+	}
+	if q.Page.Size <= 0 {
+		q.Page.Size = 20
+	}
+	var errs Errors
+	fail := func(field string, value any, err error) {
+		errs = append(errs, &FieldError{
+			Field: field, Value: rawString(value), Err: err,
+		})
+	}
+	if raw, ok := out["filter"]; ok {
+		if filter, ok := raw.(map[string]any); ok {
+			q.Filter = filter
+		} else {
+			fail("filter", raw, errors.New("expected filter[name] parameters"))
+		}
+	}
+	if raw, ok := out["sort"]; ok {
+		sort, err := d.parseSort(raw)
+		if err != nil {
+			fail("sort", raw, err)
+		}
+		q.Sort = sort
+	}
+	if raw, ok := out["page"]; ok {
+		d.parsePage(raw, &q.Page, fail)
+	}
+	if raw, ok := out["fields"]; ok {
+		fields, ok := raw.(map[string]any)
+		if !ok {
+			fail("fields", raw, errors.New("expected fields[type] parameters"))
+		}
+		for typ, list := range fields {
+			s, ok := list.(string)
+			if !ok {
+				fail("fields["+typ+"]", list, errors.New("expected one list"))
+				continue
+			}
+			q.Fields[typ] = splitList(s)
+		}
+	}
+	if raw, ok := out["include"]; ok {
+		if s, ok := raw.(string); ok {
+			q.Include = splitList(s)
+		} else {
+			fail("include", raw, errors.New("expected one list"))
+		}
+	}
+	if len(errs) > 0 {
+		slices.SortFunc(errs, func(a, b *FieldError) int {
+			return strings.Compare(a.Field, b.Field)
+		})
+		return nil, JSONAPIQuery{}, errs
+	}
+	return out, q, nil
+}
+
+// parseSort parses a sort list.
+func (d JSONAPIDecoder) parseSort(raw any) ([]SortField, error) {
+	s, ok := raw.(string)
+	if !ok {
+		return nil, errors.New("expected one list")
+	}
+	var sort []SortField
+	for _, item := range strings.Split(s, ",") {
+		field, desc := strings.CutPrefix(strings.TrimSpace(item), "-")
+		if field == "" {
+			return nil, fmt.Errorf("invalid sort field %q", item)
+		}
+		if d.SortFields != nil && !slices.Contains(d.SortFields, field) {
+			return nil, fmt.Errorf("cannot sort by %q", field)
+		}
+		sort = append(sort, SortField{Field: field, Desc: desc})
+	}
+	return sort, nil
+}
+
+// parsePage parses the page members into page.
+func (d JSONAPIDecoder) parsePage(
+	raw any, page *JSONAPIPage, fail func(string, any, error),
+) {
+	params, ok := raw.(map[string]any)
+	if !ok {
+		fail("page", raw, errors.New("expected page[name] parameters"))
+		return
+	}
+	maxSize := d.MaxPageSize
+	if maxSize <= 0 {
+		maxSize = 100
+	}
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		field := "page[" + name + "]"
+		s, ok := params[name].(string)
+		if !ok {
+			fail(field, params[name], errors.New("expected one value"))
+			continue
+		}
+		switch name {
+		case "number", "size":
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 {
+				fail(field, s, fmt.Errorf("invalid positive integer %q", s))
+				continue
+			}
+			if name == "number" {
+				page.Number = n
+			} else {
+				page.Size = min(n, maxSize)
+			}
+		case "cursor":
+			page.Cursor = s
+		default:
+			fail(field, s, errors.New("unknown page parameter"))
+		}
+	}
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
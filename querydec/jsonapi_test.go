@@ -0,0 +1,100 @@
+package querydec
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestJSONAPIDecoder_Parse(t *testing.T) {
+	values, _ := url.ParseQuery(
+		"filter[status]=open&filter[age][gte]=30&sort=-created, name" +
+			"&page[number]=3&page[size]=500&fields[articles]=title,body" +
+			"&include=author,comments.author&q=x",
+	)
+
+	q, err := JSONAPIDecoder{}.Parse(values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := JSONAPIQuery{
+		Filter: map[string]any{
+			"status": "open",
+			"age":    map[string]any{"gte": "30"},
+		},
+		Sort:    []SortField{{Field: "created", Desc: true}, {Field: "name"}},
+		Page:    JSONAPIPage{Number: 3, Size: 100},
+		Fields:  map[string][]string{"articles": {"title", "body"}},
+		Include: []string{"author", "comments.author"},
+	}
+	if !reflect.DeepEqual(q, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, q)
+	}
+	if q.Offset() != 200 || q.Limit() != 100 {
+		t.Fatalf("Expected offset 200 and limit 100, got %d and %d",
+			q.Offset(), q.Limit())
+	}
+}
+
+func TestJSONAPIDecoder_Parse_Defaults(t *testing.T) {
+	q, err := JSONAPIDecoder{DefaultPageSize: 10}.Parse(url.Values{
+		"page[cursor]": {"abc"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := JSONAPIPage{Number: 1, Size: 10, Cursor: "abc"}
+	if q.Page != expected || len(q.Filter) != 0 || q.Sort != nil {
+		t.Fatalf("Expected defaults, got %+v", q)
+	}
+}
+
+func TestJSONAPIDecoder_Decode(t *testing.T) {
+	values, _ := url.ParseQuery("sort=name&q=x")
+
+	result, err := JSONAPIDecoder{}.Decode(values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(result["sort"], []SortField{{Field: "name"}}) ||
+		result["page"] != (JSONAPIPage{Number: 1, Size: 20}) ||
+		result["q"] != "x" {
+		t.Fatalf("Expected typed members, got %v", result)
+	}
+}
+
+func TestJSONAPIDecoder_Parse_Errors(t *testing.T) {
+	d := JSONAPIDecoder{SortFields: []string{"name"}}
+	values, _ := url.ParseQuery(
+		"filter=x&sort=-created&page[number]=0&page[size]=x" +
+			"&page[offset]=1&fields=a&include=a&include=b",
+	)
+
+	_, err := d.Parse(values)
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected Errors, got %v", err)
+	}
+	var fields []string
+	for _, fe := range errs {
+		fields = append(fields, fe.Field)
+	}
+	expected := []string{
+		"fields", "filter", "include", "page[number]", "page[offset]",
+		"page[size]", "sort",
+	}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Fatalf("Expected %v, got %v", expected, fields)
+	}
+
+	if _, err := d.Parse(url.Values{"sort": {"name,,"}}); err == nil {
+		t.Fatal("Expected an error for an empty sort field")
+	}
+	if _, err := d.Parse(url.Values{"page[": {"1"}}); err == nil {
+		t.Fatal("Expected an error for a malformed key")
+	}
+}
@@ -0,0 +1,134 @@
+package querydec
+
+import (
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// ListOption configures a key of a ListDecoder.
+type ListOption func(*listKey)
+
+// listKey holds the settings of a list key.
+type listKey struct {
+	sep   string
+	dedup bool
+}
+
+// WithSeparator sets the separator of the values of a key, "," by default.
+//
+// Parameters:
+//   - sep: The separator.
+//
+// Returns:
+//   - ListOption: The option.
+func WithSeparator(sep string) ListOption {
+	return func(k *listKey) { k.sep = sep }
+}
+
+// WithDedup removes repeated values of a key, keeping the first of each.
+//
+// Returns:
+//   - ListOption: The option.
+func WithDedup() ListOption {
+	return func(k *listKey) { k.dedup = true }
+}
+
+// ListDecoder decodes configured keys into slices, splitting their values
+// on a separator, so `?ids=1,2,3`, `?ids=1&ids=2,3` and `?ids=1` all give
+// a []string:
+//
+//	d := querydec.NewListDecoder(nil).
+//		Key("ids", querydec.WithDedup()).
+//		Key("tags", querydec.WithSeparator("|"))
+//
+// Values are trimmed of surrounding spaces and empty values are dropped.
+// The other keys are decoded by the wrapped decoder.
+type ListDecoder struct {
+	next Decoder
+	keys map[string]listKey
+}
+
+// ListDecoder implements Decoder.
+var _ Decoder = (*ListDecoder)(nil)
+
+// NewListDecoder creates a list decoder wrapping a decoder.
+//
+// Parameters:
+//   - next: The decoder of the other keys, PlainDecoder if nil.
+//
+// Returns:
+//   - *ListDecoder: A new ListDecoder instance.
+func NewListDecoder(next Decoder) *ListDecoder {
+	if next == nil {
+		next = PlainDecoder{}
+	}
+	return &ListDecoder{next: next, keys: map[string]listKey{}}
+}
+
+// Key declares a key decoded into a slice. Configure keys before decoding
+// requests.
+//
+// Parameters:
+//   - key: The query parameter name.
+//   - opts: Optional list options.
+//
+// Returns:
+//   - *ListDecoder: The decoder, for chaining.
+func (d *ListDecoder) Key(key string, opts ...ListOption) *ListDecoder {
+	k := listKey{sep: ","}
+	for _, opt := range opts {
+		opt(&k)
+	}
+	d.keys[key] = k
+	return d
+}
+
+// Decode splits the values of the list keys and decodes the others with
+// the wrapped decoder.
+//
+// Parameters:
+//   - v: The URL values to decode.
+//
+// Returns:
+//   - map[string]any: The decoded query parameters.
+//   - error: An error if the wrapped decoder fails.
+func (d *ListDecoder) Decode(v url.Values) (map[string]any, error) {
+	rest := v
+	lists := map[string][]string{}
+	for key, k := range d.keys {
+		values, ok := v[key]
+		if !ok {
+			continue
+		}
+		if len(lists) == 0 {
+			rest = maps.Clone(v)
+		}
+		delete(rest, key)
+		lists[key] = k.split(values)
+	}
+	out, err := d.next.Decode(rest)
+	if err != nil {
+		return nil, err
+	}
+	for key, list := range lists {
+		out[key] = list
+	}
+	return out, nil
+}
+
+// split splits and cleans the values of a key.
+func (k listKey) split(values []string) []string {
+	out := []string{}
+	for _, value := range values {
+		for _, s := range strings.Split(value, k.sep) {
+			s = strings.TrimSpace(s)
+			if s == "" || (k.dedup && slices.Contains(out, s)) {
+				continue
+			}
+			out = append(out, s)
+		}
+	}
+	return out
+}
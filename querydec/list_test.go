@@ -0,0 +1,52 @@
+package querydec
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// failingDecoder is a Decoder always failing.
+type failingDecoder struct{}
+
+func (failingDecoder) Decode(url.Values) (map[string]any, error) {
+	return nil, errors.New("failed")
+}
+
+func TestListDecoder_Decode(t *testing.T) {
+	d := NewListDecoder(nil).
+		Key("ids", WithDedup()).
+		Key("tags", WithSeparator("|")).
+		Key("one").
+		Key("missing")
+
+	values, _ := url.ParseQuery(
+		"ids=1,2, 3&ids=2,,4&tags=a,b|c&one=x&sort=name",
+	)
+	result, err := d.Decode(values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]any{
+		"ids":  []string{"1", "2", "3", "4"},
+		"tags": []string{"a,b", "c"},
+		"one":  []string{"x"},
+		"sort": "name",
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+	if len(values["ids"]) != 2 {
+		t.Fatalf("Expected the values to be left untouched, got %v", values)
+	}
+}
+
+func TestListDecoder_Decode_Error(t *testing.T) {
+	d := NewListDecoder(failingDecoder{}).Key("ids")
+
+	if _, err := d.Decode(url.Values{"ids": {"1"}}); err == nil {
+		t.Fatal("Expected the wrapped decoder error")
+	}
+}
@@ -0,0 +1,194 @@
+package querydec
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+// ErrIDInvalidQuery is the ID of the API errors of ParamDecoder.
+const ErrIDInvalidQuery = "invalid_query"
+
+// ErrRequired is the error of missing required parameters.
+var ErrRequired = errors.New("required")
+
+// ParamError describes an invalid query parameter. The API errors of
+// ParamDecoder carry a list of them as data, in the JSON form of the field
+// errors of the endpoint input handlers.
+type ParamError struct {
+	Field   string `json:"field"`
+	In      string `json:"in,omitempty"` // Always "query".
+	Message string `json:"message"`
+}
+
+// errInvalidQuery is the template of the invalid_query API errors.
+var errInvalidQuery = apierror.Define(apierror.Definition{
+	ID:          ErrIDInvalidQuery,
+	Status:      http.StatusBadRequest,
+	Description: "Query parameters are missing or invalid.",
+})
+
+// init lets clients parse the parameter errors with
+// apierror.ParseAPIError.
+func init() {
+	apierror.RegisterDataType[[]ParamError](ErrIDInvalidQuery)
+}
+
+// ParamDecoder declares required parameters and defaults around a
+// decoder:
+//
+//	coerce := querydec.CoercingDecoderFor[ListUsers](nil)
+//	d := querydec.NewParamDecoder(coerce).
+//		Require("org").
+//		Default("page", "1").
+//		Default("per_page", "20")
+//
+// Defaults fill in missing parameters before the wrapped decoder runs, so
+// they are converted like sent values. Parameters without a non-empty
+// value are missing. Missing required parameters and the field errors of
+// the wrapped decoder are reported together as a 400 invalid_query API
+// error with a list of ParamError values as data, which endpoint handlers
+// return as is.
+type ParamDecoder struct {
+	next     Decoder
+	required []string
+	defaults map[string]string
+}
+
+// ParamDecoder implements Decoder.
+var _ Decoder = (*ParamDecoder)(nil)
+
+// NewParamDecoder creates a param decoder wrapping a decoder.
+//
+// Parameters:
+//   - next: The wrapped decoder, PlainDecoder if nil.
+//
+// Returns:
+//   - *ParamDecoder: A new ParamDecoder instance.
+func NewParamDecoder(next Decoder) *ParamDecoder {
+	if next == nil {
+		next = PlainDecoder{}
+	}
+	return &ParamDecoder{next: next, defaults: map[string]string{}}
+}
+
+// Require declares required parameters. Configure the decoder before
+// decoding requests.
+//
+// Parameters:
+//   - keys: The parameter names.
+//
+// Returns:
+//   - *ParamDecoder: The decoder, for chaining.
+func (d *ParamDecoder) Require(keys ...string) *ParamDecoder {
+	d.required = append(d.required, keys...)
+	return d
+}
+
+// Default declares the value of a missing parameter. Configure the
+// decoder before decoding requests.
+//
+// Parameters:
+//   - key: The parameter name.
+//   - value: The default value.
+//
+// Returns:
+//   - *ParamDecoder: The decoder, for chaining.
+func (d *ParamDecoder) Default(key, value string) *ParamDecoder {
+	d.defaults[key] = value
+	return d
+}
+
+// Decode applies the defaults, checks the required parameters and
+// decodes v with the wrapped decoder.
+//
+// Parameters:
+//   - v: The URL values to decode.
+//
+// Returns:
+//   - map[string]any: The decoded query parameters.
+//   - error: An invalid_query API error, or a non-field error of the
+//     wrapped decoder.
+func (d *ParamDecoder) Decode(v url.Values) (map[string]any, error) {
+	var params []ParamError
+	for _, key := range d.required {
+		if !present(v, key) {
+			params = append(params, ParamError{
+				Field: key, In: "query", Message: ErrRequired.Error(),
+			})
+		}
+	}
+	if len(d.defaults) > 0 {
+		v = maps.Clone(v)
+		for key, value := range d.defaults {
+			if !present(v, key) {
+				v[key] = []string{value}
+			}
+		}
+	}
+	out, err := d.next.Decode(v)
+	var errs Errors
+	switch {
+	case errors.As(err, &errs):
+		params = append(params, paramErrors(errs)...)
+	case err != nil:
+		return nil, err
+	}
+	if len(params) > 0 {
+		slices.SortStableFunc(params, func(a, b ParamError) int {
+			return strings.Compare(a.Field, b.Field)
+		})
+		return nil, InvalidQuery(params)
+	}
+	return out, nil
+}
+
+// InvalidQuery returns the 400 invalid_query API error listing invalid
+// parameters, for decoders and handlers checking parameters themselves.
+//
+// Parameters:
+//   - params: The invalid parameters.
+//
+// Returns:
+//   - *apierror.DefaultAPIError: The API error.
+func InvalidQuery(params []ParamError) *apierror.DefaultAPIError {
+	return errInvalidQuery.WithMessage("invalid query parameters").
+		WithData(params)
+}
+
+// AsInvalidQuery converts the Errors of DecodeInto and the decoders into
+// the 400 invalid_query API error. Other errors are returned unchanged.
+//
+// Parameters:
+//   - err: The decoding error.
+//
+// Returns:
+//   - error: The API error, err, or nil if err is nil.
+func AsInvalidQuery(err error) error {
+	var errs Errors
+	if !errors.As(err, &errs) {
+		return err
+	}
+	return InvalidQuery(paramErrors(errs))
+}
+
+// paramErrors converts field errors to parameter errors.
+func paramErrors(errs Errors) []ParamError {
+	params := make([]ParamError, len(errs))
+	for i, fe := range errs {
+		params[i] = ParamError{
+			Field: fe.Field, In: "query", Message: fe.Err.Error(),
+		}
+	}
+	return params
+}
+
+// present reports whether v has a non-empty value for key.
+func present(v url.Values, key string) bool {
+	return slices.ContainsFunc(v[key], func(s string) bool { return s != "" })
+}
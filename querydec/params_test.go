@@ -0,0 +1,66 @@
+package querydec
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/apierror"
+)
+
+func TestParamDecoder_Decode(t *testing.T) {
+	d := NewParamDecoder(NewCoercingDecoder(map[string]Kind{
+		"page": KindInt,
+	}, nil)).Require("org").Default("page", "1").Default("sort", "name")
+
+	values, _ := url.ParseQuery("org=acme&sort=")
+	result, err := d.Decode(values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]any{"org": "acme", "page": int64(1), "sort": "name"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+	if _, ok := values["page"]; ok {
+		t.Fatal("Expected the values to be left untouched")
+	}
+}
+
+func TestParamDecoder_Decode_Errors(t *testing.T) {
+	d := NewParamDecoder(NewCoercingDecoder(map[string]Kind{
+		"page": KindInt,
+	}, nil)).Require("org", "team")
+
+	values, _ := url.ParseQuery("page=x&team=")
+	_, err := d.Decode(values)
+
+	var apiErr *apierror.DefaultAPIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an API error, got %v", err)
+	}
+	if apiErr.ID() != ErrIDInvalidQuery ||
+		apiErr.Status() != http.StatusBadRequest {
+		t.Fatalf("Expected a 400 invalid_query error, got %v", apiErr)
+	}
+	expected := []ParamError{
+		{Field: "org", In: "query", Message: "required"},
+		{Field: "page", In: "query", Message: `invalid integer "x"`},
+		{Field: "team", In: "query", Message: "required"},
+	}
+	if !reflect.DeepEqual(apiErr.Data(), expected) {
+		t.Fatalf("Expected %v, got %v", expected, apiErr.Data())
+	}
+	if status, ok := apierror.StatusFor(ErrIDInvalidQuery); !ok ||
+		status != http.StatusBadRequest {
+		t.Fatalf("Expected the status to be registered, got %d", status)
+	}
+
+	_, err = NewParamDecoder(failingDecoder{}).Decode(url.Values{})
+	if err == nil || errors.As(err, &apiErr) {
+		t.Fatalf("Expected the wrapped decoder error, got %v", err)
+	}
+}
@@ -1,9 +1,11 @@
 package querydec
 
 import (
+	"errors"
 	"net/url"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestPlainDecoder_Decode(t *testing.T) {
@@ -45,3 +47,59 @@ func TestPlainDecoder_Decode_Empty(t *testing.T) {
 		t.Fatalf("Expected empty result, got %v", result)
 	}
 }
+
+type typedQuery struct {
+	Page    int           `query:"page"`
+	Active  *bool         `query:"active"`
+	Tags    []string      `query:"tag"`
+	Timeout time.Duration `query:"timeout"`
+	Ignored string
+}
+
+func TestDecodeInto(t *testing.T) {
+	values := url.Values{
+		"page":    []string{"2"},
+		"active":  []string{"true"},
+		"tag":     []string{"a", "b"},
+		"timeout": []string{"5s"},
+		"Ignored": []string{"x"},
+	}
+
+	result, err := DecodeInto[typedQuery](values)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	active := true
+	expected := typedQuery{
+		Page: 2, Active: &active, Tags: []string{"a", "b"},
+		Timeout: 5 * time.Second,
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, result)
+	}
+}
+
+func TestDecodeInto_Errors(t *testing.T) {
+	values := url.Values{
+		"page":   []string{"two"},
+		"active": []string{"maybe"},
+		"tag":    []string{"a"},
+	}
+
+	result, err := DecodeInto[typedQuery](values)
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected Errors, got %v", err)
+	}
+	if len(errs) != 2 || errs[0].Field != "page" || errs[1].Field != "active" {
+		t.Fatalf("Expected page and active errors, got %v", errs)
+	}
+	if !reflect.DeepEqual(result.Tags, []string{"a"}) {
+		t.Fatalf("Expected valid fields to be decoded, got %+v", result)
+	}
+
+	if _, err := DecodeInto[int](values); err == nil {
+		t.Fatal("Expected an error for a non-struct type")
+	}
+}
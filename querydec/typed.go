@@ -0,0 +1,83 @@
+package querydec
+
+import (
+	"net/url"
+
+	"github.com/aatuh/pureapi-core/internal/bind"
+)
+
+// FieldError describes a query parameter that could not be converted to
+// the type of its field.
+type FieldError = bind.FieldError
+
+// Errors aggregates the field errors of DecodeInto. It unwraps to the
+// individual *FieldError values.
+type Errors = bind.Errors
+
+// DecodeInto maps query parameters onto the fields of a struct selected by
+// "query" tags, converting the values to the field types:
+//
+//	type ListUsers struct {
+//		Page   int      `query:"page"`
+//		Active *bool    `query:"active"`
+//		Tags   []string `query:"tag"`
+//	}
+//	q, err := querydec.DecodeInto[ListUsers](r.URL.Query())
+//
+// Slice fields receive every value of a parameter, other fields the first
+// one. Supported are types with a converter registered with
+// RegisterConverter, strings, booleans, integers, unsigned integers,
+// floats, time.Duration, time.Time (RFC 3339), encoding.TextUnmarshaler
+// implementations, pointers to and slices of them. Fields of missing
+// parameters keep their zero value; embedded structs are decoded
+// recursively.
+//
+// Parameters:
+//   - values: The URL values to decode.
+//
+// Returns:
+//   - T: The decoded struct.
+//   - error: Errors with one FieldError per failed field, or an error if T
+//     is not a struct.
+func DecodeInto[T any](values url.Values) (T, error) {
+	var out T
+	err := TagDecoder{}.DecodeStruct(values, &out)
+	return out, err
+}
+
+// StructDecoder decodes URL values into a struct, for typed access to
+// query parameters.
+type StructDecoder interface {
+	DecodeStruct(values url.Values, dst any) error
+}
+
+// TagDecoder is the StructDecoder of DecodeInto, selecting fields by a
+// struct tag.
+type TagDecoder struct {
+	// Tag is the struct tag naming the parameters, "query" if empty.
+	Tag string
+}
+
+// TagDecoder implements StructDecoder.
+var _ StructDecoder = TagDecoder{}
+
+// DecodeStruct decodes values into the struct dst points to, as
+// DecodeInto.
+//
+// Parameters:
+//   - values: The URL values to decode.
+//   - dst: Pointer to the destination struct.
+//
+// Returns:
+//   - error: Errors with one FieldError per failed field, or an error if
+//     dst is not a struct pointer.
+func (d TagDecoder) DecodeStruct(values url.Values, dst any) error {
+	tag := d.Tag
+	if tag == "" {
+		tag = "query"
+	}
+	return bind.Struct(dst, tag, func(name string) ([]string, bool) {
+		v, ok := values[name]
+		return v, ok
+	})
+}
@@ -0,0 +1,74 @@
+package querydec
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// UUID is a UUID in its 16-byte form, decoded from the canonical
+// 8-4-4-4-12 hexadecimal text form.
+type UUID [16]byte
+
+// ParseUUID parses a UUID in the canonical text form, in either case.
+//
+// Parameters:
+//   - s: The text form.
+//
+// Returns:
+//   - UUID: The parsed UUID.
+//   - error: An error if s is not a UUID.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' ||
+		s[23] != '-' {
+		return u, fmt.Errorf("invalid UUID %q", s)
+	}
+	digits := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36]
+	if _, err := hex.Decode(u[:], []byte(digits)); err != nil {
+		return UUID{}, fmt.Errorf("invalid UUID %q", s)
+	}
+	return u, nil
+}
+
+// String returns the canonical lower-case text form.
+//
+// Returns:
+//   - string: The text form.
+func (u UUID) String() string {
+	var b [36]byte
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:36], u[10:16])
+	return string(b[:])
+}
+
+// MarshalText returns the canonical text form.
+//
+// Returns:
+//   - []byte: The text form.
+//   - error: Always nil.
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText parses the canonical text form.
+//
+// Parameters:
+//   - text: The text form.
+//
+// Returns:
+//   - error: An error if text is not a UUID.
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
@@ -0,0 +1,31 @@
+package router
+
+import "context"
+
+// ctxKeyParams is the context key of matched route params.
+type ctxKeyParams struct{}
+
+// WithParams returns a copy of ctx carrying the route params of a match.
+// Servers call it before invoking the matched handler.
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - params: The matched route params.
+//
+// Returns:
+//   - context.Context: The context carrying params.
+func WithParams(ctx context.Context, params Params) context.Context {
+	return context.WithValue(ctx, ctxKeyParams{}, params)
+}
+
+// ParamsFromContext returns the route params stored by WithParams, or nil.
+//
+// Parameters:
+//   - ctx: The request context.
+//
+// Returns:
+//   - Params: The route params.
+func ParamsFromContext(ctx context.Context) Params {
+	params, _ := ctx.Value(ctxKeyParams{}).(Params)
+	return params
+}
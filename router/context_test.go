@@ -0,0 +1,16 @@
+package router
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParamsContext(t *testing.T) {
+	if got := ParamsFromContext(context.Background()); got != nil {
+		t.Fatalf("expected nil params, got %v", got)
+	}
+	ctx := WithParams(context.Background(), Params{"id": "7"})
+	if got := ParamsFromContext(ctx)["id"]; got != "7" {
+		t.Fatalf("expected id 7, got %q", got)
+	}
+}
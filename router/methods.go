@@ -45,24 +45,5 @@ func (r *BuiltinRouter) MethodsFor(path string) []string {
 
 // matchSegments matches a path to a list of segments (helper for MethodsFor).
 func matchSegments(segs []segment, path string) Params {
-	parts := splitPath(path)
-	if len(parts) != len(segs) {
-		return nil
-	}
-	params := make(Params, 2)
-	for i, sg := range segs {
-		pp := parts[i]
-		if sg.isParam {
-			// Reject empty segment for params to avoid matching "/" or "//".
-			if pp == "" {
-				return nil
-			}
-			params[sg.name] = pp
-			continue
-		}
-		if sg.lit != pp {
-			return nil
-		}
-	}
-	return params
+	return match(segs, path)
 }
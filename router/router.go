@@ -23,9 +23,10 @@ type Router interface {
 }
 
 type segment struct {
-	lit     string // literal segment; empty if param
-	name    string // ":id" or "{id}" -> "id" when isParam
-	isParam bool
+	lit        string // literal segment; empty if param
+	name       string // ":id", "{id}" or "*id" -> "id" when isParam
+	isParam    bool
+	isCatchAll bool // trailing "*name" matching the rest of the path
 }
 
 type routeEntry struct {
@@ -34,9 +35,10 @@ type routeEntry struct {
 	h       http.Handler
 }
 
-// BuiltinRouter supports exact and param (colon/braces) patterns.
-// Matching is deterministic: exact first, then param routes in
-// registration order.
+// BuiltinRouter supports exact and param (colon/braces) patterns. A trailing
+// "*name" segment is a catch-all that matches the remainder of the path
+// (possibly empty), e.g. "/static/*filepath". Matching is deterministic: exact
+// first, then param routes in registration order.
 type BuiltinRouter struct {
 	exact map[string]map[string]http.Handler // method -> path -> handler
 	param map[string][]routeEntry            // method -> ordered entries
@@ -155,7 +157,15 @@ func hasParam(p string) bool {
 func compile(pat string) []segment {
 	parts := splitPath(pat)
 	segs := make([]segment, 0, len(parts))
-	for _, p := range parts {
+	for i, p := range parts {
+		if isCatchAllSeg(p) && i == len(parts)-1 {
+			segs = append(segs, segment{
+				isParam:    true,
+				isCatchAll: true,
+				name:       p[1:],
+			})
+			continue
+		}
 		if isParamSeg(p) {
 			segs = append(segs, segment{
 				isParam: true,
@@ -171,11 +181,20 @@ func compile(pat string) []segment {
 // match matches a path to a list of segments.
 func match(segs []segment, path string) Params {
 	parts := splitPath(path)
-	if len(parts) != len(segs) {
+	n := len(segs)
+	if n > 0 && segs[n-1].isCatchAll {
+		if len(parts) < n-1 {
+			return nil
+		}
+	} else if len(parts) != n {
 		return nil
 	}
 	params := make(Params, 2)
 	for i, sg := range segs {
+		if sg.isCatchAll {
+			params[sg.name] = strings.Join(parts[i:], "/")
+			break
+		}
 		pp := parts[i]
 		if sg.isParam {
 			// Reject empty segment for params to avoid matching "/" or "//".
@@ -203,12 +222,18 @@ func splitPath(p string) []string {
 // isParamSeg checks if a segment is a parameter.
 func isParamSeg(s string) bool {
 	return (len(s) > 0 && s[0] == ':') ||
-		(len(s) > 1 && s[0] == '{' && s[len(s)-1] == '}')
+		(len(s) > 1 && s[0] == '{' && s[len(s)-1] == '}') ||
+		isCatchAllSeg(s)
+}
+
+// isCatchAllSeg checks if a segment is a named catch-all.
+func isCatchAllSeg(s string) bool {
+	return len(s) > 1 && s[0] == '*'
 }
 
 // trimDelims trims delimiters from a segment.
 func trimDelims(s string) string {
-	if s[0] == ':' {
+	if s[0] == ':' || s[0] == '*' {
 		return s[1:]
 	}
 	if s[0] == '{' && s[len(s)-1] == '}' {
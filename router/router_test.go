@@ -307,3 +307,45 @@ func TestBuiltinRouter_Unregister_WithBraces(t *testing.T) {
 		t.Fatal("Expected no match after unregister, got match")
 	}
 }
+
+func TestBuiltinRouter_CatchAll(t *testing.T) {
+	router := NewBuiltinRouter()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	if err := router.Register("GET", "/static/*filepath", handler); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"/static/app.js", "app.js", true},
+		{"/static/css/site/main.css", "css/site/main.css", true},
+		{"/static/", "", true},
+		{"/static", "", true},
+		{"/other/app.js", "", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		matched := router.Match(req)
+		if !tt.ok {
+			if matched != nil {
+				t.Fatalf("%s: expected no match", tt.path)
+			}
+			continue
+		}
+		if matched == nil {
+			t.Fatalf("%s: expected match, got nil", tt.path)
+		}
+		if got := matched.Params["filepath"]; got != tt.want {
+			t.Fatalf("%s: expected filepath %q, got %q", tt.path, tt.want, got)
+		}
+	}
+
+	methods := router.MethodsFor("/static/a/b")
+	if len(methods) == 0 || methods[1] != "GET" {
+		t.Fatalf("Expected GET in allowed methods, got %v", methods)
+	}
+}
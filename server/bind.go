@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// BoundServer is an HTTPServer whose listener is opened before serving, so
+// the actual address is known even when the server is configured with port 0.
+// The bound address is also reported in the EventStart data under "addr".
+type BoundServer struct {
+	Server   *http.Server
+	listener net.Listener
+}
+
+// BoundServer implements the HTTPServer interface.
+var _ HTTPServer = (*BoundServer)(nil)
+
+// Bind opens a TCP listener on the server address. Use port 0 in the address
+// to let the operating system pick a free port.
+//
+// Parameters:
+//   - srv: The HTTP server to bind.
+//
+// Returns:
+//   - *BoundServer: The bound server.
+//   - error: An error if the address cannot be bound.
+func Bind(srv *http.Server) (*BoundServer, error) {
+	addr := srv.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("Bind: %w", err)
+	}
+	return NewBoundServer(srv, ln), nil
+}
+
+// NewBoundServer creates a BoundServer serving on an existing listener.
+//
+// Parameters:
+//   - srv: The HTTP server to serve.
+//   - ln: The listener to serve on.
+//
+// Returns:
+//   - *BoundServer: A new BoundServer instance.
+func NewBoundServer(srv *http.Server, ln net.Listener) *BoundServer {
+	return &BoundServer{Server: srv, listener: ln}
+}
+
+// Addr returns the address the server is bound to.
+//
+// Returns:
+//   - net.Addr: The bound address.
+func (s *BoundServer) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Port returns the bound TCP port, or 0 for non-TCP listeners.
+//
+// Returns:
+//   - int: The bound port.
+func (s *BoundServer) Port() int {
+	if a, ok := s.listener.Addr().(*net.TCPAddr); ok {
+		return a.Port
+	}
+	return 0
+}
+
+// ListenAndServe serves on the bound listener.
+//
+// Returns:
+//   - error: An error if serving fails.
+func (s *BoundServer) ListenAndServe() error {
+	return s.Server.Serve(s.listener)
+}
+
+// Shutdown gracefully shuts down the server.
+//
+// Parameters:
+//   - ctx: Context bounding the shutdown.
+//
+// Returns:
+//   - error: An error if the shutdown fails.
+func (s *BoundServer) Shutdown(ctx context.Context) error {
+	return s.Server.Shutdown(ctx)
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBind_EphemeralPort(t *testing.T) {
+	emitter := &recordingEmitter{}
+	handler := NewHandler(emitter)
+	ep := endpoint.NewEndpoint("/ping", http.MethodGet).WithHandler(
+		func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("pong")) })
+	srv := DefaultHTTPServer(handler, 0, []endpoint.Endpoint{ep})
+	srv.Addr = "127.0.0.1:0"
+
+	bound, err := Bind(srv)
+	require.NoError(t, err)
+	require.NotZero(t, bound.Port())
+
+	stopChan := make(chan os.Signal, 1)
+	errCh := make(chan error, 1)
+	go func() { errCh <- handler.startServer(stopChan, bound, time.Second) }()
+
+	resp, err := http.Get("http://127.0.0.1:" + strconv.Itoa(bound.Port()) + "/ping")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "pong", string(body))
+
+	stopChan <- os.Interrupt
+	require.NoError(t, <-errCh)
+
+	starts := emitter.ofType(EventStart)
+	require.Len(t, starts, 1)
+	assert.Equal(t,
+		map[string]any{"addr": bound.Addr().String()}, starts[0].Data)
+}
+
+func TestBind_Error(t *testing.T) {
+	_, err := Bind(&http.Server{Addr: "127.0.0.1:-1"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/aatuh/pureapi-core/event"
+)
+
+// Define certificate reload events.
+const (
+	EventCertReloaded    event.EventType = "event_cert_reloaded"
+	EventCertReloadError event.EventType = "event_cert_reload_error"
+)
+
+// CertReloader serves a certificate and key pair from disk and replaces it
+// without restarting the listener. Connections already established keep the
+// certificate they negotiated; new handshakes use the reloaded one. A failed
+// reload keeps serving the previous certificate.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+	emitter  event.EventEmitter
+	cert     atomic.Pointer[tls.Certificate]
+	mu       sync.Mutex // Serializes reloads
+	stamp    string     // Size and modification time of the loaded files
+}
+
+// NewCertReloader loads the certificate and key files.
+//
+// Parameters:
+//   - certFile: Path to the PEM encoded certificate.
+//   - keyFile: Path to the PEM encoded private key.
+//   - emitter: Event emitter for reload events, may be nil.
+//
+// Returns:
+//   - *CertReloader: A new CertReloader instance.
+//   - error: An error if the initial certificate cannot be loaded.
+func NewCertReloader(
+	certFile, keyFile string, emitter event.EventEmitter,
+) (*CertReloader, error) {
+	if emitter == nil {
+		emitter = event.NewNoopEventEmitter()
+	}
+	c := &CertReloader{certFile: certFile, keyFile: keyFile, emitter: emitter}
+	if err := c.load(); err != nil {
+		return nil, fmt.Errorf("NewCertReloader: %w", err)
+	}
+	return c, nil
+}
+
+// Reload reads the certificate and key files again. It emits
+// EventCertReloaded on success and EventCertReloadError on failure.
+//
+// Returns:
+//   - error: An error if the files cannot be loaded.
+func (c *CertReloader) Reload() error {
+	if err := c.load(); err != nil {
+		c.emitter.Emit(
+			event.NewEvent(
+				EventCertReloadError,
+				fmt.Sprintf("Certificate reload failed: %v", err),
+			).WithData(map[string]any{"cert_file": c.certFile, "error": err}),
+		)
+		return fmt.Errorf("Reload: %w", err)
+	}
+	c.emitter.Emit(
+		event.NewEvent(EventCertReloaded, "Certificate reloaded").
+			WithData(map[string]any{"cert_file": c.certFile}),
+	)
+	return nil
+}
+
+// GetCertificate returns the current certificate. It is meant for
+// tls.Config.GetCertificate.
+//
+// Parameters:
+//   - hello: The client hello, unused.
+//
+// Returns:
+//   - *tls.Certificate: The current certificate.
+//   - error: Always nil.
+func (c *CertReloader) GetCertificate(
+	_ *tls.ClientHelloInfo,
+) (*tls.Certificate, error) {
+	return c.cert.Load(), nil
+}
+
+// WatchSignals reloads the certificate on every SIGHUP until ctx is done.
+// It blocks, run it in its own goroutine.
+//
+// Parameters:
+//   - ctx: Context stopping the watch.
+func (c *CertReloader) WatchSignals(ctx context.Context) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	defer signal.Stop(sig)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sig:
+			_ = c.Reload()
+		}
+	}
+}
+
+// Watch polls the certificate and key files and reloads them when their size
+// or modification time changes, until ctx is done. It blocks, run it in its
+// own goroutine.
+//
+// Parameters:
+//   - ctx: Context stopping the watch.
+//   - interval: How often the files are checked.
+func (c *CertReloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stamp, err := c.fileStamp()
+			if err != nil {
+				continue // Files may be mid-replacement.
+			}
+			c.mu.Lock()
+			changed := stamp != c.stamp
+			c.mu.Unlock()
+			if changed {
+				_ = c.Reload()
+			}
+		}
+	}
+}
+
+// WithCertReloader serves TLS certificates from the reloader. It uses
+// TLSModern when the server has no TLS configuration yet. Serve the server
+// with NewTLSServer and empty certificate and key file paths.
+//
+// Parameters:
+//   - c: The certificate reloader.
+//
+// Returns:
+//   - HTTPServerOption: A server option function.
+func WithCertReloader(c *CertReloader) HTTPServerOption {
+	return func(s *http.Server) {
+		if s.TLSConfig == nil {
+			s.TLSConfig = TLSModern()
+		}
+		s.TLSConfig.GetCertificate = c.GetCertificate
+	}
+}
+
+// load reads the key pair and swaps it in.
+func (c *CertReloader) load() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stamp, err := c.fileStamp()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return err
+	}
+	c.cert.Store(&cert)
+	c.stamp = stamp
+	return nil
+}
+
+// fileStamp describes the current state of the certificate and key files.
+func (c *CertReloader) fileStamp() (string, error) {
+	stamp := ""
+	for _, name := range []string{c.certFile, c.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return "", err
+		}
+		stamp += fmt.Sprintf("%d-%d;", info.Size(), info.ModTime().UnixNano())
+	}
+	return stamp, nil
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func currentSerial(t *testing.T, c *CertReloader) string {
+	t.Helper()
+	cert, err := c.GetCertificate(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	require.NotNil(t, cert.Leaf)
+	return cert.Leaf.SerialNumber.String()
+}
+
+func TestCertReloader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSignedCert(t, dir)
+	emitter := &syncEmitter{}
+	c, err := NewCertReloader(certFile, keyFile, emitter)
+	require.NoError(t, err)
+	first := currentSerial(t, c)
+
+	writeSelfSignedCert(t, dir)
+	require.NoError(t, c.Reload())
+	assert.NotEqual(t, first, currentSerial(t, c))
+	assert.Len(t, emitter.ofType(EventCertReloaded), 1)
+
+	// A broken key pair keeps the previous certificate.
+	second := currentSerial(t, c)
+	require.NoError(t, os.WriteFile(keyFile, []byte("garbage"), 0o600))
+	assert.Error(t, c.Reload())
+	assert.Equal(t, second, currentSerial(t, c))
+	assert.Len(t, emitter.ofType(EventCertReloadError), 1)
+}
+
+func TestCertReloader_Watch(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSignedCert(t, dir)
+	c, err := NewCertReloader(certFile, keyFile, nil)
+	require.NoError(t, err)
+	first := currentSerial(t, c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Watch(ctx, 10*time.Millisecond)
+
+	// Ensure a different modification time on coarse file systems.
+	time.Sleep(20 * time.Millisecond)
+	writeSelfSignedCert(t, dir)
+	assert.Eventually(t, func() bool {
+		return currentSerial(t, c) != first
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestCertReloader_WatchSignals(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSignedCert(t, dir)
+	emitter := &syncEmitter{}
+	c, err := NewCertReloader(certFile, keyFile, emitter)
+	require.NoError(t, err)
+
+	// Keep SIGHUP from terminating the test binary if the watcher is late.
+	guard := make(chan os.Signal, 1)
+	signal.Notify(guard, syscall.SIGHUP)
+	defer signal.Stop(guard)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.WatchSignals(ctx)
+	time.Sleep(20 * time.Millisecond)
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGHUP))
+	assert.Eventually(t, func() bool {
+		emitter.mu.Lock()
+		defer emitter.mu.Unlock()
+		return len(emitter.ofType(EventCertReloaded)) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestWithCertReloader(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t, t.TempDir())
+	c, err := NewCertReloader(certFile, keyFile, nil)
+	require.NoError(t, err)
+
+	srv := DefaultHTTPServer(NewHandler(event.NewNoopEventEmitter()), 0, nil, WithCertReloader(c))
+	require.NotNil(t, srv.TLSConfig)
+	assert.NotNil(t, srv.TLSConfig.GetCertificate)
+	assert.Equal(t, uint16(tls.VersionTLS13), srv.TLSConfig.MinVersion)
+}
+
+func TestNewCertReloader_MissingFiles(t *testing.T) {
+	_, err := NewCertReloader("missing.pem", "missing-key.pem", nil)
+	assert.Error(t, err)
+}
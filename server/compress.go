@@ -0,0 +1,363 @@
+package server
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Compressor produces encoders for a single Content-Encoding. Implement it to
+// plug in additional encodings such as brotli.
+type Compressor interface {
+	// Encoding returns the Content-Encoding token, e.g. "gzip" or "br".
+	Encoding() string
+	// NewWriter returns an encoder writing to w. Closing the encoder must
+	// flush any buffered data but must not close w.
+	NewWriter(w io.Writer) io.WriteCloser
+}
+
+// CompressionOption configures response compression.
+type CompressionOption func(*compressionConfig)
+
+// WithCompressionMinSize sets the minimum response size in bytes before a
+// response is compressed. Defaults to 1024.
+//
+// Parameters:
+//   - n: The minimum size in bytes.
+//
+// Returns:
+//   - CompressionOption: A compression option function.
+func WithCompressionMinSize(n int) CompressionOption {
+	return func(c *compressionConfig) { c.minSize = n }
+}
+
+// WithCompressionTypes replaces the list of compressible media types. Entries
+// ending in "/" match a whole top-level type (e.g. "text/"), entries starting
+// with "+" match a structured syntax suffix (e.g. "+json").
+//
+// Parameters:
+//   - types: The compressible media types.
+//
+// Returns:
+//   - CompressionOption: A compression option function.
+func WithCompressionTypes(types ...string) CompressionOption {
+	return func(c *compressionConfig) { c.types = types }
+}
+
+// WithCompressionLevel sets the gzip compression level.
+//
+// Parameters:
+//   - level: A compress/gzip level.
+//
+// Returns:
+//   - CompressionOption: A compression option function.
+func WithCompressionLevel(level int) CompressionOption {
+	return func(c *compressionConfig) { c.gzipLevel = level }
+}
+
+// WithCompressor adds a compressor. Compressors added this way are preferred
+// over gzip, in the order they are added, when the client accepts them.
+//
+// Parameters:
+//   - cmp: The compressor to add.
+//
+// Returns:
+//   - CompressionOption: A compression option function.
+func WithCompressor(cmp Compressor) CompressionOption {
+	return func(c *compressionConfig) {
+		if cmp != nil {
+			c.extra = append(c.extra, cmp)
+		}
+	}
+}
+
+// WithCompression enables response compression for clients sending a
+// matching Accept-Encoding. Only compressible content types of at least the
+// minimum size are compressed; HEAD requests, partial content and responses
+// that already carry a Content-Encoding are left untouched.
+//
+// Parameters:
+//   - opts: Optional compression options.
+//
+// Returns:
+//   - HandlerOption: A handler option function.
+func WithCompression(opts ...CompressionOption) HandlerOption {
+	return func(h *Handler) {
+		cfg := &compressionConfig{
+			minSize:   1024,
+			gzipLevel: gzip.DefaultCompression,
+			types: []string{
+				"text/",
+				"application/json",
+				"application/javascript",
+				"application/xml",
+				"application/x-ndjson",
+				"image/svg+xml",
+				"+json",
+				"+xml",
+			},
+		}
+		for _, opt := range opts {
+			opt(cfg)
+		}
+		cfg.compressors = append(
+			append([]Compressor{}, cfg.extra...),
+			newGzipCompressor(cfg.gzipLevel),
+		)
+		h.compression = cfg
+	}
+}
+
+// compressionConfig holds the compression settings of a Handler.
+type compressionConfig struct {
+	minSize     int
+	gzipLevel   int
+	types       []string
+	extra       []Compressor
+	compressors []Compressor
+}
+
+// wrap returns a compressing writer for the request, or nil if the client
+// accepts none of the configured encodings.
+func (c *compressionConfig) wrap(
+	w http.ResponseWriter, r *http.Request,
+) *compressWriter {
+	w.Header().Add("Vary", "Accept-Encoding")
+	cmp := c.negotiate(r.Header.Values("Accept-Encoding"))
+	if cmp == nil {
+		return nil
+	}
+	return &compressWriter{ResponseWriter: w, cfg: c, cmp: cmp}
+}
+
+// negotiate picks the first configured compressor accepted by the client.
+func (c *compressionConfig) negotiate(acceptEncoding []string) Compressor {
+	accepted := map[string]float64{}
+	for _, header := range acceptEncoding {
+		for _, part := range strings.Split(header, ",") {
+			token, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+			q := 1.0
+			if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+			if token != "" {
+				accepted[strings.ToLower(token)] = q
+			}
+		}
+	}
+	for _, cmp := range c.compressors {
+		q, ok := accepted[cmp.Encoding()]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+		if ok && q > 0 {
+			return cmp
+		}
+	}
+	return nil
+}
+
+// compressible reports whether the media type is configured for compression.
+func (c *compressionConfig) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range c.types {
+		switch {
+		case strings.HasSuffix(t, "/") && strings.HasPrefix(mediaType, t):
+			return true
+		case strings.HasPrefix(t, "+") && strings.HasSuffix(mediaType, t):
+			return true
+		case mediaType == t:
+			return true
+		}
+	}
+	return false
+}
+
+// compressWriter buffers the start of a response until it knows whether the
+// response qualifies for compression, then streams it encoded or as is.
+type compressWriter struct {
+	http.ResponseWriter
+	cfg         *compressionConfig
+	cmp         Compressor
+	status      int
+	wroteHeader bool
+	decided     bool
+	enc         io.WriteCloser
+	buf         []byte
+}
+
+// WriteHeader records the status code. Headers are sent once the
+// compression decision has been made.
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	if code >= 100 && code < 200 {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = code
+	if code == http.StatusNoContent || code == http.StatusNotModified ||
+		code == http.StatusPartialContent ||
+		cw.Header().Get("Content-Encoding") != "" {
+		_ = cw.decide(false)
+	}
+}
+
+// Write buffers or encodes the data.
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.decided {
+		if cw.enc != nil {
+			return cw.enc.Write(p)
+		}
+		return cw.ResponseWriter.Write(p)
+	}
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= cw.cfg.minSize {
+		if err := cw.decide(cw.shouldCompress()); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush sends buffered data to the client. A flushed response is compressed
+// regardless of its size since streams are expected to grow; flushing
+// before any write commits the 200 status and the decision, as the headers
+// are sent.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			cw.WriteHeader(http.StatusOK)
+		}
+		_ = cw.decide(cw.shouldCompress())
+	}
+	if f, ok := cw.enc.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying response writer.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Close finishes the response, flushing any buffered data.
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if !cw.wroteHeader && len(cw.buf) == 0 {
+			return nil
+		}
+		if !cw.wroteHeader {
+			cw.WriteHeader(http.StatusOK)
+		}
+		if err := cw.decide(false); err != nil {
+			return err
+		}
+	}
+	if cw.enc != nil {
+		return cw.enc.Close()
+	}
+	return nil
+}
+
+// shouldCompress reports whether the buffered response qualifies.
+func (cw *compressWriter) shouldCompress() bool {
+	hdr := cw.Header()
+	if hdr.Get("Content-Encoding") != "" || hdr.Get("Content-Range") != "" {
+		return false
+	}
+	ct := hdr.Get("Content-Type")
+	if ct == "" && len(cw.buf) == 0 {
+		// Nothing to sniff, as when flushing before writing.
+		return false
+	}
+	if ct == "" {
+		// Sniff now: once encoded the server could no longer do it.
+		ct = http.DetectContentType(cw.buf)
+		hdr.Set("Content-Type", ct)
+	}
+	return cw.cfg.compressible(ct)
+}
+
+// decide sends the headers and the buffered data, compressed or not.
+func (cw *compressWriter) decide(compress bool) error {
+	cw.decided = true
+	if compress {
+		hdr := cw.Header()
+		hdr.Del("Content-Length")
+		hdr.Set("Content-Encoding", cw.cmp.Encoding())
+		cw.ResponseWriter.WriteHeader(cw.status)
+		cw.enc = cw.cmp.NewWriter(cw.ResponseWriter)
+		if len(cw.buf) > 0 {
+			if _, err := cw.enc.Write(cw.buf); err != nil {
+				return err
+			}
+		}
+	} else {
+		cw.ResponseWriter.WriteHeader(cw.status)
+		if len(cw.buf) > 0 {
+			if _, err := cw.ResponseWriter.Write(cw.buf); err != nil {
+				return err
+			}
+		}
+	}
+	cw.buf = nil
+	return nil
+}
+
+// gzipCompressor is the built-in gzip Compressor with writer pooling.
+type gzipCompressor struct {
+	level int
+	pool  sync.Pool
+}
+
+// newGzipCompressor creates a gzip compressor for the given level.
+func newGzipCompressor(level int) *gzipCompressor {
+	return &gzipCompressor{level: level}
+}
+
+// Encoding returns "gzip".
+func (g *gzipCompressor) Encoding() string { return "gzip" }
+
+// NewWriter returns a pooled gzip writer.
+func (g *gzipCompressor) NewWriter(w io.Writer) io.WriteCloser {
+	if gz, ok := g.pool.Get().(*gzip.Writer); ok {
+		gz.Reset(w)
+		return &pooledGzipWriter{Writer: gz, pool: &g.pool}
+	}
+	gz, err := gzip.NewWriterLevel(w, g.level)
+	if err != nil {
+		gz = gzip.NewWriter(w)
+	}
+	return &pooledGzipWriter{Writer: gz, pool: &g.pool}
+}
+
+// pooledGzipWriter returns its gzip writer to the pool on Close.
+type pooledGzipWriter struct {
+	*gzip.Writer
+	pool *sync.Pool
+}
+
+// Close flushes the gzip stream and releases the writer.
+func (p *pooledGzipWriter) Close() error {
+	err := p.Writer.Close()
+	p.pool.Put(p.Writer)
+	return err
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// upperCompressor is a toy Compressor used to test plug-in encodings.
+type upperCompressor struct{}
+
+func (upperCompressor) Encoding() string { return "upper" }
+
+func (upperCompressor) NewWriter(w io.Writer) io.WriteCloser {
+	return nopCloser{Writer: upperWriter{w}}
+}
+
+type upperWriter struct{ w io.Writer }
+
+func (u upperWriter) Write(p []byte) (int, error) {
+	return u.w.Write([]byte(strings.ToUpper(string(p))))
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+func newCompressionTestHandler(opts ...CompressionOption) *Handler {
+	h := NewHandler(event.NewNoopEventEmitter(), WithCompression(opts...))
+	h.Register([]endpoint.Endpoint{
+		endpoint.NewEndpoint("/big", http.MethodGet).WithHandler(
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Content-Length", "4000")
+				_, _ = w.Write([]byte(strings.Repeat("a", 4000)))
+			}),
+		endpoint.NewEndpoint("/small", http.MethodGet).WithHandler(
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = w.Write([]byte("tiny"))
+			}),
+		endpoint.NewEndpoint("/image", http.MethodGet).WithHandler(
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				_, _ = w.Write(make([]byte, 4000))
+			}),
+	})
+	return h
+}
+
+func doCompressionRequest(h http.Handler, method, path, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept-Encoding", accept)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestCompression_Gzip(t *testing.T) {
+	h := newCompressionTestHandler()
+
+	rr := doCompressionRequest(h, http.MethodGet, "/big", "gzip, deflate")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Empty(t, rr.Header().Get("Content-Length"))
+	assert.Contains(t, rr.Header().Values("Vary"), "Accept-Encoding")
+
+	zr, err := gzip.NewReader(rr.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("a", 4000), string(body))
+}
+
+func TestCompression_Skipped(t *testing.T) {
+	h := newCompressionTestHandler()
+
+	tests := []struct {
+		name, method, path, accept string
+	}{
+		{"no accept-encoding", http.MethodGet, "/big", ""},
+		{"refused", http.MethodGet, "/big", "gzip;q=0"},
+		{"below threshold", http.MethodGet, "/small", "gzip"},
+		{"incompressible type", http.MethodGet, "/image", "gzip"},
+		{"head", http.MethodHead, "/big", "gzip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doCompressionRequest(h, tt.method, tt.path, tt.accept)
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		})
+	}
+
+	rr := doCompressionRequest(h, http.MethodGet, "/small", "gzip")
+	assert.Equal(t, "tiny", rr.Body.String())
+	rr = doCompressionRequest(h, http.MethodHead, "/big", "gzip")
+	assert.Empty(t, rr.Body.String())
+	assert.Equal(t, "4000", rr.Header().Get("Content-Length"))
+}
+
+func TestCompression_PluggableCompressor(t *testing.T) {
+	h := newCompressionTestHandler(
+		WithCompressor(upperCompressor{}), WithCompressionMinSize(1),
+	)
+
+	rr := doCompressionRequest(h, http.MethodGet, "/small", "gzip, upper")
+	assert.Equal(t, "upper", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "TINY", rr.Body.String())
+
+	rr = doCompressionRequest(h, http.MethodGet, "/small", "gzip")
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+}
+
+func TestCompression_FlushFirst(t *testing.T) {
+	h := NewHandler(event.NewNoopEventEmitter(), WithCompression())
+	stream := func(contentType string) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			if contentType != "" {
+				w.Header().Set("Content-Type", contentType)
+			}
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte("data: hello\n\n"))
+		}
+	}
+	h.Register([]endpoint.Endpoint{
+		endpoint.NewEndpoint("/typed", http.MethodGet).WithHandler(stream("text/plain")),
+		endpoint.NewEndpoint("/untyped", http.MethodGet).WithHandler(stream("")),
+	})
+
+	rr := doCompressionRequest(h, http.MethodGet, "/typed", "gzip")
+	require.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	zr, err := gzip.NewReader(rr.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, "data: hello\n\n", string(body))
+
+	rr = doCompressionRequest(h, http.MethodGet, "/untyped", "gzip")
+	assert.Empty(t, rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "data: hello\n\n", rr.Body.String())
+}
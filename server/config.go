@@ -0,0 +1,126 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ServerConfig holds the timeouts and limits of an HTTP server. All fields
+// are applied as set, with zero timeouts meaning no timeout as in
+// http.Server, so callers should start from DefaultServerConfig:
+//
+//	cfg := server.DefaultServerConfig()
+//	cfg.WriteTimeout = 0 // Long-lived streams.
+type ServerConfig struct {
+	ReadTimeout       time.Duration // Maximum duration for reading a request.
+	ReadHeaderTimeout time.Duration // Maximum duration for reading headers.
+	WriteTimeout      time.Duration // Maximum duration for writing a response.
+	IdleTimeout       time.Duration // Keep-alive idle time between requests.
+	MaxHeaderBytes    int           // Maximum size of request headers.
+	DisableKeepAlives bool          // Whether HTTP keep-alives are disabled.
+}
+
+// DefaultServerConfig returns the configuration used by DefaultHTTPServer:
+// read and write timeouts of 10 seconds, header timeout of 5 seconds, idle
+// timeout of 60 seconds, 64KB max header size and keep-alives enabled.
+//
+// Returns:
+//   - ServerConfig: The default configuration.
+func DefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		ReadTimeout:       10 * time.Second, // Limits slow clients.
+		ReadHeaderTimeout: 5 * time.Second,  // Prevent slow header attacks.
+		WriteTimeout:      10 * time.Second, // Ensures fast responses.
+		IdleTimeout:       60 * time.Second, // Keeps alive long enough.
+		MaxHeaderBytes:    1 << 16,          // 64KB to prevent excessive memory use.
+	}
+}
+
+// ServerConfigFromEnv returns the default configuration overridden by
+// environment variables. Variable names are the prefix followed by
+// READ_TIMEOUT, READ_HEADER_TIMEOUT, WRITE_TIMEOUT, IDLE_TIMEOUT,
+// MAX_HEADER_BYTES and KEEP_ALIVES, e.g. "API_READ_TIMEOUT" for the prefix
+// "API_". Timeouts use time.ParseDuration syntax ("15s"), KEEP_ALIVES uses
+// strconv.ParseBool syntax. Unset or empty variables keep their defaults.
+//
+// Parameters:
+//   - prefix: The environment variable prefix.
+//
+// Returns:
+//   - ServerConfig: The resulting configuration.
+//   - error: An error if a variable cannot be parsed.
+func ServerConfigFromEnv(prefix string) (ServerConfig, error) {
+	cfg := DefaultServerConfig()
+	durations := []struct {
+		name string
+		dst  *time.Duration
+	}{
+		{"READ_TIMEOUT", &cfg.ReadTimeout},
+		{"READ_HEADER_TIMEOUT", &cfg.ReadHeaderTimeout},
+		{"WRITE_TIMEOUT", &cfg.WriteTimeout},
+		{"IDLE_TIMEOUT", &cfg.IdleTimeout},
+	}
+	for _, d := range durations {
+		v, ok := lookupEnv(prefix + d.name)
+		if !ok {
+			continue
+		}
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return ServerConfig{}, fmt.Errorf(
+				"ServerConfigFromEnv: %s: %w", prefix+d.name, err,
+			)
+		}
+		*d.dst = parsed
+	}
+	if v, ok := lookupEnv(prefix + "MAX_HEADER_BYTES"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return ServerConfig{}, fmt.Errorf(
+				"ServerConfigFromEnv: %sMAX_HEADER_BYTES: %w", prefix, err,
+			)
+		}
+		cfg.MaxHeaderBytes = n
+	}
+	if v, ok := lookupEnv(prefix + "KEEP_ALIVES"); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return ServerConfig{}, fmt.Errorf(
+				"ServerConfigFromEnv: %sKEEP_ALIVES: %w", prefix, err,
+			)
+		}
+		cfg.DisableKeepAlives = !b
+	}
+	return cfg, nil
+}
+
+// WithServerConfig applies the configuration to the server, replacing the
+// defaults of DefaultHTTPServer. Every field is applied, see ServerConfig.
+//
+// Parameters:
+//   - cfg: The configuration to apply.
+//
+// Returns:
+//   - HTTPServerOption: A server option function.
+func WithServerConfig(cfg ServerConfig) HTTPServerOption {
+	return cfg.apply
+}
+
+// apply sets the configuration on the server.
+func (c ServerConfig) apply(s *http.Server) {
+	s.ReadTimeout = c.ReadTimeout
+	s.ReadHeaderTimeout = c.ReadHeaderTimeout
+	s.WriteTimeout = c.WriteTimeout
+	s.IdleTimeout = c.IdleTimeout
+	s.MaxHeaderBytes = c.MaxHeaderBytes
+	s.SetKeepAlivesEnabled(!c.DisableKeepAlives)
+}
+
+// lookupEnv returns a non-empty environment variable.
+func lookupEnv(name string) (string, bool) {
+	v, ok := os.LookupEnv(name)
+	return v, ok && v != ""
+}
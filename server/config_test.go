@@ -0,0 +1,64 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerConfigFromEnv(t *testing.T) {
+	t.Setenv("API_READ_TIMEOUT", "15s")
+	t.Setenv("API_IDLE_TIMEOUT", "2m")
+	t.Setenv("API_MAX_HEADER_BYTES", "4096")
+	t.Setenv("API_KEEP_ALIVES", "false")
+
+	cfg, err := ServerConfigFromEnv("API_")
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Second, cfg.ReadTimeout)
+	assert.Equal(t, 2*time.Minute, cfg.IdleTimeout)
+	assert.Equal(t, 10*time.Second, cfg.WriteTimeout)
+	assert.Equal(t, 5*time.Second, cfg.ReadHeaderTimeout)
+	assert.Equal(t, 4096, cfg.MaxHeaderBytes)
+	assert.True(t, cfg.DisableKeepAlives)
+}
+
+func TestServerConfigFromEnv_Invalid(t *testing.T) {
+	t.Setenv("API_WRITE_TIMEOUT", "soon")
+	_, err := ServerConfigFromEnv("API_")
+	assert.ErrorContains(t, err, "API_WRITE_TIMEOUT")
+
+	t.Setenv("API_WRITE_TIMEOUT", "")
+	t.Setenv("API_MAX_HEADER_BYTES", "lots")
+	_, err = ServerConfigFromEnv("API_")
+	assert.ErrorContains(t, err, "API_MAX_HEADER_BYTES")
+}
+
+func TestDefaultHTTPServer_WithServerConfig(t *testing.T) {
+	cfg := DefaultServerConfig()
+	cfg.WriteTimeout = 30 * time.Second
+	cfg.MaxHeaderBytes = 1 << 20
+
+	srv := DefaultHTTPServer(
+		NewHandler(event.NewNoopEventEmitter()), 0, nil, WithServerConfig(cfg),
+	)
+	assert.Equal(t, 30*time.Second, srv.WriteTimeout)
+	assert.Equal(t, 10*time.Second, srv.ReadTimeout)
+	assert.Equal(t, 1<<20, srv.MaxHeaderBytes)
+}
+
+func TestServerConfig_ZeroTimeout(t *testing.T) {
+	t.Setenv("API_WRITE_TIMEOUT", "0s")
+	cfg, err := ServerConfigFromEnv("API_")
+	require.NoError(t, err)
+	assert.Zero(t, cfg.WriteTimeout)
+
+	srv := DefaultHTTPServer(
+		NewHandler(event.NewNoopEventEmitter()), 0, nil, WithServerConfig(cfg),
+	)
+	assert.Zero(t, srv.WriteTimeout)
+	assert.Equal(t, 10*time.Second, srv.ReadTimeout)
+	assert.Equal(t, 60*time.Second, srv.IdleTimeout)
+}
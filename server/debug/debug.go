@@ -0,0 +1,157 @@
+package debug
+
+import (
+	"expvar"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
+
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/aatuh/pureapi-core/server"
+)
+
+// Guard decides whether a request may access the debug endpoints.
+type Guard func(r *http.Request) bool
+
+// LoopbackOnly allows requests whose remote address is a loopback address.
+// Behind a reverse proxy every request appears to come from the proxy, so use
+// a custom guard (e.g. checking credentials) or a separate admin listener.
+//
+// Parameters:
+//   - r: The request.
+//
+// Returns:
+//   - bool: True if the client is on the loopback interface.
+func LoopbackOnly(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Handler returns a handler serving pprof under prefix+"pprof/" and expvar
+// under prefix+"vars". Requests rejected by the guard receive 404 so the
+// endpoints are not advertised. A nil guard means LoopbackOnly.
+//
+// Parameters:
+//   - prefix: The path prefix, e.g. "/debug/".
+//   - guard: Optional access guard.
+//
+// Returns:
+//   - http.Handler: The debug handler.
+func Handler(prefix string, guard Guard) http.Handler {
+	prefix = normalizePrefix(prefix)
+	if guard == nil {
+		guard = LoopbackOnly
+	}
+	vars := expvar.Handler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !guard(r) {
+			http.NotFound(w, r)
+			return
+		}
+		rest, ok := strings.CutPrefix(r.URL.Path, prefix)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		switch rest {
+		case "vars":
+			vars.ServeHTTP(w, r)
+			return
+		case "pprof", "pprof/":
+			// pprof.Index resolves profile names from the standard path.
+			r2 := r.Clone(r.Context())
+			r2.URL.Path = "/debug/pprof/"
+			pprof.Index(w, r2)
+			return
+		}
+		name, ok := strings.CutPrefix(rest, "pprof/")
+		if !ok || name == "" {
+			http.NotFound(w, r)
+			return
+		}
+		switch name {
+		case "cmdline":
+			pprof.Cmdline(w, r)
+		case "profile":
+			pprof.Profile(w, r)
+		case "symbol":
+			pprof.Symbol(w, r)
+		case "trace":
+			pprof.Trace(w, r)
+		default:
+			pprof.Handler(name).ServeHTTP(w, r)
+		}
+	})
+}
+
+// WithEndpoints mounts the debug handler on a server.Handler under prefix.
+//
+// Parameters:
+//   - prefix: The path prefix, e.g. "/debug/".
+//   - guard: Optional access guard, nil means LoopbackOnly.
+//
+// Returns:
+//   - server.HandlerOption: A handler option function.
+func WithEndpoints(prefix string, guard Guard) server.HandlerOption {
+	prefix = normalizePrefix(prefix)
+	return server.WithMount(prefix, Handler(prefix, guard))
+}
+
+// WithEvents mounts a handler serving the recent events recorded by
+// recent under path, see event.RecentEvents. Like the other debug
+// endpoints, requests rejected by the guard receive 404.
+//
+// Parameters:
+//   - path: The path, e.g. "/debug/events".
+//   - recent: The recorded events.
+//   - guard: Optional access guard, nil means LoopbackOnly.
+//
+// Returns:
+//   - server.HandlerOption: A handler option function.
+func WithEvents(
+	path string, recent *event.RecentEvents, guard Guard,
+) server.HandlerOption {
+	if guard == nil {
+		guard = LoopbackOnly
+	}
+	return server.WithMount(path, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if !guard(r) {
+				http.NotFound(w, r)
+				return
+			}
+			recent.ServeHTTP(w, r)
+		},
+	))
+}
+
+// NewServer returns a separate admin server exposing only the debug
+// endpoints under "/debug/". Manage it like any other HTTPServer. CPU
+// profiles and traces can take a while, so the write timeout is generous.
+//
+// Parameters:
+//   - addr: The listen address, e.g. "127.0.0.1:6060".
+//   - guard: Optional access guard, nil means LoopbackOnly.
+//
+// Returns:
+//   - *http.Server: The admin server.
+func NewServer(addr string, guard Guard) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           Handler("/debug/", guard),
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      90 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+}
+
+// normalizePrefix ensures the prefix starts and ends with a slash.
+func normalizePrefix(prefix string) string {
+	return "/" + strings.Trim(prefix, "/") + "/"
+}
@@ -0,0 +1,99 @@
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/aatuh/pureapi-core/server"
+)
+
+func TestWithEndpoints(t *testing.T) {
+	h := server.NewHandler(
+		event.NewNoopEventEmitter(), WithEndpoints("/admin/debug", nil),
+	)
+
+	tests := []struct {
+		name     string
+		path     string
+		remote   string
+		wantCode int
+	}{
+		{"index", "/admin/debug/pprof/", "127.0.0.1:1234", http.StatusOK},
+		{"named profile", "/admin/debug/pprof/goroutine?debug=1", "127.0.0.1:1234", http.StatusOK},
+		{"cmdline", "/admin/debug/pprof/cmdline", "[::1]:1234", http.StatusOK},
+		{"vars", "/admin/debug/vars", "127.0.0.1:1234", http.StatusOK},
+		{"remote client", "/admin/debug/pprof/", "203.0.113.7:1234", http.StatusNotFound},
+		{"unknown", "/admin/debug/nope", "127.0.0.1:1234", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.RemoteAddr = tt.remote
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_CustomGuardAndVars(t *testing.T) {
+	h := Handler("/debug/", func(r *http.Request) bool {
+		return r.Header.Get("X-Admin-Token") == "secret"
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without token, got %d", rr.Code)
+	}
+
+	req.Header.Set("X-Admin-Token", "secret")
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 with token, got %d", rr.Code)
+	}
+	var vars map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &vars); err != nil {
+		t.Fatalf("expected JSON vars, got error: %v", err)
+	}
+	if _, ok := vars["memstats"]; !ok {
+		t.Fatalf("expected memstats in vars, got %v", vars)
+	}
+}
+
+func TestWithEvents(t *testing.T) {
+	recent := event.NewRecentEvents(5)
+	recent.Record(event.NewEvent(server.EventPanic, "boom"))
+	h := server.NewHandler(
+		event.NewNoopEventEmitter(), WithEvents("/debug/events", recent, nil),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/events", nil)
+	req.RemoteAddr = "127.0.0.1:1234"
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var events []map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &events); err != nil {
+		t.Fatalf("decode events: %v", err)
+	}
+	if len(events) != 1 || events[0]["message"] != "boom" {
+		t.Fatalf("unexpected events: %v", events)
+	}
+
+	req.RemoteAddr = "203.0.113.7:1234"
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for remote client, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,12 @@
+// Package debug mounts net/http/pprof profiling and expvar metrics handlers,
+// and a handler of recent events.
+//
+// The handlers live in a separate package because importing net/http/pprof
+// and expvar registers them on http.DefaultServeMux. Applications only pay
+// for that side effect when they opt in by importing this package. Access is
+// restricted to loopback clients unless a custom guard is provided.
+//
+// Example:
+//
+//	h := server.NewHandler(emitter, debug.WithEndpoints("/debug/", nil))
+package debug
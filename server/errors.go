@@ -0,0 +1,146 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/aatuh/pureapi-core/endpoint"
+)
+
+// Error IDs used for failures produced by the Handler itself.
+const (
+	ErrIDNotFound         = "not_found"
+	ErrIDMethodNotAllowed = "method_not_allowed"
+	ErrIDRequestTooLarge  = "request_too_large"
+	ErrIDForbidden        = "forbidden"
+	ErrIDInternal         = "internal_error"
+)
+
+// ErrorRenderer writes the responses for failures produced by the Handler
+// itself rather than by endpoints: unknown routes (404), wrong methods (405),
+// oversized bodies (413), blocked clients (403) and recovered panics (500).
+type ErrorRenderer interface {
+	RenderError(
+		w http.ResponseWriter, r *http.Request, status int, err apierror.APIError,
+	)
+}
+
+// ErrorRendererFunc adapts a function to the ErrorRenderer interface.
+type ErrorRendererFunc func(
+	w http.ResponseWriter, r *http.Request, status int, err apierror.APIError,
+)
+
+// RenderError calls f(w, r, status, err).
+func (f ErrorRendererFunc) RenderError(
+	w http.ResponseWriter, r *http.Request, status int, err apierror.APIError,
+) {
+	f(w, r, status, err)
+}
+
+// TextErrorRenderer renders plain text responses like http.Error. It is the
+// default renderer.
+type TextErrorRenderer struct{}
+
+// RenderError writes the error message as plain text.
+//
+// Parameters:
+//   - w: The response writer.
+//   - r: The request.
+//   - status: The HTTP status code.
+//   - err: The error to render.
+func (TextErrorRenderer) RenderError(
+	w http.ResponseWriter, r *http.Request, status int, err apierror.APIError,
+) {
+	if status == http.StatusNotFound {
+		http.NotFound(w, r)
+		return
+	}
+	http.Error(w, err.Message(), status)
+}
+
+// JSONErrorRenderer renders apierror JSON bodies, extended with the request
+// ID when one is available, so server-level failures share the format of
+// endpoint errors.
+type JSONErrorRenderer struct{}
+
+// RenderError writes the error as JSON.
+//
+// Parameters:
+//   - w: The response writer.
+//   - r: The request.
+//   - status: The HTTP status code.
+//   - err: The error to render.
+func (JSONErrorRenderer) RenderError(
+	w http.ResponseWriter, r *http.Request, status int, err apierror.APIError,
+) {
+	body := struct {
+		*apierror.DefaultAPIError
+		RequestID string `json:"request_id,omitempty"`
+	}{
+		DefaultAPIError: apierror.APIErrorFrom(err),
+		RequestID:       requestID(r),
+	}
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Type", "application/json; charset=utf-8")
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// EndpointErrorRenderer renders JSON bodies built by endpoint.RenderError,
+// so server-level failures follow the error format set with
+// endpoint.SetErrorRenderer.
+type EndpointErrorRenderer struct{}
+
+// RenderError writes the rendered error as JSON.
+//
+// Parameters:
+//   - w: The response writer.
+//   - r: The request.
+//   - status: The HTTP status code.
+//   - err: The error to render.
+func (EndpointErrorRenderer) RenderError(
+	w http.ResponseWriter, r *http.Request, status int, err apierror.APIError,
+) {
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Type", "application/json; charset=utf-8")
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(endpoint.RenderError(r, status, err))
+}
+
+// WithErrorRenderer sets the renderer for failures produced by the Handler.
+// A custom not found handler set with WithNotFound takes precedence for 404.
+//
+// Parameters:
+//   - renderer: The error renderer to use.
+//
+// Returns:
+//   - HandlerOption: A handler option function.
+func WithErrorRenderer(renderer ErrorRenderer) HandlerOption {
+	return func(h *Handler) {
+		if renderer != nil {
+			h.errorRenderer = renderer
+		}
+	}
+}
+
+// renderError renders a Handler level failure.
+func (h *Handler) renderError(
+	w http.ResponseWriter, r *http.Request, status int, id, message string,
+) {
+	h.errorRenderer.RenderError(
+		w, r, status, apierror.NewAPIError(id).WithMessage(message),
+	)
+}
+
+// requestID returns the request ID from the context or the request header.
+func requestID(r *http.Request) string {
+	if id := endpoint.RequestIDFromRequest(r); id != "" {
+		return id
+	}
+	return r.Header.Get("X-Request-ID")
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/apierror"
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonErrorBody struct {
+	ID        string `json:"id"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+}
+
+func TestJSONErrorRenderer_ServerFailures(t *testing.T) {
+	h := NewHandler(event.NewNoopEventEmitter(),
+		WithErrorRenderer(JSONErrorRenderer{}),
+		WithBodyLimit(4),
+	)
+	h.Register([]endpoint.Endpoint{
+		endpoint.NewEndpoint("/items", http.MethodPost).WithHandler(
+			func(w http.ResponseWriter, r *http.Request) {}),
+		endpoint.NewEndpoint("/panic", http.MethodGet).WithHandler(
+			func(w http.ResponseWriter, r *http.Request) { panic("boom") }),
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		id     string
+	}{
+		{"not found", http.MethodGet, "/missing", "", http.StatusNotFound, ErrIDNotFound},
+		{"method not allowed", http.MethodDelete, "/items", "", http.StatusMethodNotAllowed, ErrIDMethodNotAllowed},
+		{"too large", http.MethodPost, "/items", "too large", http.StatusRequestEntityTooLarge, ErrIDRequestTooLarge},
+		{"panic", http.MethodGet, "/panic", "", http.StatusInternalServerError, ErrIDInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("X-Request-ID", "req-1")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+			var body jsonErrorBody
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.id, body.ID)
+			assert.NotEmpty(t, body.Message)
+			assert.Equal(t, "req-1", body.RequestID)
+		})
+	}
+}
+
+func TestTextErrorRenderer_Default(t *testing.T) {
+	h := NewHandler(event.NewNoopEventEmitter())
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "404 page not found\n", rec.Body.String())
+}
+
+func TestErrorRendererFunc(t *testing.T) {
+	var got apierror.APIError
+	h := NewHandler(event.NewNoopEventEmitter(), WithErrorRenderer(
+		ErrorRendererFunc(func(w http.ResponseWriter, r *http.Request, status int, err apierror.APIError) {
+			got = err
+			w.WriteHeader(status)
+		}),
+	))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	require.NotNil(t, got)
+	assert.Equal(t, ErrIDNotFound, got.ID())
+}
+
+func TestWithNotFound_OverridesRenderer(t *testing.T) {
+	h := NewHandler(event.NewNoopEventEmitter(),
+		WithErrorRenderer(JSONErrorRenderer{}),
+		WithNotFound(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})),
+	)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+}
+
+func TestEndpointErrorRenderer(t *testing.T) {
+	endpoint.SetErrorRenderer(func(_ *http.Request, status int, err apierror.APIError) any {
+		return map[string]any{"code": err.ID(), "status": status}
+	})
+	t.Cleanup(func() { endpoint.SetErrorRenderer(nil) })
+
+	h := NewHandler(event.NewNoopEventEmitter(), WithErrorRenderer(EndpointErrorRenderer{}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"code":"not_found","status":404}`, rec.Body.String())
+}
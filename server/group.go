@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/aatuh/pureapi-core/event"
+)
+
+// Group starts and gracefully stops several HTTP servers together, e.g. a
+// public API, an internal admin server and a metrics server. When one server
+// fails, the others are shut down as well. Events carry the member name in
+// their data under "server".
+type Group struct {
+	emitter event.EventEmitter
+	members []groupMember
+}
+
+// groupMember is a named server of a Group.
+type groupMember struct {
+	name   string
+	server HTTPServer
+}
+
+// NewGroup creates an empty server group.
+//
+// Parameters:
+//   - emitter: Event emitter for lifecycle events, may be nil.
+//
+// Returns:
+//   - *Group: A new Group instance.
+func NewGroup(emitter event.EventEmitter) *Group {
+	if emitter == nil {
+		emitter = event.NewNoopEventEmitter()
+	}
+	return &Group{emitter: emitter}
+}
+
+// Add adds a named server to the group. Servers whose handler is a *Handler
+// get their shutdown context cancelled when the group shuts down.
+//
+// Parameters:
+//   - name: The server name used in events and errors.
+//   - srv: The server to add.
+//
+// Returns:
+//   - *Group: The group, for chaining.
+func (g *Group) Add(name string, srv HTTPServer) *Group {
+	g.members = append(g.members, groupMember{name: name, server: srv})
+	return g
+}
+
+// Start runs the group until an OS interrupt or SIGTERM is received or a
+// server fails. If no shutdown timeout is provided, 60 seconds will be used
+// by default.
+//
+// Parameters:
+//   - shutdownTimeout: Optional shutdown timeout shared by all servers.
+//
+// Returns:
+//   - error: The joined errors of all servers, nil on clean shutdown.
+func (g *Group) Start(shutdownTimeout *time.Duration) error {
+	timeout := defaultShutdownTimeout
+	if shutdownTimeout != nil {
+		timeout = *shutdownTimeout
+	}
+	ctx, stop := signal.NotifyContext(
+		context.Background(), os.Interrupt, syscall.SIGTERM,
+	)
+	defer stop()
+	return g.Run(ctx, timeout)
+}
+
+// Run starts all servers and shuts them down when ctx is done or any server
+// stops with an error. Shutdowns run concurrently within shutdownTimeout.
+//
+// Parameters:
+//   - ctx: Context whose cancellation triggers shutdown.
+//   - shutdownTimeout: Shutdown timeout shared by all servers.
+//
+// Returns:
+//   - error: The joined errors of all servers, nil on clean shutdown.
+func (g *Group) Run(ctx context.Context, shutdownTimeout time.Duration) error {
+	errs := make([]error, len(g.members))
+	failed := make(chan struct{}, len(g.members))
+	var serving sync.WaitGroup
+	for i, m := range g.members {
+		if ts, ok := m.server.(shutdownTimeoutSetter); ok {
+			ts.setShutdownTimeout(shutdownTimeout)
+		}
+		serving.Add(1)
+		go func() {
+			defer serving.Done()
+			g.emit(EventStart, "Starting HTTP server", m, nil)
+			err := m.server.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				g.emit(EventErrorStart,
+					fmt.Sprintf("Error starting HTTP server: %v", err), m, err)
+				errs[i] = fmt.Errorf("%s: %w", m.name, err)
+				failed <- struct{}{}
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-failed:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(
+		context.Background(), shutdownTimeout,
+	)
+	defer cancel()
+	shutdownErrs := make([]error, len(g.members))
+	var stopping sync.WaitGroup
+	for i, m := range g.members {
+		stopping.Add(1)
+		go func() {
+			defer stopping.Done()
+			g.emit(EventShutDownStarted, "Shutting down HTTP server", m, nil)
+			if h := serverHandler(m.server); h != nil {
+				h.BeginShutdown()
+			}
+			if err := m.server.Shutdown(shutdownCtx); err != nil {
+				g.emit(EventShutDownError, "HTTP server shutdown error", m, err)
+				shutdownErrs[i] = fmt.Errorf("%s: shutdown error: %w", m.name, err)
+				return
+			}
+			g.emit(EventShutDown, "HTTP server shut down", m, nil)
+		}()
+	}
+	stopping.Wait()
+	serving.Wait()
+	return errors.Join(append(errs, shutdownErrs...)...)
+}
+
+// emit emits a lifecycle event for a member.
+func (g *Group) emit(
+	t event.EventType, msg string, m groupMember, err error,
+) {
+	data := map[string]any{"server": m.name}
+	if addr := serverAddr(m.server); addr != "" {
+		data["addr"] = addr
+	}
+	if err != nil {
+		data["error"] = err
+	}
+	g.emitter.Emit(
+		event.NewEvent(t, fmt.Sprintf("%s: %s", m.name, msg)).WithData(data),
+	)
+}
+
+// serverHandler returns the *Handler served by a server, if any.
+func serverHandler(server HTTPServer) *Handler {
+	var srv *http.Server
+	switch s := server.(type) {
+	case *http.Server:
+		srv = s
+	case *BoundServer:
+		srv = s.Server
+	case *TLSServer:
+		srv = s.Server
+	}
+	if srv == nil {
+		return nil
+	}
+	h, _ := srv.Handler.(*Handler)
+	return h
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncEmitter records emitted events from several goroutines.
+type syncEmitter struct {
+	recordingEmitter
+	mu sync.Mutex
+}
+
+func (s *syncEmitter) Emit(ev *event.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recordingEmitter.Emit(ev)
+}
+
+func bindTestServer(t *testing.T, h *Handler) *BoundServer {
+	srv := DefaultHTTPServer(h, 0, nil)
+	srv.Addr = "127.0.0.1:0"
+	bound, err := Bind(srv)
+	require.NoError(t, err)
+	return bound
+}
+
+func TestGroup_Run(t *testing.T) {
+	emitter := &syncEmitter{}
+	api := NewHandler(event.NewNoopEventEmitter())
+	api.Register([]endpoint.Endpoint{textEndpoint("/", http.MethodGet, "api")})
+	admin := NewHandler(event.NewNoopEventEmitter())
+	admin.Register([]endpoint.Endpoint{textEndpoint("/", http.MethodGet, "admin")})
+	apiSrv, adminSrv := bindTestServer(t, api), bindTestServer(t, admin)
+
+	g := NewGroup(emitter).Add("api", apiSrv).Add("admin", adminSrv)
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- g.Run(ctx, time.Second) }()
+
+	for _, port := range []int{apiSrv.Port(), adminSrv.Port()} {
+		resp, err := http.Get("http://127.0.0.1:" + strconv.Itoa(port) + "/")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	cancel()
+	require.NoError(t, <-errCh)
+	assert.Len(t, emitter.ofType(EventStart), 2)
+	assert.Len(t, emitter.ofType(EventShutDown), 2)
+	assert.Error(t, api.shutdownCtx.Err())
+	assert.Error(t, admin.shutdownCtx.Err())
+}
+
+func TestGroup_FailureStopsOthers(t *testing.T) {
+	failing := NewDummyHTTPServer()
+	failing.ListenAndServeErr = errors.New("address in use")
+	healthy := NewDummyHTTPServer()
+
+	g := NewGroup(nil).Add("metrics", failing).Add("api", healthy)
+	err := g.Run(context.Background(), time.Second)
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "metrics: address in use")
+	assert.True(t, healthy.ShutdownCalled)
+}
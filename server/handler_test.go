@@ -1,11 +1,14 @@
 package server
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
+	"github.com/aatuh/pureapi-core/apierror"
 	"github.com/aatuh/pureapi-core/endpoint"
 	"github.com/aatuh/pureapi-core/event"
 	"github.com/aatuh/pureapi-core/querydec"
@@ -195,6 +198,34 @@ func TestQueryMap(t *testing.T) {
 	}
 }
 
+func TestQuery_DecoderError(t *testing.T) {
+	handler := NewHandler(
+		event.NewNoopEventEmitter(),
+		WithQueryDecoder(querydec.NewParamDecoder(nil).Require("org")),
+	)
+	var queryErr error
+	testRouter := router.NewBuiltinRouter()
+	testRouter.Register("GET", "/test", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var query map[string]any
+			query, queryErr = Query(r)
+			if query != nil || QueryMap(r) != nil {
+				t.Errorf("Expected no query map, got %v", query)
+			}
+		},
+	))
+	handler.router = testRouter
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+
+	var apiErr apierror.APIError
+	if !errors.As(queryErr, &apiErr) ||
+		apiErr.ID() != querydec.ErrIDInvalidQuery {
+		t.Fatalf("Expected an invalid_query error, got %v", queryErr)
+	}
+}
+
 func TestRouteParams(t *testing.T) {
 	// Test with no route params in context
 	req := httptest.NewRequest("GET", "/test", nil)
@@ -339,3 +370,176 @@ func containsMethod(allow, method string) bool {
 	}
 	return false
 }
+
+func TestHandler_WithMount(t *testing.T) {
+	mounted := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("mounted " + r.URL.Path))
+	})
+	handler := NewHandler(
+		event.NewNoopEventEmitter(),
+		WithMount("/admin/", mounted),
+		WithMount("/metrics", mounted),
+	)
+
+	tests := []struct {
+		path     string
+		wantCode int
+	}{
+		{"/admin/", http.StatusOK},
+		{"/admin", http.StatusOK},
+		{"/admin/x/y", http.StatusOK},
+		{"/metrics", http.StatusOK},
+		{"/metrics/x", http.StatusNotFound},
+		{"/administrator", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != tt.wantCode {
+			t.Fatalf("%s: expected %d, got %d", tt.path, tt.wantCode, w.Code)
+		}
+	}
+}
+
+// benchmarkQuery serves requests with a query string to a handler that
+// reads the query map or not.
+func benchmarkQuery(b *testing.B, read bool) {
+	handler := NewHandler(event.NewNoopEventEmitter())
+	testRouter := router.NewBuiltinRouter()
+	testRouter.Register("GET", "/test", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if read {
+				_ = QueryMap(r)
+			}
+		},
+	))
+	handler.router = testRouter
+	req := httptest.NewRequest("GET", "/test?page=2&per_page=50&sort=name", nil)
+	w := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		handler.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkHandler_QueryUnused(b *testing.B) { benchmarkQuery(b, false) }
+
+func BenchmarkHandler_QueryUsed(b *testing.B) { benchmarkQuery(b, true) }
+
+// countingDecoder counts its calls.
+type countingDecoder struct {
+	calls int
+}
+
+func (d *countingDecoder) Decode(v url.Values) (map[string]any, error) {
+	d.calls++
+	return querydec.PlainDecoder{}.Decode(v)
+}
+
+func TestQuery_Lazy(t *testing.T) {
+	decoder := &countingDecoder{}
+	handler := NewHandler(
+		event.NewNoopEventEmitter(), WithQueryDecoder(decoder),
+	)
+	testRouter := router.NewBuiltinRouter()
+	testRouter.Register("GET", "/unused", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {},
+	))
+	testRouter.Register("GET", "/used", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			for range 3 {
+				if q := QueryMap(r); q["a"] != "1" {
+					t.Errorf("Expected the decoded query, got %v", q)
+				}
+			}
+		},
+	))
+	handler.router = testRouter
+
+	handler.ServeHTTP(httptest.NewRecorder(),
+		httptest.NewRequest("GET", "/unused?a=1", nil))
+	if decoder.calls != 0 {
+		t.Fatalf("Expected no decoding, got %d calls", decoder.calls)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(),
+		httptest.NewRequest("GET", "/used?a=1", nil))
+	if decoder.calls != 1 {
+		t.Fatalf("Expected one decoding, got %d calls", decoder.calls)
+	}
+}
+
+// countingStructDecoder counts its calls.
+type countingStructDecoder struct {
+	calls int
+}
+
+func (d *countingStructDecoder) DecodeStruct(v url.Values, dst any) error {
+	d.calls++
+	return querydec.TagDecoder{}.DecodeStruct(v, dst)
+}
+
+type listQuery struct {
+	Page int    `query:"page"`
+	Sort string `query:"sort"`
+}
+
+func TestQueryAs(t *testing.T) {
+	decoder := &countingStructDecoder{}
+	handler := NewHandler(
+		event.NewNoopEventEmitter(), WithStructDecoder(decoder),
+	)
+	var got []listQuery
+	var errs []error
+	testRouter := router.NewBuiltinRouter()
+	testRouter.Register("GET", "/test", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			for range 2 {
+				q, err := QueryAs[listQuery](r)
+				got = append(got, q)
+				errs = append(errs, err)
+			}
+		},
+	))
+	handler.router = testRouter
+
+	handler.ServeHTTP(httptest.NewRecorder(),
+		httptest.NewRequest("GET", "/test?page=2&sort=name", nil))
+	if decoder.calls != 1 {
+		t.Fatalf("Expected one decoding, got %d calls", decoder.calls)
+	}
+	want := listQuery{Page: 2, Sort: "name"}
+	if got[0] != want || got[1] != want || errs[0] != nil || errs[1] != nil {
+		t.Fatalf("Expected %+v twice, got %+v, %v", want, got, errs)
+	}
+
+	got, errs = nil, nil
+	handler.ServeHTTP(httptest.NewRecorder(),
+		httptest.NewRequest("GET", "/test?page=x&sort=name", nil))
+	var apiErr apierror.APIError
+	if !errors.As(errs[1], &apiErr) ||
+		apiErr.ID() != querydec.ErrIDInvalidQuery {
+		t.Fatalf("Expected an invalid_query error, got %v", errs[1])
+	}
+	if got[1].Sort != "name" {
+		t.Fatalf("Expected the valid fields, got %+v", got[1])
+	}
+}
+
+func TestQueryAs_OutsideHandler(t *testing.T) {
+	req := httptest.NewRequest("GET", "/test?page=3", nil)
+
+	q, err := QueryAs[listQuery](req)
+	if err != nil || q.Page != 3 {
+		t.Fatalf("Expected page 3, got %+v, %v", q, err)
+	}
+
+	req = httptest.NewRequest("GET", "/test?page=x", nil)
+	var apiErr apierror.APIError
+	if _, err := QueryAs[listQuery](req); !errors.As(err, &apiErr) ||
+		apiErr.ID() != querydec.ErrIDInvalidQuery {
+		t.Fatalf("Expected an invalid_query error, got %v", err)
+	}
+}
@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
+	"net/url"
 	"os"
 	"os/signal"
+	"reflect"
+	"runtime/debug"
 	"slices"
 	"strings"
 	"sync"
@@ -16,6 +20,7 @@ import (
 
 	"github.com/aatuh/pureapi-core/endpoint"
 	"github.com/aatuh/pureapi-core/event"
+	"github.com/aatuh/pureapi-core/internal/shutdown"
 	"github.com/aatuh/pureapi-core/querydec"
 	"github.com/aatuh/pureapi-core/router"
 )
@@ -33,38 +38,43 @@ const (
 	EventShutDownError    event.EventType = "event_shutdown_error"
 )
 
+// defaultShutdownTimeout is the shutdown timeout used when none is given.
+const defaultShutdownTimeout = 60 * time.Second
+
 // HTTPServer represents an HTTP server.
 type HTTPServer interface {
 	ListenAndServe() error              // Start the server.
 	Shutdown(ctx context.Context) error // Shut down the server.
 }
 
-// DefaultHTTPServer returns the default HTTP server implementation. It sets
-// default request read and write timeouts of 10 seconds, idle timeout of 60
-// seconds, and a max header size of 64KB.
+// HTTPServerOption configures the *http.Server built by DefaultHTTPServer.
+type HTTPServerOption func(*http.Server)
+
+// DefaultHTTPServer returns the default HTTP server implementation. It applies
+// DefaultServerConfig: request read and write timeouts of 10 seconds, idle
+// timeout of 60 seconds, and a max header size of 64KB. Options are applied
+// after the defaults, use WithServerConfig to tune them.
 //
 // Parameters:
 //   - handler: HTTP server handler.
 //   - port: Port for the HTTP server.
 //   - endpoints: Endpoints to register.
+//   - opts: Optional server options.
 //
 // Returns:
 //   - *http.Server: A configured http.Server instance.
 func DefaultHTTPServer(
-	handler *Handler, port int, endpoints []endpoint.Endpoint,
+	handler *Handler,
+	port int,
+	endpoints []endpoint.Endpoint,
+	opts ...HTTPServerOption,
 ) *http.Server {
 	// Register endpoints with the handler
 	handler.Register(endpoints)
 
-	return &http.Server{
-		Addr:           fmt.Sprintf(":%d", port),
-		Handler:        handler,
-		ReadTimeout:    10 * time.Second, // Limits slow clients.
-		WriteTimeout:   10 * time.Second, // Ensures fast responses.
-		IdleTimeout:    60 * time.Second, // Keeps alive long enough.
-		MaxHeaderBytes: 1 << 16,          // 64KB to prevent excessive memory use.
-		// Security hardening
-		ReadHeaderTimeout: 5 * time.Second, // Prevent slow header attacks
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: handler,
 		// Connection handling
 		ConnState: func(conn net.Conn, state http.ConnState) {
 			// Close connections on parser errors or oversized headers
@@ -73,6 +83,38 @@ func DefaultHTTPServer(
 			}
 		},
 	}
+	DefaultServerConfig().apply(srv)
+	for _, opt := range opts {
+		opt(srv)
+	}
+	return srv
+}
+
+// WithBaseContext sets the base context of the server. Values stored in it,
+// such as database pools or configuration, are available in every request
+// context. The context is created once per listener.
+//
+// Parameters:
+//   - fn: Returns the base context for a listener.
+//
+// Returns:
+//   - HTTPServerOption: A server option function.
+func WithBaseContext(fn func(net.Listener) context.Context) HTTPServerOption {
+	return func(s *http.Server) { s.BaseContext = fn }
+}
+
+// WithConnContext sets a function deriving the context of each connection
+// from the base context. Request contexts derive from the connection context.
+//
+// Parameters:
+//   - fn: Returns the context for a new connection.
+//
+// Returns:
+//   - HTTPServerOption: A server option function.
+func WithConnContext(
+	fn func(ctx context.Context, c net.Conn) context.Context,
+) HTTPServerOption {
+	return func(s *http.Server) { s.ConnContext = fn }
 }
 
 // StartServer sets up an HTTP server with the specified port and endpoints,
@@ -94,7 +136,7 @@ func StartServer(
 ) error {
 	var useShutdownTimeout time.Duration
 	if shutdownTimeout == nil {
-		useShutdownTimeout = 60 * time.Second
+		useShutdownTimeout = defaultShutdownTimeout
 	} else {
 		useShutdownTimeout = *shutdownTimeout
 	}
@@ -105,15 +147,29 @@ func StartServer(
 
 // Handler represents an HTTP server handler.
 type Handler struct {
-	emitter      event.EventEmitter
-	router       router.Router
-	queryDecoder querydec.Decoder
-	notFound     http.Handler
-	recoverer    func(http.Handler) http.Handler
-	bodyLimit    int64 // Maximum request body size in bytes
-	// Store registered routes for method not allowed checking
-	registeredRoutes map[string]map[string]bool // path -> method -> exists
-	routesMu         sync.RWMutex
+	emitter       event.EventEmitter
+	queryDecoder  querydec.Decoder
+	structDecoder querydec.StructDecoder
+	notFound      http.Handler
+	recoverer     func(http.Handler) http.Handler
+	errorRenderer ErrorRenderer
+	panicDebug    bool  // Render panic details in responses
+	bodyLimit     int64 // Maximum request body size in bytes
+	sizeEvents    bool  // Emit per-request byte counts
+	compression   *compressionConfig
+	microCache    *microCache
+	mounts        []mount
+	// Client IP resolution and filtering
+	trustedProxies []CIDR
+	ipAllow        []CIDR
+	ipDeny         []CIDR
+	// Default route table and per-host route tables
+	routeTable
+	hosts   []*hostTable
+	hostsMu sync.RWMutex
+	// Cancelled when graceful shutdown begins
+	shutdownCtx    context.Context
+	shutdownCancel context.CancelFunc
 }
 
 // HandlerOption configures a Handler.
@@ -141,6 +197,18 @@ func WithQueryDecoder(d querydec.Decoder) HandlerOption {
 	return func(h *Handler) { h.queryDecoder = d }
 }
 
+// WithStructDecoder sets the decoder of QueryAs, querydec.TagDecoder by
+// default.
+//
+// Parameters:
+//   - d: The struct decoder to use.
+//
+// Returns:
+//   - HandlerOption: A handler option function.
+func WithStructDecoder(d querydec.StructDecoder) HandlerOption {
+	return func(h *Handler) { h.structDecoder = d }
+}
+
 // WithEventEmitter overrides the handler event emitter.
 func WithEventEmitter(em event.EventEmitter) HandlerOption {
 	return func(h *Handler) {
@@ -150,7 +218,8 @@ func WithEventEmitter(em event.EventEmitter) HandlerOption {
 	}
 }
 
-// WithNotFound sets the not found handler.
+// WithNotFound sets the not found handler. It replaces the error renderer
+// for unmatched routes.
 //
 // Parameters:
 //   - nf: The not found handler to use.
@@ -172,6 +241,19 @@ func WithRecoverer(wrap func(http.Handler) http.Handler) HandlerOption {
 	return func(h *Handler) { h.recoverer = wrap }
 }
 
+// WithPanicDebug renders the panic value and goroutine stack in 500
+// responses produced by the default recoverer. Only enable it in development:
+// stacks disclose internals. The stack is always included in EventPanic data.
+//
+// Parameters:
+//   - enabled: Whether to render panic details.
+//
+// Returns:
+//   - HandlerOption: A handler option function.
+func WithPanicDebug(enabled bool) HandlerOption {
+	return func(h *Handler) { h.panicDebug = enabled }
+}
+
 // WithBodyLimit sets the maximum request body size in bytes.
 //
 // Parameters:
@@ -183,6 +265,26 @@ func WithBodyLimit(limit int64) HandlerOption {
 	return func(h *Handler) { h.bodyLimit = limit }
 }
 
+// WithMount serves every request whose path starts with prefix with the given
+// handler, before routing takes place. Mounted handlers are protected by the
+// panic recoverer but bypass the router, its 404/405 handling and endpoint
+// middlewares. A prefix ending in "/" matches a subtree, otherwise the path
+// must match exactly.
+//
+// Parameters:
+//   - prefix: The path prefix, e.g. "/debug/".
+//   - handler: The handler to mount.
+//
+// Returns:
+//   - HandlerOption: A handler option function.
+func WithMount(prefix string, handler http.Handler) HandlerOption {
+	return func(h *Handler) {
+		if prefix != "" && handler != nil {
+			h.mounts = append(h.mounts, mount{prefix: prefix, handler: handler})
+		}
+	}
+}
+
 // NewHandler creates a new HTTPServer.
 // If an event emitter is provided, it will be used to emit events. Otherwise,
 // logging will be used. If no logger is provided, log.Default() will be used.
@@ -199,15 +301,23 @@ func NewHandler(
 	opts ...HandlerOption,
 ) *Handler {
 	h := &Handler{
-		emitter:          emitter,
-		notFound:         http.NotFoundHandler(),
-		queryDecoder:     querydec.PlainDecoder{},
-		bodyLimit:        2 * 1024 * 1024, // 2MB default
-		registeredRoutes: make(map[string]map[string]bool),
-	}
+		emitter:       emitter,
+		errorRenderer: TextErrorRenderer{},
+		queryDecoder:  querydec.PlainDecoder{},
+		structDecoder: querydec.TagDecoder{},
+		bodyLimit:     2 * 1024 * 1024, // 2MB default
+		routeTable:    newRouteTable(nil),
+	}
+	h.shutdownCtx, h.shutdownCancel = context.WithCancel(context.Background())
 	for _, opt := range opts {
 		opt(h)
 	}
+	if h.notFound == nil {
+		h.notFound = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.renderError(w, r, http.StatusNotFound, ErrIDNotFound,
+				http.StatusText(http.StatusNotFound))
+		})
+	}
 	if h.router == nil {
 		// Provide a tiny built-in router for zero deps.
 		h.router = router.NewBuiltinRouter()
@@ -228,6 +338,9 @@ func (s *Handler) startServer(
 	signal.Notify(stopChan, os.Interrupt, syscall.SIGTERM)
 	defer signal.Stop(stopChan)
 	errChan := make(chan error, 1)
+	if ts, ok := server.(shutdownTimeoutSetter); ok {
+		ts.setShutdownTimeout(shutdownTimeout)
+	}
 
 	go func() {
 		s.listenAndServe(server, errChan, stopChan)
@@ -240,6 +353,7 @@ func (s *Handler) startServer(
 	s.emitter.Emit(
 		event.NewEvent(EventShutDownStarted, "Shutting down HTTP server"),
 	)
+	s.BeginShutdown()
 	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
@@ -262,9 +376,11 @@ func (s *Handler) startServer(
 func (s *Handler) listenAndServe(
 	server HTTPServer, errChan chan error, stopChan chan os.Signal,
 ) {
-	s.emitter.Emit(
-		event.NewEvent(EventStart, "Starting HTTP server"),
-	)
+	ev := event.NewEvent(EventStart, "Starting HTTP server")
+	if addr := serverAddr(server); addr != "" {
+		ev = ev.WithData(map[string]any{"addr": addr})
+	}
+	s.emitter.Emit(ev)
 	err := server.ListenAndServe()
 	if !errors.Is(err, http.ErrServerClosed) {
 		s.emitter.Emit(
@@ -280,6 +396,21 @@ func (s *Handler) listenAndServe(
 	}
 }
 
+// serverAddr returns the address a server is bound or configured to.
+func serverAddr(server HTTPServer) string {
+	switch srv := server.(type) {
+	case interface{ Addr() net.Addr }:
+		return srv.Addr().String()
+	case *http.Server:
+		return srv.Addr
+	case *TLSServer:
+		return srv.Server.Addr
+	case *combinedServer:
+		return serverAddr(srv.main)
+	}
+	return ""
+}
+
 // Register registers endpoints with the handler.
 //
 // Parameters:
@@ -288,6 +419,13 @@ func (s *Handler) listenAndServe(
 // Returns:
 //   - error: An error if the endpoint registration fails.
 func (h *Handler) Register(endpoints []endpoint.Endpoint) {
+	h.register(&h.routeTable, "", endpoints)
+}
+
+// register registers endpoints in a route table.
+func (h *Handler) register(
+	t *routeTable, host string, endpoints []endpoint.Endpoint,
+) {
 	for _, ep := range endpoints {
 		// Compose middleware stack.
 		middlewares := ep.Middlewares()
@@ -303,24 +441,30 @@ func (h *Handler) Register(endpoints []endpoint.Endpoint) {
 		if middlewares != nil {
 			handler = middlewares.Chain(handler)
 		}
+		if bl, ok := ep.(endpoint.BodyLimiter); ok && bl.BodyLimit() != 0 {
+			handler = &routeLimitHandler{Handler: handler, limit: bl.BodyLimit()}
+		}
 
 		// Register to router with method+pattern.
-		h.router.Register(ep.Method(), ep.URL(), handler)
+		t.router.Register(ep.Method(), ep.URL(), handler)
 
 		// Track registered routes for method not allowed checking
-		h.routesMu.Lock()
-		if h.registeredRoutes[ep.URL()] == nil {
-			h.registeredRoutes[ep.URL()] = make(map[string]bool)
+		t.routesMu.Lock()
+		if t.registeredRoutes[ep.URL()] == nil {
+			t.registeredRoutes[ep.URL()] = make(map[string]bool)
 		}
-		h.registeredRoutes[ep.URL()][ep.Method()] = true
-		h.routesMu.Unlock()
-
-		h.emitter.Emit(
-			event.NewEvent(
-				EventRegisterURL,
-				fmt.Sprintf("Registering URL: %s %s", ep.URL(), ep.Method()),
-			).WithData(map[string]any{"path": ep.URL(), "method": ep.Method()}),
-		)
+		t.registeredRoutes[ep.URL()][ep.Method()] = true
+		t.routesMu.Unlock()
+
+		data := map[string]any{"path": ep.URL(), "method": ep.Method()}
+		msg := fmt.Sprintf("Registering URL: %s %s", ep.URL(), ep.Method())
+		if host != "" {
+			data["host"] = host
+			msg = fmt.Sprintf(
+				"Registering URL: %s %s (host %s)", ep.URL(), ep.Method(), host,
+			)
+		}
+		h.emitter.Emit(event.NewEvent(EventRegisterURL, msg).WithData(data))
 	}
 }
 
@@ -333,17 +477,7 @@ func (h *Handler) Register(endpoints []endpoint.Endpoint) {
 // Returns:
 //   - error: An error if the endpoint unregistration fails.
 func (h *Handler) Unregister(method, path string) {
-	if h.router != nil {
-		_ = h.router.Unregister(method, path)
-	}
-	h.routesMu.Lock()
-	if mm, ok := h.registeredRoutes[path]; ok {
-		delete(mm, method)
-		if len(mm) == 0 {
-			delete(h.registeredRoutes, path)
-		}
-	}
-	h.routesMu.Unlock()
+	h.routeTable.unregister(method, path)
 }
 
 // ServeHTTP implements http.Handler.
@@ -355,52 +489,86 @@ func (h *Handler) Unregister(method, path string) {
 // Returns:
 //   - error: An error if the request serving fails.
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Let long-lived handlers observe graceful shutdown.
+	r = r.WithContext(shutdown.WithContext(r.Context(), h.shutdownCtx))
 	// Wrap with tracking response writer to prevent double WriteHeader
 	tw := newTrackingResponseWriter(w)
-	// Body limits as you have them...
-	if h.bodyLimit > 0 && r.ContentLength > h.bodyLimit {
-		http.Error(tw, "Request body too large", http.StatusRequestEntityTooLarge)
+	var rw http.ResponseWriter = tw
+	if h.sizeEvents {
+		// Registered before compression so it runs after the encoder flushed.
+		defer h.trackSize(tw, r)()
+	}
+	if h.compression != nil && r.Method != http.MethodHead {
+		if cw := h.compression.wrap(tw, r); cw != nil {
+			defer cw.Close()
+			rw = cw
+		}
+	}
+	// Resolve the client IP and apply the IP filter before anything else.
+	if len(h.trustedProxies) > 0 || len(h.ipAllow) > 0 || len(h.ipDeny) > 0 {
+		var ip netip.Addr
+		ip, r = h.resolveClientIP(r)
+		if !h.ipAllowed(ip) {
+			event.EmitCtx(h.emitter, r.Context(),
+				event.NewEvent(
+					EventIPBlocked,
+					fmt.Sprintf("Blocked request from IP: %s", ip),
+				).WithData(map[string]any{
+					"ip": ip.String(), "method": r.Method, "path": r.URL.Path,
+				}),
+			)
+			h.renderError(rw, r, http.StatusForbidden, ErrIDForbidden,
+				http.StatusText(http.StatusForbidden))
+			return
+		}
+	}
+
+	// Mounted handlers bypass routing.
+	if mh := h.mounted(r.URL.Path); mh != nil {
+		if h.limitBody(rw, r, h.bodyLimit) {
+			h.recoverer(mh).ServeHTTP(rw, r)
+		}
 		return
 	}
-	if h.bodyLimit > 0 {
-		r.Body = http.MaxBytesReader(tw, r.Body, h.bodyLimit)
+
+	t := h.table(r.Host)
+	m := t.router.Match(r)
+
+	// Body limits, routes may override the server-wide limit.
+	if !h.limitBody(rw, r, h.routeBodyLimit(m)) {
+		return
 	}
 
 	// Auto OPTIONS: check for explicit handler first, then synthesize
 	if r.Method == http.MethodOptions {
 		// Check if there's an explicit OPTIONS handler
-		m := h.router.Match(r)
 		if m != nil {
 			// Use explicit OPTIONS handler
-			qm, _ := h.queryDecoder.Decode(r.URL.Query())
-			ctx := context.WithValue(r.Context(), ctxKeyQueryMapVal, qm)
+			ctx := h.withQuery(r.Context(), r.URL.RawQuery)
 			if len(m.Params) > 0 {
-				ctx = context.WithValue(ctx, ctxKeyRouteParamsVal, m.Params)
+				ctx = router.WithParams(ctx, m.Params)
 			}
 			r = r.WithContext(ctx)
-			h.recoverer(m.Handler).ServeHTTP(tw, r)
+			h.recoverer(m.Handler).ServeHTTP(rw, r)
 			return
 		}
 		// No explicit OPTIONS handler, synthesize response
-		if allow := h.allowedMethods(r.URL.Path); len(allow) > 0 {
-			tw.Header().Set("Allow", strings.Join(allow, ", "))
-			tw.WriteHeader(http.StatusNoContent)
+		if allow := t.allowedMethods(r.URL.Path); len(allow) > 0 {
+			rw.Header().Set("Allow", strings.Join(allow, ", "))
+			rw.WriteHeader(http.StatusNoContent)
 			return
 		}
 	}
 
-	m := h.router.Match(r)
-
 	// HEAD fallback: if GET exists but no direct HEAD handler.
 	if m == nil && r.Method == http.MethodHead {
 		r2 := r.Clone(r.Context())
 		r2.Method = http.MethodGet
-		if m2 := h.router.Match(r2); m2 != nil {
+		if m2 := t.router.Match(r2); m2 != nil {
 			// Decode query + params same as below
-			qm, _ := h.queryDecoder.Decode(r2.URL.Query())
-			ctx := context.WithValue(r2.Context(), ctxKeyQueryMapVal, qm)
+			ctx := h.withQuery(r2.Context(), r2.URL.RawQuery)
 			if len(m2.Params) > 0 {
-				ctx = context.WithValue(ctx, ctxKeyRouteParamsVal, m2.Params)
+				ctx = router.WithParams(ctx, m2.Params)
 			}
 			r2 = r2.WithContext(ctx)
 
@@ -408,56 +576,110 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				// Discard body writes.
 				dw := &discardingWriter{ResponseWriter: w}
 				m2.Handler.ServeHTTP(dw, r2) // use r2 (GET)
-			})).ServeHTTP(tw, r2)
+			})).ServeHTTP(rw, r2)
 			return
 		}
 	}
 
 	if m == nil {
-		if h.isMethodNotAllowed(r) {
-			if allow := h.allowedMethods(r.URL.Path); len(allow) > 0 {
-				tw.Header().Set("Allow", strings.Join(allow, ", "))
+		if t.isMethodNotAllowed(r) {
+			if allow := t.allowedMethods(r.URL.Path); len(allow) > 0 {
+				rw.Header().Set("Allow", strings.Join(allow, ", "))
 			}
-			http.Error(tw, http.StatusText(http.StatusMethodNotAllowed),
-				http.StatusMethodNotAllowed)
+			h.renderError(rw, r, http.StatusMethodNotAllowed,
+				ErrIDMethodNotAllowed,
+				http.StatusText(http.StatusMethodNotAllowed))
 			return
 		}
-		h.notFound.ServeHTTP(tw, r)
+		h.notFound.ServeHTTP(rw, r)
 		return
 	}
 
 	// Decode query + params into context.
-	qm, _ := h.queryDecoder.Decode(r.URL.Query())
-	ctx := context.WithValue(r.Context(), ctxKeyQueryMapVal, qm)
+	ctx := h.withQuery(r.Context(), r.URL.RawQuery)
 	if len(m.Params) > 0 {
-		ctx = context.WithValue(ctx, ctxKeyRouteParamsVal, m.Params)
+		ctx = router.WithParams(ctx, m.Params)
 	}
 	r = r.WithContext(ctx)
 
-	h.recoverer(m.Handler).ServeHTTP(tw, r)
+	if h.microCache != nil {
+		h.microCache.serve(rw, r, h.recoverer(m.Handler))
+		return
+	}
+	h.recoverer(m.Handler).ServeHTTP(rw, r)
+}
+
+// mount is a handler serving a path prefix.
+type mount struct {
+	prefix  string
+	handler http.Handler
+}
+
+// mounted returns the mounted handler for the path, if any.
+func (h *Handler) mounted(path string) http.Handler {
+	for _, m := range h.mounts {
+		if path == m.prefix || path+"/" == m.prefix ||
+			(strings.HasSuffix(m.prefix, "/") && strings.HasPrefix(path, m.prefix)) {
+			return m.handler
+		}
+	}
+	return nil
 }
 
-func (h *Handler) allowedMethods(path string) []string {
+// routeLimitHandler carries a route specific body limit.
+type routeLimitHandler struct {
+	http.Handler
+	limit int64
+}
+
+// routeBodyLimit returns the body limit for the matched route.
+func (h *Handler) routeBodyLimit(m *router.Matched) int64 {
+	if m != nil {
+		if rl, ok := m.Handler.(*routeLimitHandler); ok {
+			return rl.limit
+		}
+	}
+	return h.bodyLimit
+}
+
+// limitBody enforces the body limit. It writes 413 and returns false if the
+// declared content length already exceeds the limit.
+func (h *Handler) limitBody(
+	w http.ResponseWriter, r *http.Request, limit int64,
+) bool {
+	if limit <= 0 {
+		return true
+	}
+	if r.ContentLength > limit {
+		h.renderError(w, r, http.StatusRequestEntityTooLarge,
+			ErrIDRequestTooLarge, "Request body too large")
+		return false
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	return true
+}
+
+func (t *routeTable) allowedMethods(path string) []string {
 	// Prefer router introspection if available.
 	type methodsFor interface{ MethodsFor(string) []string }
-	if mf, ok := h.router.(methodsFor); ok {
+	if mf, ok := t.router.(methodsFor); ok {
 		return mf.MethodsFor(path)
 	}
 
 	// Fallback to registeredRoutes map + stableAllow
-	h.routesMu.RLock()
-	defer h.routesMu.RUnlock()
+	t.routesMu.RLock()
+	defer t.routesMu.RUnlock()
 
 	set := map[string]struct{}{}
 	// Exact path methods
-	if mm, ok := h.registeredRoutes[path]; ok {
+	if mm, ok := t.registeredRoutes[path]; ok {
 		for m := range mm {
 			set[m] = struct{}{}
 		}
 	}
 	// Colon/braces pattern methods
-	for pat, mm := range h.registeredRoutes {
-		if pat == path || h.matchesPattern(pat, path) {
+	for pat, mm := range t.registeredRoutes {
+		if pat == path || t.matchesPattern(pat, path) {
 			for m := range mm {
 				set[m] = struct{}{}
 			}
@@ -468,15 +690,15 @@ func (h *Handler) allowedMethods(path string) []string {
 
 // isMethodNotAllowed checks if the request path exists but with a different
 // method.
-func (h *Handler) isMethodNotAllowed(r *http.Request) bool {
+func (t *routeTable) isMethodNotAllowed(r *http.Request) bool {
 	path := r.URL.Path
 	method := r.Method
 
-	h.routesMu.RLock()
-	defer h.routesMu.RUnlock()
+	t.routesMu.RLock()
+	defer t.routesMu.RUnlock()
 
 	// Check if this path exists with any method
-	if methods, exists := h.registeredRoutes[path]; exists {
+	if methods, exists := t.registeredRoutes[path]; exists {
 		// Check if the current method is not in the allowed methods
 		if !methods[method] {
 			return true
@@ -484,9 +706,9 @@ func (h *Handler) isMethodNotAllowed(r *http.Request) bool {
 	}
 
 	// Also check for colon parameter patterns
-	for registeredPath := range h.registeredRoutes {
-		if h.matchesPattern(registeredPath, path) {
-			methods := h.registeredRoutes[registeredPath]
+	for registeredPath := range t.registeredRoutes {
+		if t.matchesPattern(registeredPath, path) {
+			methods := t.registeredRoutes[registeredPath]
 			if !methods[method] {
 				return true
 			}
@@ -509,12 +731,20 @@ type discardingWriter struct{ http.ResponseWriter }
 func (d *discardingWriter) Write(p []byte) (int, error) { return len(p), nil }
 
 // matchesPattern checks if a pattern matches a path (for colon and brace parameters).
-func (h *Handler) matchesPattern(pattern, path string) bool {
+func (t *routeTable) matchesPattern(pattern, path string) bool {
 	// Simple colon and brace parameter matching
 	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
 	pathParts := strings.Split(strings.Trim(path, "/"), "/")
 
-	if len(patternParts) != len(pathParts) {
+	// A trailing "*name" segment matches the remainder of the path.
+	last := patternParts[len(patternParts)-1]
+	if len(last) > 1 && last[0] == '*' {
+		if len(pathParts) < len(patternParts)-1 {
+			return false
+		}
+		patternParts = patternParts[:len(patternParts)-1]
+		pathParts = pathParts[:len(patternParts)]
+	} else if len(patternParts) != len(pathParts) {
 		return false
 	}
 
@@ -540,33 +770,156 @@ func (h *Handler) matchesPattern(pattern, path string) bool {
 
 // Access helpers for handlers.
 type ctxKeyQueryMap struct{}
-type ctxKeyRouteParams struct{}
 
-var (
-	ctxKeyQueryMapVal    = ctxKeyQueryMap{}
-	ctxKeyRouteParamsVal = ctxKeyRouteParams{}
-)
+var ctxKeyQueryMapVal = ctxKeyQueryMap{}
+
+// lazyQuery decodes the query of a request on first use, so requests
+// whose handlers never read it do not pay for decoding.
+type lazyQuery struct {
+	decoder       querydec.Decoder
+	structDecoder querydec.StructDecoder
+	rawQuery      string
+
+	parseOnce sync.Once
+	values    url.Values
+
+	once sync.Once
+	m    map[string]any
+	err  error
+
+	mu    sync.Mutex
+	typed map[reflect.Type]typedQuery
+}
+
+// typedQuery is a query decoded by the struct decoder.
+type typedQuery struct {
+	v   any
+	err error
+}
+
+// parsed returns the query values, parsing them on the first call.
+func (q *lazyQuery) parsed() url.Values {
+	q.parseOnce.Do(func() {
+		q.values, _ = url.ParseQuery(q.rawQuery)
+	})
+	return q.values
+}
+
+// get returns the decoded query, decoding it on the first call.
+func (q *lazyQuery) get() (map[string]any, error) {
+	q.once.Do(func() {
+		q.m, q.err = q.decoder.Decode(q.parsed())
+	})
+	return q.m, q.err
+}
+
+// getAs returns the query decoded into a value of type t, decoding it
+// with decode on the first call for the type.
+func (q *lazyQuery) getAs(
+	t reflect.Type, decode func(values url.Values) (any, error),
+) (any, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if tq, ok := q.typed[t]; ok {
+		return tq.v, tq.err
+	}
+	v, err := decode(q.parsed())
+	if q.typed == nil {
+		q.typed = map[reflect.Type]typedQuery{}
+	}
+	q.typed[t] = typedQuery{v: v, err: err}
+	return v, err
+}
 
-// QueryMap extracts the query map from the request context.
+// withQuery stores the raw query in the context for lazy decoding.
+func (h *Handler) withQuery(
+	ctx context.Context, rawQuery string,
+) context.Context {
+	return context.WithValue(ctx, ctxKeyQueryMapVal, &lazyQuery{
+		decoder:       h.queryDecoder,
+		structDecoder: h.structDecoder,
+		rawQuery:      rawQuery,
+	})
+}
+
+// QueryMap extracts the query map from the request context, decoding it
+// on the first call for the request. It is nil if the query decoder
+// failed; use Query to get the error.
 func QueryMap(r *http.Request) map[string]any {
-	if v := r.Context().Value(ctxKeyQueryMapVal); v != nil {
-		return v.(map[string]any)
+	qm, _ := Query(r)
+	return qm
+}
+
+// Query returns the query map from the request context and the error of
+// the query decoder, such as the 400 invalid_query API error of a
+// querydec.ParamDecoder, which endpoint handlers return as is. The query is
+// decoded on the first call for the request:
+//
+//	query, err := server.Query(r)
+//	if err != nil {
+//		return nil, err
+//	}
+//
+// Both are nil outside a Handler.
+//
+// Parameters:
+//   - r: The HTTP request.
+//
+// Returns:
+//   - map[string]any: The decoded query parameters.
+//   - error: The error of the query decoder.
+func Query(r *http.Request) (map[string]any, error) {
+	if q, ok := r.Context().Value(ctxKeyQueryMapVal).(*lazyQuery); ok {
+		return q.get()
 	}
-	return nil
+	return nil, nil
+}
+
+// QueryAs decodes the query of a request into a struct with the struct
+// decoder of the Handler, see WithStructDecoder, for one-line typed
+// access to query parameters:
+//
+//	q, err := server.QueryAs[ListUsers](r)
+//	if err != nil {
+//		return nil, err
+//	}
+//
+// The result is cached in the request context, so middlewares and the
+// handler share one decoding per type. Field errors are returned as the
+// 400 invalid_query API error of querydec.AsInvalidQuery. Outside a
+// Handler the query is decoded with querydec.TagDecoder, uncached.
+//
+// Parameters:
+//   - r: The HTTP request.
+//
+// Returns:
+//   - T: The decoded struct.
+//   - error: The invalid_query API error, or an error of the decoder.
+func QueryAs[T any](r *http.Request) (T, error) {
+	decode := func(d querydec.StructDecoder, values url.Values) (any, error) {
+		var out T
+		err := d.DecodeStruct(values, &out)
+		return out, querydec.AsInvalidQuery(err)
+	}
+	var (
+		v   any
+		err error
+	)
+	if q, ok := r.Context().Value(ctxKeyQueryMapVal).(*lazyQuery); ok {
+		v, err = q.getAs(reflect.TypeFor[T](),
+			func(values url.Values) (any, error) {
+				return decode(q.structDecoder, values)
+			})
+	} else {
+		v, err = decode(querydec.TagDecoder{}, r.URL.Query())
+	}
+	out, _ := v.(T)
+	return out, err
 }
 
 // RouteParams extracts the route parameters from the request context.
 func RouteParams(r *http.Request) map[string]string {
-	if v := r.Context().Value(ctxKeyRouteParamsVal); v != nil {
-		// Handle both router.Params and map[string]string
-		if params, ok := v.(router.Params); ok {
-			return map[string]string(params)
-		}
-		if params, ok := v.(map[string]string); ok {
-			return params
-		}
-	}
-	return nil
+	return router.ParamsFromContext(r.Context())
 }
 
 // serverPanicHandler returns an HTTP handler that recovers from panics.
@@ -580,7 +933,7 @@ func (s *Handler) serverPanicHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				panicRecovery(w, err, s.emitter)
+				s.panicRecovery(w, r, err)
 			}
 		}()
 		next.ServeHTTP(w, r)
@@ -596,7 +949,7 @@ func (h *Handler) createRecoverer() func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
-					panicRecovery(w, err, h.emitter)
+					h.panicRecovery(w, r, err)
 				}
 			}()
 			next.ServeHTTP(w, r)
@@ -604,24 +957,29 @@ func (h *Handler) createRecoverer() func(http.Handler) http.Handler {
 	}
 }
 
-// panicRecovery handles recovery from panics.
+// panicRecovery handles recovery from panics. It must be called from the
+// deferred function that recovered so the captured stack includes the
+// panicking frames.
 //
 // Parameters:
 //   - w: The HTTP response writer.
+//   - r: The request.
 //   - err: The panic error.
-//   - emitter: The event emitter for logging.
-func panicRecovery(w http.ResponseWriter, err any, emitter event.EventEmitter) {
-	emitter.Emit(
+func (h *Handler) panicRecovery(
+	w http.ResponseWriter, r *http.Request, err any,
+) {
+	stack := string(debug.Stack())
+	event.EmitCtx(h.emitter, r.Context(),
 		event.NewEvent(
 			EventPanic,
 			fmt.Sprintf("Panic recovered: %v", err),
-		).WithData(map[string]any{"panic": err}),
-	)
-	http.Error(
-		w,
-		http.StatusText(http.StatusInternalServerError),
-		http.StatusInternalServerError,
+		).WithData(map[string]any{"panic": err, "stack": stack}),
 	)
+	message := http.StatusText(http.StatusInternalServerError)
+	if h.panicDebug {
+		message = fmt.Sprintf("panic: %v\n\n%s", err, stack)
+	}
+	h.renderError(w, r, http.StatusInternalServerError, ErrIDInternal, message)
 }
 
 // stableAllow returns a deterministic, RFC-friendly Allow list.
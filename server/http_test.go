@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -69,6 +70,35 @@ func TestDefaultHTTPServer(t *testing.T) {
 	assert.NotNil(t, server.Handler)
 }
 
+func TestDefaultHTTPServer_Contexts(t *testing.T) {
+	type ctxKey string
+	handler := NewHandler(event.NewNoopEventEmitter())
+	var base, conn any
+	ep := endpoint.NewEndpoint("/ctx", http.MethodGet).WithHandler(
+		func(w http.ResponseWriter, r *http.Request) {
+			base = r.Context().Value(ctxKey("base"))
+			conn = r.Context().Value(ctxKey("conn"))
+		})
+	srv := DefaultHTTPServer(handler, 0, []endpoint.Endpoint{ep},
+		WithBaseContext(func(net.Listener) context.Context {
+			return context.WithValue(context.Background(), ctxKey("base"), "pool")
+		}),
+		WithConnContext(func(ctx context.Context, c net.Conn) context.Context {
+			return context.WithValue(ctx, ctxKey("conn"), c.RemoteAddr().String())
+		}),
+	)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Close()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/ctx")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "pool", base)
+	assert.NotEmpty(t, conn)
+}
+
 func TestStartServer_Normal(t *testing.T) {
 	// Simulate normal shutdown:
 	// Dummy server blocks in ListenAndServe until shutdown is triggered.
@@ -155,3 +185,31 @@ func TestServerPanicHandler(t *testing.T) {
 	// The panic should be recovered and return an internal server error.
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 }
+
+func TestPanicRecovery_StackAndDebug(t *testing.T) {
+	panicky := endpoint.NewEndpoint("/panic", "GET").WithHandler(
+		func(w http.ResponseWriter, r *http.Request) { panic("kaboom") },
+	)
+
+	emitter := &recordingEmitter{}
+	handler := NewHandler(emitter)
+	handler.Register([]endpoint.Endpoint{panicky})
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "kaboom")
+
+	panics := emitter.ofType(EventPanic)
+	require.Len(t, panics, 1)
+	data := panics[0].Data.(map[string]any)
+	assert.Equal(t, "kaboom", data["panic"])
+	assert.Contains(t, data["stack"], "TestPanicRecovery_StackAndDebug")
+
+	debugHandler := NewHandler(event.NewNoopEventEmitter(), WithPanicDebug(true))
+	debugHandler.Register([]endpoint.Endpoint{panicky})
+	rr = httptest.NewRecorder()
+	debugHandler.ServeHTTP(rr, httptest.NewRequest("GET", "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "panic: kaboom")
+	assert.Contains(t, rr.Body.String(), "goroutine")
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/aatuh/pureapi-core/event"
+)
+
+// EventIPBlocked is emitted when a request is rejected by the IP filter.
+const EventIPBlocked event.EventType = "event_ip_blocked"
+
+// CIDR is an IP network in CIDR notation. Single addresses are represented as
+// full-length prefixes.
+type CIDR = netip.Prefix
+
+// ParseCIDRs parses networks in CIDR notation ("10.0.0.0/8") or single
+// addresses ("192.0.2.1", "::1").
+//
+// Parameters:
+//   - values: The networks or addresses to parse.
+//
+// Returns:
+//   - []CIDR: The parsed networks.
+//   - error: An error if a value cannot be parsed.
+func ParseCIDRs(values ...string) ([]CIDR, error) {
+	out := make([]CIDR, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if strings.Contains(v, "/") {
+			p, err := netip.ParsePrefix(v)
+			if err != nil {
+				return nil, fmt.Errorf("ParseCIDRs: %w", err)
+			}
+			out = append(out, p.Masked())
+			continue
+		}
+		a, err := netip.ParseAddr(v)
+		if err != nil {
+			return nil, fmt.Errorf("ParseCIDRs: %w", err)
+		}
+		a = a.Unmap()
+		out = append(out, netip.PrefixFrom(a, a.BitLen()))
+	}
+	return out, nil
+}
+
+// MustParseCIDRs is like ParseCIDRs but panics on invalid input. It is
+// intended for static configuration.
+//
+// Parameters:
+//   - values: The networks or addresses to parse.
+//
+// Returns:
+//   - []CIDR: The parsed networks.
+func MustParseCIDRs(values ...string) []CIDR {
+	out, err := ParseCIDRs(values...)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// WithTrustedProxies sets the reverse proxies whose X-Forwarded-For header is
+// trusted when resolving the client IP. The client is the right-most address
+// in X-Forwarded-For that is not itself a trusted proxy. Without trusted
+// proxies the client IP is the connection's remote address.
+//
+// Parameters:
+//   - proxies: The trusted proxy networks.
+//
+// Returns:
+//   - HandlerOption: A handler option function.
+func WithTrustedProxies(proxies []CIDR) HandlerOption {
+	return func(h *Handler) { h.trustedProxies = proxies }
+}
+
+// WithIPFilter rejects requests with 403 based on the resolved client IP
+// before routing. Deny rules win over allow rules; when the allow list is
+// non-empty only clients inside it are accepted.
+//
+// Parameters:
+//   - allow: Networks allowed to access the handler, empty allows all.
+//   - deny: Networks denied access.
+//
+// Returns:
+//   - HandlerOption: A handler option function.
+func WithIPFilter(allow, deny []CIDR) HandlerOption {
+	return func(h *Handler) {
+		h.ipAllow = allow
+		h.ipDeny = deny
+	}
+}
+
+// ClientIP returns the client IP resolved by the Handler, honoring trusted
+// proxies. Outside a Handler it falls back to the request's remote address.
+//
+// Parameters:
+//   - r: The request.
+//
+// Returns:
+//   - string: The client IP, or an empty string if it cannot be determined.
+func ClientIP(r *http.Request) string {
+	if v, ok := r.Context().Value(ctxKeyClientIPVal).(netip.Addr); ok {
+		return v.String()
+	}
+	if a, ok := remoteAddr(r); ok {
+		return a.String()
+	}
+	return ""
+}
+
+// resolveClientIP determines the client IP and stores it in the request
+// context when proxies are trusted.
+func (h *Handler) resolveClientIP(r *http.Request) (netip.Addr, *http.Request) {
+	addr, ok := remoteAddr(r)
+	if !ok || len(h.trustedProxies) == 0 || !containsAddr(h.trustedProxies, addr) {
+		return addr, r
+	}
+	hops := r.Header.Values("X-Forwarded-For")
+	var forwarded []string
+	for _, hop := range hops {
+		forwarded = append(forwarded, strings.Split(hop, ",")...)
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		a, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			break
+		}
+		addr = a.Unmap()
+		if !containsAddr(h.trustedProxies, addr) {
+			break
+		}
+	}
+	ctx := context.WithValue(r.Context(), ctxKeyClientIPVal, addr)
+	return addr, r.WithContext(ctx)
+}
+
+// ipAllowed applies the allow and deny lists to the client IP.
+func (h *Handler) ipAllowed(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return len(h.ipAllow) == 0
+	}
+	if containsAddr(h.ipDeny, addr) {
+		return false
+	}
+	return len(h.ipAllow) == 0 || containsAddr(h.ipAllow, addr)
+}
+
+// remoteAddr parses the request's remote address.
+func remoteAddr(r *http.Request) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	a, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return a.Unmap(), true
+}
+
+// containsAddr reports whether any of the networks contains addr.
+func containsAddr(networks []CIDR, addr netip.Addr) bool {
+	for _, n := range networks {
+		if n.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+type ctxKeyClientIP struct{}
+
+var ctxKeyClientIPVal = ctxKeyClientIP{}
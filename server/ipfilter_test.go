@@ -0,0 +1,92 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingEmitter records emitted events.
+type recordingEmitter struct {
+	event.NoopEventEmitter
+	events []*event.Event
+}
+
+func (r *recordingEmitter) Emit(ev *event.Event) { r.events = append(r.events, ev) }
+
+func (r *recordingEmitter) ofType(t event.EventType) []*event.Event {
+	var out []*event.Event
+	for _, ev := range r.events {
+		if ev.Type == t {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+func TestParseCIDRs(t *testing.T) {
+	cidrs, err := ParseCIDRs("10.0.0.0/8", "192.0.2.1", "::1", "2001:db8::/32")
+	require.NoError(t, err)
+	require.Len(t, cidrs, 4)
+	assert.Equal(t, 32, cidrs[1].Bits())
+	assert.Equal(t, 128, cidrs[2].Bits())
+
+	_, err = ParseCIDRs("not-an-ip")
+	assert.Error(t, err)
+	assert.Panics(t, func() { MustParseCIDRs("10.0.0.0/99") })
+}
+
+func TestHandler_IPFilter(t *testing.T) {
+	emitter := &recordingEmitter{}
+	h := NewHandler(emitter,
+		WithTrustedProxies(MustParseCIDRs("10.0.0.1")),
+		WithIPFilter(
+			MustParseCIDRs("192.0.2.0/24", "10.0.0.0/8"),
+			MustParseCIDRs("192.0.2.66"),
+		),
+	)
+	var seenIP string
+	h.Register([]endpoint.Endpoint{
+		endpoint.NewEndpoint("/", http.MethodGet).WithHandler(
+			func(w http.ResponseWriter, r *http.Request) { seenIP = ClientIP(r) }),
+	})
+
+	tests := []struct {
+		name     string
+		remote   string
+		xff      string
+		wantCode int
+		wantIP   string
+	}{
+		{"allowed direct", "192.0.2.10:5000", "", http.StatusOK, "192.0.2.10"},
+		{"denied direct", "192.0.2.66:5000", "", http.StatusForbidden, ""},
+		{"not allowed", "198.51.100.1:5000", "", http.StatusForbidden, ""},
+		{"via trusted proxy", "10.0.0.1:5000", "192.0.2.20", http.StatusOK, "192.0.2.20"},
+		{"denied via proxy", "10.0.0.1:5000", "192.0.2.66", http.StatusForbidden, ""},
+		{"spoofed left-most", "10.0.0.1:5000", "192.0.2.66, 198.51.100.9", http.StatusForbidden, ""},
+		{"untrusted xff ignored", "192.0.2.10:5000", "192.0.2.66", http.StatusOK, "192.0.2.10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seenIP = ""
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			if tt.xff != "" {
+				req.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.wantIP, seenIP)
+		})
+	}
+
+	blocked := emitter.ofType(EventIPBlocked)
+	require.Len(t, blocked, 4)
+	assert.Equal(t, "192.0.2.66", blocked[0].Data.(map[string]any)["ip"])
+}
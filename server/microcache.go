@@ -0,0 +1,232 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// microCacheMaxBody is the largest response body kept by the micro-cache.
+const microCacheMaxBody = 1 << 20
+
+// WithMicroCache caches successful GET responses for a very short time,
+// keyed by host, path and query, to absorb traffic spikes. Concurrent
+// requests for a key that is being rendered wait for the first response
+// instead of all hitting the handler.
+//
+// Only 200 responses up to 1MB are cached. Requests carrying Authorization
+// or Cookie headers bypass the cache, as do responses that set cookies or
+// send Cache-Control no-store, no-cache or private, so handlers can opt out
+// per response. Responses that Vary on anything but Accept-Encoding, such
+// as negotiated formats and locales, are not cached either, since the key
+// does not tell the representations apart.
+//
+// Parameters:
+//   - ttl: How long responses are served from the cache.
+//
+// Returns:
+//   - HandlerOption: A handler option function.
+func WithMicroCache(ttl time.Duration) HandlerOption {
+	return func(h *Handler) {
+		if ttl > 0 {
+			h.microCache = &microCache{
+				ttl:     ttl,
+				entries: make(map[string]*cacheEntry),
+			}
+		} else {
+			h.microCache = nil
+		}
+	}
+}
+
+// microCache stores rendered responses.
+type microCache struct {
+	ttl       time.Duration
+	mu        sync.Mutex
+	entries   map[string]*cacheEntry
+	lastSweep time.Time
+}
+
+// cacheEntry is a cached response. ready is closed once it is rendered.
+type cacheEntry struct {
+	ready   chan struct{}
+	expires time.Time
+	status  int
+	header  http.Header
+	body    []byte
+}
+
+// serve answers the request from the cache or renders it with next.
+func (c *microCache) serve(
+	w http.ResponseWriter, r *http.Request, next http.Handler,
+) {
+	if r.Method != http.MethodGet || r.Header.Get("Authorization") != "" ||
+		r.Header.Get("Cookie") != "" {
+		next.ServeHTTP(w, r)
+		return
+	}
+	key := r.Host + r.URL.RequestURI()
+	now := time.Now()
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok && isReady(e) && !now.Before(e.expires) {
+		ok = false
+	}
+	if ok {
+		c.mu.Unlock()
+		select {
+		case <-e.ready:
+		case <-r.Context().Done():
+			return
+		}
+		if e.body != nil {
+			e.write(w)
+			return
+		}
+		// The leader's response was not cacheable.
+		next.ServeHTTP(w, r)
+		return
+	}
+	e = &cacheEntry{ready: make(chan struct{})}
+	c.entries[key] = e
+	c.sweep(now)
+	c.mu.Unlock()
+
+	rec := &cacheRecorder{ResponseWriter: w}
+	defer func() {
+		if rec.cacheable() {
+			e.status = rec.status
+			e.header = rec.header
+			e.body = append([]byte{}, rec.body...)
+			e.expires = time.Now().Add(c.ttl)
+		} else {
+			c.mu.Lock()
+			if c.entries[key] == e {
+				delete(c.entries, key)
+			}
+			c.mu.Unlock()
+		}
+		close(e.ready)
+	}()
+	next.ServeHTTP(rec, r)
+}
+
+// sweep removes expired entries at most once per TTL. The caller must hold
+// the lock.
+func (c *microCache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+	for k, e := range c.entries {
+		if isReady(e) && !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// isReady reports whether the entry has been rendered.
+func isReady(e *cacheEntry) bool {
+	select {
+	case <-e.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+// write replays the cached response.
+func (e *cacheEntry) write(w http.ResponseWriter) {
+	hdr := w.Header()
+	for k, v := range e.header {
+		hdr[k] = append([]string(nil), v...)
+	}
+	w.WriteHeader(e.status)
+	_, _ = w.Write(e.body)
+}
+
+// cacheRecorder passes a response through while recording it.
+type cacheRecorder struct {
+	http.ResponseWriter
+	status   int
+	header   http.Header
+	body     []byte
+	tooLarge bool
+	flushed  bool
+}
+
+// WriteHeader records the status and a snapshot of the headers.
+func (c *cacheRecorder) WriteHeader(code int) {
+	if c.status == 0 && code >= 200 {
+		c.status = code
+		c.header = c.ResponseWriter.Header().Clone()
+	}
+	c.ResponseWriter.WriteHeader(code)
+}
+
+// Write records and writes the data.
+func (c *cacheRecorder) Write(p []byte) (int, error) {
+	if c.status == 0 {
+		c.WriteHeader(http.StatusOK)
+	}
+	if !c.tooLarge {
+		if len(c.body)+len(p) > microCacheMaxBody {
+			c.tooLarge = true
+			c.body = nil
+		} else {
+			c.body = append(c.body, p...)
+		}
+	}
+	return c.ResponseWriter.Write(p)
+}
+
+// Flush marks the response as streamed, which is never cached.
+func (c *cacheRecorder) Flush() {
+	c.flushed = true
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying response writer.
+func (c *cacheRecorder) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// cacheable reports whether the recorded response may be cached.
+func (c *cacheRecorder) cacheable() bool {
+	if c.status != http.StatusOK || c.tooLarge || c.flushed ||
+		c.header.Get("Set-Cookie") != "" {
+		return false
+	}
+	if variesBeyondEncoding(c.header) {
+		return false
+	}
+	cc := strings.ToLower(c.header.Get("Cache-Control"))
+	for _, directive := range []string{"no-store", "no-cache", "private"} {
+		if strings.Contains(cc, directive) {
+			return false
+		}
+	}
+	if c.body == nil {
+		c.body = []byte{}
+	}
+	return true
+}
+
+// variesBeyondEncoding reports whether the Vary headers name anything but
+// Accept-Encoding, which the cache does not need to consider because
+// compression is applied after it.
+func variesBeyondEncoding(header http.Header) bool {
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" && !strings.EqualFold(name, "Accept-Encoding") {
+				return true
+			}
+		}
+	}
+	return false
+}
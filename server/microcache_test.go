@@ -0,0 +1,128 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func countingHandler(calls *atomic.Int32, delay time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		time.Sleep(delay)
+		if r.URL.Query().Get("private") != "" {
+			w.Header().Set("Cache-Control", "private")
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(strconv.Itoa(int(n))))
+	}
+}
+
+func TestMicroCache(t *testing.T) {
+	var calls atomic.Int32
+	h := NewHandler(event.NewNoopEventEmitter(), WithMicroCache(100*time.Millisecond))
+	h.Register([]endpoint.Endpoint{
+		endpoint.NewEndpoint("/items", http.MethodGet).WithHandler(countingHandler(&calls, 0)),
+	})
+	get := func(target string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, "1", get("/items").Body.String())
+	cached := get("/items")
+	assert.Equal(t, "1", cached.Body.String())
+	assert.Equal(t, "text/plain", cached.Header().Get("Content-Type"))
+
+	// Query strings are part of the key.
+	assert.Equal(t, "2", get("/items?page=2").Body.String())
+	// Credentials and private responses bypass the cache.
+	assert.Equal(t, "3", get("/items", "Authorization", "Bearer x").Body.String())
+	assert.Equal(t, "4", get("/items?private=1").Body.String())
+	assert.Equal(t, "5", get("/items?private=1").Body.String())
+
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, "6", get("/items").Body.String())
+}
+
+func TestMicroCache_CoalescesConcurrentMisses(t *testing.T) {
+	var calls atomic.Int32
+	h := NewHandler(event.NewNoopEventEmitter(), WithMicroCache(time.Second))
+	h.Register([]endpoint.Endpoint{
+		endpoint.NewEndpoint("/slow", http.MethodGet).WithHandler(
+			countingHandler(&calls, 50*time.Millisecond)),
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+			assert.Equal(t, "1", rec.Body.String())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestMicroCache_ErrorsNotCached(t *testing.T) {
+	var calls atomic.Int32
+	h := NewHandler(event.NewNoopEventEmitter(), WithMicroCache(time.Second))
+	h.Register([]endpoint.Endpoint{
+		endpoint.NewEndpoint("/fail", http.MethodGet).WithHandler(
+			func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			}),
+	})
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fail", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	}
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestMicroCache_NegotiatedResponsesNotShared(t *testing.T) {
+	output := endpoint.NegotiatingOutput(endpoint.JSONEncoder(), endpoint.XMLEncoder())
+	localized := endpoint.NegotiateLocale("en", "fi")(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			locale, _ := endpoint.LocaleFromRequest(r)
+			_, _ = w.Write([]byte(locale))
+		}))
+	h := NewHandler(event.NewNoopEventEmitter(), WithMicroCache(time.Second))
+	h.Register([]endpoint.Endpoint{
+		endpoint.NewEndpoint("/format", http.MethodGet).WithHandler(
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = output.Handle(w, r, "ok", nil, http.StatusOK)
+			}),
+		endpoint.NewEndpoint("/greeting", http.MethodGet).WithHandler(localized.ServeHTTP),
+	})
+	get := func(target, name, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set(name, value)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Contains(t, get("/format", "Accept", "application/json").Header().Get("Content-Type"), "application/json")
+	assert.Contains(t, get("/format", "Accept", "application/xml").Header().Get("Content-Type"), "application/xml")
+	assert.Equal(t, "en", get("/greeting", "Accept-Language", "en").Body.String())
+	assert.Equal(t, "fi", get("/greeting", "Accept-Language", "fi").Body.String())
+}
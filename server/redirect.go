@@ -0,0 +1,220 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// acmeChallengePrefix is the path of HTTP-01 ACME challenges.
+const acmeChallengePrefix = "/.well-known/acme-challenge/"
+
+// RedirectOption configures an HTTP to HTTPS redirect handler.
+type RedirectOption func(*redirectHandler)
+
+// WithRedirectStatus sets the redirect status code. Defaults to 308, which
+// keeps the request method; use 301 for older clients.
+//
+// Parameters:
+//   - code: The redirect status code.
+//
+// Returns:
+//   - RedirectOption: A redirect option function.
+func WithRedirectStatus(code int) RedirectOption {
+	return func(h *redirectHandler) { h.status = code }
+}
+
+// WithRedirectHost redirects to a fixed host instead of the request's Host
+// header. Set it when the server may be reached under untrusted host names.
+//
+// Parameters:
+//   - host: The canonical host name, without port.
+//
+// Returns:
+//   - RedirectOption: A redirect option function.
+func WithRedirectHost(host string) RedirectOption {
+	return func(h *redirectHandler) { h.host = host }
+}
+
+// WithACMEChallenge answers ACME HTTP-01 challenges below
+// /.well-known/acme-challenge/ with the given handler instead of
+// redirecting them, e.g. with the handler of an autocert manager.
+//
+// Parameters:
+//   - handler: The challenge handler.
+//
+// Returns:
+//   - RedirectOption: A redirect option function.
+func WithACMEChallenge(handler http.Handler) RedirectOption {
+	return func(h *redirectHandler) { h.acme = handler }
+}
+
+// RedirectHandler returns a handler redirecting every request to HTTPS,
+// preserving path and query. The port of httpsAddr is added to the target
+// unless it is 443 or empty.
+//
+// Parameters:
+//   - httpsAddr: The HTTPS listen address, e.g. ":443" or ":8443".
+//   - opts: Optional redirect options.
+//
+// Returns:
+//   - http.Handler: The redirect handler.
+func RedirectHandler(httpsAddr string, opts ...RedirectOption) http.Handler {
+	h := &redirectHandler{status: http.StatusPermanentRedirect}
+	if _, port, err := net.SplitHostPort(httpsAddr); err == nil &&
+		port != "" && port != "443" && port != "https" {
+		h.port = port
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// NewRedirectServer returns a minimal HTTP server redirecting to HTTPS with
+// RedirectHandler and the timeouts of DefaultServerConfig. Run it next to
+// the HTTPS server with Combine or a Group.
+//
+// Parameters:
+//   - addr: The HTTP listen address, e.g. ":80".
+//   - httpsAddr: The HTTPS listen address, e.g. ":443".
+//   - opts: Optional redirect options.
+//
+// Returns:
+//   - *http.Server: The redirect server.
+func NewRedirectServer(
+	addr, httpsAddr string, opts ...RedirectOption,
+) *http.Server {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: RedirectHandler(httpsAddr, opts...),
+	}
+	DefaultServerConfig().apply(srv)
+	return srv
+}
+
+// redirectHandler redirects requests to HTTPS.
+type redirectHandler struct {
+	status int
+	host   string
+	port   string
+	acme   http.Handler
+}
+
+// ServeHTTP redirects the request or answers an ACME challenge.
+func (h *redirectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.acme != nil && strings.HasPrefix(r.URL.Path, acmeChallengePrefix) {
+		h.acme.ServeHTTP(w, r)
+		return
+	}
+	host := h.host
+	if host == "" {
+		host = r.Host
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+	}
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		host = host[1 : len(host)-1] // IPv6 literal without a port.
+	}
+	if host == "" {
+		http.Error(w, http.StatusText(http.StatusBadRequest),
+			http.StatusBadRequest)
+		return
+	}
+	if h.port != "" {
+		host = net.JoinHostPort(host, h.port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6 literal
+	}
+	http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), h.status)
+}
+
+// Combine returns an HTTPServer running main together with companion
+// servers, such as a redirect server, so a single StartServer call manages
+// all of them. If a companion fails, main is shut down within the shutdown
+// timeout of StartServer or Group, 60 seconds by default, and the error is
+// returned; Shutdown stops all servers concurrently.
+//
+// Parameters:
+//   - main: The main server.
+//   - companions: Servers running alongside main.
+//
+// Returns:
+//   - HTTPServer: The combined server.
+func Combine(main HTTPServer, companions ...HTTPServer) HTTPServer {
+	return &combinedServer{
+		main:            main,
+		companions:      companions,
+		shutdownTimeout: defaultShutdownTimeout,
+	}
+}
+
+// shutdownTimeoutSetter is implemented by servers that shut themselves
+// down, so StartServer and Group can pass their shutdown timeout on.
+type shutdownTimeoutSetter interface {
+	setShutdownTimeout(d time.Duration)
+}
+
+// combinedServer runs several servers as one.
+type combinedServer struct {
+	main            HTTPServer
+	companions      []HTTPServer
+	shutdownTimeout time.Duration
+}
+
+// combinedServer implements shutdownTimeoutSetter.
+var _ shutdownTimeoutSetter = (*combinedServer)(nil)
+
+// setShutdownTimeout sets the timeout of shutdowns after a companion
+// failure.
+func (c *combinedServer) setShutdownTimeout(d time.Duration) {
+	c.shutdownTimeout = d
+}
+
+// ListenAndServe serves all servers until main stops.
+func (c *combinedServer) ListenAndServe() error {
+	failed := make(chan error, len(c.companions))
+	for _, s := range c.companions {
+		go func() {
+			err := s.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				failed <- err
+			}
+		}()
+	}
+	done := make(chan error, 1)
+	go func() { done <- c.main.ListenAndServe() }()
+	select {
+	case err := <-done:
+		return err
+	case err := <-failed:
+		ctx, cancel := context.WithTimeout(
+			context.Background(), c.shutdownTimeout,
+		)
+		defer cancel()
+		_ = c.Shutdown(ctx)
+		<-done
+		return err
+	}
+}
+
+// Shutdown shuts down all servers concurrently.
+func (c *combinedServer) Shutdown(ctx context.Context) error {
+	servers := append([]HTTPServer{c.main}, c.companions...)
+	errs := make([]error, len(servers))
+	var wg sync.WaitGroup
+	for i, s := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.Shutdown(ctx)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedirectHandler(t *testing.T) {
+	acme := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("token"))
+	})
+	tests := []struct {
+		name     string
+		https    string
+		opts     []RedirectOption
+		host     string
+		target   string
+		status   int
+		location string
+	}{
+		{"default port", ":443", nil, "example.com", "/a/b?x=1",
+			http.StatusPermanentRedirect, "https://example.com/a/b?x=1"},
+		{"strips http port", ":443", nil, "example.com:8080", "/",
+			http.StatusPermanentRedirect, "https://example.com/"},
+		{"custom port", ":8443", nil, "example.com:8080", "/p",
+			http.StatusPermanentRedirect, "https://example.com:8443/p"},
+		{"ipv6", ":443", nil, "[::1]", "/p",
+			http.StatusPermanentRedirect, "https://[::1]/p"},
+		{"ipv6 with port", ":443", nil, "[::1]:80", "/p",
+			http.StatusPermanentRedirect, "https://[::1]/p"},
+		{"ipv6 custom port", ":8443", nil, "[::1]", "/p",
+			http.StatusPermanentRedirect, "https://[::1]:8443/p"},
+		{"fixed host", ":443", []RedirectOption{WithRedirectHost("api.example.com")},
+			"evil.test", "/p", http.StatusPermanentRedirect, "https://api.example.com/p"},
+		{"status", "", []RedirectOption{WithRedirectStatus(http.StatusMovedPermanently)},
+			"example.com", "/", http.StatusMovedPermanently, "https://example.com/"},
+		{"acme", ":443", []RedirectOption{WithACMEChallenge(acme)},
+			"example.com", "/.well-known/acme-challenge/abc", http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Host = tt.host
+			rec := httptest.NewRecorder()
+			RedirectHandler(tt.https, tt.opts...).ServeHTTP(rec, req)
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.location, rec.Header().Get("Location"))
+		})
+	}
+}
+
+func TestNewRedirectServer(t *testing.T) {
+	srv := NewRedirectServer(":80", ":443")
+	assert.Equal(t, ":80", srv.Addr)
+	assert.Equal(t, 10*time.Second, srv.ReadTimeout)
+	assert.NotNil(t, srv.Handler)
+}
+
+func TestCombine(t *testing.T) {
+	main, companion := NewDummyHTTPServer(), NewDummyHTTPServer()
+	combined := Combine(main, companion)
+	errCh := make(chan error, 1)
+	go func() { errCh <- combined.ListenAndServe() }()
+	time.Sleep(20 * time.Millisecond)
+
+	require.NoError(t, combined.Shutdown(context.Background()))
+	require.NoError(t, <-errCh)
+	assert.True(t, main.ShutdownCalled)
+	assert.True(t, companion.ShutdownCalled)
+}
+
+func TestCombine_CompanionFailure(t *testing.T) {
+	main, companion := NewDummyHTTPServer(), NewDummyHTTPServer()
+	companion.ListenAndServeErr = errors.New("port 80 in use")
+
+	err := Combine(main, companion).ListenAndServe()
+	assert.EqualError(t, err, "port 80 in use")
+	assert.True(t, main.ShutdownCalled)
+}
+
+// stuckServer is a server whose shutdown waits for its context to end.
+type stuckServer struct {
+	stopped chan struct{}
+}
+
+func (s *stuckServer) ListenAndServe() error {
+	<-s.stopped
+	return http.ErrServerClosed
+}
+
+func (s *stuckServer) Shutdown(ctx context.Context) error {
+	<-ctx.Done()
+	close(s.stopped)
+	return ctx.Err()
+}
+
+func TestCombine_CompanionFailureShutdownTimeout(t *testing.T) {
+	companion := NewDummyHTTPServer()
+	companion.ListenAndServeErr = errors.New("port 80 in use")
+	combined := Combine(&stuckServer{stopped: make(chan struct{})}, companion)
+	combined.(shutdownTimeoutSetter).setShutdownTimeout(20 * time.Millisecond)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- combined.ListenAndServe() }()
+	select {
+	case err := <-errCh:
+		assert.EqualError(t, err, "port 80 in use")
+	case <-time.After(time.Second):
+		t.Fatal("ListenAndServe did not return after the shutdown timeout")
+	}
+}
@@ -6,6 +6,7 @@ import "net/http"
 type trackingResponseWriter struct {
 	http.ResponseWriter
 	wroteHeader  bool
+	status       int
 	bytesWritten int64
 }
 
@@ -23,6 +24,7 @@ func (w *trackingResponseWriter) WriteHeader(code int) {
 		return
 	}
 	w.wroteHeader = true
+	w.status = code
 	w.ResponseWriter.WriteHeader(code)
 }
 
@@ -36,6 +38,22 @@ func (w *trackingResponseWriter) Write(data []byte) (int, error) {
 	return n, err
 }
 
+// Flush sends buffered data to the client if the underlying writer supports
+// it, so streaming handlers work through the Handler.
+func (w *trackingResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying response writer.
+func (w *trackingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 // WroteHeader returns true if headers have been written.
 func (w *trackingResponseWriter) WroteHeader() bool {
 	return w.wroteHeader
@@ -46,6 +64,11 @@ func (w *trackingResponseWriter) BytesWritten() int64 {
 	return w.bytesWritten
 }
 
+// Status returns the response status code, or 0 if no header was written.
+func (w *trackingResponseWriter) Status() int {
+	return w.status
+}
+
 // CanWriteHeader returns true if headers can still be written.
 func (w *trackingResponseWriter) CanWriteHeader() bool {
 	return !w.wroteHeader
@@ -2,10 +2,12 @@ package server
 
 import (
 	"bytes"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/aatuh/pureapi-core/endpoint"
 	"github.com/aatuh/pureapi-core/event"
 	"github.com/aatuh/pureapi-core/router"
 )
@@ -99,3 +101,48 @@ func TestHandler_DefaultBodyLimit(t *testing.T) {
 		t.Fatalf("Expected status 200 within default limit, got %d", w.Code)
 	}
 }
+
+func TestHandler_PerRouteBodyLimit(t *testing.T) {
+	handler := NewHandler(event.NewNoopEventEmitter(), WithBodyLimit(100))
+	read := func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	handler.Register([]endpoint.Endpoint{
+		endpoint.NewEndpoint("/upload", "POST").WithBodyLimit(1000).WithHandler(read),
+		endpoint.NewEndpoint("/tiny", "POST").WithBodyLimit(10).WithHandler(read),
+		endpoint.NewEndpoint("/unlimited", "POST").WithBodyLimit(-1).WithHandler(read),
+		endpoint.NewEndpoint("/default", "POST").WithHandler(read),
+	})
+
+	tests := []struct {
+		path     string
+		size     int
+		chunked  bool
+		wantCode int
+	}{
+		{"/upload", 500, false, http.StatusOK},
+		{"/upload", 1500, false, http.StatusRequestEntityTooLarge},
+		{"/upload", 1500, true, http.StatusRequestEntityTooLarge},
+		{"/tiny", 50, false, http.StatusRequestEntityTooLarge},
+		{"/tiny", 50, true, http.StatusRequestEntityTooLarge},
+		{"/unlimited", 5000, false, http.StatusOK},
+		{"/default", 50, false, http.StatusOK},
+		{"/default", 500, false, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", tt.path, bytes.NewReader(make([]byte, tt.size)))
+		if tt.chunked {
+			req.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != tt.wantCode {
+			t.Fatalf("%s (%d bytes, chunked=%v): expected %d, got %d",
+				tt.path, tt.size, tt.chunked, tt.wantCode, w.Code)
+		}
+	}
+}
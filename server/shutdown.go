@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aatuh/pureapi-core/internal/shutdown"
+)
+
+// BeginShutdown cancels the shutdown context handed to requests, telling
+// long-lived handlers such as SSE streams and long polls to finish. It is
+// called by StartServer when graceful shutdown starts, before the server
+// waits for active requests; call it yourself when shutting down the server
+// by other means. It is safe to call more than once.
+func (h *Handler) BeginShutdown() {
+	h.shutdownCancel()
+}
+
+// ShutdownContext returns a context that is cancelled when the Handler that
+// serves the request begins graceful shutdown. Long-lived handlers should
+// stop when either it or the request context is done:
+//
+//	select {
+//	case <-r.Context().Done():
+//	case <-server.ShutdownContext(r).Done():
+//	case msg := <-messages:
+//	}
+//
+// Outside a Handler the returned context is never cancelled.
+//
+// Parameters:
+//   - r: The request.
+//
+// Returns:
+//   - context.Context: The shutdown context.
+func ShutdownContext(r *http.Request) context.Context {
+	return shutdown.FromContext(r.Context())
+}
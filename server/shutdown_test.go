@@ -0,0 +1,62 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownContext_LongLivedHandler(t *testing.T) {
+	handler := NewHandler(event.NewNoopEventEmitter())
+	started := make(chan struct{})
+	ep := endpoint.NewEndpoint("/stream", http.MethodGet).WithHandler(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			close(started)
+			select {
+			case <-r.Context().Done():
+			case <-ShutdownContext(r).Done():
+				_, _ = w.Write([]byte("bye"))
+			}
+		})
+	srv := DefaultHTTPServer(handler, 0, []endpoint.Endpoint{ep})
+	srv.Addr = "127.0.0.1:0"
+	bound, err := Bind(srv)
+	require.NoError(t, err)
+
+	stopChan := make(chan os.Signal, 1)
+	errCh := make(chan error, 1)
+	go func() { errCh <- handler.startServer(stopChan, bound, 5*time.Second) }()
+
+	resp, err := http.Get("http://127.0.0.1:" + strconv.Itoa(bound.Port()) + "/stream")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	<-started
+
+	start := time.Now()
+	stopChan <- os.Interrupt
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "bye", string(body))
+	require.NoError(t, <-errCh)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestShutdownContext_OutsideHandler(t *testing.T) {
+	ctx := ShutdownContext(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Nil(t, ctx.Done())
+
+	h := NewHandler(event.NewNoopEventEmitter())
+	h.BeginShutdown()
+	h.BeginShutdown()
+	assert.Error(t, h.shutdownCtx.Err())
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aatuh/pureapi-core/event"
+)
+
+// EventRequestSize is emitted after each request when size events are
+// enabled. Its data holds "method", "path", "status", "request_bytes",
+// "response_bytes" and "duration".
+const EventRequestSize event.EventType = "event_request_size"
+
+// WithSizeEvents enables an EventRequestSize event per request reporting the
+// request body bytes read by the server and the response bytes written to
+// the connection, after compression. It is opt-in as it adds an event to
+// every request.
+//
+// Returns:
+//   - HandlerOption: A handler option function.
+func WithSizeEvents() HandlerOption {
+	return func(h *Handler) { h.sizeEvents = true }
+}
+
+// countingReader counts the bytes read from a request body.
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+// Read reads from the body and counts the bytes.
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// trackSize counts the request body and returns a function emitting the
+// EventRequestSize event once the response is complete.
+func (h *Handler) trackSize(
+	tw *trackingResponseWriter, r *http.Request,
+) func() {
+	start := time.Now()
+	body := &countingReader{ReadCloser: http.NoBody}
+	if r.Body != nil {
+		body.ReadCloser = r.Body
+	}
+	r.Body = body
+	method, path := r.Method, r.URL.Path
+	return func() {
+		status := tw.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		event.EmitCtx(h.emitter, r.Context(),
+			event.NewEvent(
+				EventRequestSize,
+				fmt.Sprintf(
+					"Request %s %s: %d bytes in, %d bytes out",
+					method, path, body.n, tw.BytesWritten(),
+				),
+			).WithData(map[string]any{
+				"method":         method,
+				"path":           path,
+				"status":         status,
+				"request_bytes":  body.n,
+				"response_bytes": tw.BytesWritten(),
+				"duration":       time.Since(start),
+			}),
+		)
+	}
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_SizeEvents(t *testing.T) {
+	emitter := &recordingEmitter{}
+	h := NewHandler(emitter, WithSizeEvents())
+	h.Register([]endpoint.Endpoint{
+		endpoint.NewEndpoint("/echo", http.MethodPost).WithHandler(
+			func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write(append(b, b...))
+			}),
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(
+		http.MethodPost, "/echo", strings.NewReader("hello")))
+
+	events := emitter.ofType(EventRequestSize)
+	require.Len(t, events, 1)
+	data := events[0].Data.(map[string]any)
+	assert.Equal(t, http.MethodPost, data["method"])
+	assert.Equal(t, "/echo", data["path"])
+	assert.Equal(t, http.StatusCreated, data["status"])
+	assert.Equal(t, int64(5), data["request_bytes"])
+	assert.Equal(t, int64(10), data["response_bytes"])
+}
+
+func TestHandler_SizeEventsCompressed(t *testing.T) {
+	emitter := &recordingEmitter{}
+	h := NewHandler(emitter, WithSizeEvents(),
+		WithCompression(WithCompressionMinSize(1)))
+	h.Register([]endpoint.Endpoint{
+		endpoint.NewEndpoint("/", http.MethodGet).WithHandler(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = w.Write([]byte(strings.Repeat("a", 4096)))
+			}),
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	events := emitter.ofType(EventRequestSize)
+	require.Len(t, events, 1)
+	data := events[0].Data.(map[string]any)
+	assert.Equal(t, int64(rec.Body.Len()), data["response_bytes"])
+	assert.Less(t, data["response_bytes"].(int64), int64(4096))
+	assert.Equal(t, int64(0), data["request_bytes"])
+}
+
+func TestHandler_SizeEventsDisabled(t *testing.T) {
+	emitter := &recordingEmitter{}
+	h := NewHandler(emitter)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Empty(t, emitter.ofType(EventRequestSize))
+}
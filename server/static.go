@@ -0,0 +1,235 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+)
+
+// StaticOption configures a static file endpoint.
+type StaticOption func(*staticHandler)
+
+// WithStaticIndex sets the file served for directory requests. Defaults to
+// "index.html". Directories without an index file respond with 404; listings
+// are never generated.
+//
+// Parameters:
+//   - name: The index file name.
+//
+// Returns:
+//   - StaticOption: A static option function.
+func WithStaticIndex(name string) StaticOption {
+	return func(s *staticHandler) { s.index = name }
+}
+
+// WithStaticCacheControl sets the Cache-Control header sent with files.
+// Defaults to "public, max-age=3600". An empty value omits the header.
+//
+// Parameters:
+//   - value: The Cache-Control header value.
+//
+// Returns:
+//   - StaticOption: A static option function.
+func WithStaticCacheControl(value string) StaticOption {
+	return func(s *staticHandler) { s.cacheControl = value }
+}
+
+// WithStaticDotfiles allows serving files and directories whose name starts
+// with a dot. They are hidden by default to avoid leaking files such as .env
+// or .git.
+//
+// Returns:
+//   - StaticOption: A static option function.
+func WithStaticDotfiles() StaticOption {
+	return func(s *staticHandler) { s.dotfiles = true }
+}
+
+// Static returns a GET endpoint serving files from fsys below prefix. It
+// works with os.DirFS as well as embed.FS. Paths are cleaned and confined to
+// fsys, content types are detected from extensions or content, and responses
+// carry ETag and Last-Modified validators for conditional and range requests.
+// Register the endpoint like any other, HEAD is answered automatically.
+//
+// Parameters:
+//   - prefix: The URL prefix, e.g. "/assets".
+//   - fsys: The file system to serve.
+//   - opts: Optional static options.
+//
+// Returns:
+//   - endpoint.Endpoint: The static file endpoint.
+func Static(
+	prefix string, fsys fs.FS, opts ...StaticOption,
+) endpoint.Endpoint {
+	prefix = "/" + strings.Trim(prefix, "/")
+	s := &staticHandler{
+		fsys:         fsys,
+		prefix:       prefix,
+		index:        "index.html",
+		cacheControl: "public, max-age=3600",
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return endpoint.NewEndpoint(
+		path.Join(prefix, "*filepath"), http.MethodGet,
+	).WithHandler(s.ServeHTTP)
+}
+
+// staticHandler serves files from a file system.
+type staticHandler struct {
+	fsys         fs.FS
+	prefix       string
+	index        string
+	cacheControl string
+	dotfiles     bool
+	etags        sync.Map // name -> content hash for files without mod time
+}
+
+// ServeHTTP serves the file addressed by the request path.
+func (s *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, ok := s.resolve(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	f, info, err := s.open(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+			http.NotFound(w, r)
+			return
+		}
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	}
+	defer f.Close()
+
+	content, err := readSeeker(f)
+	if err != nil {
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	}
+	etag, err := s.etag(name, info, content)
+	if err != nil {
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	if s.cacheControl != "" {
+		w.Header().Set("Cache-Control", s.cacheControl)
+	}
+	http.ServeContent(w, r, info.Name(), info.ModTime(), content)
+}
+
+// resolve maps a request path to a cleaned name inside the file system.
+func (s *staticHandler) resolve(urlPath string) (string, bool) {
+	rest, ok := strings.CutPrefix(urlPath, s.prefix)
+	if !ok || (rest != "" && rest[0] != '/' && s.prefix != "/") {
+		return "", false
+	}
+	// Cleaning a rooted path removes any ".." that would escape the root.
+	name := strings.TrimPrefix(path.Clean("/"+rest), "/")
+	if name == "" {
+		name = "."
+	}
+	if !fs.ValidPath(name) {
+		return "", false
+	}
+	if !s.dotfiles && name != "." {
+		for _, seg := range strings.Split(name, "/") {
+			if strings.HasPrefix(seg, ".") {
+				return "", false
+			}
+		}
+	}
+	return name, true
+}
+
+// open opens name, falling back to the index file for directories.
+func (s *staticHandler) open(name string) (fs.File, fs.FileInfo, error) {
+	f, err := s.fsys.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	if !info.IsDir() {
+		return f, info, nil
+	}
+	f.Close()
+	if s.index == "" {
+		return nil, nil, fs.ErrNotExist
+	}
+	f, err = s.fsys.Open(path.Join(name, s.index))
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err = f.Stat()
+	if err != nil || info.IsDir() {
+		f.Close()
+		return nil, nil, fs.ErrNotExist
+	}
+	return f, info, nil
+}
+
+// etag returns a strong validator for the file. Files with a modification
+// time use size and time; files without one (embed.FS) are hashed once.
+func (s *staticHandler) etag(
+	name string, info fs.FileInfo, content io.ReadSeeker,
+) (string, error) {
+	if !info.ModTime().IsZero() {
+		return fmt.Sprintf(
+			`"%x-%x"`, info.Size(), info.ModTime().UnixNano(),
+		), nil
+	}
+	if v, ok := s.etags.Load(name); ok {
+		return v.(string), nil
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag := `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+	s.etags.Store(name, etag)
+	return etag, nil
+}
+
+// readSeeker returns the file as an io.ReadSeeker, buffering it if the file
+// does not support seeking.
+func readSeeker(f fs.File) (io.ReadSeeker, error) {
+	if rs, ok := f.(io.ReadSeeker); ok {
+		return rs, nil
+	}
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStaticTestHandler(ep endpoint.Endpoint) *Handler {
+	h := NewHandler(event.NewNoopEventEmitter())
+	h.Register([]endpoint.Endpoint{ep})
+	return h
+}
+
+func serveStatic(h http.Handler, method, target string, hdr map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range hdr {
+		req.Header.Set(k, v)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestStatic_MapFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":    {Data: []byte("<h1>home</h1>")},
+		"css/site.css":  {Data: []byte("body{}")},
+		"docs/readme":   {Data: []byte("plain text")},
+		".env":          {Data: []byte("SECRET=1")},
+		"empty/.keep":   {Data: nil},
+		"nested/a.json": {Data: []byte(`{"a":1}`)},
+	}
+	h := newStaticTestHandler(Static("/assets", fsys))
+
+	rr := serveStatic(h, http.MethodGet, "/assets/css/site.css", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "body{}", rr.Body.String())
+	assert.Contains(t, rr.Header().Get("Content-Type"), "text/css")
+	assert.Equal(t, "public, max-age=3600", rr.Header().Get("Cache-Control"))
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	// Conditional request.
+	rr = serveStatic(h, http.MethodGet, "/assets/css/site.css",
+		map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+
+	// Index handling.
+	rr = serveStatic(h, http.MethodGet, "/assets/", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "<h1>home</h1>", rr.Body.String())
+
+	// Directories without index are not listed.
+	rr = serveStatic(h, http.MethodGet, "/assets/nested/", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Dotfiles are hidden.
+	rr = serveStatic(h, http.MethodGet, "/assets/.env", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// HEAD falls back to GET without a body.
+	rr = serveStatic(h, http.MethodHead, "/assets/css/site.css", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Body.String())
+
+	// Other methods are rejected.
+	rr = serveStatic(h, http.MethodPost, "/assets/css/site.css", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
+
+func TestStatic_DirFS_Traversal(t *testing.T) {
+	root := t.TempDir()
+	public := filepath.Join(root, "public")
+	require.NoError(t, os.Mkdir(public, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "secret.txt"), []byte("secret"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(public, "hello.txt"), []byte("hello"), 0o600))
+
+	h := newStaticTestHandler(Static("/files", os.DirFS(public),
+		WithStaticCacheControl("no-cache")))
+
+	rr := serveStatic(h, http.MethodGet, "/files/hello.txt", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "hello", rr.Body.String())
+	assert.Equal(t, "no-cache", rr.Header().Get("Cache-Control"))
+	assert.NotEmpty(t, rr.Header().Get("Last-Modified"))
+
+	for _, target := range []string{
+		"/files/../secret.txt",
+		"/files/%2e%2e/secret.txt",
+		"/files/..%2fsecret.txt",
+	} {
+		rr = serveStatic(h, http.MethodGet, target, nil)
+		assert.NotEqual(t, "secret", rr.Body.String(), target)
+	}
+
+	// Range requests are honored.
+	rr = serveStatic(h, http.MethodGet, "/files/hello.txt",
+		map[string]string{"Range": "bytes=1-3"})
+	assert.Equal(t, http.StatusPartialContent, rr.Code)
+	assert.Equal(t, "ell", rr.Body.String())
+}
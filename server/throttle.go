@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+)
+
+// ThrottleListener wraps a listener so that every accepted connection is
+// limited to the given read and write bandwidth in bytes per second. A limit
+// of zero or less leaves that direction unthrottled. Serve it with
+// NewBoundServer:
+//
+//	ln, _ := net.Listen("tcp", ":8080")
+//	srv := server.NewBoundServer(httpServer, server.ThrottleListener(ln, 0, 1<<20))
+//
+// Parameters:
+//   - ln: The listener to wrap.
+//   - readBPS: Maximum bytes read per second and connection.
+//   - writeBPS: Maximum bytes written per second and connection.
+//
+// Returns:
+//   - net.Listener: The throttling listener.
+func ThrottleListener(ln net.Listener, readBPS, writeBPS int64) net.Listener {
+	return &throttledListener{Listener: ln, readBPS: readBPS, writeBPS: writeBPS}
+}
+
+// ThrottleMiddleware limits the request body read rate and the response
+// write rate of a route in bytes per second, e.g. for large downloads. Each
+// request gets its own budget. A limit of zero or less leaves that direction
+// unthrottled. Throttled writes stop when the request context is cancelled.
+//
+// Parameters:
+//   - readBPS: Maximum request body bytes read per second.
+//   - writeBPS: Maximum response bytes written per second.
+//
+// Returns:
+//   - endpoint.Middleware: The throttling middleware.
+func ThrottleMiddleware(readBPS, writeBPS int64) endpoint.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			if rb := newBandwidth(readBPS); rb != nil && r.Body != nil {
+				r.Body = &throttledBody{ReadCloser: r.Body, bw: rb, ctx: ctx}
+			}
+			if wb := newBandwidth(writeBPS); wb != nil {
+				w = &throttledResponseWriter{ResponseWriter: w, bw: wb, ctx: ctx}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bandwidth is a token bucket measured in bytes. It allows bursts of a
+// quarter second's worth of data.
+type bandwidth struct {
+	mu     sync.Mutex
+	rate   float64 // bytes per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newBandwidth creates a token bucket, or returns nil for unlimited rates.
+func newBandwidth(bps int64) *bandwidth {
+	if bps <= 0 {
+		return nil
+	}
+	burst := float64(bps) / 4
+	if burst < 1 {
+		burst = 1
+	}
+	return &bandwidth{
+		rate: float64(bps), burst: burst, tokens: burst, last: time.Now(),
+	}
+}
+
+// chunk returns the largest piece of n bytes that fits a single burst.
+func (b *bandwidth) chunk(n int) int {
+	if float64(n) > b.burst {
+		return int(b.burst)
+	}
+	return n
+}
+
+// reserve takes n tokens and returns how long to wait until they are paid.
+func (b *bandwidth) reserve(n int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// throttledWrite writes p in bursts paced by bw.
+func throttledWrite(
+	ctx context.Context, bw *bandwidth, p []byte, write func([]byte) (int, error),
+) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := bw.chunk(len(p))
+		if err := sleepCtx(ctx, bw.reserve(n)); err != nil {
+			return written, err
+		}
+		m, err := write(p[:n])
+		written += m
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// throttledRead reads at most one burst and waits for the bytes read.
+func throttledRead(
+	ctx context.Context, bw *bandwidth, p []byte, read func([]byte) (int, error),
+) (int, error) {
+	n, err := read(p[:bw.chunk(len(p))])
+	if n > 0 {
+		if serr := sleepCtx(ctx, bw.reserve(n)); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	return n, err
+}
+
+// sleepCtx sleeps for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// throttledListener wraps accepted connections with bandwidth limits.
+type throttledListener struct {
+	net.Listener
+	readBPS  int64
+	writeBPS int64
+}
+
+// Accept waits for and returns the next throttled connection.
+func (l *throttledListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &throttledConn{
+		Conn:  c,
+		read:  newBandwidth(l.readBPS),
+		write: newBandwidth(l.writeBPS),
+	}, nil
+}
+
+// throttledConn is a connection with read and write bandwidth limits.
+type throttledConn struct {
+	net.Conn
+	read  *bandwidth
+	write *bandwidth
+}
+
+// Read reads from the connection within the read limit.
+func (c *throttledConn) Read(p []byte) (int, error) {
+	if c.read == nil {
+		return c.Conn.Read(p)
+	}
+	return throttledRead(context.Background(), c.read, p, c.Conn.Read)
+}
+
+// Write writes to the connection within the write limit.
+func (c *throttledConn) Write(p []byte) (int, error) {
+	if c.write == nil {
+		return c.Conn.Write(p)
+	}
+	return throttledWrite(context.Background(), c.write, p, c.Conn.Write)
+}
+
+// throttledBody is a request body with a read limit.
+type throttledBody struct {
+	io.ReadCloser
+	bw  *bandwidth
+	ctx context.Context
+}
+
+// Read reads from the body within the read limit.
+func (b *throttledBody) Read(p []byte) (int, error) {
+	return throttledRead(b.ctx, b.bw, p, b.ReadCloser.Read)
+}
+
+// throttledResponseWriter is a response writer with a write limit.
+type throttledResponseWriter struct {
+	http.ResponseWriter
+	bw  *bandwidth
+	ctx context.Context
+}
+
+// Write writes the response within the write limit.
+func (w *throttledResponseWriter) Write(p []byte) (int, error) {
+	return throttledWrite(w.ctx, w.bw, p, w.ResponseWriter.Write)
+}
+
+// Flush flushes the underlying writer if it supports flushing.
+func (w *throttledResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying response writer.
+func (w *throttledResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
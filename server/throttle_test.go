@@ -0,0 +1,84 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottleMiddleware_Write(t *testing.T) {
+	payload := bytes.Repeat([]byte("x"), 150_000)
+	h := NewHandler(event.NewNoopEventEmitter())
+	h.Register([]endpoint.Endpoint{
+		endpoint.NewEndpoint("/download", http.MethodGet).
+			WithHandler(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(payload)
+			}).
+			WithMiddlewares(endpoint.NewMiddlewares(ThrottleMiddleware(0, 200_000))),
+	})
+
+	start := time.Now()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/download", nil))
+	elapsed := time.Since(start)
+
+	assert.Equal(t, len(payload), rec.Body.Len())
+	// 50KB burst, the remaining 100KB at 200KB/s.
+	assert.GreaterOrEqual(t, elapsed, 400*time.Millisecond)
+	assert.Less(t, elapsed, 2*time.Second)
+}
+
+func TestThrottleMiddleware_ReadCancelled(t *testing.T) {
+	mw := ThrottleMiddleware(1000, 0)
+	var readErr error
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("x", 10_000)))
+	handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+	assert.ErrorIs(t, readErr, context.DeadlineExceeded)
+}
+
+func TestThrottleListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	tl := ThrottleListener(ln, 0, 100_000)
+	defer tl.Close()
+
+	go func() {
+		c, err := tl.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		_, _ = c.Write(bytes.Repeat([]byte("x"), 75_000))
+	}()
+
+	start := time.Now()
+	c, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer c.Close()
+	b, err := io.ReadAll(c)
+	require.NoError(t, err)
+	assert.Len(t, b, 75_000)
+	// 25KB burst, the remaining 50KB at 100KB/s.
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+}
+
+func TestNewBandwidth_Unlimited(t *testing.T) {
+	assert.Nil(t, newBandwidth(0))
+	assert.Nil(t, newBandwidth(-1))
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+)
+
+// TLSModern returns a TLS configuration following the "modern" profile: TLS
+// 1.3 only with X25519 and NIST P-256/P-384 key exchange. TLS 1.3 cipher
+// suites are not configurable in Go and are always secure. Use it when all
+// clients are recent.
+//
+// Returns:
+//   - *tls.Config: A new TLS configuration.
+func TLSModern() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS13,
+		CurvePreferences: []tls.CurveID{
+			tls.X25519, tls.CurveP256, tls.CurveP384,
+		},
+	}
+}
+
+// TLSIntermediate returns a TLS configuration following the "intermediate"
+// profile: TLS 1.2 and 1.3, restricted to ECDHE key exchange with AEAD cipher
+// suites. It is the recommended default for general purpose servers.
+//
+// Returns:
+//   - *tls.Config: A new TLS configuration.
+func TLSIntermediate() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		CurvePreferences: []tls.CurveID{
+			tls.X25519, tls.CurveP256, tls.CurveP384,
+		},
+		// Only consulted for TLS 1.2, TLS 1.3 suites are fixed by Go.
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		},
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of the server. A nil config is
+// ignored. The config is cloned so presets can be shared safely.
+//
+// Parameters:
+//   - cfg: The TLS configuration, e.g. TLSModern() or TLSIntermediate().
+//
+// Returns:
+//   - HTTPServerOption: A server option function.
+func WithTLSConfig(cfg *tls.Config) HTTPServerOption {
+	return func(s *http.Server) {
+		if cfg != nil {
+			s.TLSConfig = cfg.Clone()
+		}
+	}
+}
+
+// TLSServer adapts an *http.Server to HTTPServer so that ListenAndServe
+// serves TLS. It can be passed to StartServer like a plain server.
+type TLSServer struct {
+	Server   *http.Server
+	CertFile string // Empty when certificates come from Server.TLSConfig.
+	KeyFile  string // Empty when certificates come from Server.TLSConfig.
+}
+
+// TLSServer implements the HTTPServer interface.
+var _ HTTPServer = (*TLSServer)(nil)
+
+// NewTLSServer creates a new TLSServer. The certificate and key files may be
+// empty if the server TLS configuration provides certificates itself, e.g.
+// through Certificates or GetCertificate.
+//
+// Parameters:
+//   - srv: The HTTP server to serve.
+//   - certFile: Path to the PEM encoded certificate.
+//   - keyFile: Path to the PEM encoded private key.
+//
+// Returns:
+//   - *TLSServer: A new TLSServer instance.
+func NewTLSServer(srv *http.Server, certFile, keyFile string) *TLSServer {
+	return &TLSServer{Server: srv, CertFile: certFile, KeyFile: keyFile}
+}
+
+// ListenAndServe listens on the server address and serves TLS.
+//
+// Returns:
+//   - error: An error if serving fails.
+func (s *TLSServer) ListenAndServe() error {
+	return s.Server.ListenAndServeTLS(s.CertFile, s.KeyFile)
+}
+
+// Shutdown gracefully shuts down the server.
+//
+// Parameters:
+//   - ctx: Context bounding the shutdown.
+//
+// Returns:
+//   - error: An error if the shutdown fails.
+func (s *TLSServer) Shutdown(ctx context.Context) error {
+	return s.Server.Shutdown(ctx)
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSelfSignedCert writes a self-signed certificate and key for
+// "localhost" into dir and returns their paths.
+func writeSelfSignedCert(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile,
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestTLSPresets(t *testing.T) {
+	modern := TLSModern()
+	assert.Equal(t, uint16(tls.VersionTLS13), modern.MinVersion)
+	assert.Empty(t, modern.CipherSuites)
+
+	inter := TLSIntermediate()
+	assert.Equal(t, uint16(tls.VersionTLS12), inter.MinVersion)
+	assert.NotEmpty(t, inter.CipherSuites)
+	for _, id := range inter.CipherSuites {
+		for _, insecure := range tls.InsecureCipherSuites() {
+			assert.NotEqual(t, insecure.ID, id, "insecure suite %s", insecure.Name)
+		}
+	}
+
+	// Presets must be independent values.
+	modern.MinVersion = tls.VersionTLS10
+	assert.Equal(t, uint16(tls.VersionTLS13), TLSModern().MinVersion)
+}
+
+func TestDefaultHTTPServer_WithTLSConfig(t *testing.T) {
+	cfg := TLSIntermediate()
+	srv := DefaultHTTPServer(
+		NewHandler(event.NewNoopEventEmitter()), 8443, nil, WithTLSConfig(cfg),
+	)
+	require.NotNil(t, srv.TLSConfig)
+	assert.NotSame(t, cfg, srv.TLSConfig)
+	assert.Equal(t, cfg.MinVersion, srv.TLSConfig.MinVersion)
+}
+
+func TestTLSModern_RejectsTLS12Clients(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t, t.TempDir())
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, err)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte("ok")) },
+	))
+	ts.TLS = TLSModern()
+	ts.TLS.Certificates = []tls.Certificate{cert}
+	ts.StartTLS()
+	defer ts.Close()
+
+	client := ts.Client()
+	transport := client.Transport.(*http.Transport)
+	transport.TLSClientConfig.InsecureSkipVerify = true
+	transport.TLSClientConfig.MaxVersion = tls.VersionTLS12
+	_, err = client.Get(ts.URL)
+	assert.Error(t, err)
+
+	transport.TLSClientConfig.MaxVersion = 0
+	transport.CloseIdleConnections()
+	resp, err := client.Get(ts.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
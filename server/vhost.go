@@ -0,0 +1,150 @@
+package server
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/aatuh/pureapi-core/router"
+)
+
+// routeTable holds a router and the routes registered with it.
+type routeTable struct {
+	router router.Router
+	// Store registered routes for method not allowed checking
+	registeredRoutes map[string]map[string]bool // path -> method -> exists
+	routesMu         sync.RWMutex
+}
+
+// newRouteTable creates a route table using the given router.
+func newRouteTable(r router.Router) routeTable {
+	return routeTable{
+		router:           r,
+		registeredRoutes: make(map[string]map[string]bool),
+	}
+}
+
+// unregister removes a method+path route from the router and tracking map.
+func (t *routeTable) unregister(method, path string) {
+	if t.router != nil {
+		_ = t.router.Unregister(method, path)
+	}
+	t.routesMu.Lock()
+	if mm, ok := t.registeredRoutes[path]; ok {
+		delete(mm, method)
+		if len(mm) == 0 {
+			delete(t.registeredRoutes, path)
+		}
+	}
+	t.routesMu.Unlock()
+}
+
+// hostTable is a route table serving requests for a host pattern.
+type hostTable struct {
+	pattern string
+	routeTable
+}
+
+// WithHostRouter sets the router of the route table for a host pattern. Host
+// tables without an explicit router use the built-in router.
+//
+// Parameters:
+//   - host: The host pattern, e.g. "api.example.com" or "*.example.com".
+//   - r: The router implementation to use.
+//
+// Returns:
+//   - HandlerOption: A handler option function.
+func WithHostRouter(host string, r router.Router) HandlerOption {
+	return func(h *Handler) {
+		if r != nil {
+			h.hostTable(host).router = r
+		}
+	}
+}
+
+// RegisterHost registers endpoints in the route table for a host pattern.
+// Requests are dispatched on their Host header before routing: exact host
+// patterns win over wildcard patterns such as "*.example.com", which match
+// any subdomain but not the bare domain. Requests for hosts without a table
+// use the routes registered with Register. Ports are ignored when matching.
+//
+// Parameters:
+//   - host: The host pattern, e.g. "api.example.com" or "*.example.com".
+//   - endpoints: The endpoints to register.
+func (h *Handler) RegisterHost(host string, endpoints []endpoint.Endpoint) {
+	h.register(&h.hostTable(host).routeTable, host, endpoints)
+}
+
+// UnregisterHost removes a method+path route from the table of a host
+// pattern.
+//
+// Parameters:
+//   - host: The host pattern.
+//   - method: The HTTP method of the route.
+//   - path: The path of the route.
+func (h *Handler) UnregisterHost(host, method, path string) {
+	h.hostsMu.RLock()
+	defer h.hostsMu.RUnlock()
+	pattern := normalizeHost(host)
+	for _, ht := range h.hosts {
+		if ht.pattern == pattern {
+			ht.unregister(method, path)
+			return
+		}
+	}
+}
+
+// hostTable returns the route table for a host pattern, creating it if
+// needed.
+func (h *Handler) hostTable(host string) *hostTable {
+	pattern := normalizeHost(host)
+	h.hostsMu.Lock()
+	defer h.hostsMu.Unlock()
+	for _, ht := range h.hosts {
+		if ht.pattern == pattern {
+			return ht
+		}
+	}
+	ht := &hostTable{
+		pattern:    pattern,
+		routeTable: newRouteTable(router.NewBuiltinRouter()),
+	}
+	h.hosts = append(h.hosts, ht)
+	return ht
+}
+
+// table returns the route table serving the request host.
+func (h *Handler) table(host string) *routeTable {
+	h.hostsMu.RLock()
+	defer h.hostsMu.RUnlock()
+	if len(h.hosts) == 0 {
+		return &h.routeTable
+	}
+	host = normalizeHost(host)
+	var wildcard *hostTable
+	for _, ht := range h.hosts {
+		if ht.pattern == host {
+			return &ht.routeTable
+		}
+		suffix, ok := strings.CutPrefix(ht.pattern, "*")
+		if ok && strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+			// Prefer the most specific wildcard.
+			if wildcard == nil || len(ht.pattern) > len(wildcard.pattern) {
+				wildcard = ht
+			}
+		}
+	}
+	if wildcard != nil {
+		return &wildcard.routeTable
+	}
+	return &h.routeTable
+}
+
+// normalizeHost lowercases a host and strips its port and trailing dot.
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
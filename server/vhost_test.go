@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aatuh/pureapi-core/endpoint"
+	"github.com/aatuh/pureapi-core/event"
+	"github.com/aatuh/pureapi-core/router"
+	"github.com/stretchr/testify/assert"
+)
+
+func textEndpoint(path, method, body string) endpoint.Endpoint {
+	return endpoint.NewEndpoint(path, method).WithHandler(
+		func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(body)) })
+}
+
+func TestHandler_RegisterHost(t *testing.T) {
+	h := NewHandler(event.NewNoopEventEmitter())
+	h.Register([]endpoint.Endpoint{textEndpoint("/", http.MethodGet, "default")})
+	h.RegisterHost("api.example.com", []endpoint.Endpoint{
+		textEndpoint("/", http.MethodGet, "api"),
+		textEndpoint("/items", http.MethodPost, "create"),
+	})
+	h.RegisterHost("*.example.com", []endpoint.Endpoint{textEndpoint("/", http.MethodGet, "wildcard")})
+	h.RegisterHost("*.eu.example.com", []endpoint.Endpoint{textEndpoint("/", http.MethodGet, "eu")})
+
+	tests := []struct {
+		host   string
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{"api.example.com", http.MethodGet, "/", http.StatusOK, "api"},
+		{"API.Example.com:8443", http.MethodGet, "/", http.StatusOK, "api"},
+		{"admin.example.com", http.MethodGet, "/", http.StatusOK, "wildcard"},
+		{"shop.eu.example.com", http.MethodGet, "/", http.StatusOK, "eu"},
+		{"example.com", http.MethodGet, "/", http.StatusOK, "default"},
+		{"other.org", http.MethodGet, "/", http.StatusOK, "default"},
+		{"admin.example.com", http.MethodPost, "/items", http.StatusNotFound, ""},
+		{"api.example.com", http.MethodGet, "/items", http.StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Host = tt.host
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			assert.Equal(t, tt.status, rec.Code)
+			if tt.body != "" {
+				assert.Equal(t, tt.body, rec.Body.String())
+			}
+		})
+	}
+
+	h.UnregisterHost("api.example.com", http.MethodGet, "/")
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Host = "api.example.com"
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestWithHostRouter(t *testing.T) {
+	r := router.NewBuiltinRouter()
+	h := NewHandler(event.NewNoopEventEmitter(), WithHostRouter("admin.example.com", r))
+	h.RegisterHost("admin.example.com", []endpoint.Endpoint{textEndpoint("/", http.MethodGet, "admin")})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.NotNil(t, r.Match(req))
+}